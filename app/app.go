@@ -2,7 +2,12 @@
 // can be used in main goroutine only.
 package app
 
-import "github.com/mkch/gw/app/gwapp"
+import (
+	"context"
+	"time"
+
+	"github.com/mkch/gw/app/gwapp"
+)
 
 var app *gwapp.GwApp = gwapp.New()
 
@@ -16,6 +21,17 @@ func Post(f func()) error {
 	return app.Post(f)
 }
 
+// Invoke runs f in the UI thread and returns the results of f.
+// See [gwapp.Invoke].
+func Invoke[T any](ctx context.Context, f func() (T, error)) (T, error) {
+	return gwapp.Invoke(ctx, app, f)
+}
+
+// PostDelayed runs f in the UI thread after d, unless ctx is done before that.
+func PostDelayed(ctx context.Context, d time.Duration, f func()) error {
+	return app.PostDelayed(ctx, d, f)
+}
+
 // Quit calls win32.PostQuitMessage which tells the message loop to exit.
 // The exit code will be the return value of Run.
 func Quit(exitCode int) {
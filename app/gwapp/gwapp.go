@@ -1,20 +1,28 @@
 // Package gwapp implements application initialization and message loop
 // that can be used in any goroutine.
+//
+// Several GwApps can run in different goroutines. Each GwApp locks its goroutine
+// to a UI thread, and the windows, menus and dialogs created in the thread are
+// registered in the thread and processed by the message loop of the GwApp only.
 package gwapp
 
 import (
+	"context"
 	"errors"
+	"log/slog"
 	"math"
 	"runtime"
 	"sync"
+	"time"
 	"unsafe"
 
 	"github.com/mkch/gg"
 	"github.com/mkch/gw/internal/appmsg"
 	"github.com/mkch/gw/internal/objectmap"
+	"github.com/mkch/gw/internal/timer"
+	"github.com/mkch/gw/internal/uithread"
 	"github.com/mkch/gw/win32"
 	"github.com/mkch/gw/window"
-	"golang.org/x/sys/windows"
 )
 
 type GwApp struct {
@@ -24,6 +32,13 @@ type GwApp struct {
 
 	msgDispatcher     MessageDispatcher
 	prevMsgDispatcher func(msg *win32.MSG) win32.LRESULT
+
+	// Message tracing, see SetMsgTracer.
+	tracer       *window.MsgTracer
+	dispatchers  int // Number of the dispatchers set by SetMessageDispatcher.
+	dispatchLink int // The deepest link of the dispatcher chain reached.
+
+	timers *timer.Map // Thread timers, see StartTimer.
 }
 
 // New creates a GwApp and do application initialization.
@@ -31,13 +46,18 @@ func New() *GwApp {
 	runtime.LockOSThread()
 
 	app := &GwApp{
-		uiThreadId: win32.DWORD(windows.GetCurrentThreadId()),
+		uiThreadId: win32.GetCurrentThreadId(),
 		postMap:    safeMap{ObjectMap: objectmap.New[func()](1, math.MaxUint)},
 		msgDispatcher: func(msg *win32.MSG, prevProc func(msg *win32.MSG) win32.LRESULT) win32.LRESULT {
 			return prevProc(msg)
 		},
 		prevMsgDispatcher: win32.DispatchMessageW,
 	}
+	app.timers = timer.NewThread(win32.NewCallback(func(_ win32.HWND, _ win32.UINT, id win32.UINT_PTR, _ win32.DWORD) uintptr {
+		app.timers.Fire(id)
+		return 0
+	}))
+	uithread.Register(app.uiThreadId)
 
 	// Prepare postMap
 	// See https://learn.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-postthreadmessagew#remarks
@@ -45,7 +65,7 @@ func New() *GwApp {
 	// Initialize message queue
 	win32.PeekMessageW(&win32.MSG{}, 0, 0, 0, win32.PM_NOREMOVE)
 	// Install thread message hook
-	proc := windows.NewCallback(func(code win32.HookCode, wParam win32.WPARAM, lParam win32.LPARAM) win32.LRESULT {
+	proc := win32.NewCallback(func(code win32.HookCode, wParam win32.WPARAM, lParam win32.LPARAM) win32.LRESULT {
 		if code >= 0 && win32.PeekMessageFlag(wParam) == win32.PM_REMOVE {
 			if msg := (*win32.MSG)(unsafe.Add(nil, lParam)); msg.Message == appmsg.POST {
 				// Handle posted functions
@@ -61,10 +81,13 @@ func New() *GwApp {
 	return app
 }
 
-// Run runs the message loop.
+// Run runs the message loop. The windows, menus and dialogs registered in
+// the UI thread are forgotten when it returns.
 func (app *GwApp) Run() int {
 	defer func() {
 		gg.MustOK(win32.UnhookWindowsHookEx(app.threadMsgHook))
+		// The thread ID may be reused by a new thread after unlocking.
+		uithread.Unregister(app.uiThreadId)
 		runtime.UnlockOSThread()
 	}()
 	var msg win32.MSG
@@ -76,7 +99,12 @@ func (app *GwApp) Run() int {
 		if r == 0 {
 			return int(msg.WParam)
 		}
+		if app.tracer != nil && app.tracer.Match(msg.Hwnd, msg.Message) {
+			app.traceMessage(&msg)
+			continue
+		}
 		if msg.Hwnd == 0 {
+			app.threadMessage(&msg)
 			continue // Messages not associated with a window cannot be dispatched
 		}
 
@@ -88,6 +116,46 @@ func (app *GwApp) Run() int {
 	}
 }
 
+// threadMessage processes msg not associated with a window.
+func (app *GwApp) threadMessage(msg *win32.MSG) {
+	if msg.Message == win32.WM_TIMER {
+		win32.DispatchMessageW(msg) // Calls the TIMERPROC of the thread timer.
+	}
+}
+
+// traceMessage processes msg as Run does and logs it with app.tracer.
+func (app *GwApp) traceMessage(msg *win32.MSG) {
+	m := *msg // msg may be modified during processing.
+	if msg.Hwnd == 0 {
+		app.tracer.Log("thread message", &m)
+		app.threadMessage(msg)
+		return
+	}
+	start := time.Now()
+	preTranslated := window.PreTranslateMessage(msg)
+	if !preTranslated {
+		win32.TranslateMessage(msg)
+	}
+	app.dispatchLink = app.dispatchers
+	ret := app.msgDispatcher(msg, app.prevMsgDispatcher)
+	app.tracer.Log("message loop", &m,
+		slog.Bool("pretranslated", preTranslated),
+		slog.Int64("result", int64(ret)),
+		slog.Int("link", app.dispatchLink),
+		slog.Duration("elapsed", time.Since(start)))
+}
+
+// SetMsgTracer sets the tracer which logs the messages retrieved by the
+// message loop, along with the processing time and the link of the
+// dispatcher chain where the dispatching stops. Link n > 0 is the dispatcher
+// set by the nth SetMessageDispatcher and link 0 is [win32.DispatchMessageW].
+// Messages not associated with a window are logged too.
+// Use [window.WindowBase.SetMsgTracer] to trace the sent messages.
+// A nil t stops tracing.
+func (app *GwApp) SetMsgTracer(t *window.MsgTracer) {
+	app.tracer = t
+}
+
 // MessageDispatcher is a function that dispatches Windows messages.
 // The prevProc parameter is the previous message dispatcher in the chain,
 // which can be called to continue the default message processing.
@@ -100,7 +168,10 @@ func (app *GwApp) SetMessageDispatcher(dispatcher MessageDispatcher) {
 		panic(errors.New("nil MsgProc"))
 	}
 	oldMsgDispatcher, oldPrevMsgDispatcher := app.msgDispatcher, app.prevMsgDispatcher
+	app.dispatchers++
+	oldLink := app.dispatchers - 1
 	app.prevMsgDispatcher = func(msg *win32.MSG) win32.LRESULT {
+		app.dispatchLink = oldLink
 		return oldMsgDispatcher(msg, oldPrevMsgDispatcher)
 	}
 	app.msgDispatcher = dispatcher
@@ -116,6 +187,124 @@ func (app *GwApp) Post(f func()) error {
 	return win32.PostThreadMessageW(app.uiThreadId, appmsg.POST, win32.WPARAM(h), 0)
 }
 
+// inUIThread reports whether the calling goroutine runs in the UI thread of app.
+func (app *GwApp) inUIThread() bool {
+	return win32.GetCurrentThreadId() == app.uiThreadId
+}
+
+// Invoke runs f in the UI thread of app and returns the results of f.
+// It blocks until f returns, or ctx is done, in which case ctx.Err() is returned and
+// f is not run if not started yet.
+// If Invoke is called in the UI thread, f is called directly.
+func Invoke[T any](ctx context.Context, app *GwApp, f func() (T, error)) (result T, err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	if app.inUIThread() {
+		return f()
+	}
+	type ret struct {
+		v   T
+		err error
+	}
+	done := make(chan ret, 1)
+	if err = app.Post(func() {
+		if err := ctx.Err(); err != nil {
+			done <- ret{err: err}
+			return
+		}
+		v, err := f()
+		done <- ret{v, err}
+	}); err != nil {
+		return
+	}
+	select {
+	case r := <-done:
+		return r.v, r.err
+	case <-ctx.Done():
+		err = ctx.Err()
+		return
+	}
+}
+
+// PostDelayed runs f in the UI thread after d, unless ctx is done before that.
+// The delay is measured by a thread timer of the message loop. If PostDelayed is
+// called in another thread, the timer is started in the UI thread later, and the
+// error of starting it is logged to slog.Default() rather than returned.
+func (app *GwApp) PostDelayed(ctx context.Context, d time.Duration, f func()) error {
+	start := func() error {
+		if ctx.Err() != nil {
+			return nil
+		}
+		var stop func() bool
+		key, err := app.StartTimer(&window.TimerSpec{Interval: d, OneShot: true, Func: func() {
+			stop()
+			if ctx.Err() == nil {
+				f()
+			}
+		}})
+		if err != nil {
+			return err
+		}
+		// Kill the timer in the UI thread as soon as ctx is done.
+		stop = context.AfterFunc(ctx, func() {
+			app.Post(func() { key.Kill() })
+		})
+		return nil
+	}
+	if app.inUIThread() {
+		return start()
+	}
+	return app.Post(func() {
+		if err := start(); err != nil {
+			slog.Error("gwapp: PostDelayed can't start the timer", slog.Any("error", err))
+		}
+	})
+}
+
+// StartTimer starts a thread timer of spec, which runs in the message loop,
+// or in the modal message loops of dialogs and menus.
+// It must be called in the UI thread.
+func (app *GwApp) StartTimer(spec *window.TimerSpec) (TimerKey, error) {
+	uithread.CheckThread(app.uiThreadId)
+	if spec.Func == nil {
+		panic("nil timer func")
+	}
+	id, err := app.timers.Set(spec.Interval, spec.OneShot, spec.Tolerance, spec.Func)
+	if err != nil {
+		return TimerKey{}, err
+	}
+	return TimerKey{app.timers, id}, nil
+}
+
+// SetTimer starts a thread timer that calls f every interval. See StartTimer.
+// It panics if the timer can't be created.
+func (app *GwApp) SetTimer(interval time.Duration, f func()) TimerKey {
+	return gg.Must(app.StartTimer(&window.TimerSpec{Interval: interval, Func: f}))
+}
+
+// AfterFunc starts a one-shot thread timer that calls f after d. See StartTimer.
+// It panics if the timer can't be created.
+func (app *GwApp) AfterFunc(d time.Duration, f func()) TimerKey {
+	return gg.Must(app.StartTimer(&window.TimerSpec{Interval: d, OneShot: true, Func: f}))
+}
+
+// TimerKey identifies a thread timer.
+type TimerKey struct {
+	m  *timer.Map
+	id win32.UINT_PTR
+}
+
+// Kill destroys the timer. Killing a killed timer, or a fired one-shot timer,
+// does nothing.
+func (k TimerKey) Kill() error {
+	if k.m == nil {
+		return nil
+	}
+	uithread.CheckThread(k.m.Thread())
+	return k.m.Kill(k.id)
+}
+
 // Quit calls win32.PostQuitMessage which tells the message loop to exit.
 // The exit code will be the return value of Run.
 func (app *GwApp) Quit(exitCode int) {
@@ -0,0 +1,196 @@
+package gwapp_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mkch/gw/app/gwapp"
+	"github.com/mkch/gw/menu"
+	"github.com/mkch/gw/win32"
+	"github.com/mkch/gw/win32/fake"
+	"github.com/mkch/gw/window"
+)
+
+var backend = fake.New()
+
+func TestMain(m *testing.M) {
+	win32.SetBackend(backend)
+	os.Exit(m.Run())
+}
+
+// start runs a GwApp in a new goroutine and returns it.
+// The message loop quits when the test finishes.
+func start(t *testing.T) *gwapp.GwApp {
+	ch := make(chan *gwapp.GwApp)
+	exit := make(chan int)
+	go func() {
+		app := gwapp.New()
+		ch <- app
+		exit <- app.Run()
+	}()
+	app := <-ch
+	t.Cleanup(func() {
+		app.Post(func() { app.Quit(0) })
+		<-exit
+	})
+	return app
+}
+
+func TestInvoke(t *testing.T) {
+	app := start(t)
+	ctx := context.Background()
+	uiThread, err := gwapp.Invoke(ctx, app, func() (win32.DWORD, error) {
+		return win32.GetCurrentThreadId(), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uiThread == win32.GetCurrentThreadId() {
+		t.Fatal("not in UI thread")
+	}
+
+	// Re-entrance.
+	errTest := errors.New("test")
+	v, err := gwapp.Invoke(ctx, app, func() (int, error) {
+		return gwapp.Invoke(ctx, app, func() (int, error) {
+			if win32.GetCurrentThreadId() != uiThread {
+				t.Error("not in UI thread")
+			}
+			return 1, errTest
+		})
+	})
+	if v != 1 || err != errTest {
+		t.Fatal(v, err)
+	}
+
+	// Cancellation.
+	block := make(chan struct{})
+	app.Post(func() { <-block })
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	run := false
+	if _, err := gwapp.Invoke(ctx, app, func() (int, error) { run = true; return 0, nil }); err != context.DeadlineExceeded {
+		t.Fatal(err)
+	}
+	close(block)
+	if _, err := gwapp.Invoke(context.Background(), app, func() (int, error) { return 0, nil }); err != nil {
+		t.Fatal(err)
+	}
+	if run {
+		t.Fatal("canceled function is run")
+	}
+}
+
+func TestPostDelayed(t *testing.T) {
+	app := start(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	fired := make(chan string, 2)
+	if err := app.PostDelayed(context.Background(), 100*time.Millisecond, func() { fired <- "a" }); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.PostDelayed(ctx, 100*time.Millisecond, func() { fired <- "b" }); err != nil {
+		t.Fatal(err)
+	}
+	sync := func() {
+		gwapp.Invoke(context.Background(), app, func() (struct{}, error) { return struct{}{}, nil })
+	}
+	sync()
+	backend.Advance(50 * time.Millisecond)
+	sync()
+	cancel()
+	sync()
+	backend.Advance(50 * time.Millisecond)
+	sync()
+	select {
+	case s := <-fired:
+		if s != "a" {
+			t.Fatal(s)
+		}
+	default:
+		t.Fatal("not fired")
+	}
+	select {
+	case s := <-fired:
+		t.Fatal(s)
+	default:
+	}
+}
+
+func TestTimerInModalLoop(t *testing.T) {
+	app := start(t)
+	fired, err := gwapp.Invoke(context.Background(), app, func() (bool, error) {
+		fired := false
+		app.AfterFunc(100*time.Millisecond, func() { fired = true })
+		backend.Advance(100 * time.Millisecond)
+		// A modal loop, such as the one of a dialog box, dispatches the messages
+		// it retrieves without knowing the message loop of app.
+		var msg win32.MSG
+		for !fired && win32.GetMessageW(&msg, 0, 0, 0) > 0 {
+			win32.DispatchMessageW(&msg)
+		}
+		return fired, nil
+	})
+	if err != nil || !fired {
+		t.Fatal(fired, err)
+	}
+}
+
+func TestMultipleApps(t *testing.T) {
+	type result struct {
+		hwnd     win32.HWND
+		clicked  *int
+		menuItem win32.WORD
+	}
+	apps := []*gwapp.GwApp{start(t), start(t)}
+	var results []result
+	for _, app := range apps {
+		r, err := gwapp.Invoke(context.Background(), app, func() (r result, err error) {
+			w, err := window.New(&window.Spec{})
+			if err != nil {
+				return
+			}
+			m := menu.New(false)
+			if err = w.SetMenu(m); err != nil {
+				return
+			}
+			r.clicked = new(int)
+			item, err := m.InsertItem(0, &menu.ItemSpec{Title: "Item", OnClick: func() { *r.clicked++ }})
+			if err != nil {
+				return
+			}
+			return result{w.HWND(), r.clicked, item.ID()}, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, r)
+	}
+
+	for i, app := range apps {
+		other := results[1-i]
+		if found, _ := gwapp.Invoke(context.Background(), app, func() (bool, error) {
+			return window.LookupWindowBase(other.hwnd) != nil, nil
+		}); found {
+			t.Fatalf("window of app %v is found in app %v", 1-i, i)
+		}
+		// Menu item IDs are allocated independently, and the commands are
+		// routed to the items of the window's own app.
+		if err := win32.PostMessageW(results[i].hwnd, win32.WM_COMMAND, win32.WPARAM(results[i].menuItem), 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, app := range apps {
+		clicked, _ := gwapp.Invoke(context.Background(), app, func() (int, error) {
+			return *results[i].clicked, nil
+		})
+		if clicked != 1 {
+			t.Fatalf("app %v: %v clicks", i, clicked)
+		}
+		gwapp.Invoke(context.Background(), app, func() (struct{}, error) {
+			return struct{}{}, window.LookupWindowBase(results[i].hwnd).Destroy()
+		})
+	}
+}
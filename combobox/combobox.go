@@ -0,0 +1,355 @@
+package combobox
+
+import (
+	"errors"
+	"runtime"
+	"slices"
+	"strings"
+	"unicode/utf16"
+	"unsafe"
+
+	"github.com/mkch/gg"
+	"github.com/mkch/gw/control"
+	"github.com/mkch/gw/internal/appmsg"
+	"github.com/mkch/gw/internal/uithread"
+	"github.com/mkch/gw/metrics"
+	"github.com/mkch/gw/win32"
+	"github.com/mkch/gw/win32/win32util"
+)
+
+var (
+	// ErrFailed is returned if a combo box message fails, for example, with an index out of range.
+	ErrFailed = errors.New("combobox: failed")
+	// ErrSpace is returned if there is insufficient space to store the items.
+	ErrSpace = errors.New("combobox: insufficient space")
+	// ErrNotEditable is returned by the operations on the edit control of a CBS_DROPDOWNLIST combo box.
+	ErrNotEditable = errors.New("combobox: not editable")
+)
+
+// ComboBox is a combo box control whose items carry values of type T.
+//
+// The indexes of items are the positions in all the items, whether the list
+// is filtered by autocomplete or not, see SetAutoComplete.
+type ComboBox[T any] struct {
+	control.Control
+	// OnSelChange is called when the user changes the selection in the list.
+	OnSelChange func()
+	// OnEditChange is called when the user changes the edit text.
+	OnEditChange func()
+	// OnDropDown is called when the drop-down list is about to be shown.
+	OnDropDown func()
+	items      []item[T]
+	match      func(input, text string) bool
+	shown      []int // Indexes of the items shown in the filtered list, nil if not filtered.
+}
+
+type item[T any] struct {
+	text  string
+	value T
+}
+
+type Spec struct {
+	Text      string // Initial edit text.
+	CueBanner string
+	X         metrics.Dimension
+	Y         metrics.Dimension
+	Width     metrics.Dimension
+	Height    metrics.Dimension // Height including the drop-down list.
+	// Style is one of CBS_SIMPLE, CBS_DROPDOWN and CBS_DROPDOWNLIST, combined with
+	// other styles. CBS_DROPDOWN is used if none is specified.
+	// CBS_OWNERDRAWFIXED and CBS_OWNERDRAWVARIABLE are not supported.
+	Style   win32.WINDOW_STYLE
+	ExStyle win32.WINDOW_EX_STYLE
+}
+
+func New[T any](parent win32.HWND, spec *Spec) (*ComboBox[T], error) {
+	uithread.Check()
+	dpi := gg.Must(win32.GetDpiForWindow(parent))
+	style := spec.Style&^(win32.CBS_OWNERDRAWFIXED|win32.CBS_OWNERDRAWVARIABLE) | win32.WS_CHILD
+	if style&win32.CBS_DROPDOWNLIST == 0 {
+		style |= win32.CBS_DROPDOWN
+	}
+	hwnd, err := win32util.CreateWindow(&win32util.Wnd{
+		ClassName:  "COMBOBOX",
+		WndParent:  parent,
+		WindowName: spec.Text,
+		X:          spec.X.Px(dpi),
+		Y:          spec.Y.Px(dpi),
+		Width:      spec.Width.Px(dpi),
+		Height:     spec.Height.Px(dpi),
+		Style:      style,
+		ExStyle:    spec.ExStyle,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var cb ComboBox[T]
+	if err := control.Attach(hwnd, &cb.Control); err != nil {
+		win32.DestroyWindow(hwnd)
+		return nil, err
+	}
+	if spec.CueBanner != "" {
+		if err := cb.SetCueBanner(spec.CueBanner); err != nil {
+			cb.Destroy()
+			return nil, err
+		}
+	}
+	cb.SetWndProc(func(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM, prev win32.WndProc) win32.LRESULT {
+		switch message {
+		case appmsg.REFLECT_COMMAND:
+			switch win32.HIWORD(wParam) {
+			case win32.CBN_SELCHANGE:
+				if cb.OnSelChange != nil {
+					cb.OnSelChange()
+				}
+			case win32.CBN_EDITCHANGE:
+				if cb.match != nil {
+					cb.filter()
+				}
+				if cb.OnEditChange != nil {
+					cb.OnEditChange()
+				}
+			case win32.CBN_DROPDOWN:
+				if cb.OnDropDown != nil {
+					cb.OnDropDown()
+				}
+			}
+		}
+		return prev(hwnd, message, wParam, lParam)
+	})
+	return &cb, nil
+}
+
+// send sends a combo box message and converts CB_ERR and CB_ERRSPACE to errors.
+func (cb *ComboBox[T]) send(message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) (int, error) {
+	// The error of SendMessageW is ignored, because combo box messages do not set the last error.
+	r, _ := win32.SendMessageW(cb.HWND(), message, wParam, lParam)
+	switch r {
+	case win32.CB_ERR:
+		return 0, ErrFailed
+	case win32.CB_ERRSPACE:
+		return 0, ErrSpace
+	}
+	return int(r), nil
+}
+
+// sendString sends a combo box message with the C string of s as lParam.
+func (cb *ComboBox[T]) sendString(message win32.UINT, wParam win32.WPARAM, s string) (int, error) {
+	var buf []win32.WCHAR
+	win32util.CString(s, &buf)
+	defer runtime.KeepAlive(buf)
+	return cb.send(message, wParam, win32.LPARAM(uintptr(unsafe.Pointer(&buf[0]))))
+}
+
+// editable reports whether cb has an edit control.
+func (cb *ComboBox[T]) editable() bool {
+	style, _ := win32.GetWindowLongPtrW(cb.HWND(), win32.GWL_STYLE)
+	return style&win32.CBS_DROPDOWNLIST != win32.CBS_DROPDOWNLIST
+}
+
+// Add adds an item and returns its index. The item is added to the end of the list,
+// or to the sorted position if the combo box is CBS_SORT.
+// The autocomplete filter, if any, is cleared.
+func (cb *ComboBox[T]) Add(text string, value T) (int, error) {
+	uithread.CheckWindow(cb.HWND())
+	cb.unfilter()
+	i, err := cb.sendString(win32.CB_ADDSTRING, 0, text)
+	if err != nil {
+		return 0, err
+	}
+	cb.items = slices.Insert(cb.items, i, item[T]{text, value})
+	return i, nil
+}
+
+// Insert inserts an item at index, -1 for the end of the list. The list is not sorted.
+// The autocomplete filter, if any, is cleared.
+func (cb *ComboBox[T]) Insert(index int, text string, value T) error {
+	uithread.CheckWindow(cb.HWND())
+	cb.unfilter()
+	i, err := cb.sendString(win32.CB_INSERTSTRING, win32.WPARAM(index), text)
+	if err != nil {
+		return err
+	}
+	cb.items = slices.Insert(cb.items, i, item[T]{text, value})
+	return nil
+}
+
+// Remove removes the item at index. The autocomplete filter, if any, is cleared.
+func (cb *ComboBox[T]) Remove(index int) error {
+	uithread.CheckWindow(cb.HWND())
+	cb.unfilter()
+	if _, err := cb.send(win32.CB_DELETESTRING, win32.WPARAM(index), 0); err != nil {
+		return err
+	}
+	cb.items = slices.Delete(cb.items, index, index+1)
+	return nil
+}
+
+// Clear removes all items and the edit text.
+func (cb *ComboBox[T]) Clear() {
+	uithread.CheckWindow(cb.HWND())
+	win32.SendMessageW(cb.HWND(), win32.CB_RESETCONTENT, 0, 0)
+	clear(cb.items)
+	cb.items = cb.items[:0]
+	cb.shown = nil
+}
+
+// Len returns the number of items.
+func (cb *ComboBox[T]) Len() int {
+	return len(cb.items)
+}
+
+// Text returns the text of the item at index. It panics if index is out of range.
+func (cb *ComboBox[T]) Text(index int) string {
+	return cb.items[index].text
+}
+
+// Value returns the value of the item at index. It panics if index is out of range.
+func (cb *ComboBox[T]) Value(index int) T {
+	return cb.items[index].value
+}
+
+// SetValue sets the value of the item at index. It panics if index is out of range.
+func (cb *ComboBox[T]) SetValue(index int, value T) {
+	uithread.CheckWindow(cb.HWND())
+	cb.items[index].value = value
+}
+
+// Find returns the index of the first item whose text is s, case insensitive, or -1 if not found.
+func (cb *ComboBox[T]) Find(s string) int {
+	return slices.IndexFunc(cb.items, func(item item[T]) bool { return strings.EqualFold(item.text, s) })
+}
+
+// Selected returns the index of the selected item, or -1 if none.
+func (cb *ComboBox[T]) Selected() int {
+	i, err := cb.send(win32.CB_GETCURSEL, 0, 0)
+	if err != nil {
+		return -1
+	}
+	if cb.shown != nil {
+		return cb.shown[i]
+	}
+	return i
+}
+
+// Select selects the item at index and sets the edit text to its text, -1 to clear
+// the selection and the edit text. The autocomplete filter, if any, is cleared.
+func (cb *ComboBox[T]) Select(index int) error {
+	uithread.CheckWindow(cb.HWND())
+	cb.unfilter()
+	_, err := cb.send(win32.CB_SETCURSEL, win32.WPARAM(index), 0)
+	if index == -1 && err == ErrFailed { // CB_ERR is returned when the selection is cleared.
+		err = nil
+	}
+	return err
+}
+
+// EditText returns the text of the edit control, or the text of the selected item
+// of a CBS_DROPDOWNLIST combo box.
+func (cb *ComboBox[T]) EditText() (string, error) {
+	return win32util.GetWindowText(cb.HWND())
+}
+
+// SetEditText sets the text of the edit control.
+func (cb *ComboBox[T]) SetEditText(text string) error {
+	uithread.CheckWindow(cb.HWND())
+	if !cb.editable() {
+		return ErrNotEditable
+	}
+	return win32util.SetWindowText(cb.HWND(), text)
+}
+
+// CueBanner returns the text displayed in the edit control when it is empty.
+func (cb *ComboBox[T]) CueBanner() (string, error) {
+	// There is no way to get the length of the cue banner.
+	var buf [256]win32.WCHAR
+	if r, _ := win32.SendMessageW(cb.HWND(), win32.CB_GETCUEBANNER, win32.WPARAM(uintptr(unsafe.Pointer(&buf[0]))), win32.LPARAM(len(buf))); r != 1 {
+		return "", ErrFailed
+	}
+	return win32util.GoString(&buf[0], slices.Index(buf[:], 0)+1), nil
+}
+
+// SetCueBanner sets the text displayed in the edit control when it is empty.
+func (cb *ComboBox[T]) SetCueBanner(text string) error {
+	uithread.CheckWindow(cb.HWND())
+	var buf []win32.WCHAR
+	win32util.CString(text, &buf)
+	if r, _ := win32.SendMessageW(cb.HWND(), win32.CB_SETCUEBANNER, 0, win32.LPARAM(uintptr(unsafe.Pointer(&buf[0])))); r != 1 {
+		return ErrFailed
+	}
+	return nil
+}
+
+// ShowDropDown shows or hides the drop-down list.
+func (cb *ComboBox[T]) ShowDropDown(show bool) {
+	uithread.CheckWindow(cb.HWND())
+	win32.SendMessageW(cb.HWND(), win32.CB_SHOWDROPDOWN, win32.WPARAM(gg.If(show, 1, 0)), 0)
+}
+
+// HasPrefix reports whether text begins with input, case insensitive.
+func HasPrefix(input, text string) bool {
+	return strings.HasPrefix(strings.ToLower(text), strings.ToLower(input))
+}
+
+// SetAutoComplete sets the autocomplete filter mode of an editable combo box.
+// When the user changes the edit text, the list shows only the items whose text
+// matches the edit text, such as HasPrefix, and the drop-down list is shown if any
+// item matches a nonempty edit text. A nil match disables the mode.
+func (cb *ComboBox[T]) SetAutoComplete(match func(input, text string) bool) error {
+	uithread.CheckWindow(cb.HWND())
+	if !cb.editable() {
+		return ErrNotEditable
+	}
+	if match == nil {
+		cb.unfilter()
+	}
+	cb.match = match
+	return nil
+}
+
+// filter shows only the items matching the edit text in the list.
+func (cb *ComboBox[T]) filter() {
+	text, _ := cb.EditText()
+	cb.shown = make([]int, 0, len(cb.items))
+	for i, item := range cb.items {
+		if cb.match(text, item.text) {
+			cb.shown = append(cb.shown, i)
+		}
+	}
+	cb.reload(text, len(cb.shown) > 0 && text != "")
+}
+
+// unfilter shows all the items if the list is filtered.
+func (cb *ComboBox[T]) unfilter() {
+	if cb.shown == nil {
+		return
+	}
+	selected := cb.Selected()
+	cb.shown = nil
+	text, _ := cb.EditText()
+	cb.reload(text, false)
+	if selected != -1 {
+		cb.send(win32.CB_SETCURSEL, win32.WPARAM(selected), 0)
+	}
+}
+
+// reload replaces the items in the list with the items shown, and restores the
+// edit text, which is cleared by CB_RESETCONTENT.
+func (cb *ComboBox[T]) reload(text string, dropDown bool) {
+	win32.SendMessageW(cb.HWND(), win32.CB_RESETCONTENT, 0, 0)
+	if cb.shown == nil {
+		for _, item := range cb.items {
+			cb.sendString(win32.CB_INSERTSTRING, ^win32.WPARAM(0), item.text)
+		}
+	} else {
+		for _, i := range cb.shown {
+			cb.sendString(win32.CB_INSERTSTRING, ^win32.WPARAM(0), cb.items[i].text)
+		}
+	}
+	// Show the drop-down list before restoring the edit text, because showing the list
+	// selects the item matching the edit text and replaces the edit text with it.
+	cb.ShowDropDown(dropDown)
+	win32util.SetWindowText(cb.HWND(), text)
+	n := len(utf16.Encode([]rune(text)))
+	win32.SendMessageW(cb.HWND(), win32.CB_SETEDITSEL, 0, win32.LPARAM(win32.MAKELONG(win32.WORD(n), win32.WORD(n))))
+}
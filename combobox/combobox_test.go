@@ -0,0 +1,210 @@
+package combobox_test
+
+import (
+	"slices"
+	"testing"
+	"unsafe"
+
+	"github.com/mkch/gw/combobox"
+	"github.com/mkch/gw/win32"
+	"github.com/mkch/gw/win32/fake"
+	"github.com/mkch/gw/win32/fake/faketest"
+	"github.com/mkch/gw/win32/win32util"
+)
+
+var backend = fake.New()
+
+func TestMain(m *testing.M) {
+	faketest.Main(m, backend)
+}
+
+// newComboBox creates a parent window and a combo box in it.
+func newComboBox[T any](t *testing.T, spec *combobox.Spec) *combobox.ComboBox[T] {
+	parent := faketest.NewParent(t)
+	cb, err := combobox.New[T](parent.HWND(), spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cb
+}
+
+// list returns the texts of the items in the list of cb.
+func list[T any](cb *combobox.ComboBox[T]) (texts []string) {
+	var buf [64]win32.WCHAR
+	n, _ := win32.SendMessageW(cb.HWND(), win32.CB_GETCOUNT, 0, 0)
+	for i := range n {
+		l, _ := win32.SendMessageW(cb.HWND(), win32.CB_GETLBTEXT, win32.WPARAM(i), win32.LPARAM(uintptr(unsafe.Pointer(&buf[0]))))
+		texts = append(texts, win32util.GoString(&buf[0], int(l)+1))
+	}
+	return
+}
+
+func TestItems(t *testing.T) {
+	cb := newComboBox[int](t, &combobox.Spec{Style: win32.CBS_DROPDOWNLIST | win32.CBS_SORT})
+	for i, text := range []string{"b", "c", "a"} {
+		if _, err := cb.Add(text, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := cb.Insert(1, "x", 9); err != nil {
+		t.Fatal(err)
+	}
+	if got := list(cb); !slices.Equal(got, []string{"a", "x", "b", "c"}) {
+		t.Fatal(got)
+	}
+	if cb.Len() != 4 || cb.Text(1) != "x" || cb.Value(0) != 2 || cb.Value(1) != 9 {
+		t.Fatal(cb.Len(), cb.Text(1), cb.Value(0), cb.Value(1))
+	}
+	if i := cb.Find("C"); i != 3 {
+		t.Fatal(i)
+	}
+	if err := cb.Remove(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := cb.Remove(3); err != combobox.ErrFailed {
+		t.Fatal(err)
+	}
+	cb.SetValue(0, 5)
+	if got := list(cb); !slices.Equal(got, []string{"a", "b", "c"}) || cb.Value(0) != 5 {
+		t.Fatal(got, cb.Value(0))
+	}
+	cb.Clear()
+	if cb.Len() != 0 || len(list(cb)) != 0 {
+		t.Fatal(cb.Len())
+	}
+}
+
+func TestSelection(t *testing.T) {
+	cb := newComboBox[string](t, &combobox.Spec{Style: win32.CBS_DROPDOWNLIST})
+	for _, text := range []string{"a", "b", "c"} {
+		cb.Add(text, text)
+	}
+	var changes, dropDowns int
+	cb.OnSelChange = func() { changes++ }
+	cb.OnDropDown = func() { dropDowns++ }
+
+	if cb.Selected() != -1 {
+		t.Fatal(cb.Selected())
+	}
+	if err := cb.Select(1); err != nil || cb.Selected() != 1 {
+		t.Fatal(err, cb.Selected())
+	}
+	if text, err := cb.EditText(); err != nil || text != "b" {
+		t.Fatal(text, err)
+	}
+	if err := cb.Select(-1); err != nil || cb.Selected() != -1 {
+		t.Fatal(err, cb.Selected())
+	}
+	if changes != 0 { // Not changed by the user.
+		t.Fatal(changes)
+	}
+
+	cb.ShowDropDown(true)
+	if dropDowns != 1 {
+		t.Fatal(dropDowns)
+	}
+	if err := backend.ComboBoxSelect(cb.HWND(), 2); err != nil {
+		t.Fatal(err)
+	}
+	if changes != 1 || cb.Selected() != 2 {
+		t.Fatal(changes, cb.Selected())
+	}
+	if r, _ := win32.SendMessageW(cb.HWND(), win32.CB_GETDROPPEDSTATE, 0, 0); r != 0 {
+		t.Fatal("drop-down list not closed")
+	}
+	if err := cb.SetEditText("x"); err != combobox.ErrNotEditable {
+		t.Fatal(err)
+	}
+	if err := cb.SetAutoComplete(combobox.HasPrefix); err != combobox.ErrNotEditable {
+		t.Fatal(err)
+	}
+}
+
+func TestEdit(t *testing.T) {
+	cb := newComboBox[int](t, &combobox.Spec{Text: "init", CueBanner: "Search", Style: win32.CBS_SIMPLE})
+	if text, err := cb.EditText(); err != nil || text != "init" {
+		t.Fatal(text, err)
+	}
+	if cue, err := cb.CueBanner(); err != nil || cue != "Search" {
+		t.Fatal(cue, err)
+	}
+	var edits []string
+	cb.OnEditChange = func() {
+		text, _ := cb.EditText()
+		edits = append(edits, text)
+	}
+	if err := cb.SetEditText("x"); err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.ComboBoxType(cb.HWND(), "typed"); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(edits, []string{"typed"}) { // Only the changes by the user.
+		t.Fatal(edits)
+	}
+}
+
+func TestAutoComplete(t *testing.T) {
+	cb := newComboBox[int](t, &combobox.Spec{})
+	for i, text := range []string{"apple", "Apricot", "banana", "avocado"} {
+		cb.Add(text, i)
+	}
+	var dropDowns int
+	cb.OnDropDown = func() { dropDowns++ }
+	if err := cb.SetAutoComplete(combobox.HasPrefix); err != nil {
+		t.Fatal(err)
+	}
+
+	backend.ComboBoxType(cb.HWND(), "ap")
+	if got := list(cb); !slices.Equal(got, []string{"apple", "Apricot"}) {
+		t.Fatal(got)
+	}
+	if text, _ := cb.EditText(); text != "ap" {
+		t.Fatal(text)
+	}
+	if r, _ := win32.SendMessageW(cb.HWND(), win32.CB_GETEDITSEL, 0, 0); r != 2|2<<16 {
+		t.Fatalf("caret %#x", r)
+	}
+	if r, _ := win32.SendMessageW(cb.HWND(), win32.CB_GETDROPPEDSTATE, 0, 0); r != 1 || dropDowns != 1 {
+		t.Fatal(r, dropDowns)
+	}
+
+	// Indexes are of all the items.
+	backend.ComboBoxSelect(cb.HWND(), 1)
+	if i := cb.Selected(); i != 1 || cb.Value(i) != 1 {
+		t.Fatal(i)
+	}
+	backend.ComboBoxType(cb.HWND(), "a")
+	backend.ComboBoxSelect(cb.HWND(), 2)
+	if i := cb.Selected(); i != 3 || cb.Text(i) != "avocado" {
+		t.Fatal(i)
+	}
+
+	// No match hides the drop-down list.
+	backend.ComboBoxType(cb.HWND(), "x")
+	if got := list(cb); len(got) != 0 {
+		t.Fatal(got)
+	}
+	if r, _ := win32.SendMessageW(cb.HWND(), win32.CB_GETDROPPEDSTATE, 0, 0); r != 0 {
+		t.Fatal(r)
+	}
+
+	// Changing the items clears the filter.
+	backend.ComboBoxType(cb.HWND(), "b")
+	cb.Add("cherry", 4)
+	if got := list(cb); !slices.Equal(got, []string{"apple", "Apricot", "banana", "avocado", "cherry"}) {
+		t.Fatal(got)
+	}
+	if text, _ := cb.EditText(); text != "b" {
+		t.Fatal(text)
+	}
+
+	// Disabled.
+	if err := cb.SetAutoComplete(nil); err != nil {
+		t.Fatal(err)
+	}
+	backend.ComboBoxType(cb.HWND(), "ch")
+	if got := list(cb); len(got) != 5 {
+		t.Fatal(got)
+	}
+}
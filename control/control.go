@@ -1,7 +1,11 @@
 package control
 
 import (
+	"unsafe"
+
 	"github.com/mkch/gg"
+	"github.com/mkch/gw/internal/appmsg"
+	"github.com/mkch/gw/internal/uithread"
 	"github.com/mkch/gw/paint/font"
 	"github.com/mkch/gw/win32"
 	"github.com/mkch/gw/window"
@@ -9,10 +13,52 @@ import (
 
 type Control struct {
 	window.WindowBase
-	font *font.Font
+	// OnDrawItem draws an item of an owner-drawn control, see WM_DRAWITEM.
+	OnDrawItem func(item *win32.DRAWITEMSTRUCT)
+	// OnMeasureItem measures an item of an owner-drawn control, see WM_MEASUREITEM.
+	// The ID of the control must be unique among its siblings to receive WM_MEASUREITEM,
+	// which is not received before Attach.
+	OnMeasureItem func(item *win32.MEASUREITEMSTRUCT)
+	// OnCtlColor sets the colors of hdc to draw the control, and returns the brush
+	// to paint the background. 0 is for the default processing. See WM_CTLCOLOR*.
+	OnCtlColor     func(hdc win32.HDC) win32.HBRUSH
+	font           *font.Font
+	notifyHandlers map[win32.UINT]NotifyHandler
+}
+
+// NotifyHandler handles a WM_NOTIFY notification of a control, and returns the
+// result of the notification.
+type NotifyHandler func(hdr *win32.NMHDR) win32.LRESULT
+
+// SetNotifyHandler sets the handler of the WM_NOTIFY notification code sent by ctrl.
+// A nil handler removes the handler.
+func (ctrl *Control) SetNotifyHandler(code win32.UINT, h NotifyHandler) {
+	uithread.CheckWindow(ctrl.HWND())
+	if h == nil {
+		delete(ctrl.notifyHandlers, code)
+		return
+	}
+	if ctrl.notifyHandlers == nil {
+		ctrl.notifyHandlers = make(map[win32.UINT]NotifyHandler)
+	}
+	ctrl.notifyHandlers[code] = h
+}
+
+// HandleNotify sets the handler of the WM_NOTIFY notification code sent by ctrl,
+// with the notification structure of type T, which must begin with NMHDR.
+func HandleNotify[T any](ctrl *Control, code win32.UINT, h func(nm *T) win32.LRESULT) {
+	ctrl.SetNotifyHandler(code, func(hdr *win32.NMHDR) win32.LRESULT {
+		return h(Notification[T](hdr))
+	})
+}
+
+// Notification converts hdr to the notification structure of type T containing it.
+func Notification[T any](hdr *win32.NMHDR) *T {
+	return (*T)(unsafe.Pointer(hdr))
 }
 
 func Attach(hwnd win32.HWND, control *Control) error {
+	uithread.CheckWindow(hwnd)
 	if err := window.Attach(hwnd, &control.WindowBase); err != nil {
 		return err
 	}
@@ -28,6 +74,27 @@ func Attach(hwnd win32.HWND, control *Control) error {
 			dpi := gg.Must(win32.GetDpiForWindow(control.HWND()))
 			gg.MustOK(control.font.ChangeDPI(dpi))
 			control.applyFont()
+		case appmsg.REFLECT_NOTIFY:
+			hdr := win32.LParamPointer[win32.NMHDR](lParam)
+			if h := control.notifyHandlers[hdr.Code]; h != nil {
+				return h(hdr)
+			}
+		case appmsg.REFLECT_DRAWITEM:
+			if control.OnDrawItem != nil {
+				control.OnDrawItem(win32.LParamPointer[win32.DRAWITEMSTRUCT](lParam))
+				return 1
+			}
+		case appmsg.REFLECT_MEASUREITEM:
+			if control.OnMeasureItem != nil {
+				control.OnMeasureItem(win32.LParamPointer[win32.MEASUREITEMSTRUCT](lParam))
+				return 1
+			}
+		case appmsg.REFLECT_CTLCOLORSTATIC, appmsg.REFLECT_CTLCOLOREDIT, appmsg.REFLECT_CTLCOLORBTN, appmsg.REFLECT_CTLCOLORLISTBOX:
+			if control.OnCtlColor != nil {
+				if br := control.OnCtlColor(win32.HDC(wParam)); br != 0 {
+					return win32.LRESULT(br)
+				}
+			}
 		}
 		return prevWndProc(hwnd, message, wParam, lParam)
 	})
@@ -40,6 +107,7 @@ func (ctrl *Control) applyFont() {
 
 // SetFont sets the font used by this control. System default font is used if font is nil.
 func (ctrl *Control) SetFont(f *font.Font) {
+	uithread.CheckWindow(ctrl.HWND())
 	if ctrl.font != nil {
 		ctrl.font.Release()
 	}
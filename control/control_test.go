@@ -0,0 +1,162 @@
+package control_test
+
+import (
+	"slices"
+	"testing"
+	"unsafe"
+
+	"github.com/mkch/gw/control"
+	"github.com/mkch/gw/win32"
+	"github.com/mkch/gw/win32/fake"
+	"github.com/mkch/gw/win32/fake/faketest"
+	"github.com/mkch/gw/win32/win32util"
+	"github.com/mkch/gw/window"
+)
+
+func TestMain(m *testing.M) {
+	faketest.Main(m, fake.New())
+}
+
+// newControl creates a parent window and a control of class with id.
+func newControl(t *testing.T, class string, id win32.HMENU) (parent *window.Window, ctrl *control.Control) {
+	parent = faketest.NewParent(t)
+	hwnd, err := win32util.CreateWindow(&win32util.Wnd{ClassName: class, WndParent: parent.HWND(), Style: win32.WS_CHILD, Menu: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctrl = &control.Control{}
+	if err := control.Attach(hwnd, ctrl); err != nil {
+		t.Fatal(err)
+	}
+	return
+}
+
+type nmTest struct {
+	win32.NMHDR
+	Value int
+}
+
+func TestNotify(t *testing.T) {
+	parent, ctrl := newControl(t, "SysListView32", 7)
+	var got *nmTest
+	control.HandleNotify(ctrl, win32.NM_CLICK, func(nm *nmTest) win32.LRESULT {
+		got = nm
+		return 3
+	})
+	nm := fake.Alloc[nmTest]()
+	defer fake.Free(nm)
+	*nm = nmTest{NMHDR: win32.NMHDR{HwndFrom: ctrl.HWND(), IdFrom: 7, Code: win32.NM_CLICK}, Value: 42}
+	if r, _ := win32.SendMessageW(parent.HWND(), win32.WM_NOTIFY, 7, win32.LPARAM(uintptr(unsafe.Pointer(nm)))); r != 3 || got != nm || got.Value != 42 {
+		t.Fatal(r, got)
+	}
+
+	// Notifications without handlers.
+	got = nil
+	nm.Code = win32.NM_DBLCLK
+	if r, _ := win32.SendMessageW(parent.HWND(), win32.WM_NOTIFY, 7, win32.LPARAM(uintptr(unsafe.Pointer(nm)))); r != 0 || got != nil {
+		t.Fatal(r, got)
+	}
+	ctrl.SetNotifyHandler(win32.NM_CLICK, nil)
+	nm.Code = win32.NM_CLICK
+	if r, _ := win32.SendMessageW(parent.HWND(), win32.WM_NOTIFY, 7, win32.LPARAM(uintptr(unsafe.Pointer(nm)))); r != 0 || got != nil {
+		t.Fatal(r, got)
+	}
+}
+
+func TestOwnerDraw(t *testing.T) {
+	parent, ctrl := newControl(t, "LISTBOX", 8)
+	var drawn, measured win32.UINT
+	ctrl.OnDrawItem = func(item *win32.DRAWITEMSTRUCT) { drawn = item.ItemID }
+	ctrl.OnMeasureItem = func(item *win32.MEASUREITEMSTRUCT) {
+		measured = item.ItemID
+		item.ItemHeight = 20
+	}
+	draw := fake.Alloc[win32.DRAWITEMSTRUCT]()
+	defer fake.Free(draw)
+	*draw = win32.DRAWITEMSTRUCT{CtlType: win32.ODT_LISTBOX, CtlID: 8, ItemID: 2, HwndItem: ctrl.HWND()}
+	if r, _ := win32.SendMessageW(parent.HWND(), win32.WM_DRAWITEM, 8, win32.LPARAM(uintptr(unsafe.Pointer(draw)))); r != 1 || drawn != 2 {
+		t.Fatal(r, drawn)
+	}
+	measure := fake.Alloc[win32.MEASUREITEMSTRUCT]()
+	defer fake.Free(measure)
+	*measure = win32.MEASUREITEMSTRUCT{CtlType: win32.ODT_LISTBOX, CtlID: 8, ItemID: 3}
+	if r, _ := win32.SendMessageW(parent.HWND(), win32.WM_MEASUREITEM, 8, win32.LPARAM(uintptr(unsafe.Pointer(measure)))); r != 1 || measured != 3 || measure.ItemHeight != 20 {
+		t.Fatal(r, measured, *measure)
+	}
+}
+
+func TestCtlColor(t *testing.T) {
+	parent, ctrl := newControl(t, "EDIT", 9)
+	const brush win32.HBRUSH = 0x1234
+	var dc win32.HDC
+	ctrl.OnCtlColor = func(hdc win32.HDC) win32.HBRUSH {
+		dc = hdc
+		return brush
+	}
+	for _, message := range []win32.UINT{win32.WM_CTLCOLOREDIT, win32.WM_CTLCOLORSTATIC} {
+		if r, _ := win32.SendMessageW(parent.HWND(), message, 0x5678, win32.LPARAM(ctrl.HWND())); r != win32.LRESULT(brush) || dc != 0x5678 {
+			t.Fatal(message, r, dc)
+		}
+	}
+}
+
+func TestReflectUnhandled(t *testing.T) {
+	// A native parent, whose window procedure handles the messages of the controls.
+	hwnd, err := win32util.CreateWindow(&win32util.Wnd{ClassName: "STATIC"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer win32.DestroyWindow(hwnd)
+	native, _ := win32.GetWindowLongPtrW(hwnd, win32.GWLP_WNDPROC)
+	var nativeMessages []win32.UINT
+	proc := win32.NewCallback(func(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) win32.LRESULT {
+		switch message {
+		case win32.WM_NOTIFY, win32.WM_DRAWITEM, win32.WM_MEASUREITEM, win32.WM_CTLCOLOREDIT:
+			nativeMessages = append(nativeMessages, message)
+			return 5
+		}
+		return win32.CallWindowProcW(uintptr(native), hwnd, message, wParam, lParam)
+	})
+	win32.SetWindowLongPtrW(hwnd, win32.GWLP_WNDPROC, win32.LONG_PTR(proc))
+	if err := window.Attach(hwnd, &window.WindowBase{}); err != nil {
+		t.Fatal(err)
+	}
+	child, err := win32util.CreateWindow(&win32util.Wnd{ClassName: "EDIT", WndParent: hwnd, Style: win32.WS_CHILD, Menu: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctrl := &control.Control{}
+	if err := control.Attach(child, ctrl); err != nil {
+		t.Fatal(err)
+	}
+	control.HandleNotify(ctrl, win32.NM_CLICK, func(nm *win32.NMHDR) win32.LRESULT { return 3 })
+
+	nm := fake.Alloc[win32.NMHDR]()
+	defer fake.Free(nm)
+	*nm = win32.NMHDR{HwndFrom: child, IdFrom: 10, Code: win32.NM_CLICK}
+	if r, _ := win32.SendMessageW(hwnd, win32.WM_NOTIFY, 10, win32.LPARAM(uintptr(unsafe.Pointer(nm)))); r != 3 || len(nativeMessages) != 0 {
+		t.Fatal(r, nativeMessages)
+	}
+	nm.Code = win32.NM_DBLCLK
+	if r, _ := win32.SendMessageW(hwnd, win32.WM_NOTIFY, 10, win32.LPARAM(uintptr(unsafe.Pointer(nm)))); r != 5 {
+		t.Fatal(r)
+	}
+	draw := fake.Alloc[win32.DRAWITEMSTRUCT]()
+	defer fake.Free(draw)
+	*draw = win32.DRAWITEMSTRUCT{CtlID: 10, HwndItem: child}
+	if r, _ := win32.SendMessageW(hwnd, win32.WM_DRAWITEM, 10, win32.LPARAM(uintptr(unsafe.Pointer(draw)))); r != 5 {
+		t.Fatal(r)
+	}
+	measure := fake.Alloc[win32.MEASUREITEMSTRUCT]()
+	defer fake.Free(measure)
+	*measure = win32.MEASUREITEMSTRUCT{CtlID: 10}
+	if r, _ := win32.SendMessageW(hwnd, win32.WM_MEASUREITEM, 10, win32.LPARAM(uintptr(unsafe.Pointer(measure)))); r != 5 {
+		t.Fatal(r)
+	}
+	if r, _ := win32.SendMessageW(hwnd, win32.WM_CTLCOLOREDIT, 0, win32.LPARAM(child)); r != 5 {
+		t.Fatal(r)
+	}
+	if want := []win32.UINT{win32.WM_NOTIFY, win32.WM_DRAWITEM, win32.WM_MEASUREITEM, win32.WM_CTLCOLOREDIT}; !slices.Equal(nativeMessages, want) {
+		t.Fatal(nativeMessages)
+	}
+}
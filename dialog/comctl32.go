@@ -4,6 +4,7 @@ import (
 	"unsafe"
 
 	"github.com/mkch/gw/internal/objectmap"
+	"github.com/mkch/gw/internal/uithread"
 	"github.com/mkch/gw/paint/font"
 	"github.com/mkch/gw/win32"
 	"golang.org/x/sys/windows"
@@ -46,14 +47,26 @@ type chooseFontCustomData struct {
 	onApply func(*FontChosen)
 }
 
-var chooseFontCustomDataMap = objectmap.New[*chooseFontCustomData](0, 0xFF)
+var chooseFontCustomDataMaps = uithread.NewLocal(func() *objectmap.ObjectMap[*chooseFontCustomData] {
+	return objectmap.New[*chooseFontCustomData](0, 0xFF)
+}, nil)
+
+// chooseFontCustomDataMap returns the custom data of the font dialogs of the calling thread.
+func chooseFontCustomDataMap() *objectmap.ObjectMap[*chooseFontCustomData] {
+	return chooseFontCustomDataMaps.Get()
+}
 
 type chooseFontData struct {
 	*chooseFontCustomData
 	*win32.CHOOSEFONTW
 }
 
-var chooseFontHwndMap = make(map[win32.HWND]*chooseFontData)
+var chooseFontHwndMaps = uithread.NewLocal(func() map[win32.HWND]*chooseFontData { return make(map[win32.HWND]*chooseFontData) }, nil)
+
+// chooseFontHwndMap returns the font dialogs of the calling thread.
+func chooseFontHwndMap() map[win32.HWND]*chooseFontData {
+	return chooseFontHwndMaps.Get()
+}
 
 const WM_CHOOSEFONT_GETLOGFONT = (win32.WM_USER + 1)
 
@@ -62,15 +75,15 @@ var hookProc = windows.NewCallback(
 		switch message {
 		case win32.WM_INITDIALOG:
 			cf := (*win32.CHOOSEFONTW)(unsafe.Pointer(uintptr(unsafe.Pointer(nil)) + uintptr(lParam)))
-			if customData, ok := chooseFontCustomDataMap.Value(objectmap.Handle(cf.CustomData)); ok {
-				chooseFontHwndMap[hwnd] = &chooseFontData{chooseFontCustomData: customData, CHOOSEFONTW: cf}
+			if customData, ok := chooseFontCustomDataMap().Value(objectmap.Handle(cf.CustomData)); ok {
+				chooseFontHwndMap()[hwnd] = &chooseFontData{chooseFontCustomData: customData, CHOOSEFONTW: cf}
 			}
 		case win32.WM_NCDESTROY:
-			delete(chooseFontHwndMap, hwnd)
+			delete(chooseFontHwndMap(), hwnd)
 		case win32.WM_COMMAND:
 			id := win32.LOWORD(wParam)
 			if id == 1026 { // What is the const name for 1026??
-				data := chooseFontHwndMap[hwnd]
+				data := chooseFontHwndMap()[hwnd]
 				cf := *data.CHOOSEFONTW
 				cf.LogFont = &win32.LOGFONTW{}
 				win32.SendMessageW(hwnd, WM_CHOOSEFONT_GETLOGFONT, 0, win32.LPARAM(uintptr(unsafe.Pointer(cf.LogFont))))
@@ -84,6 +97,7 @@ var hookProc = windows.NewCallback(
 // If the user cancels or closes the Font dialog box, it returns nil, nil.
 // Nil spec means default setting.
 func ChooseFont(spec *ChooseFontSpec) (*FontChosen, error) {
+	uithread.Check()
 	// ChooseFont does not work well under PER_MONITOR_AWARE or PER_MONITOR_AWARE_V2.
 	if oldDpiCtx, err := win32.SetThreadDpiAwarenessContext(win32.DPI_AWARENESS_CONTEXT_SYSTEM_AWARE); err != nil {
 		return nil, err
@@ -118,8 +132,8 @@ func ChooseFont(spec *ChooseFontSpec) (*FontChosen, error) {
 	if spec.OnApply != nil {
 		cf.Flags |= (win32.CF_APPLY | win32.CF_ENABLEHOOK)
 		cf.Hook = hookProc
-		h := chooseFontCustomDataMap.Add(&chooseFontCustomData{dpi: dpi, onApply: spec.OnApply})
-		defer chooseFontCustomDataMap.Remove(h)
+		h := chooseFontCustomDataMap().Add(&chooseFontCustomData{dpi: dpi, onApply: spec.OnApply})
+		defer chooseFontCustomDataMap().Remove(h)
 		cf.CustomData = win32.LPARAM(h)
 	}
 
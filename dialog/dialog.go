@@ -8,6 +8,7 @@ import (
 	"github.com/mkch/gw/button"
 	"github.com/mkch/gw/internal"
 	"github.com/mkch/gw/internal/objectmap"
+	"github.com/mkch/gw/internal/uithread"
 	"github.com/mkch/gw/menu"
 	"github.com/mkch/gw/win32"
 	"github.com/mkch/gw/win32/win32util"
@@ -16,13 +17,28 @@ import (
 )
 
 // HWND -> Dialog
-var dialogMap = make(map[win32.HWND]*Dialog)
+var dialogMaps = uithread.NewLocal(func() map[win32.HWND]*Dialog { return make(map[win32.HWND]*Dialog) }, nil)
+
+// dialogMap returns the dialogs of the calling thread.
+func dialogMap() map[win32.HWND]*Dialog {
+	return dialogMaps.Get()
+}
 
 // Dialog return code -> return value
-var retMap = objectmap.New[any](1, math.MaxUint)
+var retMaps = uithread.NewLocal(func() *objectmap.ObjectMap[any] { return objectmap.New[any](1, math.MaxUint) }, nil)
+
+// retMap returns the return values of the calling thread.
+func retMap() *objectmap.ObjectMap[any] {
+	return retMaps.Get()
+}
 
 // LPARAM of WM_INITDIALOG
-var dialogParamMap = objectmap.New[*Dialog](1, math.MaxUint)
+var dialogParamMaps = uithread.NewLocal(func() *objectmap.ObjectMap[*Dialog] { return objectmap.New[*Dialog](1, math.MaxUint) }, nil)
+
+// dialogParamMap returns the dialogs being created of the calling thread.
+func dialogParamMap() *objectmap.ObjectMap[*Dialog] {
+	return dialogParamMaps.Get()
+}
 
 type Dialog struct {
 	window.Window
@@ -54,21 +70,21 @@ var dlgProc = windows.NewCallback(func(hwnd win32.HWND, msg win32.UINT, wParam w
 	switch msg {
 	case win32.WM_INITDIALOG:
 		// Find the *Dialog set in lParam
-		dialog, _ := dialogParamMap.Value(objectmap.Handle(lParam))
+		dialog, _ := dialogParamMap().Value(objectmap.Handle(lParam))
 		if err := window.Attach(hwnd, &dialog.WindowBase); err != nil {
 			panic(err)
 		}
 		// Put dialog in dialogMap for following messages to retrieve.
-		dialogMap[hwnd] = dialog
+		dialogMap()[hwnd] = dialog
 		dialog.SetText(dialog.initSpec.Text)
 		dialog.SetMenu(dialog.initSpec.Menu)
 		return dialog.callDlgProc(hwnd, msg, wParam, lParam)
 	case win32.WM_NCDESTROY:
-		r := dialogMap[hwnd].callDlgProc(hwnd, msg, wParam, lParam)
-		delete(dialogMap, hwnd)
+		r := dialogMap()[hwnd].callDlgProc(hwnd, msg, wParam, lParam)
+		delete(dialogMap(), hwnd)
 		return r
 	default:
-		return dialogMap[hwnd].callDlgProc(hwnd, msg, wParam, lParam)
+		return dialogMap()[hwnd].callDlgProc(hwnd, msg, wParam, lParam)
 	}
 })
 
@@ -80,6 +96,7 @@ func (d *Dialog) callDlgProc(hwnd win32.HWND, msg win32.UINT, wParam win32.WPARA
 }
 
 func (d *Dialog) SetDlgProc(dlgProc DlgProc) {
+	uithread.CheckWindow(d.HWND())
 	if dlgProc == nil {
 		panic(errors.New("nil DlgProc"))
 	}
@@ -92,11 +109,13 @@ func (d *Dialog) SetDlgProc(dlgProc DlgProc) {
 
 // End ends the dialog and set the result value.
 func (d *Dialog) End(result any) error {
-	return win32.EndDialog(d.HWND(), win32.INT_PTR(retMap.Add(result)))
+	uithread.CheckWindow(d.HWND())
+	return win32.EndDialog(d.HWND(), win32.INT_PTR(retMap().Add(result)))
 }
 
 // Reposition repositions a top-level dialog box so that it fits within the desktop area.
 func (d *Dialog) Reposition() error {
+	uithread.CheckWindow(d.HWND())
 	_, err := win32.SendMessageW(d.HWND(), win32.DM_REPOSITION, 0, 0)
 	return err
 }
@@ -110,6 +129,7 @@ var ErrNotChild = errors.New("no a child")
 // SetDefault sets the default button of this dialog.
 // Panic if btn is not a child of d.
 func (d *Dialog) SetDefault(btn *button.Button) error {
+	uithread.CheckWindow(d.HWND())
 	if parent, err := win32.GetAncestor(btn.HWND(), win32.GA_PARENT); err != nil {
 		return err
 	} else if parent != d.HWND() {
@@ -173,18 +193,21 @@ func (d *Dialog) setButtonID(btn *button.Button, id int) error {
 // SetOK sets the OK button of this dialog.
 // Panic if btn is not a child of d.
 func (d *Dialog) SetOK(btn *button.Button) error {
+	uithread.CheckWindow(d.HWND())
 	return d.setButtonID(btn, win32.IDOK)
 }
 
 // SetCancel sets the Cancel button of this dialog.
 // Panic if btn is not a child of d.
 func (d *Dialog) SetCancel(btn *button.Button) error {
+	uithread.CheckWindow(d.HWND())
 	return d.setButtonID(btn, win32.IDCANCEL)
 }
 
 // Modal shows a modal dialog box.
 // The ret is the return value set by Dialog.End(), nil if Dialog.End() is not called.
 func Modal(spec *Spec) (ret any, err error) {
+	uithread.Check()
 	tpl := win32util.EmptyDialogTemplate(spec.Style, spec.ExStyle, win32.SHORT(spec.X), win32.SHORT(spec.Y), win32.SHORT(spec.Width), win32.SHORT(spec.Height))
 	instance := spec.Instance
 	if instance == 0 {
@@ -221,13 +244,13 @@ func Modal(spec *Spec) (ret any, err error) {
 	dialog.dlgProc = func(hwnd win32.HWND, msg win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM, prevDlgProc Proc) bool {
 		return prevDlgProc(hwnd, msg, wParam, lParam)
 	}
-	param := dialogParamMap.Add(dialog)
-	defer dialogParamMap.Remove(param)
+	param := dialogParamMap().Add(dialog)
+	defer dialogParamMap().Remove(param)
 	r, err := win32.DialogBoxIndirectParamW(instance, tpl, spec.WndParent, dlgProc, win32.LPARAM(param))
 	if err != nil {
 		return nil, err
 	}
-	ret, _ = retMap.Value(objectmap.Handle(r))
-	retMap.Remove(objectmap.Handle(r))
+	ret, _ = retMap().Value(objectmap.Handle(r))
+	retMap().Remove(objectmap.Handle(r))
 	return
 }
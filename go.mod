@@ -6,4 +6,5 @@ require (
 	github.com/mkch/gg v0.0.0-20240126094904-8d17c81281f6
 	golang.org/x/exp v0.0.0-20231206192017-f3f8817b8deb
 	golang.org/x/sys v0.15.0
+	gopkg.in/yaml.v3 v3.0.1
 )
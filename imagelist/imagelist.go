@@ -0,0 +1,58 @@
+package imagelist
+
+import (
+	"github.com/mkch/gw/util/ref"
+	"github.com/mkch/gw/win32"
+)
+
+// ImageList holds a win32.HIMAGELIST, which is destroyed when all the clones
+// are released.
+type ImageList struct {
+	ref *ref.Ref[win32.HIMAGELIST]
+}
+
+// New creates an empty image list of images cx*cy pixels in size.
+// flags is a combination of win32.ILC_* values. Use win32.ILC_COLOR32|win32.ILC_MASK
+// for icons with alpha channel.
+func New(cx, cy int, flags win32.UINT) (*ImageList, error) {
+	h, err := win32.ImageList_Create(win32.INT(cx), win32.INT(cy), flags, 0, 4)
+	if err != nil {
+		return nil, err
+	}
+	return &ImageList{
+		ref: ref.New(h, func(h win32.HIMAGELIST) { win32.ImageList_Destroy(h) }),
+	}, nil
+}
+
+func (l *ImageList) HIMAGELIST() win32.HIMAGELIST {
+	return l.ref.MustData()
+}
+
+func (l *ImageList) Clone() *ImageList {
+	return &ImageList{
+		ref: l.ref.AddRef(),
+	}
+}
+
+func (l *ImageList) Release() {
+	l.ref.Release()
+	l.ref = nil
+}
+
+// AddIcon appends a copy of icon to the image list, and returns the index of the image.
+// The icon can be destroyed after AddIcon returns.
+func (l *ImageList) AddIcon(icon win32.HICON) (int, error) {
+	i, err := win32.ImageList_ReplaceIcon(l.HIMAGELIST(), -1, icon)
+	return int(i), err
+}
+
+// SetIcon replaces the image at index i with a copy of icon.
+func (l *ImageList) SetIcon(i int, icon win32.HICON) error {
+	_, err := win32.ImageList_ReplaceIcon(l.HIMAGELIST(), win32.INT(i), icon)
+	return err
+}
+
+// Len returns the number of images in the image list.
+func (l *ImageList) Len() int {
+	return int(win32.ImageList_GetImageCount(l.HIMAGELIST()))
+}
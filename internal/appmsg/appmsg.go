@@ -7,4 +7,18 @@ const (
 	POST
 	NOTIFY_ICON_CALLBACK
 	REFLECT_CTLCOLORSTATIC
+	REFLECT_NOTIFY
+	REFLECT_DRAWITEM
+	REFLECT_MEASUREITEM
+	REFLECT_CTLCOLOREDIT
+	REFLECT_CTLCOLORBTN
+	REFLECT_CTLCOLORLISTBOX
 )
+
+// ReflectCtlColor maps WM_CTLCOLOR* messages to the reflected messages.
+var ReflectCtlColor = map[win32.UINT]win32.UINT{
+	win32.WM_CTLCOLORSTATIC:  REFLECT_CTLCOLORSTATIC,
+	win32.WM_CTLCOLOREDIT:    REFLECT_CTLCOLOREDIT,
+	win32.WM_CTLCOLORBTN:     REFLECT_CTLCOLORBTN,
+	win32.WM_CTLCOLORLISTBOX: REFLECT_CTLCOLORLISTBOX,
+}
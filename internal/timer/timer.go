@@ -0,0 +1,112 @@
+// Package timer implements the timers of windows and threads.
+package timer
+
+import (
+	"math"
+	"time"
+
+	"github.com/mkch/gw/win32"
+)
+
+// NoCoalescing as the tolerance disables timer coalescing.
+const NoCoalescing time.Duration = -1
+
+// firstID is the first ID of window timers. Window timers start from a
+// large ID to avoid the IDs used by native window procedures.
+const firstID = 0x6777_0000
+
+type timer struct {
+	f       func()
+	oneShot bool
+}
+
+// Map is the timers of a window, or of the calling thread if the window is 0.
+type Map struct {
+	hwnd   win32.HWND
+	thread win32.DWORD // The thread owning the timers.
+	proc   uintptr     // TIMERPROC of the timers.
+	timers map[win32.UINT_PTR]*timer
+	lastID win32.UINT_PTR
+}
+
+// New returns the Map of the timers of hwnd.
+func New(hwnd win32.HWND) *Map {
+	return &Map{hwnd: hwnd, thread: win32.GetCurrentThreadId(), timers: make(map[win32.UINT_PTR]*timer), lastID: firstID - 1}
+}
+
+// NewThread returns the Map of the timers of the calling thread. The WM_TIMER of
+// the timers is dispatched to proc, a TIMERPROC, by DispatchMessageW of any
+// message loop, including the modal ones which discard the thread messages.
+func NewThread(proc uintptr) *Map {
+	return &Map{thread: win32.GetCurrentThreadId(), proc: proc, timers: make(map[win32.UINT_PTR]*timer)}
+}
+
+// Thread returns the thread owning the timers, the one calling New or NewThread.
+func (m *Map) Thread() win32.DWORD {
+	return m.thread
+}
+
+// milliseconds converts d to timer milliseconds.
+func milliseconds(d time.Duration) win32.UINT {
+	return win32.UINT(min(max(d.Milliseconds(), 0), math.MaxInt32))
+}
+
+// Set creates a timer that calls f after interval, once if oneShot or
+// repeatedly otherwise. If tolerance is not 0, it is the coalescing
+// tolerance, see NoCoalescing. The ID of the timer is returned.
+func (m *Map) Set(interval time.Duration, oneShot bool, tolerance time.Duration, f func()) (id win32.UINT_PTR, err error) {
+	if m.hwnd != 0 {
+		m.lastID++
+		id = m.lastID
+	}
+	elapse := milliseconds(interval)
+	switch tolerance {
+	case 0:
+		id, err = win32.SetTimer(m.hwnd, id, elapse, m.proc)
+	case NoCoalescing:
+		id, err = win32.SetCoalescableTimer(m.hwnd, id, elapse, m.proc, win32.TIMERV_NO_COALESCING)
+	default:
+		id, err = win32.SetCoalescableTimer(m.hwnd, id, elapse, m.proc, win32.ULONG(max(milliseconds(tolerance), 1)))
+	}
+	if err != nil {
+		return
+	}
+	m.timers[id] = &timer{f: f, oneShot: oneShot}
+	return
+}
+
+// Kill destroys the timer id.
+func (m *Map) Kill(id win32.UINT_PTR) error {
+	if _, ok := m.timers[id]; !ok {
+		return nil
+	}
+	delete(m.timers, id)
+	return win32.KillTimer(m.hwnd, id)
+}
+
+// KillAll destroys all the timers.
+func (m *Map) KillAll() {
+	for id := range m.timers {
+		win32.KillTimer(m.hwnd, id)
+	}
+	clear(m.timers)
+}
+
+// Fire runs the timer of the WM_TIMER id and reports whether the timer is in m.
+// One-shot timers are destroyed before running.
+func (m *Map) Fire(id win32.UINT_PTR) bool {
+	t := m.timers[id]
+	if t == nil {
+		return false
+	}
+	if t.oneShot {
+		m.Kill(id)
+	}
+	t.f()
+	return true
+}
+
+// Len returns the number of timers.
+func (m *Map) Len() int {
+	return len(m.timers)
+}
@@ -0,0 +1,101 @@
+package uithread
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mkch/gw/win32"
+	"github.com/mkch/gw/win32/fake"
+	"github.com/mkch/gw/win32/win32util"
+)
+
+// TestOwner tests the checks, which run regardless of the gwdebug build tag.
+func TestOwner(t *testing.T) {
+	win32.SetBackend(fake.New())
+	mu.Lock()
+	registered := threads
+	threads = nil
+	mu.Unlock()
+	t.Cleanup(func() {
+		mu.Lock()
+		threads = registered
+		mu.Unlock()
+	})
+	id := win32.GetCurrentThreadId()
+	Register(id)
+	hwnd, err := win32util.CreateWindow(&win32util.Wnd{ClassName: "EDIT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer win32.DestroyWindow(hwnd)
+	if owner := windowThread(hwnd); owner != id {
+		t.Fatal(owner, id)
+	}
+	if owner := windowThread(0); owner != 0 {
+		t.Fatal(owner)
+	}
+	if _, reason := check(0); reason != "" {
+		t.Fatal(reason)
+	}
+	if _, reason := check(id); reason != "" {
+		t.Fatal(reason)
+	}
+
+	// Another UI thread can't use the objects of id.
+	done := make(chan string)
+	go func() {
+		Register(win32.GetCurrentThreadId())
+		_, any := check(0)
+		_, owned := check(id)
+		done <- any + ";" + owned
+	}()
+	if r := <-done; !strings.HasPrefix(r, ";") || !strings.Contains(r, "not the UI thread") {
+		t.Fatal(r)
+	}
+	// Neither can a thread which is not a UI thread.
+	go func() {
+		_, reason := check(0)
+		done <- reason
+	}()
+	if r := <-done; !strings.Contains(r, "not a UI thread") {
+		t.Fatal(r)
+	}
+}
+
+func TestUnregister(t *testing.T) {
+	win32.SetBackend(fake.New())
+	var freed []*int
+	l := NewLocal(func() *int { return new(int) }, func(v *int) { freed = append(freed, v) })
+	done := make(chan *int)
+	var id win32.DWORD
+	go func() {
+		id = win32.GetCurrentThreadId()
+		Register(id)
+		done <- l.Get()
+	}()
+	v := <-done
+	Unregister(id)
+	if len(freed) != 1 || freed[0] != v {
+		t.Fatal(freed)
+	}
+	mu.RLock()
+	registered := slices.Contains(threads, id)
+	mu.RUnlock()
+	if registered {
+		t.Fatal(id)
+	}
+	// The threads not registered share a value.
+	go func() { done <- l.Get() }()
+	shared := <-done
+	go func() { done <- l.Get() }()
+	if v := <-done; v != shared || v == freed[0] {
+		t.Fatal(v, shared)
+	}
+	l.mu.Lock()
+	n := len(l.values)
+	l.mu.Unlock()
+	if n != 0 {
+		t.Fatal(n)
+	}
+}
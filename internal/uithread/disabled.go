@@ -0,0 +1,5 @@
+//go:build !gwdebug
+
+package uithread
+
+const enabled = false
@@ -0,0 +1,172 @@
+// Package uithread implements the states local to UI threads, and checks that
+// the UI objects are used in the UI threads owning them.
+//
+// The check is enabled by the gwdebug build tag, for example
+// go build -tags gwdebug. Otherwise Check does nothing.
+package uithread
+
+import (
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/mkch/gw/win32"
+)
+
+var (
+	mu      sync.RWMutex
+	threads []win32.DWORD // The UI threads.
+	locals  []local       // All the Locals.
+)
+
+// Register records id as a UI thread.
+func Register(id win32.DWORD) {
+	mu.Lock()
+	defer mu.Unlock()
+	if !slices.Contains(threads, id) {
+		threads = append(threads, id)
+	}
+}
+
+// Unregister removes the UI thread id, and drops the values of all the Locals
+// of it. It is called when the message loop of the thread exits, the ID may be
+// reused by a new thread afterwards.
+func Unregister(id win32.DWORD) {
+	mu.Lock()
+	threads = slices.DeleteFunc(threads, func(thread win32.DWORD) bool { return thread == id })
+	ls := slices.Clone(locals)
+	mu.Unlock()
+	for _, l := range ls {
+		l.drop(id)
+	}
+}
+
+// Check panics if the calling goroutine does not run in a UI thread, when
+// the check is enabled and any UI thread is registered. The functions using
+// an object check the thread owning it with CheckThread or CheckWindow instead.
+func Check() {
+	if enabled {
+		checkThread(0)
+	}
+}
+
+// CheckThread panics if the calling goroutine does not run in owner, the UI
+// thread owning the object used, when the check is enabled.
+func CheckThread(owner win32.DWORD) {
+	if enabled {
+		checkThread(owner)
+	}
+}
+
+// CheckWindow panics if the calling goroutine does not run in the thread
+// which created hwnd, when the check is enabled. The windows not created yet,
+// or destroyed, are checked as Check does.
+func CheckWindow(hwnd win32.HWND) {
+	if enabled {
+		checkThread(windowThread(hwnd))
+	}
+}
+
+// windowThread returns the thread which created hwnd, or 0 if hwnd is not a window.
+func windowThread(hwnd win32.HWND) win32.DWORD {
+	if hwnd == 0 {
+		return 0
+	}
+	id, _ := win32.GetWindowThreadProcessId(hwnd, nil)
+	return id
+}
+
+func checkThread(owner win32.DWORD) {
+	if id, reason := check(owner); reason != "" {
+		panic(fmt.Sprintf("gw: %v called from thread %v, %v", caller(), id, reason))
+	}
+}
+
+// check returns the calling thread, and the reason why it can't use the objects
+// of owner, or "" if it can. Owner 0 is any UI thread.
+func check(owner win32.DWORD) (id win32.DWORD, reason string) {
+	id = win32.GetCurrentThreadId()
+	if owner != 0 {
+		if id != owner {
+			reason = fmt.Sprintf("which is not the UI thread %v owning the object", owner)
+		}
+		return
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	if len(threads) != 0 && !slices.Contains(threads, id) {
+		reason = fmt.Sprintf("which is not a UI thread %v", threads)
+	}
+	return
+}
+
+// caller returns the name of the function calling Check.
+func caller() string {
+	pc, _, _, ok := runtime.Caller(3)
+	if !ok {
+		return "function"
+	}
+	name := runtime.FuncForPC(pc).Name()
+	return strings.TrimPrefix(name, "github.com/mkch/gw/")
+}
+
+type local interface {
+	drop(id win32.DWORD)
+}
+
+// Local is a variable local to each UI thread.
+type Local[T any] struct {
+	new    func() T
+	free   func(T)
+	mu     sync.Mutex
+	values map[win32.DWORD]T
+	shared *T // The value of the threads not registered.
+}
+
+// NewLocal returns a Local whose value of each UI thread is created by new on first use.
+// The value is passed to free, if not nil, when the thread is unregistered.
+// The threads not registered share one value, which is never freed.
+func NewLocal[T any](new func() T, free func(T)) *Local[T] {
+	l := &Local[T]{new: new, free: free, values: make(map[win32.DWORD]T)}
+	mu.Lock()
+	defer mu.Unlock()
+	locals = append(locals, l)
+	return l
+}
+
+// Get returns the value of the calling thread.
+func (l *Local[T]) Get() T {
+	id := win32.GetCurrentThreadId()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if v, ok := l.values[id]; ok {
+		return v
+	}
+	mu.RLock()
+	registered := slices.Contains(threads, id)
+	mu.RUnlock()
+	if !registered {
+		// Not kept per thread, because nothing drops it.
+		if l.shared == nil {
+			v := l.new()
+			l.shared = &v
+		}
+		return *l.shared
+	}
+	v := l.new()
+	l.values[id] = v
+	return v
+}
+
+// drop removes the value of thread id.
+func (l *Local[T]) drop(id win32.DWORD) {
+	l.mu.Lock()
+	v, ok := l.values[id]
+	delete(l.values, id)
+	l.mu.Unlock()
+	if ok && l.free != nil {
+		l.free(v)
+	}
+}
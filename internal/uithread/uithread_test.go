@@ -0,0 +1,40 @@
+//go:build gwdebug
+
+package uithread_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mkch/gw/internal/uithread"
+	"github.com/mkch/gw/win32"
+	"github.com/mkch/gw/win32/fake"
+)
+
+func TestCheck(t *testing.T) {
+	win32.SetBackend(fake.New())
+	uithread.Check() // No UI thread registered.
+	uithread.Register(win32.GetCurrentThreadId())
+	uithread.Check()
+
+	recovered := make(chan any)
+	go func() {
+		defer func() { recovered <- recover() }()
+		uithread.Check()
+	}()
+	if r, ok := (<-recovered).(string); !ok || !strings.Contains(r, "internal/uithread_test.TestCheck.func1 called from thread") {
+		t.Fatal(r)
+	}
+
+	// Another UI thread.
+	owner := win32.GetCurrentThreadId()
+	go func() {
+		defer func() { recovered <- recover() }()
+		uithread.Register(win32.GetCurrentThreadId())
+		uithread.Check()
+		uithread.CheckThread(owner)
+	}()
+	if r, ok := (<-recovered).(string); !ok || !strings.Contains(r, "not the UI thread") {
+		t.Fatal(r)
+	}
+}
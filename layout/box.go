@@ -0,0 +1,108 @@
+package layout
+
+import (
+	"github.com/mkch/gw/metrics"
+	"github.com/mkch/gw/win32"
+)
+
+// Orientation is the direction in which a Box stacks its items.
+type Orientation uint8
+
+const (
+	Horizontal Orientation = iota
+	Vertical
+)
+
+// Box is a layout that stacks its items in a row or a column.
+// Extra space is distributed to the items in proportion to their stretch factors.
+// If there is not enough space, items shrink in proportion to the room above their minimum size.
+type Box struct {
+	Orientation Orientation
+	// Padding is the space between the bounds of the box and the items.
+	Padding Spacing
+	// Spacing is the space between adjacent items.
+	Spacing metrics.Dimension
+	Items   []*Item
+}
+
+// HBox returns a horizontal Box of items.
+func HBox(items ...*Item) *Box {
+	return &Box{Orientation: Horizontal, Items: items}
+}
+
+// VBox returns a vertical Box of items.
+func VBox(items ...*Item) *Box {
+	return &Box{Orientation: Vertical, Items: items}
+}
+
+// Add adds item to the end of the box and returns it.
+func (b *Box) Add(item *Item) *Item {
+	b.Items = append(b.Items, item)
+	return item
+}
+
+// main returns the size along the main axis and the cross axis.
+func (b *Box) main(size Size) (main, cross win32.INT) {
+	if b.Orientation == Vertical {
+		return size.Height, size.Width
+	}
+	return size.Width, size.Height
+}
+
+// spacing returns the total space between items.
+func (b *Box) spacing(dpi win32.UINT) win32.INT {
+	if len(b.Items) == 0 {
+		return 0
+	}
+	return b.Spacing.Px(dpi) * win32.INT(len(b.Items)-1)
+}
+
+// Measure implements Element.
+func (b *Box) Measure(dpi win32.UINT) Size {
+	var main, cross win32.INT
+	for _, item := range b.Items {
+		m, c := b.main(item.outerSize(dpi))
+		main += m
+		cross = max(cross, c)
+	}
+	padMain, padCross := b.main(b.Padding.size(dpi))
+	main += b.spacing(dpi) + padMain
+	cross += padCross
+	if b.Orientation == Vertical {
+		return Size{cross, main}
+	}
+	return Size{main, cross}
+}
+
+// Arrange implements Element.
+func (b *Box) Arrange(bounds Rect, dpi win32.UINT) {
+	content := b.Padding.inset(bounds, dpi)
+	avail, _ := b.main(Size{content.Width, content.Height})
+	avail -= b.spacing(dpi)
+
+	n := len(b.Items)
+	sizes, mins, maxs := make([]win32.INT, n), make([]win32.INT, n), make([]win32.INT, n)
+	weights := make([]int, n)
+	for i, item := range b.Items {
+		sizes[i], _ = b.main(item.outerSize(dpi))
+		lo, hi := item.outerLimits(dpi)
+		mins[i], _ = b.main(lo)
+		maxs[i], _ = b.main(hi)
+		weights[i] = item.Stretch
+	}
+	fit(sizes, avail, weights, mins, maxs)
+
+	spacing := b.Spacing.Px(dpi)
+	pos := content.X
+	if b.Orientation == Vertical {
+		pos = content.Y
+	}
+	for i, item := range b.Items {
+		slot := Rect{pos, content.Y, sizes[i], content.Height}
+		if b.Orientation == Vertical {
+			slot = Rect{content.X, pos, content.Width, sizes[i]}
+		}
+		item.arrange(slot, dpi)
+		pos += sizes[i] + spacing
+	}
+}
@@ -0,0 +1,89 @@
+package layout
+
+import "github.com/mkch/gw/win32"
+
+// Side is the side of a Dock an item is docked to.
+type Side uint8
+
+const (
+	DockLeft Side = iota
+	DockTop
+	DockRight
+	DockBottom
+	// DockFill fills the remaining space.
+	DockFill
+)
+
+// Docked is an item of a Dock.
+type Docked struct {
+	Item
+	Side Side
+}
+
+// Dock is a layout that docks its items to the sides of the remaining space in order.
+// Items docked to the left or right take their preferred width and the full remaining height,
+// items docked to the top or bottom take their preferred height and the full remaining width.
+// An item of DockFill, usually the last one, takes all the remaining space.
+type Dock struct {
+	// Padding is the space between the bounds of the dock and the items.
+	Padding Spacing
+	Items   []*Docked
+}
+
+// Add adds item docked to side and returns it.
+func (d *Dock) Add(side Side, item *Item) *Docked {
+	docked := &Docked{Item: *item, Side: side}
+	d.Items = append(d.Items, docked)
+	return docked
+}
+
+// Measure implements Element.
+func (d *Dock) Measure(dpi win32.UINT) Size {
+	var size Size
+	// From the innermost item out.
+	for i := len(d.Items) - 1; i >= 0; i-- {
+		item := d.Items[i]
+		outer := item.outerSize(dpi)
+		switch item.Side {
+		case DockLeft, DockRight:
+			size.Width += outer.Width
+			size.Height = max(size.Height, outer.Height)
+		case DockTop, DockBottom:
+			size.Width = max(size.Width, outer.Width)
+			size.Height += outer.Height
+		default:
+			size.Width = max(size.Width, outer.Width)
+			size.Height = max(size.Height, outer.Height)
+		}
+	}
+	padding := d.Padding.size(dpi)
+	return Size{size.Width + padding.Width, size.Height + padding.Height}
+}
+
+// Arrange implements Element.
+func (d *Dock) Arrange(bounds Rect, dpi win32.UINT) {
+	rest := d.Padding.inset(bounds, dpi)
+	for _, item := range d.Items {
+		outer := item.outerSize(dpi)
+		slot := rest
+		switch item.Side {
+		case DockLeft:
+			slot.Width = min(outer.Width, rest.Width)
+			rest.X += slot.Width
+			rest.Width -= slot.Width
+		case DockRight:
+			slot.Width = min(outer.Width, rest.Width)
+			slot.X = rest.X + rest.Width - slot.Width
+			rest.Width -= slot.Width
+		case DockTop:
+			slot.Height = min(outer.Height, rest.Height)
+			rest.Y += slot.Height
+			rest.Height -= slot.Height
+		case DockBottom:
+			slot.Height = min(outer.Height, rest.Height)
+			slot.Y = rest.Y + rest.Height - slot.Height
+			rest.Height -= slot.Height
+		}
+		item.arrange(slot, dpi)
+	}
+}
@@ -0,0 +1,32 @@
+package layout_test
+
+import (
+	"testing"
+
+	"github.com/mkch/gw/layout"
+	"github.com/mkch/gw/metrics"
+)
+
+func TestDock(t *testing.T) {
+	toolbar, status, tree, content := fake(100, 20), fake(50, 15), fake(40, 100), fake(200, 150)
+	dock := &layout.Dock{Padding: layout.Uniform(metrics.Dip(1))}
+	dock.Add(layout.DockTop, &layout.Item{Element: toolbar})
+	dock.Add(layout.DockBottom, &layout.Item{Element: status})
+	dock.Add(layout.DockLeft, &layout.Item{Element: tree, Margin: layout.Spacing{Right: metrics.Dip(2)}})
+	dock.Add(layout.DockFill, &layout.Item{Element: content})
+
+	if got, want := dock.Measure(96), (layout.Size{Width: 244, Height: 187}); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	dock.Arrange(layout.Rect{X: 0, Y: 0, Width: 302, Height: 202}, 96)
+	checkBounds(t, "toolbar", toolbar, layout.Rect{X: 1, Y: 1, Width: 300, Height: 20})
+	checkBounds(t, "status", status, layout.Rect{X: 1, Y: 186, Width: 300, Height: 15})
+	checkBounds(t, "tree", tree, layout.Rect{X: 1, Y: 21, Width: 40, Height: 165})
+	checkBounds(t, "content", content, layout.Rect{X: 43, Y: 21, Width: 258, Height: 165})
+
+	// Not enough space for all.
+	dock.Arrange(layout.Rect{X: 0, Y: 0, Width: 30, Height: 30}, 96)
+	checkBounds(t, "small toolbar", toolbar, layout.Rect{X: 1, Y: 1, Width: 28, Height: 20})
+	checkBounds(t, "small status", status, layout.Rect{X: 1, Y: 21, Width: 28, Height: 8})
+	checkBounds(t, "small content", content, layout.Rect{X: 29, Y: 21, Width: 0, Height: 0})
+}
@@ -0,0 +1,162 @@
+package layout
+
+import (
+	"github.com/mkch/gw/metrics"
+	"github.com/mkch/gw/win32"
+)
+
+// Track is the definition of a column or a row of a Grid.
+type Track struct {
+	// Size is the fixed size of the track.
+	// If zero, the track is as large as the largest preferred size of the cells in it.
+	Size metrics.Dimension
+	// Stretch is the stretch factor. Extra space of the grid is distributed
+	// to the tracks in proportion to their stretch factors.
+	Stretch int
+}
+
+// Cell is an item of a Grid.
+// The Stretch of the item is ignored, see Track.Stretch.
+type Cell struct {
+	Item
+	Row, Column int
+	// RowSpan and ColumnSpan are the numbers of rows and columns the cell spans.
+	// Zero is treated as 1.
+	RowSpan, ColumnSpan int
+}
+
+// span returns the start and the number of the tracks of the cell.
+func (c *Cell) span(vertical bool) (start, n int) {
+	if vertical {
+		return c.Row, max(c.RowSpan, 1)
+	}
+	return c.Column, max(c.ColumnSpan, 1)
+}
+
+// Grid is a layout that places its cells in columns and rows.
+// Columns and rows not defined in Columns and Rows are auto sized and not stretched.
+type Grid struct {
+	Columns, Rows []Track
+	// Padding is the space between the bounds of the grid and the cells.
+	Padding Spacing
+	// ColumnSpacing and RowSpacing are the space between adjacent columns and rows.
+	ColumnSpacing, RowSpacing metrics.Dimension
+	Cells                     []*Cell
+}
+
+// Add adds an item at row and column, spanning one row and one column, and returns the cell.
+func (g *Grid) Add(row, column int, item *Item) *Cell {
+	cell := &Cell{Item: *item, Row: row, Column: column}
+	g.Cells = append(g.Cells, cell)
+	return cell
+}
+
+// tracks returns the preferred and min sizes and the stretch factors of the rows(vertical)
+// or the columns, and the spacing between them.
+func (g *Grid) tracks(dpi win32.UINT, vertical bool) (sizes, mins []win32.INT, weights []int, spacing win32.INT) {
+	defs, spacing := g.Columns, g.ColumnSpacing.Px(dpi)
+	if vertical {
+		defs, spacing = g.Rows, g.RowSpacing.Px(dpi)
+	}
+	n := len(defs)
+	for _, cell := range g.Cells {
+		start, span := cell.span(vertical)
+		n = max(n, start+span)
+	}
+	sizes, mins, weights = make([]win32.INT, n), make([]win32.INT, n), make([]int, n)
+	fixed := make([]bool, n)
+	for i, def := range defs {
+		weights[i] = def.Stretch
+		if def.Size.Value != 0 {
+			sizes[i], mins[i], fixed[i] = def.Size.Px(dpi), def.Size.Px(dpi), true
+		}
+	}
+	// main returns the size along the axis.
+	main := func(size Size) win32.INT {
+		if vertical {
+			return size.Height
+		}
+		return size.Width
+	}
+	for _, cell := range g.Cells {
+		if start, span := cell.span(vertical); span == 1 && !fixed[start] {
+			lo, _ := cell.outerLimits(dpi)
+			sizes[start] = max(sizes[start], main(cell.outerSize(dpi)))
+			mins[start] = max(mins[start], main(lo))
+		}
+	}
+	// Cells spanning multiple tracks enlarge the auto sized tracks evenly if necessary.
+	for _, cell := range g.Cells {
+		start, span := cell.span(vertical)
+		if span == 1 {
+			continue
+		}
+		need := main(cell.outerSize(dpi)) - spacing*win32.INT(span-1)
+		autos := make([]int, span)
+		for i := start; i < start+span; i++ {
+			need -= sizes[i]
+			if !fixed[i] {
+				autos[i-start] = 1
+			}
+		}
+		if need > 0 {
+			grow(sizes[start:start+span], need, autos, make([]win32.INT, span))
+		}
+	}
+	return
+}
+
+// Measure implements Element.
+func (g *Grid) Measure(dpi win32.UINT) Size {
+	size := g.Padding.size(dpi)
+	for _, vertical := range []bool{false, true} {
+		sizes, _, _, spacing := g.tracks(dpi, vertical)
+		total := spacing * win32.INT(max(len(sizes)-1, 0))
+		for _, s := range sizes {
+			total += s
+		}
+		if vertical {
+			size.Height += total
+		} else {
+			size.Width += total
+		}
+	}
+	return size
+}
+
+// positions fits the tracks in avail and returns their start positions from pos and sizes.
+func (g *Grid) positions(dpi win32.UINT, vertical bool, pos, avail win32.INT) (starts, sizes []win32.INT, spacing win32.INT) {
+	sizes, mins, weights, spacing := g.tracks(dpi, vertical)
+	fit(sizes, avail-spacing*win32.INT(max(len(sizes)-1, 0)), weights, mins, make([]win32.INT, len(sizes)))
+	starts = make([]win32.INT, len(sizes))
+	for i, s := range sizes {
+		starts[i] = pos
+		pos += s + spacing
+	}
+	return
+}
+
+// Arrange implements Element.
+func (g *Grid) Arrange(bounds Rect, dpi win32.UINT) {
+	content := g.Padding.inset(bounds, dpi)
+	xs, widths, colSpacing := g.positions(dpi, false, content.X, content.Width)
+	ys, heights, rowSpacing := g.positions(dpi, true, content.Y, content.Height)
+	// extent returns the size of span tracks from start.
+	extent := func(sizes []win32.INT, start, span int, spacing win32.INT) win32.INT {
+		total := spacing * win32.INT(span-1)
+		for _, s := range sizes[start : start+span] {
+			total += s
+		}
+		return total
+	}
+	for _, cell := range g.Cells {
+		col, colSpan := cell.span(false)
+		row, rowSpan := cell.span(true)
+		cell.arrange(Rect{
+			X:      xs[col],
+			Y:      ys[row],
+			Width:  extent(widths, col, colSpan, colSpacing),
+			Height: extent(heights, row, rowSpan, rowSpacing),
+		}, dpi)
+	}
+}
@@ -0,0 +1,53 @@
+package layout_test
+
+import (
+	"testing"
+
+	"github.com/mkch/gw/layout"
+	"github.com/mkch/gw/metrics"
+)
+
+func TestGrid(t *testing.T) {
+	label1, label2, edit1, edit2, button := fake(30, 10), fake(50, 10), fake(40, 12), fake(40, 12), fake(60, 20)
+	grid := &layout.Grid{
+		Columns:       []layout.Track{{}, {Stretch: 1}},
+		ColumnSpacing: metrics.Dip(4),
+		RowSpacing:    metrics.Dip(2),
+		Padding:       layout.Uniform(metrics.Dip(10)),
+	}
+	grid.Add(0, 0, &layout.Item{Element: label1, VAlign: layout.Center})
+	grid.Add(0, 1, &layout.Item{Element: edit1})
+	grid.Add(1, 0, &layout.Item{Element: label2, VAlign: layout.Center})
+	grid.Add(1, 1, &layout.Item{Element: edit2})
+	grid.Add(2, 0, &layout.Item{Element: button, HAlign: layout.End}).ColumnSpan = 2
+
+	// Columns: 50, 40. Rows: 12, 12, 20.
+	if got, want := grid.Measure(96), (layout.Size{Width: 114, Height: 68}); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	grid.Arrange(layout.Rect{X: 0, Y: 0, Width: 204, Height: 68}, 96)
+	checkBounds(t, "label1", label1, layout.Rect{X: 10, Y: 11, Width: 50, Height: 10})
+	checkBounds(t, "edit1", edit1, layout.Rect{X: 64, Y: 10, Width: 130, Height: 12})
+	checkBounds(t, "label2", label2, layout.Rect{X: 10, Y: 25, Width: 50, Height: 10})
+	checkBounds(t, "edit2", edit2, layout.Rect{X: 64, Y: 24, Width: 130, Height: 12})
+	checkBounds(t, "button", button, layout.Rect{X: 134, Y: 38, Width: 60, Height: 20})
+}
+
+func TestGridFixedAndSpan(t *testing.T) {
+	a, b, wide := fake(10, 10), fake(10, 10), fake(50, 10)
+	grid := &layout.Grid{
+		Columns: []layout.Track{{Size: metrics.Dip(20)}},
+		Rows:    []layout.Track{{}, {Size: metrics.Dip(30), Stretch: 1}},
+	}
+	grid.Add(0, 0, &layout.Item{Element: a})
+	grid.Add(0, 1, &layout.Item{Element: b})
+	grid.Add(1, 0, &layout.Item{Element: wide}).ColumnSpan = 2
+	// The auto sized column 1 is enlarged for the spanning cell: 20 + 30.
+	if got, want := grid.Measure(192), (layout.Size{Width: 100, Height: 80}); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	grid.Arrange(layout.Rect{X: 0, Y: 0, Width: 100, Height: 100}, 192)
+	checkBounds(t, "a", a, layout.Rect{X: 0, Y: 0, Width: 40, Height: 20})
+	checkBounds(t, "b", b, layout.Rect{X: 40, Y: 0, Width: 60, Height: 20})
+	checkBounds(t, "wide", wide, layout.Rect{X: 0, Y: 20, Width: 100, Height: 80})
+}
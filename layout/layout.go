@@ -0,0 +1,248 @@
+// Package layout implements declarative layouts of child controls.
+//
+// Layouts work in metrics.Dimension units and compute the bounds of their
+// elements in physical pixels for a given DPI. Box, Grid and Dock are layouts,
+// which are elements themselves and can be nested.
+// See WindowBase.SetLayout in package window to apply a layout to a window.
+package layout
+
+import (
+	"github.com/mkch/gw/metrics"
+	"github.com/mkch/gw/win32"
+)
+
+// Size is a size in physical pixels.
+type Size struct {
+	Width, Height win32.INT
+}
+
+// Rect is a rectangle in physical pixels.
+type Rect struct {
+	X, Y, Width, Height win32.INT
+}
+
+// Element is a rectangular element positioned by layouts, a control or a nested layout.
+type Element interface {
+	// Measure returns the preferred size of the element at dpi.
+	Measure(dpi win32.UINT) Size
+	// Arrange places the element in bounds at dpi.
+	// Bounds is relative to the client area of the container window.
+	Arrange(bounds Rect, dpi win32.UINT)
+}
+
+// Spacing is the space around the four sides of a rectangle, such as margin and padding.
+type Spacing struct {
+	Left, Top, Right, Bottom metrics.Dimension
+}
+
+// Uniform returns a Spacing of the same dimension on all sides.
+func Uniform(d metrics.Dimension) Spacing {
+	return Spacing{d, d, d, d}
+}
+
+// size returns the total horizontal and vertical space at dpi.
+func (s *Spacing) size(dpi win32.UINT) Size {
+	return Size{s.Left.Px(dpi) + s.Right.Px(dpi), s.Top.Px(dpi) + s.Bottom.Px(dpi)}
+}
+
+// inset returns r with the spacing removed.
+func (s *Spacing) inset(r Rect, dpi win32.UINT) Rect {
+	left, top := s.Left.Px(dpi), s.Top.Px(dpi)
+	size := s.size(dpi)
+	return Rect{r.X + left, r.Y + top, max(r.Width-size.Width, 0), max(r.Height-size.Height, 0)}
+}
+
+// Alignment is the alignment of an element in the space available to it.
+type Alignment uint8
+
+const (
+	// Fill stretches the element to the available space.
+	Fill Alignment = iota
+	Start
+	Center
+	End
+)
+
+// Item is an element with the layout properties.
+type Item struct {
+	// Element is the element to be laid out.
+	// A nil Element is an empty space(spacer) of the preferred size Width x Height.
+	Element Element
+	// Margin is the space around the element.
+	Margin Spacing
+	// Width and Height override the measured preferred size if not zero.
+	Width, Height metrics.Dimension
+	// MinWidth and MinHeight are the minimum size.
+	MinWidth, MinHeight metrics.Dimension
+	// MaxWidth and MaxHeight are the maximum size. Zero means no limit.
+	MaxWidth, MaxHeight metrics.Dimension
+	// HAlign and VAlign are the alignment of the element in its slot.
+	// The zero value is Fill.
+	HAlign, VAlign Alignment
+	// Stretch is the stretch factor. Extra space along the main axis of a Box
+	// is distributed in proportion to the stretch factors of the items.
+	// Items of zero stretch factor are not stretched.
+	Stretch int
+}
+
+// clamp clamps v to [lo, hi], where zero hi means no limit.
+func clamp(v, lo, hi win32.INT) win32.INT {
+	if hi > 0 && v > hi {
+		v = hi
+	}
+	return max(v, lo)
+}
+
+// limits returns the min and max size of the element, excluding the margin.
+func (item *Item) limits(dpi win32.UINT) (lo, hi Size) {
+	lo = Size{item.MinWidth.Px(dpi), item.MinHeight.Px(dpi)}
+	hi = Size{item.MaxWidth.Px(dpi), item.MaxHeight.Px(dpi)}
+	return
+}
+
+// size returns the preferred size of the element, excluding the margin.
+func (item *Item) size(dpi win32.UINT) Size {
+	var size Size
+	if item.Element != nil && (item.Width.Value == 0 || item.Height.Value == 0) {
+		size = item.Element.Measure(dpi)
+	}
+	if item.Width.Value != 0 {
+		size.Width = item.Width.Px(dpi)
+	}
+	if item.Height.Value != 0 {
+		size.Height = item.Height.Px(dpi)
+	}
+	lo, hi := item.limits(dpi)
+	return Size{clamp(size.Width, lo.Width, hi.Width), clamp(size.Height, lo.Height, hi.Height)}
+}
+
+// outerSize returns the preferred size of the item, including the margin.
+func (item *Item) outerSize(dpi win32.UINT) Size {
+	size, margin := item.size(dpi), item.Margin.size(dpi)
+	return Size{size.Width + margin.Width, size.Height + margin.Height}
+}
+
+// outerLimits returns the min and max size of the item, including the margin.
+// Zero max means no limit.
+func (item *Item) outerLimits(dpi win32.UINT) (lo, hi Size) {
+	lo, hi = item.limits(dpi)
+	margin := item.Margin.size(dpi)
+	lo.Width += margin.Width
+	lo.Height += margin.Height
+	if hi.Width > 0 {
+		hi.Width += margin.Width
+	}
+	if hi.Height > 0 {
+		hi.Height += margin.Height
+	}
+	return
+}
+
+// align returns the position and size of an element of the preferred size
+// in the available space [pos, pos+avail), limited by [lo, hi].
+func align(a Alignment, pos, avail, preferred, lo, hi win32.INT) (win32.INT, win32.INT) {
+	if a == Fill {
+		return pos, clamp(avail, lo, hi)
+	}
+	size := clamp(preferred, lo, hi)
+	if size > avail {
+		size = max(avail, lo)
+	}
+	switch a {
+	case Center:
+		pos += (avail - size) / 2
+	case End:
+		pos += avail - size
+	}
+	return pos, size
+}
+
+// arrange places the element of the item in the slot.
+func (item *Item) arrange(slot Rect, dpi win32.UINT) {
+	if item.Element == nil {
+		return
+	}
+	inner := item.Margin.inset(slot, dpi)
+	size := item.size(dpi)
+	lo, hi := item.limits(dpi)
+	var bounds Rect
+	bounds.X, bounds.Width = align(item.HAlign, inner.X, inner.Width, size.Width, lo.Width, hi.Width)
+	bounds.Y, bounds.Height = align(item.VAlign, inner.Y, inner.Height, size.Height, lo.Height, hi.Height)
+	item.Element.Arrange(bounds, dpi)
+}
+
+// grow distributes extra to sizes in proportion to weights, without exceeding
+// limits(zero means no limit). Sizes of zero weight are not changed.
+func grow(sizes []win32.INT, extra win32.INT, weights []int, limits []win32.INT) {
+	active := make([]bool, len(sizes))
+	for i, w := range weights {
+		active[i] = w > 0 && (limits[i] == 0 || sizes[i] < limits[i])
+	}
+	for extra > 0 {
+		var total int64
+		for i := range active {
+			if active[i] {
+				total += int64(weights[i])
+			}
+		}
+		if total == 0 {
+			return
+		}
+		// Cumulative rounding gives out exactly extra if none is limited.
+		var acc, given int64
+		limited := false
+		remaining := extra
+		for i := range sizes {
+			if !active[i] {
+				continue
+			}
+			acc += int64(weights[i])
+			share := int64(extra)*acc/total - given
+			given += share
+			if limits[i] != 0 && sizes[i]+win32.INT(share) >= limits[i] {
+				share = int64(limits[i] - sizes[i])
+				active[i] = false
+				limited = true
+			}
+			sizes[i] += win32.INT(share)
+			remaining -= win32.INT(share)
+		}
+		extra = remaining
+		if !limited {
+			return
+		}
+	}
+}
+
+// shrink takes deficit from sizes in proportion to the room above mins.
+// Sizes are not shrunk below mins.
+func shrink(sizes []win32.INT, deficit win32.INT, mins []win32.INT) {
+	var total int64
+	for i := range sizes {
+		total += int64(max(sizes[i]-mins[i], 0))
+	}
+	if total == 0 {
+		return
+	}
+	d := min(int64(deficit), total)
+	var acc, given int64
+	for i := range sizes {
+		acc += int64(max(sizes[i]-mins[i], 0))
+		share := d*acc/total - given
+		given += share
+		sizes[i] -= win32.INT(share)
+	}
+}
+
+// fit grows or shrinks sizes to make their sum equal to avail if possible.
+func fit(sizes []win32.INT, avail win32.INT, weights []int, mins, maxs []win32.INT) {
+	var sum win32.INT
+	for _, s := range sizes {
+		sum += s
+	}
+	if sum < avail {
+		grow(sizes, avail-sum, weights, maxs)
+	} else if sum > avail {
+		shrink(sizes, sum-avail, mins)
+	}
+}
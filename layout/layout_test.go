@@ -0,0 +1,133 @@
+package layout_test
+
+import (
+	"testing"
+
+	"github.com/mkch/gw/layout"
+	"github.com/mkch/gw/metrics"
+	"github.com/mkch/gw/win32"
+)
+
+// fakeElement is an Element of a preferred size in DIP, which records the arranged bounds.
+type fakeElement struct {
+	width, height win32.INT
+	bounds        layout.Rect
+	dpi           win32.UINT
+}
+
+func (e *fakeElement) Measure(dpi win32.UINT) layout.Size {
+	return layout.Size{Width: metrics.FromDefaultDPI(e.width, dpi), Height: metrics.FromDefaultDPI(e.height, dpi)}
+}
+
+func (e *fakeElement) Arrange(bounds layout.Rect, dpi win32.UINT) {
+	e.bounds, e.dpi = bounds, dpi
+}
+
+func fake(width, height win32.INT) *fakeElement {
+	return &fakeElement{width: width, height: height}
+}
+
+func checkBounds(t *testing.T, name string, e *fakeElement, want layout.Rect) {
+	t.Helper()
+	if e.bounds != want {
+		t.Errorf("%v: got %+v, want %+v", name, e.bounds, want)
+	}
+}
+
+func TestItem(t *testing.T) {
+	e := fake(20, 10)
+	box := layout.VBox(&layout.Item{
+		Element: e,
+		Margin:  layout.Spacing{Left: metrics.Px(1), Top: metrics.Px(2), Right: metrics.Px(3), Bottom: metrics.Px(4)},
+		HAlign:  layout.Center,
+		VAlign:  layout.End,
+	})
+	if got, want := box.Measure(96), (layout.Size{Width: 24, Height: 16}); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	box.Arrange(layout.Rect{X: 0, Y: 0, Width: 104, Height: 16}, 96)
+	checkBounds(t, "center", e, layout.Rect{X: 41, Y: 2, Width: 20, Height: 10})
+
+	// Width and Height override the preferred size, limited by min and max.
+	box.Items[0] = &layout.Item{Element: e, Width: metrics.Dip(30), MaxWidth: metrics.Dip(25), MinHeight: metrics.Dip(15), HAlign: layout.Start, VAlign: layout.Start}
+	if got, want := box.Measure(192), (layout.Size{Width: 50, Height: 30}); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	box.Arrange(layout.Rect{X: 10, Y: 10, Width: 100, Height: 100}, 192)
+	checkBounds(t, "start", e, layout.Rect{X: 10, Y: 10, Width: 50, Height: 30})
+	if e.dpi != 192 {
+		t.Errorf("got dpi %v", e.dpi)
+	}
+
+	// Fill is limited by max.
+	box.Items[0] = &layout.Item{Element: e, MaxWidth: metrics.Px(40), MaxHeight: metrics.Px(50), Stretch: 1}
+	box.Arrange(layout.Rect{X: 0, Y: 0, Width: 100, Height: 100}, 96)
+	checkBounds(t, "fill", e, layout.Rect{X: 0, Y: 0, Width: 40, Height: 50})
+}
+
+func TestBox(t *testing.T) {
+	a, b, c := fake(10, 10), fake(20, 30), fake(30, 20)
+	box := layout.HBox(
+		&layout.Item{Element: a},
+		&layout.Item{Element: b, Stretch: 1},
+		&layout.Item{Element: c, Stretch: 2, VAlign: layout.Center},
+	)
+	box.Padding = layout.Uniform(metrics.Dip(5))
+	box.Spacing = metrics.Dip(2)
+	if got, want := box.Measure(96), (layout.Size{Width: 74, Height: 40}); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if got, want := box.Measure(192), (layout.Size{Width: 148, Height: 80}); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	// 30 px extra distributed 1:2.
+	box.Arrange(layout.Rect{X: 0, Y: 0, Width: 104, Height: 50}, 96)
+	checkBounds(t, "a", a, layout.Rect{X: 5, Y: 5, Width: 10, Height: 40})
+	checkBounds(t, "b", b, layout.Rect{X: 17, Y: 5, Width: 30, Height: 40})
+	checkBounds(t, "c", c, layout.Rect{X: 49, Y: 15, Width: 50, Height: 20})
+
+	// b reaches its max, the rest goes to c.
+	box.Items[1].MaxWidth = metrics.Dip(25)
+	box.Arrange(layout.Rect{X: 0, Y: 0, Width: 104, Height: 50}, 96)
+	checkBounds(t, "b max", b, layout.Rect{X: 17, Y: 5, Width: 25, Height: 40})
+	checkBounds(t, "c max", c, layout.Rect{X: 44, Y: 15, Width: 55, Height: 20})
+
+	// Not enough space: shrink in proportion to the room above min.
+	box.Items[1].MaxWidth = metrics.Dimension{}
+	box.Items[0].MinWidth = metrics.Dip(10)
+	box.Items[2].MinWidth = metrics.Dip(20)
+	box.Arrange(layout.Rect{X: 0, Y: 0, Width: 54, Height: 50}, 96)
+	checkBounds(t, "a shrunk", a, layout.Rect{X: 5, Y: 5, Width: 10, Height: 40})
+	checkBounds(t, "b shrunk", b, layout.Rect{X: 17, Y: 5, Width: 7, Height: 40})
+	checkBounds(t, "c shrunk", c, layout.Rect{X: 26, Y: 15, Width: 23, Height: 20})
+}
+
+func TestVBoxSpacer(t *testing.T) {
+	a, b := fake(10, 10), fake(10, 10)
+	box := layout.VBox(
+		&layout.Item{Element: a},
+		&layout.Item{Stretch: 1},
+		&layout.Item{Height: metrics.Px(5)},
+		&layout.Item{Element: b},
+	)
+	if got, want := box.Measure(96), (layout.Size{Width: 10, Height: 25}); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	box.Arrange(layout.Rect{X: 0, Y: 0, Width: 10, Height: 100}, 96)
+	checkBounds(t, "a", a, layout.Rect{X: 0, Y: 0, Width: 10, Height: 10})
+	checkBounds(t, "b", b, layout.Rect{X: 0, Y: 90, Width: 10, Height: 10})
+}
+
+func TestNested(t *testing.T) {
+	a, b, c := fake(10, 10), fake(10, 10), fake(10, 10)
+	inner := layout.VBox(&layout.Item{Element: b}, &layout.Item{Element: c})
+	outer := layout.HBox(&layout.Item{Element: a}, &layout.Item{Element: inner, Stretch: 1})
+	if got, want := outer.Measure(96), (layout.Size{Width: 20, Height: 20}); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	outer.Arrange(layout.Rect{X: 0, Y: 0, Width: 50, Height: 20}, 96)
+	checkBounds(t, "a", a, layout.Rect{X: 0, Y: 0, Width: 10, Height: 20})
+	checkBounds(t, "b", b, layout.Rect{X: 10, Y: 0, Width: 40, Height: 10})
+	checkBounds(t, "c", c, layout.Rect{X: 10, Y: 10, Width: 40, Height: 10})
+}
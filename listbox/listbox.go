@@ -0,0 +1,305 @@
+package listbox
+
+import (
+	"errors"
+	"runtime"
+	"slices"
+	"unsafe"
+
+	"github.com/mkch/gg"
+	"github.com/mkch/gw/control"
+	"github.com/mkch/gw/internal/appmsg"
+	"github.com/mkch/gw/internal/uithread"
+	"github.com/mkch/gw/metrics"
+	"github.com/mkch/gw/win32"
+	"github.com/mkch/gw/win32/win32util"
+)
+
+var (
+	// ErrFailed is returned if a list box message fails, for example, with an index out of range.
+	ErrFailed = errors.New("listbox: failed")
+	// ErrSpace is returned if there is insufficient space to store the items.
+	ErrSpace = errors.New("listbox: insufficient space")
+)
+
+// ListBox is a list box control whose items carry values of type T.
+type ListBox[T any] struct {
+	control.Control
+	// OnSelChange is called when the user changes the selection.
+	OnSelChange func()
+	// OnDblClick is called when the user double-clicks the item at index.
+	OnDblClick func(index int)
+	// OnDraw draws an item of an owner-drawn list box, see LBS_OWNERDRAWFIXED.
+	OnDraw func(item *DrawItem[T])
+	// OnMeasure returns the height of an item of a LBS_OWNERDRAWVARIABLE list box.
+	// The list box must have a nonzero ID, see Spec.
+	OnMeasure func(index int, value T) metrics.Dimension
+	values    []T
+	adding    *T // The value being added, measured before added to values.
+}
+
+// DrawItem is an item to draw.
+type DrawItem[T any] struct {
+	*win32.DRAWITEMSTRUCT
+	Index int // -1 if the list box is empty, and only the focus rectangle is drawn.
+	Text  string
+	Value T
+}
+
+// Selected reports whether the item is selected.
+func (item *DrawItem[T]) Selected() bool {
+	return item.ItemState&win32.ODS_SELECTED != 0
+}
+
+// Focused reports whether the item has the focus.
+func (item *DrawItem[T]) Focused() bool {
+	return item.ItemState&win32.ODS_FOCUS != 0
+}
+
+type Spec struct {
+	// ID of the control, which must be unique among its siblings to measure items.
+	ID      win32.WORD
+	X       metrics.Dimension
+	Y       metrics.Dimension
+	Width   metrics.Dimension
+	Height  metrics.Dimension
+	Style   win32.WINDOW_STYLE // LBS_MULTIPLESEL or LBS_EXTENDEDSEL for multiple selection.
+	ExStyle win32.WINDOW_EX_STYLE
+}
+
+// New creates a list box. LBS_NOTIFY is always added to the style, as well as
+// LBS_HASSTRINGS for owner-drawn list boxes. LBS_NODATA is not supported.
+func New[T any](parent win32.HWND, spec *Spec) (*ListBox[T], error) {
+	uithread.Check()
+	dpi := gg.Must(win32.GetDpiForWindow(parent))
+	style := spec.Style&^win32.LBS_NODATA | win32.WS_CHILD | win32.LBS_NOTIFY
+	if style&(win32.LBS_OWNERDRAWFIXED|win32.LBS_OWNERDRAWVARIABLE) != 0 {
+		style |= win32.LBS_HASSTRINGS
+	}
+	hwnd, err := win32util.CreateWindow(&win32util.Wnd{
+		ClassName: "LISTBOX",
+		WndParent: parent,
+		X:         spec.X.Px(dpi),
+		Y:         spec.Y.Px(dpi),
+		Width:     spec.Width.Px(dpi),
+		Height:    spec.Height.Px(dpi),
+		Style:     style,
+		ExStyle:   spec.ExStyle,
+		Menu:      win32.HMENU(spec.ID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var lb ListBox[T]
+	if err := control.Attach(hwnd, &lb.Control); err != nil {
+		win32.DestroyWindow(hwnd)
+		return nil, err
+	}
+	lb.Control.OnDrawItem = lb.drawItem
+	lb.Control.OnMeasureItem = lb.measureItem
+	lb.SetWndProc(func(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM, prev win32.WndProc) win32.LRESULT {
+		switch message {
+		case appmsg.REFLECT_COMMAND:
+			switch win32.HIWORD(wParam) {
+			case win32.LBN_SELCHANGE:
+				if lb.OnSelChange != nil {
+					lb.OnSelChange()
+				}
+			case win32.LBN_DBLCLK:
+				if lb.OnDblClick != nil {
+					if i, err := lb.send(win32.LB_GETCARETINDEX, 0, 0); err == nil {
+						lb.OnDblClick(i)
+					}
+				}
+			}
+		}
+		return prev(hwnd, message, wParam, lParam)
+	})
+	return &lb, nil
+}
+
+func (lb *ListBox[T]) drawItem(item *win32.DRAWITEMSTRUCT) {
+	if lb.OnDraw == nil {
+		return
+	}
+	draw := DrawItem[T]{DRAWITEMSTRUCT: item, Index: int(int32(item.ItemID))}
+	if draw.Index >= 0 && draw.Index < len(lb.values) {
+		draw.Text, _ = lb.Text(draw.Index)
+		draw.Value = lb.values[draw.Index]
+	}
+	lb.OnDraw(&draw)
+}
+
+func (lb *ListBox[T]) measureItem(item *win32.MEASUREITEMSTRUCT) {
+	if lb.OnMeasure == nil {
+		return
+	}
+	var value T
+	if lb.adding != nil {
+		value = *lb.adding
+	} else if i := int(item.ItemID); i < len(lb.values) {
+		value = lb.values[i]
+	}
+	dpi := gg.Must(win32.GetDpiForWindow(lb.HWND()))
+	item.ItemHeight = win32.UINT(lb.OnMeasure(int(item.ItemID), value).Px(dpi))
+}
+
+// send sends a list box message and converts LB_ERR and LB_ERRSPACE to errors.
+func (lb *ListBox[T]) send(message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) (int, error) {
+	// The error of SendMessageW is ignored, because list box messages do not set the last error.
+	r, _ := win32.SendMessageW(lb.HWND(), message, wParam, lParam)
+	switch r {
+	case win32.LB_ERR:
+		return 0, ErrFailed
+	case win32.LB_ERRSPACE:
+		return 0, ErrSpace
+	}
+	return int(r), nil
+}
+
+// sendString sends a list box message with the C string of s as lParam.
+func (lb *ListBox[T]) sendString(message win32.UINT, wParam win32.WPARAM, s string) (int, error) {
+	var buf []win32.WCHAR
+	win32util.CString(s, &buf)
+	defer runtime.KeepAlive(buf)
+	return lb.send(message, wParam, win32.LPARAM(uintptr(unsafe.Pointer(&buf[0]))))
+}
+
+// insert inserts an item with LB_ADDSTRING or LB_INSERTSTRING.
+func (lb *ListBox[T]) insert(message win32.UINT, index int, text string, value T) (int, error) {
+	lb.adding = &value
+	i, err := lb.sendString(message, win32.WPARAM(index), text)
+	lb.adding = nil
+	if err != nil {
+		return 0, err
+	}
+	lb.values = slices.Insert(lb.values, i, value)
+	return i, nil
+}
+
+// Add adds an item and returns its index. The item is added to the end of the list,
+// or to the sorted position if the list box is LBS_SORT.
+func (lb *ListBox[T]) Add(text string, value T) (int, error) {
+	uithread.CheckWindow(lb.HWND())
+	return lb.insert(win32.LB_ADDSTRING, 0, text, value)
+}
+
+// Insert inserts an item at index, -1 for the end of the list. The list is not sorted.
+func (lb *ListBox[T]) Insert(index int, text string, value T) error {
+	uithread.CheckWindow(lb.HWND())
+	_, err := lb.insert(win32.LB_INSERTSTRING, index, text, value)
+	return err
+}
+
+// Remove removes the item at index.
+func (lb *ListBox[T]) Remove(index int) error {
+	uithread.CheckWindow(lb.HWND())
+	if _, err := lb.send(win32.LB_DELETESTRING, win32.WPARAM(index), 0); err != nil {
+		return err
+	}
+	lb.values = slices.Delete(lb.values, index, index+1)
+	return nil
+}
+
+// Clear removes all items.
+func (lb *ListBox[T]) Clear() {
+	uithread.CheckWindow(lb.HWND())
+	win32.SendMessageW(lb.HWND(), win32.LB_RESETCONTENT, 0, 0)
+	clear(lb.values)
+	lb.values = lb.values[:0]
+}
+
+// Len returns the number of items.
+func (lb *ListBox[T]) Len() int {
+	return len(lb.values)
+}
+
+// Text returns the text of the item at index.
+func (lb *ListBox[T]) Text(index int) (string, error) {
+	n, err := lb.send(win32.LB_GETTEXTLEN, win32.WPARAM(index), 0)
+	if err != nil {
+		return "", err
+	}
+	buf := make([]win32.WCHAR, n+1)
+	if n, err = lb.send(win32.LB_GETTEXT, win32.WPARAM(index), win32.LPARAM(uintptr(unsafe.Pointer(&buf[0])))); err != nil {
+		return "", err
+	}
+	return win32util.GoString(&buf[0], n+1), nil
+}
+
+// Value returns the value of the item at index. It panics if index is out of range.
+func (lb *ListBox[T]) Value(index int) T {
+	return lb.values[index]
+}
+
+// SetValue sets the value of the item at index. It panics if index is out of range.
+func (lb *ListBox[T]) SetValue(index int, value T) {
+	uithread.CheckWindow(lb.HWND())
+	lb.values[index] = value
+}
+
+// Find returns the index of the first item whose text is s, case insensitive, or -1 if not found.
+func (lb *ListBox[T]) Find(s string) int {
+	if i, err := lb.sendString(win32.LB_FINDSTRINGEXACT, ^win32.WPARAM(0), s); err == nil {
+		return i
+	}
+	return -1
+}
+
+// Selected returns the index of the selected item of a single-selection list box, or -1 if none.
+func (lb *ListBox[T]) Selected() int {
+	if i, err := lb.send(win32.LB_GETCURSEL, 0, 0); err == nil {
+		return i
+	}
+	return -1
+}
+
+// Select selects the item at index of a single-selection list box, -1 to clear the selection.
+func (lb *ListBox[T]) Select(index int) error {
+	uithread.CheckWindow(lb.HWND())
+	_, err := lb.send(win32.LB_SETCURSEL, win32.WPARAM(index), 0)
+	if index == -1 && err == ErrFailed { // LB_ERR is returned when the selection is cleared.
+		err = nil
+	}
+	return err
+}
+
+// IsSelected reports whether the item at index is selected.
+func (lb *ListBox[T]) IsSelected(index int) bool {
+	r, err := lb.send(win32.LB_GETSEL, win32.WPARAM(index), 0)
+	return err == nil && r > 0
+}
+
+// SelectedItems returns the indexes of the selected items of a multiple-selection list box.
+func (lb *ListBox[T]) SelectedItems() ([]int, error) {
+	n, err := lb.send(win32.LB_GETSELCOUNT, 0, 0)
+	if err != nil || n == 0 {
+		return nil, err
+	}
+	buf := make([]win32.INT, n)
+	if n, err = lb.send(win32.LB_GETSELITEMS, win32.WPARAM(n), win32.LPARAM(uintptr(unsafe.Pointer(&buf[0])))); err != nil {
+		return nil, err
+	}
+	items := make([]int, n)
+	for i := range items {
+		items[i] = int(buf[i])
+	}
+	return items, nil
+}
+
+// SetItemSelected selects or deselects the item at index of a multiple-selection list box,
+// -1 for all items.
+func (lb *ListBox[T]) SetItemSelected(index int, selected bool) error {
+	uithread.CheckWindow(lb.HWND())
+	_, err := lb.send(win32.LB_SETSEL, win32.WPARAM(gg.If(selected, 1, 0)), win32.LPARAM(index))
+	return err
+}
+
+// SetItemHeight sets the height of the item at index of a LBS_OWNERDRAWVARIABLE list box,
+// or the height of all items otherwise.
+func (lb *ListBox[T]) SetItemHeight(index int, height metrics.Dimension) error {
+	uithread.CheckWindow(lb.HWND())
+	dpi := gg.Must(win32.GetDpiForWindow(lb.HWND()))
+	_, err := lb.send(win32.LB_SETITEMHEIGHT, win32.WPARAM(index), win32.LPARAM(height.Px(dpi)))
+	return err
+}
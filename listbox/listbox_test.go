@@ -0,0 +1,203 @@
+package listbox_test
+
+import (
+	"slices"
+	"testing"
+	"unsafe"
+
+	"github.com/mkch/gw/listbox"
+	"github.com/mkch/gw/metrics"
+	"github.com/mkch/gw/win32"
+	"github.com/mkch/gw/win32/fake"
+	"github.com/mkch/gw/win32/fake/faketest"
+	"github.com/mkch/gw/window"
+)
+
+var backend = fake.New()
+
+func TestMain(m *testing.M) {
+	faketest.Main(m, backend)
+}
+
+// newListBox creates a parent window and a list box in it.
+func newListBox[T any](t *testing.T, spec *listbox.Spec) (parent *window.Window, lb *listbox.ListBox[T]) {
+	parent = faketest.NewParent(t)
+	lb, err := listbox.New[T](parent.HWND(), spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return
+}
+
+func texts[T any](t *testing.T, lb *listbox.ListBox[T]) (texts []string) {
+	t.Helper()
+	for i := range lb.Len() {
+		text, err := lb.Text(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		texts = append(texts, text)
+	}
+	return
+}
+
+func TestItems(t *testing.T) {
+	_, lb := newListBox[int](t, &listbox.Spec{Style: win32.LBS_SORT})
+	for i, text := range []string{"b", "c", "a"} {
+		if _, err := lb.Add(text, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := lb.Insert(1, "x", 9); err != nil {
+		t.Fatal(err)
+	}
+	if got := texts(t, lb); !slices.Equal(got, []string{"a", "x", "b", "c"}) {
+		t.Fatal(got)
+	}
+	if v := []int{lb.Value(0), lb.Value(1), lb.Value(2), lb.Value(3)}; !slices.Equal(v, []int{2, 9, 0, 1}) {
+		t.Fatal(v)
+	}
+	if i := lb.Find("B"); i != 2 {
+		t.Fatal(i)
+	}
+	if i := lb.Find("y"); i != -1 {
+		t.Fatal(i)
+	}
+
+	if err := lb.Remove(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := lb.Remove(3); err != listbox.ErrFailed {
+		t.Fatal(err)
+	}
+	lb.SetValue(2, 10)
+	if got := texts(t, lb); !slices.Equal(got, []string{"a", "b", "c"}) || lb.Value(0) != 2 || lb.Value(2) != 10 {
+		t.Fatal(got)
+	}
+	if _, err := lb.Text(3); err != listbox.ErrFailed {
+		t.Fatal(err)
+	}
+
+	lb.Clear()
+	if lb.Len() != 0 {
+		t.Fatal(lb.Len())
+	}
+}
+
+func TestSingleSelection(t *testing.T) {
+	_, lb := newListBox[string](t, &listbox.Spec{})
+	for _, text := range []string{"a", "b", "c"} {
+		lb.Add(text, text)
+	}
+	var changes int
+	var clicked = -1
+	lb.OnSelChange = func() { changes++ }
+	lb.OnDblClick = func(index int) { clicked = index }
+
+	if lb.Selected() != -1 {
+		t.Fatal(lb.Selected())
+	}
+	if err := lb.Select(1); err != nil || lb.Selected() != 1 || !lb.IsSelected(1) {
+		t.Fatal(err, lb.Selected())
+	}
+	if err := lb.Select(-1); err != nil || lb.Selected() != -1 {
+		t.Fatal(err, lb.Selected())
+	}
+	if changes != 0 { // Not changed by the user.
+		t.Fatal(changes)
+	}
+
+	if err := backend.ListBoxClick(lb.HWND(), 2, false); err != nil {
+		t.Fatal(err)
+	}
+	if changes != 1 || clicked != -1 || lb.Selected() != 2 {
+		t.Fatal(changes, clicked, lb.Selected())
+	}
+	if err := backend.ListBoxClick(lb.HWND(), 0, true); err != nil {
+		t.Fatal(err)
+	}
+	if changes != 2 || clicked != 0 || lb.Selected() != 0 {
+		t.Fatal(changes, clicked, lb.Selected())
+	}
+	if _, err := lb.SelectedItems(); err != listbox.ErrFailed {
+		t.Fatal(err)
+	}
+}
+
+func TestMultipleSelection(t *testing.T) {
+	for _, style := range []win32.WINDOW_STYLE{win32.LBS_MULTIPLESEL, win32.LBS_EXTENDEDSEL} {
+		_, lb := newListBox[int](t, &listbox.Spec{Style: style})
+		for i := range 4 {
+			lb.Add("item", i)
+		}
+		if err := lb.SetItemSelected(1, true); err != nil {
+			t.Fatal(err)
+		}
+		if err := lb.SetItemSelected(3, true); err != nil {
+			t.Fatal(err)
+		}
+		if items, err := lb.SelectedItems(); err != nil || !slices.Equal(items, []int{1, 3}) {
+			t.Fatal(items, err)
+		}
+		if err := lb.SetItemSelected(-1, false); err != nil {
+			t.Fatal(err)
+		}
+		if items, err := lb.SelectedItems(); err != nil || len(items) != 0 {
+			t.Fatal(items, err)
+		}
+		if err := lb.Select(1); err != listbox.ErrFailed {
+			t.Fatal(err)
+		}
+
+		backend.ListBoxClick(lb.HWND(), 0, false)
+		backend.ListBoxClick(lb.HWND(), 2, false)
+		want := []int{0, 2} // Clicks toggle the selection.
+		if style == win32.LBS_EXTENDEDSEL {
+			want = []int{2} // Clicks without modifier keys select one item.
+		}
+		if items, err := lb.SelectedItems(); err != nil || !slices.Equal(items, want) {
+			t.Fatal(style, items, err)
+		}
+	}
+}
+
+func TestOwnerDraw(t *testing.T) {
+	parent, lb := newListBox[string](t, &listbox.Spec{ID: 3, Style: win32.LBS_OWNERDRAWVARIABLE})
+	var measured []string
+	lb.OnMeasure = func(index int, value string) metrics.Dimension {
+		measured = append(measured, value)
+		return metrics.Px(win32.INT(20 + index))
+	}
+	lb.Add("a", "A")
+	lb.Add("b", "B")
+	if !slices.Equal(measured, []string{"A", "B"}) {
+		t.Fatal(measured)
+	}
+	if h, _ := win32.SendMessageW(lb.HWND(), win32.LB_GETITEMHEIGHT, 1, 0); h != 21 {
+		t.Fatal(h)
+	}
+	if err := lb.SetItemHeight(0, metrics.Px(30)); err != nil {
+		t.Fatal(err)
+	}
+	if h, _ := win32.SendMessageW(lb.HWND(), win32.LB_GETITEMHEIGHT, 0, 0); h != 30 {
+		t.Fatal(h)
+	}
+
+	var drawn *listbox.DrawItem[string]
+	lb.OnDraw = func(item *listbox.DrawItem[string]) { drawn = item }
+	draw := fake.Alloc[win32.DRAWITEMSTRUCT]()
+	defer fake.Free(draw)
+	*draw = win32.DRAWITEMSTRUCT{CtlType: win32.ODT_LISTBOX, CtlID: 3, ItemID: 1, ItemState: win32.ODS_SELECTED, HwndItem: lb.HWND()}
+	win32.SendMessageW(parent.HWND(), win32.WM_DRAWITEM, 3, win32.LPARAM(uintptr(unsafe.Pointer(draw))))
+	if drawn == nil || drawn.Index != 1 || drawn.Text != "b" || drawn.Value != "B" || !drawn.Selected() || drawn.Focused() {
+		t.Fatal(drawn)
+	}
+
+	// Empty list box draws the focus rectangle only.
+	lb.Clear()
+	*draw = win32.DRAWITEMSTRUCT{CtlType: win32.ODT_LISTBOX, CtlID: 3, ItemID: 0xFFFFFFFF, ItemState: win32.ODS_FOCUS, HwndItem: lb.HWND()}
+	win32.SendMessageW(parent.HWND(), win32.WM_DRAWITEM, 3, win32.LPARAM(uintptr(unsafe.Pointer(draw))))
+	if drawn.Index != -1 || drawn.Text != "" || drawn.Value != "" || !drawn.Focused() {
+		t.Fatal(drawn)
+	}
+}
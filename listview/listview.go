@@ -0,0 +1,414 @@
+package listview
+
+import (
+	"errors"
+	"runtime"
+	"unsafe"
+
+	"github.com/mkch/gg"
+	"github.com/mkch/gw/control"
+	"github.com/mkch/gw/imagelist"
+	"github.com/mkch/gw/internal/uithread"
+	"github.com/mkch/gw/metrics"
+	"github.com/mkch/gw/win32"
+	"github.com/mkch/gw/win32/win32util"
+)
+
+// ErrFailed is returned if a list view message fails, for example, with a row out of range.
+var ErrFailed = errors.New("listview: failed")
+
+// DataSource provides the rows of a list view. The list view retrieves the cells
+// when they are displayed, so the rows can be as many as needed.
+// Call ListView.Refresh after the rows change.
+type DataSource interface {
+	// Len returns the number of rows.
+	Len() int
+	// Cell returns the text of the cell at row and column col.
+	Cell(row, col int) string
+}
+
+// ImageSource is implemented by the data sources with an icon in each row.
+type ImageSource interface {
+	// Image returns the index of the icon of row in the small image list, see
+	// ListView.SetSmallImages. -1 for no icon.
+	Image(row int) int
+}
+
+// Checkable is implemented by the data sources with a checkbox in each row.
+type Checkable interface {
+	Checked(row int) bool
+	// SetChecked is called when the user checks or unchecks row.
+	SetChecked(row int, checked bool)
+}
+
+// Sorter is implemented by the data sources which can be sorted by the user
+// clicking the column headers.
+type Sorter interface {
+	// Sort sorts the rows by column col.
+	Sort(col int, ascending bool)
+}
+
+// Column is a column of a list view.
+type Column struct {
+	Title  string
+	Width  metrics.Dimension
+	Format win32.INT // LVCFMT_LEFT, LVCFMT_RIGHT or LVCFMT_CENTER.
+}
+
+// ListView is a list view control in report view, whose rows are provided by a DataSource.
+type ListView struct {
+	control.Control
+	// OnSelChange is called when the selection changes.
+	OnSelChange func()
+	// OnActivate is called when the user activates row, typically by double-clicking it.
+	OnActivate  func(row int)
+	source      DataSource
+	smallImages *imagelist.ImageList
+	columns     int
+	sortCol     int // -1 if not sorted.
+	ascending   bool
+}
+
+type Spec struct {
+	Columns []Column
+	Source  DataSource // nil for no rows.
+	X       metrics.Dimension
+	Y       metrics.Dimension
+	Width   metrics.Dimension
+	Height  metrics.Dimension
+	Style   win32.WINDOW_STYLE // LVS_SINGLESEL, LVS_SHOWSELALWAYS, LVS_NOSORTHEADER etc.
+	ExStyle win32.WINDOW_EX_STYLE
+	// ListExStyle is the extended list view styles, LVS_EX_*.
+	// LVS_EX_CHECKBOXES is managed by the list view, see Checkable.
+	ListExStyle win32.DWORD
+	SmallImages *imagelist.ImageList // See ImageSource.
+}
+
+// New creates a list view. LVS_REPORT, LVS_OWNERDATA and LVS_SHAREIMAGELISTS are
+// always added to the style, and the other views are not supported.
+func New(parent win32.HWND, spec *Spec) (*ListView, error) {
+	uithread.Check()
+	icc := win32.INITCOMMONCONTROLSEX{ICC: win32.ICC_LISTVIEW_CLASSES}
+	icc.Size = win32.DWORD(unsafe.Sizeof(icc))
+	if err := win32.InitCommonControlsEx(&icc); err != nil {
+		return nil, err
+	}
+	dpi := gg.Must(win32.GetDpiForWindow(parent))
+	style := spec.Style&^win32.LVS_TYPEMASK | win32.WS_CHILD | win32.LVS_REPORT | win32.LVS_OWNERDATA | win32.LVS_SHAREIMAGELISTS
+	hwnd, err := win32util.CreateWindow(&win32util.Wnd{
+		ClassName: "SysListView32",
+		WndParent: parent,
+		X:         spec.X.Px(dpi),
+		Y:         spec.Y.Px(dpi),
+		Width:     spec.Width.Px(dpi),
+		Height:    spec.Height.Px(dpi),
+		Style:     style,
+		ExStyle:   spec.ExStyle,
+	})
+	if err != nil {
+		return nil, err
+	}
+	lv := ListView{sortCol: -1}
+	if err := control.Attach(hwnd, &lv.Control); err != nil {
+		win32.DestroyWindow(hwnd)
+		return nil, err
+	}
+	lv.SetWndProc(func(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM, prev win32.WndProc) win32.LRESULT {
+		if message == win32.WM_NCDESTROY && lv.smallImages != nil {
+			lv.smallImages.Release()
+			lv.smallImages = nil
+		}
+		return prev(hwnd, message, wParam, lParam)
+	})
+	control.HandleNotify(&lv.Control, win32.LVN_GETDISPINFOW, lv.getDispInfo)
+	control.HandleNotify(&lv.Control, win32.LVN_ITEMCHANGED, func(nm *win32.NMLISTVIEW) win32.LRESULT {
+		if nm.Changed&win32.LVIF_STATE != 0 && (nm.NewState^nm.OldState)&win32.LVIS_SELECTED != 0 {
+			lv.selChange()
+		}
+		return 0
+	})
+	control.HandleNotify(&lv.Control, win32.LVN_ODSTATECHANGED, func(nm *win32.NMLVODSTATECHANGE) win32.LRESULT {
+		if (nm.NewState^nm.OldState)&win32.LVIS_SELECTED != 0 {
+			lv.selChange()
+		}
+		return 0
+	})
+	control.HandleNotify(&lv.Control, win32.LVN_ITEMACTIVATE, func(nm *win32.NMITEMACTIVATE) win32.LRESULT {
+		if lv.OnActivate != nil && nm.Item >= 0 {
+			lv.OnActivate(int(nm.Item))
+		}
+		return 0
+	})
+	control.HandleNotify(&lv.Control, win32.LVN_COLUMNCLICK, func(nm *win32.NMLISTVIEW) win32.LRESULT {
+		if _, ok := lv.source.(Sorter); ok {
+			col := int(nm.SubItem)
+			lv.SortBy(col, col != lv.sortCol || !lv.ascending)
+		}
+		return 0
+	})
+	control.HandleNotify(&lv.Control, win32.NM_CLICK, func(nm *win32.NMITEMACTIVATE) win32.LRESULT {
+		info := win32.LVHITTESTINFO{Pt: nm.Action}
+		if _, err := lv.send(win32.LVM_HITTEST, 0, win32.LPARAM(uintptr(unsafe.Pointer(&info)))); err == nil &&
+			info.Flags&win32.LVHT_ONITEMSTATEICON != 0 {
+			lv.toggle([]int{int(info.Item)})
+		}
+		return 0
+	})
+	control.HandleNotify(&lv.Control, win32.LVN_KEYDOWN, func(nm *win32.NMLVKEYDOWN) win32.LRESULT {
+		if nm.VKey == win32.VK_SPACE {
+			lv.toggle(lv.SelectedRows())
+		}
+		return 0
+	})
+
+	if spec.ListExStyle != 0 {
+		win32.SendMessageW(hwnd, win32.LVM_SETEXTENDEDLISTVIEWSTYLE, 0, win32.LPARAM(spec.ListExStyle&^win32.LVS_EX_CHECKBOXES))
+	}
+	for _, col := range spec.Columns {
+		if err := lv.AddColumn(col); err != nil {
+			lv.Destroy()
+			return nil, err
+		}
+	}
+	if spec.SmallImages != nil {
+		lv.SetSmallImages(spec.SmallImages)
+	}
+	lv.SetDataSource(spec.Source)
+	return &lv, nil
+}
+
+// send sends a list view message and converts the failures to ErrFailed.
+// Messages returning -1 or FALSE on failure only can be sent.
+func (lv *ListView) send(message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) (int, error) {
+	// The error of SendMessageW is ignored, because list view messages do not set the last error.
+	r, _ := win32.SendMessageW(lv.HWND(), message, wParam, lParam)
+	if r == -1 {
+		return 0, ErrFailed
+	}
+	return int(r), nil
+}
+
+func (lv *ListView) selChange() {
+	if lv.OnSelChange != nil {
+		lv.OnSelChange()
+	}
+}
+
+func (lv *ListView) getDispInfo(nm *win32.NMLVDISPINFOW) win32.LRESULT {
+	item := &nm.Item
+	row, col := int(item.Item), int(item.SubItem)
+	if lv.source == nil || row < 0 || row >= lv.source.Len() {
+		return 0
+	}
+	if item.Mask&win32.LVIF_TEXT != 0 && item.TextMax > 0 {
+		var text []win32.WCHAR
+		win32util.CString(lv.source.Cell(row, col), &text)
+		win32util.CopyCString(unsafe.Slice(item.Text, item.TextMax), text)
+	}
+	if item.Mask&win32.LVIF_IMAGE != 0 {
+		item.Image = win32.I_IMAGENONE
+		if src, ok := lv.source.(ImageSource); ok && col == 0 {
+			if i := src.Image(row); i >= 0 {
+				item.Image = win32.INT(i)
+			}
+		}
+	}
+	if item.Mask&win32.LVIF_STATE != 0 {
+		if src, ok := lv.source.(Checkable); ok {
+			item.State = win32.INDEXTOSTATEIMAGEMASK(gg.If[win32.UINT](src.Checked(row), 2, 1))
+			item.StateMask = win32.LVIS_STATEIMAGEMASK
+		}
+	}
+	return 0
+}
+
+// toggle toggles the checkboxes of rows, which are all set to the opposite of the first row.
+func (lv *ListView) toggle(rows []int) {
+	src, ok := lv.source.(Checkable)
+	if !ok || len(rows) == 0 {
+		return
+	}
+	checked := !src.Checked(rows[0])
+	for _, row := range rows {
+		src.SetChecked(row, checked)
+		lv.RefreshRows(row, row)
+	}
+}
+
+// AddColumn appends a column.
+func (lv *ListView) AddColumn(col Column) error {
+	uithread.CheckWindow(lv.HWND())
+	var title []win32.WCHAR
+	win32util.CString(col.Title, &title)
+	defer runtime.KeepAlive(title)
+	column := win32.LVCOLUMNW{
+		Mask: win32.LVCF_FMT | win32.LVCF_WIDTH | win32.LVCF_TEXT,
+		Fmt:  col.Format,
+		Cx:   col.Width.Px(gg.Must(win32.GetDpiForWindow(lv.HWND()))),
+		Text: &title[0],
+	}
+	if _, err := lv.send(win32.LVM_INSERTCOLUMNW, win32.WPARAM(lv.columns), win32.LPARAM(uintptr(unsafe.Pointer(&column)))); err != nil {
+		return err
+	}
+	lv.columns++
+	return nil
+}
+
+// SetColumnWidth sets the width of column col.
+func (lv *ListView) SetColumnWidth(col int, width metrics.Dimension) error {
+	uithread.CheckWindow(lv.HWND())
+	px := width.Px(gg.Must(win32.GetDpiForWindow(lv.HWND())))
+	if r, _ := lv.send(win32.LVM_SETCOLUMNWIDTH, win32.WPARAM(col), win32.LPARAM(px)); r == 0 {
+		return ErrFailed
+	}
+	return nil
+}
+
+// DataSource returns the data source of the list view.
+func (lv *ListView) DataSource() DataSource {
+	return lv.source
+}
+
+// SetDataSource sets the data source of the list view, nil for no rows.
+// The sort column is reset, and the checkboxes are shown if src is Checkable.
+func (lv *ListView) SetDataSource(src DataSource) {
+	uithread.CheckWindow(lv.HWND())
+	lv.source = src
+	_, checkable := src.(Checkable)
+	win32.SendMessageW(lv.HWND(), win32.LVM_SETEXTENDEDLISTVIEWSTYLE, win32.LVS_EX_CHECKBOXES,
+		win32.LPARAM(gg.If[win32.DWORD](checkable, win32.LVS_EX_CHECKBOXES, 0)))
+	win32.SendMessageW(lv.HWND(), win32.LVM_SETCALLBACKMASK,
+		win32.WPARAM(gg.If[win32.UINT](checkable, win32.LVIS_STATEIMAGEMASK, 0)), 0)
+	lv.setSortColumn(-1, false)
+	lv.Refresh()
+}
+
+// Refresh updates the list view after the rows of the data source change.
+// All rows are redrawn, and the selected rows are kept by index.
+func (lv *ListView) Refresh() {
+	uithread.CheckWindow(lv.HWND())
+	n := 0
+	if lv.source != nil {
+		n = lv.source.Len()
+	}
+	win32.SendMessageW(lv.HWND(), win32.LVM_SETITEMCOUNT, win32.WPARAM(n), win32.LVSICF_NOSCROLL)
+}
+
+// RefreshRows redraws the rows from first to last inclusive, after their cells change.
+func (lv *ListView) RefreshRows(first, last int) {
+	uithread.CheckWindow(lv.HWND())
+	win32.SendMessageW(lv.HWND(), win32.LVM_REDRAWITEMS, win32.WPARAM(first), win32.LPARAM(last))
+}
+
+// SetSmallImages sets the image list of the icons of rows, see ImageSource.
+// The list view holds a clone of images. nil removes the image list.
+func (lv *ListView) SetSmallImages(images *imagelist.ImageList) {
+	uithread.CheckWindow(lv.HWND())
+	var h win32.HIMAGELIST
+	if images != nil {
+		images = images.Clone()
+		h = images.HIMAGELIST()
+	}
+	win32.SendMessageW(lv.HWND(), win32.LVM_SETIMAGELIST, win32.LVSIL_SMALL, win32.LPARAM(h))
+	if lv.smallImages != nil {
+		lv.smallImages.Release()
+	}
+	lv.smallImages = images
+}
+
+// SelectedRows returns the selected rows in ascending order.
+func (lv *ListView) SelectedRows() (rows []int) {
+	for row := -1; ; {
+		r, _ := win32.SendMessageW(lv.HWND(), win32.LVM_GETNEXTITEM, win32.WPARAM(row), win32.LVNI_SELECTED)
+		if r < 0 {
+			return
+		}
+		row = int(r)
+		rows = append(rows, row)
+	}
+}
+
+// IsSelected reports whether row is selected.
+func (lv *ListView) IsSelected(row int) bool {
+	r, _ := win32.SendMessageW(lv.HWND(), win32.LVM_GETITEMSTATE, win32.WPARAM(row), win32.LVIS_SELECTED)
+	return r&win32.LVIS_SELECTED != 0
+}
+
+// FocusedRow returns the row which has the focus, or -1 if none.
+func (lv *ListView) FocusedRow() int {
+	r, _ := win32.SendMessageW(lv.HWND(), win32.LVM_GETNEXTITEM, ^win32.WPARAM(0), win32.LVNI_FOCUSED)
+	return int(r)
+}
+
+// setState sets the state of row, -1 for all rows.
+func (lv *ListView) setState(row int, state, mask win32.UINT) error {
+	item := win32.LVITEMW{State: state, StateMask: mask}
+	if r, _ := lv.send(win32.LVM_SETITEMSTATE, win32.WPARAM(row), win32.LPARAM(uintptr(unsafe.Pointer(&item)))); r == 0 {
+		return ErrFailed
+	}
+	return nil
+}
+
+// Select selects row only and gives it the focus. -1 clears the selection.
+func (lv *ListView) Select(row int) error {
+	uithread.CheckWindow(lv.HWND())
+	if err := lv.setState(-1, 0, win32.LVIS_SELECTED); err != nil {
+		return err
+	}
+	if row == -1 {
+		return nil
+	}
+	return lv.setState(row, win32.LVIS_SELECTED|win32.LVIS_FOCUSED, win32.LVIS_SELECTED|win32.LVIS_FOCUSED)
+}
+
+// SetRowSelected selects or deselects row, -1 for all rows, without changing the
+// selection of the other rows.
+func (lv *ListView) SetRowSelected(row int, selected bool) error {
+	uithread.CheckWindow(lv.HWND())
+	return lv.setState(row, gg.If[win32.UINT](selected, win32.LVIS_SELECTED, 0), win32.LVIS_SELECTED)
+}
+
+// EnsureVisible scrolls the list view to make row visible.
+func (lv *ListView) EnsureVisible(row int) error {
+	uithread.CheckWindow(lv.HWND())
+	if r, _ := lv.send(win32.LVM_ENSUREVISIBLE, win32.WPARAM(row), 0); r == 0 {
+		return ErrFailed
+	}
+	return nil
+}
+
+// SortColumn returns the column by which the rows are sorted, and the order.
+// col is -1 if the rows are not sorted.
+func (lv *ListView) SortColumn() (col int, ascending bool) {
+	return lv.sortCol, lv.ascending
+}
+
+// SortBy sorts the rows by column col with the Sorter of the data source, and
+// shows the sort arrow in the column header. The selection is cleared.
+func (lv *ListView) SortBy(col int, ascending bool) error {
+	uithread.CheckWindow(lv.HWND())
+	sorter, ok := lv.source.(Sorter)
+	if !ok {
+		return ErrFailed
+	}
+	sorter.Sort(col, ascending)
+	lv.setSortColumn(col, ascending)
+	lv.Select(-1)
+	lv.Refresh()
+	return nil
+}
+
+// setSortColumn records the sort column and updates the sort arrows of the column headers.
+func (lv *ListView) setSortColumn(col int, ascending bool) {
+	lv.sortCol, lv.ascending = col, ascending
+	header, _ := win32.SendMessageW(lv.HWND(), win32.LVM_GETHEADER, 0, 0)
+	for i := range lv.columns {
+		item := win32.HDITEMW{Mask: win32.HDI_FORMAT}
+		win32.SendMessageW(win32.HWND(header), win32.HDM_GETITEMW, win32.WPARAM(i), win32.LPARAM(uintptr(unsafe.Pointer(&item))))
+		item.Fmt &^= win32.HDF_SORTUP | win32.HDF_SORTDOWN
+		if i == col {
+			item.Fmt |= gg.If[win32.INT](ascending, win32.HDF_SORTUP, win32.HDF_SORTDOWN)
+		}
+		win32.SendMessageW(win32.HWND(header), win32.HDM_SETITEMW, win32.WPARAM(i), win32.LPARAM(uintptr(unsafe.Pointer(&item))))
+	}
+}
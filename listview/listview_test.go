@@ -0,0 +1,266 @@
+package listview_test
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+	"unsafe"
+
+	"github.com/mkch/gw/imagelist"
+	"github.com/mkch/gw/listview"
+	"github.com/mkch/gw/metrics"
+	"github.com/mkch/gw/win32"
+	"github.com/mkch/gw/win32/fake"
+	"github.com/mkch/gw/win32/fake/faketest"
+	"github.com/mkch/gw/win32/win32util"
+)
+
+var backend = fake.New()
+
+func TestMain(m *testing.M) {
+	faketest.Main(m, backend)
+}
+
+// newListView creates a parent window and a list view in it.
+func newListView(t *testing.T, spec *listview.Spec) *listview.ListView {
+	parent := faketest.NewParent(t)
+	lv, err := listview.New(parent.HWND(), spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return lv
+}
+
+// rows is a data source of names and ages.
+type rows struct {
+	names   []string
+	ages    []int
+	checked map[int]bool
+	sorts   []string
+}
+
+func (r *rows) Len() int { return len(r.names) }
+
+func (r *rows) Cell(row, col int) string {
+	if col == 0 {
+		return r.names[row]
+	}
+	return strconv.Itoa(r.ages[row])
+}
+
+func (r *rows) Image(row int) int { return row % 2 }
+
+// checkableRows have checkboxes.
+type checkableRows struct{ *rows }
+
+func (r checkableRows) Checked(row int) bool { return r.checked[row] }
+
+func (r checkableRows) SetChecked(row int, checked bool) { r.checked[row] = checked }
+
+// sortableRows sort by name only, and record the sorts.
+type sortableRows struct{ *rows }
+
+func (r sortableRows) Sort(col int, ascending bool) {
+	r.sorts = append(r.sorts, strconv.Itoa(col)+strconv.FormatBool(ascending))
+	slices.Sort(r.names)
+	if !ascending {
+		slices.Reverse(r.names)
+	}
+}
+
+var columns = []listview.Column{
+	{Title: "Name", Width: metrics.Px(100)},
+	{Title: "Age", Width: metrics.Px(50), Format: win32.LVCFMT_RIGHT},
+}
+
+func newRows() *rows {
+	return &rows{names: []string{"b", "c", "a"}, ages: []int{2, 3, 1}, checked: map[int]bool{}}
+}
+
+// cell returns the text of a cell displayed in lv.
+func cell(lv *listview.ListView, row, col int) string {
+	var buf [64]win32.WCHAR
+	item := win32.LVITEMW{SubItem: win32.INT(col), Text: &buf[0], TextMax: win32.INT(len(buf))}
+	n, _ := win32.SendMessageW(lv.HWND(), win32.LVM_GETITEMTEXTW, win32.WPARAM(row), win32.LPARAM(uintptr(unsafe.Pointer(&item))))
+	return win32util.GoString(&buf[0], int(n)+1)
+}
+
+func itemCount(lv *listview.ListView) int {
+	n, _ := win32.SendMessageW(lv.HWND(), win32.LVM_GETITEMCOUNT, 0, 0)
+	return int(n)
+}
+
+func TestDataSource(t *testing.T) {
+	src := newRows()
+	lv := newListView(t, &listview.Spec{Columns: columns, Source: src})
+	if n := itemCount(lv); n != 3 {
+		t.Fatal(n)
+	}
+	if got := []string{cell(lv, 0, 0), cell(lv, 0, 1), cell(lv, 2, 0)}; !slices.Equal(got, []string{"b", "2", "a"}) {
+		t.Fatal(got)
+	}
+	var buf [16]win32.WCHAR
+	col := win32.LVCOLUMNW{Mask: win32.LVCF_FMT | win32.LVCF_WIDTH | win32.LVCF_TEXT, Text: &buf[0], TextMax: win32.INT(len(buf))}
+	win32.SendMessageW(lv.HWND(), win32.LVM_GETCOLUMNW, 1, win32.LPARAM(uintptr(unsafe.Pointer(&col))))
+	if title := win32util.GoString(&buf[0], 4); title != "Age" || col.Cx != 50 || col.Fmt != win32.LVCFMT_RIGHT {
+		t.Fatal(title, col.Cx, col.Fmt)
+	}
+
+	src.names, src.ages = append(src.names, "d"), append(src.ages, 4)
+	lv.Refresh()
+	if n := itemCount(lv); n != 4 || cell(lv, 3, 1) != "4" {
+		t.Fatal(n, cell(lv, 3, 1))
+	}
+	lv.SetDataSource(nil)
+	if n := itemCount(lv); n != 0 {
+		t.Fatal(n)
+	}
+}
+
+func TestSelection(t *testing.T) {
+	lv := newListView(t, &listview.Spec{Columns: columns, Source: newRows()})
+	var changes int
+	var activated []int
+	lv.OnSelChange = func() { changes++ }
+	lv.OnActivate = func(row int) { activated = append(activated, row) }
+
+	if rows := lv.SelectedRows(); len(rows) != 0 || lv.FocusedRow() != -1 {
+		t.Fatal(rows, lv.FocusedRow())
+	}
+	if err := backend.ListViewClick(lv.HWND(), 20, 16+4, false); err != nil { // Row 1.
+		t.Fatal(err)
+	}
+	if rows := lv.SelectedRows(); !slices.Equal(rows, []int{1}) || lv.FocusedRow() != 1 || changes != 1 {
+		t.Fatal(rows, lv.FocusedRow(), changes)
+	}
+	backend.ListViewClick(lv.HWND(), 120, 32+4, true) // Row 2, column 1.
+	if rows := lv.SelectedRows(); !slices.Equal(rows, []int{2}) || !slices.Equal(activated, []int{2}) {
+		t.Fatal(rows, activated)
+	}
+	backend.ListViewClick(lv.HWND(), 20, 100, true) // No row.
+	if rows := lv.SelectedRows(); len(rows) != 0 || len(activated) != 1 {
+		t.Fatal(rows, activated)
+	}
+
+	if err := lv.Select(0); err != nil {
+		t.Fatal(err)
+	}
+	if err := lv.SetRowSelected(2, true); err != nil {
+		t.Fatal(err)
+	}
+	if rows := lv.SelectedRows(); !slices.Equal(rows, []int{0, 2}) || !lv.IsSelected(2) || lv.IsSelected(1) {
+		t.Fatal(rows)
+	}
+	if err := lv.SetRowSelected(3, true); err != listview.ErrFailed {
+		t.Fatal(err)
+	}
+	if err := lv.Select(-1); err != nil || len(lv.SelectedRows()) != 0 {
+		t.Fatal(err, lv.SelectedRows())
+	}
+}
+
+func TestSort(t *testing.T) {
+	src := sortableRows{newRows()}
+	lv := newListView(t, &listview.Spec{Columns: columns, Source: src})
+	sortArrow := func(col int) win32.INT {
+		header, _ := win32.SendMessageW(lv.HWND(), win32.LVM_GETHEADER, 0, 0)
+		item := win32.HDITEMW{Mask: win32.HDI_FORMAT}
+		win32.SendMessageW(win32.HWND(header), win32.HDM_GETITEMW, win32.WPARAM(col), win32.LPARAM(uintptr(unsafe.Pointer(&item))))
+		return item.Fmt & (win32.HDF_SORTUP | win32.HDF_SORTDOWN)
+	}
+	if col, _ := lv.SortColumn(); col != -1 {
+		t.Fatal(col)
+	}
+	lv.Select(1)
+	if err := backend.ListViewColumnClick(lv.HWND(), 0); err != nil {
+		t.Fatal(err)
+	}
+	if col, asc := lv.SortColumn(); col != 0 || !asc || cell(lv, 0, 0) != "a" || sortArrow(0) != win32.HDF_SORTUP {
+		t.Fatal(col, asc, cell(lv, 0, 0), sortArrow(0))
+	}
+	if len(lv.SelectedRows()) != 0 {
+		t.Fatal(lv.SelectedRows())
+	}
+	backend.ListViewColumnClick(lv.HWND(), 0)
+	if cell(lv, 0, 0) != "c" || sortArrow(0) != win32.HDF_SORTDOWN {
+		t.Fatal(cell(lv, 0, 0), sortArrow(0))
+	}
+	backend.ListViewColumnClick(lv.HWND(), 1)
+	if sortArrow(0) != 0 || sortArrow(1) != win32.HDF_SORTUP {
+		t.Fatal(sortArrow(0), sortArrow(1))
+	}
+	if !slices.Equal(src.sorts, []string{"0true", "0false", "1true"}) {
+		t.Fatal(src.sorts)
+	}
+
+	// Not sortable.
+	lv.SetDataSource(newRows())
+	if col, _ := lv.SortColumn(); col != -1 || sortArrow(1) != 0 {
+		t.Fatal(col, sortArrow(1))
+	}
+	if err := lv.SortBy(0, true); err != listview.ErrFailed {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckboxes(t *testing.T) {
+	src := checkableRows{newRows()}
+	src.checked[1] = true
+	lv := newListView(t, &listview.Spec{Columns: columns, Source: src})
+	checked := func(row int) bool {
+		state, _ := win32.SendMessageW(lv.HWND(), win32.LVM_GETITEMSTATE, win32.WPARAM(row), win32.LVIS_STATEIMAGEMASK)
+		return win32.UINT(state) == win32.INDEXTOSTATEIMAGEMASK(2)
+	}
+	if exStyle, _ := win32.SendMessageW(lv.HWND(), win32.LVM_GETEXTENDEDLISTVIEWSTYLE, 0, 0); exStyle&win32.LVS_EX_CHECKBOXES == 0 {
+		t.Fatalf("%#x", exStyle)
+	}
+	if checked(0) || !checked(1) {
+		t.Fatal(checked(0), checked(1))
+	}
+	backend.ListViewClick(lv.HWND(), 4, 4, false) // The checkbox of row 0.
+	if !src.checked[0] || !checked(0) {
+		t.Fatal(src.checked)
+	}
+	backend.ListViewClick(lv.HWND(), 40, 4, false) // The label of row 0.
+	if !src.checked[0] {
+		t.Fatal(src.checked)
+	}
+	lv.SetRowSelected(2, true)
+	backend.ListViewKeyDown(lv.HWND(), win32.VK_SPACE) // Rows 0 and 2.
+	if src.checked[0] || src.checked[2] || !src.checked[1] {
+		t.Fatal(src.checked)
+	}
+
+	lv.SetDataSource(newRows())
+	if exStyle, _ := win32.SendMessageW(lv.HWND(), win32.LVM_GETEXTENDEDLISTVIEWSTYLE, 0, 0); exStyle&win32.LVS_EX_CHECKBOXES != 0 {
+		t.Fatalf("%#x", exStyle)
+	}
+}
+
+func TestImages(t *testing.T) {
+	images, err := imagelist.New(16, 16, win32.ILC_COLOR32|win32.ILC_MASK)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer images.Release()
+	for _, icon := range []win32.HICON{1, 2} {
+		if _, err := images.AddIcon(icon); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if images.Len() != 2 {
+		t.Fatal(images.Len())
+	}
+	lv := newListView(t, &listview.Spec{Columns: columns, Source: newRows(), SmallImages: images})
+	if h, _ := win32.SendMessageW(lv.HWND(), win32.LVM_SETIMAGELIST, win32.LVSIL_SMALL, win32.LPARAM(images.HIMAGELIST())); win32.HIMAGELIST(h) != images.HIMAGELIST() {
+		t.Fatal(h)
+	}
+	image := func(row, col int) win32.INT {
+		item := win32.LVITEMW{Mask: win32.LVIF_IMAGE, Item: win32.INT(row), SubItem: win32.INT(col)}
+		win32.SendMessageW(lv.HWND(), win32.LVM_GETITEMW, 0, win32.LPARAM(uintptr(unsafe.Pointer(&item))))
+		return item.Image
+	}
+	if image(0, 0) != 0 || image(1, 0) != 1 || image(1, 1) != win32.I_IMAGENONE {
+		t.Fatal(image(0, 0), image(1, 0), image(1, 1))
+	}
+}
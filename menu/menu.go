@@ -13,22 +13,36 @@ import (
 	"github.com/mkch/gg"
 	"github.com/mkch/gw/internal"
 	"github.com/mkch/gw/internal/objectmap"
+	"github.com/mkch/gw/internal/uithread"
 	"github.com/mkch/gw/win32"
 	"github.com/mkch/gw/win32/win32util"
 )
 
-var itemMap = objectmap.New[*Item](internal.MinMenuItemID, internal.MaxMenuItemID)
+var itemMaps = uithread.NewLocal(func() *objectmap.ObjectMap[*Item] {
+	return objectmap.New[*Item](internal.MinMenuItemID, internal.MaxMenuItemID)
+}, nil)
+
+// itemMap returns the menu items of the calling thread.
+func itemMap() *objectmap.ObjectMap[*Item] {
+	return itemMaps.Get()
+}
 
 // OnWmCommand handles menu commands.
 // Called by the default WndProc of window.
 func OnWmCommand(id win32.WORD) bool {
-	if item, ok := itemMap.Value(objectmap.Handle(id)); ok {
+	uithread.Check()
+	if item, ok := itemMap().Value(objectmap.Handle(id)); ok {
 		return item.CallOnClick()
 	}
 	return false
 }
 
-var menuMap = make(map[win32.HMENU]*Menu)
+var menuMaps = uithread.NewLocal(func() map[win32.HMENU]*Menu { return make(map[win32.HMENU]*Menu) }, nil)
+
+// menuMap returns the menus of the calling thread.
+func menuMap() map[win32.HMENU]*Menu {
+	return menuMaps.Get()
+}
 
 type Menu struct {
 	// OnAccelKeyChanged is called when the accelerator key of any item
@@ -37,11 +51,13 @@ type Menu struct {
 	h                 win32.HMENU
 	parent            *Item
 	popup             bool
+	thread            win32.DWORD // The UI thread owning the menu.
 }
 
 // AccelKeyTable returns all accelerator keys in this menu and its submenus.
 // The order of ACCEL is unspecified.
 func (m *Menu) AccelKeyTable() ([]win32.ACCEL, error) {
+	uithread.CheckThread(m.thread)
 	count, err := m.ItemCount()
 	if err != nil {
 		return nil, err
@@ -89,11 +105,13 @@ func (m *Menu) callAccelKeyChanged() error {
 }
 
 func New(popup bool) *Menu {
+	uithread.Check()
 	r := &Menu{
 		h:      gg.If(popup, gg.Must(win32.CreatePopupMenu()), gg.Must(win32.CreateMenu())),
 		parent: nil,
-		popup:  popup}
-	menuMap[r.h] = r
+		popup:  popup,
+		thread: win32.GetCurrentThreadId()}
+	menuMap()[r.h] = r
 	return r
 }
 
@@ -106,6 +124,7 @@ func (m *Menu) HMENU() win32.HMENU {
 }
 
 func (m *Menu) ItemCount() (int, error) {
+	uithread.CheckThread(m.thread)
 	if count, err := win32.GetMenuItemCount(m.h); err != nil {
 		return 0, err
 	} else {
@@ -114,6 +133,7 @@ func (m *Menu) ItemCount() (int, error) {
 }
 
 func (m *Menu) Item(i int) (*Item, error) {
+	uithread.CheckThread(m.thread)
 	var mii = win32.MENUITEMINFOW{
 		Size: win32.UINT(unsafe.Sizeof(win32.MENUITEMINFOW{})),
 		Mask: win32.MIIM_ID,
@@ -121,7 +141,7 @@ func (m *Menu) Item(i int) (*Item, error) {
 	if err := win32.GetMenuItemInfoW(m.h, win32.UINT(i), true, &mii); err != nil {
 		return nil, err
 	}
-	if item, ok := itemMap.Value(objectmap.Handle(mii.ID)); !ok {
+	if item, ok := itemMap().Value(objectmap.Handle(mii.ID)); !ok {
 		panic("no this item")
 	} else {
 		return item, nil
@@ -132,6 +152,7 @@ func (m *Menu) Item(i int) (*Item, error) {
 // Use Item.SetSubmenu(nil) before deleting if the submenu
 // is intended to be used later.
 func (m *Menu) DeleteItem(item *Item) error {
+	uithread.CheckThread(m.thread)
 	if item.Menu() != m {
 		return errors.New("invalid item")
 	}
@@ -149,12 +170,13 @@ func (m *Menu) DeleteItem(item *Item) error {
 		return err
 	}
 
-	itemMap.Remove(objectmap.Handle(item.ID()))
+	itemMap().Remove(objectmap.Handle(item.ID()))
 	item.invalidate()
 	return nil
 }
 
 func (m *Menu) DeleteItemIndex(index int) error {
+	uithread.CheckThread(m.thread)
 	var mii = win32.MENUITEMINFOW{
 		Size: win32.UINT(unsafe.Sizeof(win32.MENUITEMINFOW{})),
 		Mask: win32.MIIM_ID,
@@ -162,13 +184,14 @@ func (m *Menu) DeleteItemIndex(index int) error {
 	if err := win32.GetMenuItemInfoW(m.h, win32.UINT(index), true, &mii); err != nil {
 		return err
 	}
-	item, _ := itemMap.Value(objectmap.Handle(mii.ID))
+	item, _ := itemMap().Value(objectmap.Handle(mii.ID))
 	return m.DeleteItem(item)
 }
 
 // Destroy destroys a Menu and releases all resources it uses.
 // All its submenus(if any) will be destroyed recursively.
 func (m *Menu) Destroy() error {
+	uithread.CheckThread(m.thread)
 	if m.h == 0 {
 		return nil
 	}
@@ -186,7 +209,7 @@ func (m *Menu) Destroy() error {
 	if err := win32.DestroyMenu(m.h); err != nil {
 		return err
 	}
-	delete(menuMap, m.h)
+	delete(menuMap(), m.h)
 	m.h = 0
 
 	return nil
@@ -205,6 +228,7 @@ type ItemSpec struct {
 // InsertItem inserts an item before some item.
 // If indexBefore is -1, the new item will be appended to the end of m.
 func (m *Menu) InsertItem(indexBefore int, spec *ItemSpec) (*Item, error) {
+	uithread.CheckThread(m.thread)
 	var err error
 	if indexBefore == -1 {
 		if indexBefore, err = m.ItemCount(); err != nil {
@@ -218,11 +242,11 @@ func (m *Menu) InsertItem(indexBefore int, spec *ItemSpec) (*Item, error) {
 		hSubmenu = spec.Submenu.h
 	}
 
-	var item = &Item{OnClick: spec.OnClick, title: spec.Title, menu: m}
-	item.id = win32.WORD(itemMap.Add(item))
+	var item = &Item{OnClick: spec.OnClick, title: spec.Title, menu: m, thread: m.thread}
+	item.id = win32.WORD(itemMap().Add(item))
 	for item.id == win32.IDTIMEOUT {
-		itemMap.Remove(objectmap.Handle(item.id))
-		item.id = win32.WORD(itemMap.Add(item))
+		itemMap().Remove(objectmap.Handle(item.id))
+		item.id = win32.WORD(itemMap().Add(item))
 	}
 
 	if err = win32.InsertMenuItemW(m.h, win32.UINT(indexBefore), true, &win32.MENUITEMINFOW{
@@ -244,6 +268,7 @@ func (m *Menu) InsertItem(indexBefore int, spec *ItemSpec) (*Item, error) {
 }
 
 func (m *Menu) InsertSeparator(indexBefore int) (*Item, error) {
+	uithread.CheckThread(m.thread)
 	return m.InsertItem(indexBefore, &ItemSpec{Separator: true})
 }
 
@@ -289,10 +314,12 @@ type Item struct {
 	menu     *Menu
 	id       win32.WORD
 	accelKey AccelKey
-	title    string //title without accelerator key
+	title    string      //title without accelerator key
+	thread   win32.DWORD // The UI thread owning the menu of the item.
 }
 
 func (item *Item) SetAccelKey(accel AccelKey) error {
+	uithread.CheckThread(item.thread)
 	if accel != item.accelKey {
 		item.accelKey = accel
 		// http://stackoverflow.com/questions/23592079/why-does-createacceleratortable-not-work-without-fvirtkey
@@ -310,10 +337,12 @@ func (item *Item) SetAccelKey(accel AccelKey) error {
 }
 
 func (item *Item) AccelKey() AccelKey {
+	uithread.CheckThread(item.thread)
 	return item.accelKey
 }
 
 func (item *Item) Menu() *Menu {
+	uithread.CheckThread(item.thread)
 	return item.menu
 }
 
@@ -327,6 +356,7 @@ func (item *Item) invalidate() {
 }
 
 func (item *Item) Separator() (bool, error) {
+	uithread.CheckThread(item.thread)
 	var mii = win32.MENUITEMINFOW{
 		Size: win32.UINT(unsafe.Sizeof(win32.MENUITEMINFOW{})),
 		Mask: win32.MIIM_FTYPE,
@@ -341,6 +371,7 @@ func (item *Item) Separator() (bool, error) {
 // If SetSeparator(false) is called on a separator item, the
 // item is changed to a disabled string item.
 func (item *Item) SetSeparator(sep bool) error {
+	uithread.CheckThread(item.thread)
 	var mii = win32.MENUITEMINFOW{
 		Size: win32.UINT(unsafe.Sizeof(win32.MENUITEMINFOW{})),
 		Mask: win32.MIIM_FTYPE,
@@ -359,6 +390,7 @@ func (item *Item) SetSeparator(sep bool) error {
 }
 
 func (item *Item) CallOnClick() bool {
+	uithread.CheckThread(item.thread)
 	if item.OnClick == nil {
 		return false
 	}
@@ -367,11 +399,13 @@ func (item *Item) CallOnClick() bool {
 }
 
 func (item *Item) Title() string {
+	uithread.CheckThread(item.thread)
 	return item.title
 }
 
 // Title with accelerator key.
 func (item *Item) DisplayTitle() (string, error) {
+	uithread.CheckThread(item.thread)
 	var mii = win32.MENUITEMINFOW{
 		Size: win32.UINT(unsafe.Sizeof(win32.MENUITEMINFOW{})),
 		Mask: win32.MIIM_TYPE, // Retrieve Cch.
@@ -382,6 +416,7 @@ func (item *Item) DisplayTitle() (string, error) {
 	var buf = make([]win32.WCHAR, mii.Cch+1)
 	mii.Mask = win32.MIIM_STRING
 	mii.TypeData = &buf[0]
+	mii.Cch = win32.UINT(len(buf)) // Size of buf, including the terminating null.
 	if err := win32.GetMenuItemInfoW(item.menu.h, win32.UINT(item.id), false, &mii); err != nil {
 		return "", err
 	}
@@ -393,6 +428,7 @@ func itemDisplayTitle(title string, accelKey AccelKey) string {
 }
 
 func (item *Item) SetTitle(title string) error {
+	uithread.CheckThread(item.thread)
 	item.title = title
 	displayTitle := itemDisplayTitle(item.title, item.accelKey)
 	var buf []win32.WCHAR
@@ -405,6 +441,7 @@ func (item *Item) SetTitle(title string) error {
 }
 
 func (item *Item) Checked() (bool, error) {
+	uithread.CheckThread(item.thread)
 	var mii = win32.MENUITEMINFOW{
 		Size: win32.UINT(unsafe.Sizeof(win32.MENUITEMINFOW{})),
 		Mask: win32.MIIM_STATE,
@@ -416,6 +453,7 @@ func (item *Item) Checked() (bool, error) {
 }
 
 func (item *Item) SetChecked(checked bool) error {
+	uithread.CheckThread(item.thread)
 	var mii = win32.MENUITEMINFOW{
 		Size: win32.UINT(unsafe.Sizeof(win32.MENUITEMINFOW{})),
 		Mask: win32.MIIM_STATE,
@@ -432,6 +470,7 @@ func (item *Item) SetChecked(checked bool) error {
 }
 
 func (item *Item) Disabled() (bool, error) {
+	uithread.CheckThread(item.thread)
 	var mii = win32.MENUITEMINFOW{
 		Size: win32.UINT(unsafe.Sizeof(win32.MENUITEMINFOW{})),
 		Mask: win32.MIIM_STATE,
@@ -445,6 +484,7 @@ func (item *Item) Disabled() (bool, error) {
 // SetDisabled sets the disabled state of item.
 // Has no effect on separators.
 func (item *Item) SetDisabled(disabled bool) error {
+	uithread.CheckThread(item.thread)
 	var mii = win32.MENUITEMINFOW{
 		Size: win32.UINT(unsafe.Sizeof(win32.MENUITEMINFOW{})),
 		Mask: win32.MIIM_STATE,
@@ -461,6 +501,7 @@ func (item *Item) SetDisabled(disabled bool) error {
 }
 
 func (item *Item) Submenu() (*Menu, error) {
+	uithread.CheckThread(item.thread)
 	var mii = win32.MENUITEMINFOW{
 		Size: win32.UINT(unsafe.Sizeof(win32.MENUITEMINFOW{})),
 		Mask: win32.MIIM_SUBMENU,
@@ -468,10 +509,11 @@ func (item *Item) Submenu() (*Menu, error) {
 	if err := win32.GetMenuItemInfoW(item.menu.h, win32.UINT(item.id), false, &mii); err != nil {
 		return nil, err
 	}
-	return menuMap[mii.SubMenu], nil
+	return menuMap()[mii.SubMenu], nil
 }
 
 func (item *Item) SetSubmenu(menu *Menu) error {
+	uithread.CheckThread(item.thread)
 	oldSubmenu, err := item.Submenu()
 	if err != nil {
 		return err
@@ -0,0 +1,148 @@
+package menu_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/mkch/gw/menu"
+	"github.com/mkch/gw/win32"
+	"github.com/mkch/gw/win32/fake"
+)
+
+var backend = fake.New()
+
+func TestMain(m *testing.M) {
+	win32.SetBackend(backend)
+	os.Exit(m.Run())
+}
+
+func items(t *testing.T, m *menu.Menu) []fake.MenuItem {
+	t.Helper()
+	items, err := backend.MenuItems(m.HMENU())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return items
+}
+
+func TestInsertItem(t *testing.T) {
+	m := menu.New(true)
+	defer m.Destroy()
+
+	open, err := m.InsertItem(-1, &menu.ItemSpec{Title: "Open", AccelKey: menu.AccelKey{Mod: menu.ModCtrl, VKeyCode: 'O'}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.InsertSeparator(-1); err != nil {
+		t.Fatal(err)
+	}
+	exit, err := m.InsertItem(0, &menu.ItemSpec{Title: "Exit", Checked: true, Disabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := m.ItemCount(); err != nil || n != 3 {
+		t.Fatal(n, err)
+	}
+	got := items(t, m)
+	if got[0].ID != win32.UINT(exit.ID()) || !got[0].Checked || !got[0].Disabled {
+		t.Fatal(got[0])
+	}
+	if got[1].ID != win32.UINT(open.ID()) || got[1].Text != "Open\tCtrl+O" {
+		t.Fatal(got[1])
+	}
+	if !got[2].Separator {
+		t.Fatal(got[2])
+	}
+	if title, err := open.DisplayTitle(); err != nil || title != "Open\tCtrl+O" {
+		t.Fatalf("%q %v", title, err)
+	}
+	if item, err := m.Item(1); err != nil || item != open {
+		t.Fatal(item, err)
+	}
+
+	if err := open.SetChecked(true); err != nil {
+		t.Fatal(err)
+	}
+	if err := exit.SetDisabled(false); err != nil {
+		t.Fatal(err)
+	}
+	if err := open.SetTitle("Open..."); err != nil {
+		t.Fatal(err)
+	}
+	got = items(t, m)
+	if !got[1].Checked || got[1].Text != "Open...\tCtrl+O" || got[0].Disabled {
+		t.Fatal(got)
+	}
+	if checked, err := open.Checked(); err != nil || !checked {
+		t.Fatal(checked, err)
+	}
+
+	if err := m.DeleteItem(exit); err != nil {
+		t.Fatal(err)
+	}
+	if got := items(t, m); len(got) != 2 || got[0].ID != win32.UINT(open.ID()) {
+		t.Fatal(got)
+	}
+}
+
+func TestSubmenu(t *testing.T) {
+	m := menu.New(false)
+	file, _ := m.InsertItem(-1, &menu.ItemSpec{Title: "File"})
+	sub := menu.New(true)
+	save, _ := sub.InsertItem(-1, &menu.ItemSpec{Title: "Save", AccelKey: menu.AccelKey{Mod: menu.ModCtrl, VKeyCode: 'S'}})
+	if err := file.SetSubmenu(sub); err != nil {
+		t.Fatal(err)
+	}
+	if got := items(t, m); got[0].Submenu != sub.HMENU() {
+		t.Fatal(got)
+	}
+	if s, err := file.Submenu(); err != nil || s != sub {
+		t.Fatal(s, err)
+	}
+	table, err := m.AccelKeyTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(table) != 1 || table[0].Cmd != save.ID() || table[0].Key != 'S' || table[0].Virt != win32.FCONTROL|win32.FVIRTKEY {
+		t.Fatal(table)
+	}
+
+	// Detach the submenu, the item is kept.
+	if err := file.SetSubmenu(nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := items(t, m); got[0].Submenu != 0 || got[0].ID != win32.UINT(file.ID()) {
+		t.Fatalf("%+v", got)
+	}
+	if _, err := backend.MenuItems(sub.HMENU()); err != nil {
+		t.Fatal("submenu destroyed", err)
+	}
+
+	// Destroying a menu destroys its submenus.
+	file.SetSubmenu(sub)
+	hSub := sub.HMENU()
+	if err := m.Destroy(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := backend.MenuItems(hSub); err == nil {
+		t.Fatal("submenu not destroyed")
+	}
+	if m.HMENU() != 0 {
+		t.Fatal(m.HMENU())
+	}
+}
+
+func TestOnWmCommand(t *testing.T) {
+	m := menu.New(true)
+	defer m.Destroy()
+	clicked := 0
+	item, _ := m.InsertItem(-1, &menu.ItemSpec{Title: "Click", OnClick: func() { clicked++ }})
+	if !menu.OnWmCommand(item.ID()) || clicked != 1 {
+		t.Fatal(clicked)
+	}
+	m.DeleteItem(item)
+	if menu.OnWmCommand(item.ID()) || clicked != 1 {
+		t.Fatal(clicked)
+	}
+}
@@ -3,7 +3,6 @@
 package metrics
 
 import (
-	"github.com/mkch/gg"
 	"github.com/mkch/gw/win32"
 )
 
@@ -43,11 +42,6 @@ func (dim Dimension) Px(dpi win32.UINT) win32.INT {
 	return FromDefaultDPI(dim.Value, dpi)
 }
 
-// WindowPx converts the dimension to physical pixels in the DPI of the given window.
-func (dim Dimension) WidowPx(hwnd win32.HWND) win32.INT {
-	return dim.Px(gg.Must(win32.GetDpiForWindow(hwnd)))
-}
-
 // Px creates a Dimension in physical pixels.
 func Px(n win32.INT) Dimension {
 	return Dimension{n, PX}
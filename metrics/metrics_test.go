@@ -0,0 +1,33 @@
+package metrics_test
+
+import (
+	"testing"
+
+	"github.com/mkch/gw/metrics"
+	"github.com/mkch/gw/win32"
+)
+
+func TestDPIConv(t *testing.T) {
+	for _, test := range []struct {
+		value          win32.INT
+		oldDPI, newDPI win32.UINT
+		want           win32.INT
+	}{
+		{10, 96, 96, 10},
+		{10, 96, 144, 15},
+		{11, 96, 120, 14}, // 13.75
+		{5, 96, 144, 8},   // 7.5 rounds away from zero.
+		{-5, 96, 144, -8},
+		{1, 0, 96, -1},
+	} {
+		if got := metrics.DPIConv(test.value, test.oldDPI, test.newDPI); got != test.want {
+			t.Errorf("DPIConv(%v, %v, %v) = %v, want %v", test.value, test.oldDPI, test.newDPI, got, test.want)
+		}
+	}
+	if got := metrics.Dip(10).Px(192); got != 20 {
+		t.Errorf("got %v", got)
+	}
+	if got := metrics.Px(10).Px(192); got != 10 {
+		t.Errorf("got %v", got)
+	}
+}
@@ -0,0 +1,11 @@
+package metrics
+
+import (
+	"github.com/mkch/gg"
+	"github.com/mkch/gw/win32"
+)
+
+// WindowPx converts the dimension to physical pixels in the DPI of the given window.
+func (dim Dimension) WidowPx(hwnd win32.HWND) win32.INT {
+	return dim.Px(gg.Must(win32.GetDpiForWindow(hwnd)))
+}
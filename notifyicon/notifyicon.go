@@ -4,6 +4,7 @@ import (
 	"unsafe"
 
 	"github.com/mkch/gw/internal/appmsg"
+	"github.com/mkch/gw/internal/uithread"
 	"github.com/mkch/gw/notifyicon/sys"
 	"github.com/mkch/gw/win32"
 	"github.com/mkch/gw/win32/win32util"
@@ -96,6 +97,7 @@ type Modifier struct {
 
 // Apply applies the change.
 func (m *Modifier) Apply() error {
+	uithread.CheckWindow(m.data.Wnd)
 	return sys.Shell_NotifyIconW(sys.NIM_MODIFY, &m.data)
 }
 
@@ -143,6 +145,7 @@ type NotifyIcon struct {
 // New adds an icon to taskbar's status area.
 // An NotifyIcon is identified by a window and an ID.
 func New(w *window.Window, id win32.WORD, spec *Spec) (*NotifyIcon, error) {
+	uithread.CheckWindow(w.HWND())
 	if id == 0 {
 		panic("id must > 0") // For [ParseCallback] HACK.
 	}
@@ -167,6 +170,7 @@ func New(w *window.Window, id win32.WORD, spec *Spec) (*NotifyIcon, error) {
 
 // StartModify returns a [Modifier] which can be used to modify the notify icon identified by w and id.
 func (icon *NotifyIcon) StartModify() *Modifier {
+	uithread.CheckWindow(icon.w)
 	var ret Modifier
 	ret.data.Size = win32.DWORD(unsafe.Sizeof(ret.data))
 	ret.data.ID = icon.id
@@ -181,6 +185,7 @@ func (icon *NotifyIcon) ID() win32.UINT {
 
 // Delete removes the icon.
 func (icon *NotifyIcon) Delete() error {
+	uithread.CheckWindow(icon.w)
 	return sys.Shell_NotifyIconW(sys.NIM_DELETE,
 		&sys.NOTIFYICONDATAW{
 			Size: win32.DWORD(unsafe.Sizeof(sys.NOTIFYICONDATAW{})),
@@ -194,6 +199,7 @@ func (icon *NotifyIcon) Delete() error {
 // For example, if the icon displays a shortcut menu, but the user presses ESC to cancel it, this method
 // to return focus to the notification area.
 func (icon *NotifyIcon) SetFocus() error {
+	uithread.CheckWindow(icon.w)
 	return sys.Shell_NotifyIconW(sys.NIM_SETFOCUS,
 		&sys.NOTIFYICONDATAW{
 			Size: win32.DWORD(unsafe.Sizeof(sys.NOTIFYICONDATAW{})),
@@ -1,13 +1,23 @@
 package withdpi
 
 import (
+	"os"
+	"runtime"
 	"testing"
 	"unsafe"
 
 	"github.com/mkch/gw/metrics"
 	"github.com/mkch/gw/win32"
+	"github.com/mkch/gw/win32/fake"
 )
 
+func TestMain(m *testing.M) {
+	if runtime.GOOS != "windows" {
+		win32.SetBackend(fake.New())
+	}
+	os.Exit(m.Run())
+}
+
 type LogFont = LogStruct[win32.LOGFONTW, win32.HFONT]
 type Font = Object[win32.LOGFONTW, win32.HFONT]
 
@@ -35,6 +35,7 @@ func New(parent win32.HWND, spec *Spec) (*Panel, error) {
 			WndProc: func(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) win32.LRESULT {
 				return win32.DefWindowProcW(hwnd, message, wParam, lParam)
 			},
+			Style:  win32.CS_DBLCLKS,
 			Cursor: gg.Must(win32.LoadImageW_uintptr[win32.HCURSOR](0, uintptr(win32.OCR_NORMAL), win32.IMAGE_CURSOR, 0, 0, win32.LR_DEFAULTSIZE|win32.LR_SHARED)),
 		}))
 		classRegistered = true
@@ -0,0 +1,121 @@
+package pointer
+
+import (
+	"github.com/mkch/gw/metrics"
+	"github.com/mkch/gw/win32"
+	"github.com/mkch/gw/window"
+)
+
+// Handler handles the pointer events and gestures of a window.
+type Handler struct {
+	OnDown   func(e *Event)
+	OnUpdate func(e *Event)
+	OnUp     func(e *Event)
+	// OnEnter and OnLeave are called when the pointer enters and leaves the
+	// window, or comes in and out of the detection range.
+	OnEnter func(e *Event)
+	OnLeave func(e *Event)
+	// OnGesture is called for the gestures. All gestures are enabled if
+	// OnGesture is not nil when Attach is called.
+	OnGesture func(e *GestureEvent)
+}
+
+// origin returns the screen coordinates of the client area origin of hwnd.
+func origin(hwnd win32.HWND) (pt win32.POINT, err error) {
+	err = win32.ClientToScreen(hwnd, &pt)
+	return
+}
+
+// Read reads the pointer information of a WM_POINTER* message and returns the event.
+func Read(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM) (e *Event, err error) {
+	o, err := origin(hwnd)
+	if err != nil {
+		return
+	}
+	id := win32.GET_POINTERID_WPARAM(wParam)
+	t, err := win32.GetPointerType(id)
+	if err != nil {
+		return
+	}
+	switch t {
+	case win32.PT_PEN:
+		var info win32.POINTER_PEN_INFO
+		if err = win32.GetPointerPenInfo(id, &info); err != nil {
+			return
+		}
+		e = DecodePen(message, &info, o)
+	case win32.PT_TOUCH:
+		var info win32.POINTER_TOUCH_INFO
+		if err = win32.GetPointerTouchInfo(id, &info); err != nil {
+			return
+		}
+		e = DecodeTouch(message, &info, o)
+	default:
+		var info win32.POINTER_INFO
+		if err = win32.GetPointerInfo(id, &info); err != nil {
+			return
+		}
+		e = Decode(message, &info, o)
+	}
+	e.DipX, e.DipY = e.X, e.Y
+	if dpi, err := win32.GetDpiForWindow(hwnd); err == nil {
+		e.DipX = int(metrics.DPIConv(win32.INT(e.X), dpi, win32.USER_DEFAULT_SCREEN_DPI))
+		e.DipY = int(metrics.DPIConv(win32.INT(e.Y), dpi, win32.USER_DEFAULT_SCREEN_DPI))
+	}
+	return
+}
+
+// Attach attaches h to w. The pointer messages without handlers, or not handled,
+// are passed to the previous window procedure.
+func Attach(w *window.WindowBase, h *Handler) error {
+	if h.OnGesture != nil {
+		if err := win32.SetGestureConfig(w.HWND(), []win32.GESTURECONFIG{{ID: 0, Want: win32.GC_ALLGESTURES}}); err != nil {
+			return err
+		}
+	}
+	var tracker GestureTracker
+	w.SetWndProc(func(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM, prevWndProc win32.WndProc) win32.LRESULT {
+		var handler func(e *Event)
+		switch message {
+		case win32.WM_POINTERDOWN:
+			handler = h.OnDown
+		case win32.WM_POINTERUPDATE:
+			handler = h.OnUpdate
+		case win32.WM_POINTERUP:
+			handler = h.OnUp
+		case win32.WM_POINTERENTER:
+			handler = h.OnEnter
+		case win32.WM_POINTERLEAVE:
+			handler = h.OnLeave
+		case win32.WM_GESTURE:
+			if h.OnGesture == nil {
+				break
+			}
+			var info win32.GESTUREINFO
+			if win32.GetGestureInfo(win32.HGESTUREINFO(lParam), &info) != nil {
+				break
+			}
+			o, err := origin(hwnd)
+			if err != nil {
+				break
+			}
+			if e, ok := tracker.Decode(&info, o); ok {
+				h.OnGesture(e)
+				if e.Handled {
+					win32.CloseGestureInfoHandle(win32.HGESTUREINFO(lParam))
+					return 0
+				}
+			}
+		}
+		if handler != nil {
+			if e, err := Read(hwnd, message, wParam); err == nil {
+				handler(e)
+				if e.Handled {
+					return 0
+				}
+			}
+		}
+		return prevWndProc(hwnd, message, wParam, lParam)
+	})
+	return nil
+}
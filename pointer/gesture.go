@@ -0,0 +1,128 @@
+package pointer
+
+import (
+	"math"
+
+	"github.com/mkch/gw/win32"
+)
+
+// Gesture is a kind of gesture.
+type Gesture uint8
+
+const (
+	Pan Gesture = iota + 1
+	Zoom
+	Rotate
+	TwoFingerTap
+	PressAndTap
+)
+
+func (g Gesture) String() string {
+	switch g {
+	case Pan:
+		return "pan"
+	case Zoom:
+		return "zoom"
+	case Rotate:
+		return "rotate"
+	case TwoFingerTap:
+		return "two-finger tap"
+	case PressAndTap:
+		return "press and tap"
+	}
+	return "unknown"
+}
+
+var gestures = map[win32.DWORD]Gesture{
+	win32.GID_PAN:          Pan,
+	win32.GID_ZOOM:         Zoom,
+	win32.GID_ROTATE:       Rotate,
+	win32.GID_TWOFINGERTAP: TwoFingerTap,
+	win32.GID_PRESSANDTAP:  PressAndTap,
+}
+
+// GestureEvent is a gesture event.
+type GestureEvent struct {
+	Gesture Gesture
+	Begin   bool // The gesture is beginning.
+	End     bool // The gesture is ending.
+	Inertia bool // The gesture has triggered inertia.
+	// Client coordinates of the gesture: the center of the fingers of Pan, Zoom and
+	// TwoFingerTap, the pivot of Rotate, and the first finger of PressAndTap.
+	X, Y int
+	// Pan distance since the previous event.
+	DX, DY int
+	// Zoom factor since the beginning of the gesture, and since the previous event.
+	Scale, ScaleDelta float64
+	// Rotation in radians since the beginning of the gesture, and since the previous event.
+	// Positive values are counterclockwise.
+	Angle, AngleDelta float64
+	// Handled, if set by the event handler, suppresses the default processing.
+	Handled bool
+}
+
+// GestureTracker decodes the successive GESTUREINFO of a window to GestureEvents.
+// The zero value is ready to use.
+type GestureTracker struct {
+	gesture  Gesture
+	location win32.POINTS
+	distance float64 // Zoom distance at the beginning.
+	scale    float64
+	angle    float64
+}
+
+// rotateAngle returns the angle of GID_ROTATE arguments in radians.
+// See GID_ROTATE_ANGLE_FROM_ARGUMENT.
+func rotateAngle(arg win32.UINT64) float64 {
+	return float64(uint16(arg))/65535*4*math.Pi - 2*math.Pi
+}
+
+// Decode returns the event of info. origin is the screen coordinates of the
+// client area origin of the window. The ok result is false for GID_BEGIN,
+// GID_END and unknown gestures.
+func (t *GestureTracker) Decode(info *win32.GESTUREINFO, origin win32.POINT) (e *GestureEvent, ok bool) {
+	g := gestures[info.ID]
+	if g == 0 {
+		return nil, false
+	}
+	e = &GestureEvent{
+		Gesture:    g,
+		Begin:      info.Flags&win32.GF_BEGIN != 0,
+		End:        info.Flags&win32.GF_END != 0,
+		Inertia:    info.Flags&win32.GF_INERTIA != 0,
+		X:          int(win32.LONG(info.Location.X) - origin.X),
+		Y:          int(win32.LONG(info.Location.Y) - origin.Y),
+		Scale:      1,
+		ScaleDelta: 1,
+	}
+	if e.Begin || t.gesture != g {
+		*t = GestureTracker{gesture: g, location: info.Location, scale: 1}
+		switch g {
+		case Zoom:
+			t.distance = float64(uint32(info.Arguments))
+		case Rotate:
+			// The arguments of the beginning is the absolute angle.
+			return e, true
+		}
+	}
+	switch g {
+	case Pan:
+		e.DX = int(info.Location.X) - int(t.location.X)
+		e.DY = int(info.Location.Y) - int(t.location.Y)
+	case Zoom:
+		if t.distance > 0 {
+			e.Scale = float64(uint32(info.Arguments)) / t.distance
+			e.ScaleDelta = e.Scale / t.scale
+		}
+		t.scale = e.Scale
+	case Rotate:
+		e.Angle = rotateAngle(info.Arguments)
+		e.AngleDelta = e.Angle - t.angle
+		t.angle = e.Angle
+	}
+	t.location = info.Location
+	if e.End {
+		*t = GestureTracker{}
+	}
+	return e, true
+}
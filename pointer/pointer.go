@@ -0,0 +1,152 @@
+// Package pointer implements pointer(touch, pen etc.) input and gestures of windows.
+//
+// The decoding of the raw pointer and gesture information to events is pure Go.
+// Attach hooks the WM_POINTER* and WM_GESTURE messages of a window.
+package pointer
+
+import (
+	"github.com/mkch/gw/win32"
+)
+
+// Type is the type of a pointer device.
+type Type uint8
+
+const (
+	Unknown Type = iota
+	Touch
+	Pen
+	Mouse
+	Touchpad
+)
+
+func (t Type) String() string {
+	switch t {
+	case Touch:
+		return "touch"
+	case Pen:
+		return "pen"
+	case Mouse:
+		return "mouse"
+	case Touchpad:
+		return "touchpad"
+	}
+	return "unknown"
+}
+
+func typeOf(t win32.POINTER_INPUT_TYPE) Type {
+	switch t {
+	case win32.PT_TOUCH:
+		return Touch
+	case win32.PT_PEN:
+		return Pen
+	case win32.PT_MOUSE:
+		return Mouse
+	case win32.PT_TOUCHPAD:
+		return Touchpad
+	}
+	return Unknown
+}
+
+// maxPressure is the maximum pressure of POINTER_PEN_INFO and POINTER_TOUCH_INFO.
+const maxPressure = 1024
+
+// Event is a pointer event.
+type Event struct {
+	Message win32.UINT // WM_POINTERDOWN, WM_POINTERUPDATE, WM_POINTERUP etc.
+	ID      uint32     // Pointer ID, unique among the pointers in contact or in range.
+	Type    Type
+	Flags   win32.POINTER_FLAGS
+	// Client coordinates in PX and DIP.
+	X, Y       int
+	DipX, DipY int
+	// Pressure normalized to the range 0 to 1, or -1 if not reported.
+	Pressure float64
+	// Pen tilt in degrees, -90 to +90, and rotation in degrees, 0 to 359.
+	TiltX, TiltY int
+	Rotation     int
+	PenFlags     win32.PEN_FLAGS
+	// Touch contact area in client coordinates, empty if not reported.
+	Contact win32.RECT
+	// Touch orientation in degrees, 0 to 359.
+	Orientation int
+	// Handled, if set by the event handler, suppresses the default processing.
+	Handled bool
+}
+
+// Primary reports whether the pointer is the primary pointer, the first one in contact.
+func (e *Event) Primary() bool {
+	return e.Flags&win32.POINTER_FLAG_PRIMARY != 0
+}
+
+// InContact reports whether the pointer is in contact with the digitizer surface.
+func (e *Event) InContact() bool {
+	return e.Flags&win32.POINTER_FLAG_INCONTACT != 0
+}
+
+// Canceled reports whether the pointer input is canceled, such as by palm rejection.
+func (e *Event) Canceled() bool {
+	return e.Flags&win32.POINTER_FLAG_CANCELED != 0
+}
+
+// Eraser reports whether the eraser end of the pen is used.
+func (e *Event) Eraser() bool {
+	return e.PenFlags&(win32.PEN_FLAG_ERASER|win32.PEN_FLAG_INVERTED) != 0
+}
+
+// Barrel reports whether the barrel button of the pen is pressed.
+func (e *Event) Barrel() bool {
+	return e.PenFlags&win32.PEN_FLAG_BARREL != 0
+}
+
+// Decode returns the event of message of a generic pointer.
+// origin is the screen coordinates of the client area origin of the window.
+func Decode(message win32.UINT, info *win32.POINTER_INFO, origin win32.POINT) *Event {
+	return &Event{
+		Message:  message,
+		ID:       uint32(info.PointerId),
+		Type:     typeOf(info.PointerType),
+		Flags:    info.PointerFlags,
+		X:        int(info.PtPixelLocation.X - origin.X),
+		Y:        int(info.PtPixelLocation.Y - origin.Y),
+		Pressure: -1,
+	}
+}
+
+// DecodePen returns the event of message of a pen. See Decode.
+func DecodePen(message win32.UINT, info *win32.POINTER_PEN_INFO, origin win32.POINT) *Event {
+	e := Decode(message, &info.PointerInfo, origin)
+	e.PenFlags = info.PenFlags
+	if info.PenMask&win32.PEN_MASK_PRESSURE != 0 {
+		e.Pressure = float64(info.Pressure) / maxPressure
+	}
+	if info.PenMask&win32.PEN_MASK_ROTATION != 0 {
+		e.Rotation = int(info.Rotation)
+	}
+	if info.PenMask&win32.PEN_MASK_TILT_X != 0 {
+		e.TiltX = int(info.TiltX)
+	}
+	if info.PenMask&win32.PEN_MASK_TILT_Y != 0 {
+		e.TiltY = int(info.TiltY)
+	}
+	return e
+}
+
+// DecodeTouch returns the event of message of a touch. See Decode.
+func DecodeTouch(message win32.UINT, info *win32.POINTER_TOUCH_INFO, origin win32.POINT) *Event {
+	e := Decode(message, &info.PointerInfo, origin)
+	if info.TouchMask&win32.TOUCH_MASK_PRESSURE != 0 {
+		e.Pressure = float64(info.Pressure) / maxPressure
+	}
+	if info.TouchMask&win32.TOUCH_MASK_CONTACTAREA != 0 {
+		e.Contact = win32.RECT{
+			Left:   info.RcContact.Left - origin.X,
+			Top:    info.RcContact.Top - origin.Y,
+			Right:  info.RcContact.Right - origin.X,
+			Bottom: info.RcContact.Bottom - origin.Y,
+		}
+	}
+	if info.TouchMask&win32.TOUCH_MASK_ORIENTATION != 0 {
+		e.Orientation = int(info.Orientation)
+	}
+	return e
+}
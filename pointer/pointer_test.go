@@ -0,0 +1,199 @@
+package pointer_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/mkch/gw/metrics"
+	"github.com/mkch/gw/pointer"
+	"github.com/mkch/gw/win32"
+	"github.com/mkch/gw/win32/fake"
+	"github.com/mkch/gw/win32/fake/faketest"
+	"github.com/mkch/gw/window"
+)
+
+var backend = fake.New()
+
+func TestMain(m *testing.M) {
+	faketest.Main(m, backend)
+}
+
+func pump() {
+	var msg win32.MSG
+	for win32.PeekMessageW(&msg, 0, 0, 0, win32.PM_REMOVE) != 0 {
+		if !window.PreTranslateMessage(&msg) {
+			win32.TranslateMessage(&msg)
+			win32.DispatchMessageW(&msg)
+		}
+	}
+}
+
+func TestDecodePen(t *testing.T) {
+	info := win32.POINTER_PEN_INFO{
+		PointerInfo: win32.POINTER_INFO{
+			PointerType:     win32.PT_PEN,
+			PointerId:       3,
+			PointerFlags:    win32.POINTER_FLAG_INCONTACT | win32.POINTER_FLAG_PRIMARY,
+			PtPixelLocation: win32.POINT{X: 110, Y: 220},
+		},
+		PenFlags: win32.PEN_FLAG_BARREL,
+		PenMask:  win32.PEN_MASK_PRESSURE | win32.PEN_MASK_TILT_X,
+		Pressure: 512,
+		TiltX:    -30,
+		TiltY:    45, // Not in mask.
+	}
+	e := pointer.DecodePen(win32.WM_POINTERDOWN, &info, win32.POINT{X: 10, Y: 20})
+	if e.ID != 3 || e.Type != pointer.Pen || e.X != 100 || e.Y != 200 ||
+		e.Pressure != 0.5 || e.TiltX != -30 || e.TiltY != 0 ||
+		!e.Barrel() || e.Eraser() || !e.InContact() || !e.Primary() || e.Canceled() {
+		t.Fatalf("%+v", e)
+	}
+
+	info.PenMask = 0
+	if e := pointer.DecodePen(win32.WM_POINTERUPDATE, &info, win32.POINT{}); e.Pressure != -1 || e.TiltX != 0 {
+		t.Fatalf("%+v", e)
+	}
+}
+
+func TestDecodeTouch(t *testing.T) {
+	info := win32.POINTER_TOUCH_INFO{
+		PointerInfo: win32.POINTER_INFO{
+			PointerType:     win32.PT_TOUCH,
+			PointerId:       7,
+			PtPixelLocation: win32.POINT{X: 50, Y: 60},
+		},
+		TouchMask:   win32.TOUCH_MASK_CONTACTAREA | win32.TOUCH_MASK_ORIENTATION,
+		RcContact:   win32.RECT{Left: 45, Top: 55, Right: 55, Bottom: 65},
+		Orientation: 90,
+		Pressure:    100, // Not in mask.
+	}
+	e := pointer.DecodeTouch(win32.WM_POINTERUP, &info, win32.POINT{X: 40, Y: 50})
+	if e.ID != 7 || e.Type != pointer.Touch || e.X != 10 || e.Y != 10 ||
+		e.Contact != (win32.RECT{Left: 5, Top: 5, Right: 15, Bottom: 15}) ||
+		e.Orientation != 90 || e.Pressure != -1 {
+		t.Fatalf("%+v", e)
+	}
+}
+
+// rotateArgument is the inverse of GID_ROTATE_ANGLE_FROM_ARGUMENT.
+func rotateArgument(angle float64) win32.UINT64 {
+	return win32.UINT64(math.Round((angle + 2*math.Pi) / (4 * math.Pi) * 65535))
+}
+
+func TestGestureTracker(t *testing.T) {
+	var tracker pointer.GestureTracker
+	origin := win32.POINT{X: 100, Y: 100}
+	decode := func(id, flags win32.DWORD, x, y win32.SHORT, args win32.UINT64) *pointer.GestureEvent {
+		t.Helper()
+		e, ok := tracker.Decode(&win32.GESTUREINFO{ID: id, Flags: flags, Location: win32.POINTS{X: x, Y: y}, Arguments: args}, origin)
+		if !ok {
+			t.Fatalf("gesture %v not decoded", id)
+		}
+		return e
+	}
+	if _, ok := tracker.Decode(&win32.GESTUREINFO{ID: win32.GID_BEGIN}, origin); ok {
+		t.Fatal("GID_BEGIN decoded")
+	}
+
+	if e := decode(win32.GID_PAN, win32.GF_BEGIN, 150, 150, 0); !e.Begin || e.Gesture != pointer.Pan || e.X != 50 || e.Y != 50 || e.DX != 0 {
+		t.Fatalf("%+v", e)
+	}
+	if e := decode(win32.GID_PAN, 0, 160, 145, 0); e.DX != 10 || e.DY != -5 {
+		t.Fatalf("%+v", e)
+	}
+	if e := decode(win32.GID_PAN, win32.GF_END|win32.GF_INERTIA, 170, 145, 0); !e.End || !e.Inertia || e.DX != 10 || e.DY != 0 {
+		t.Fatalf("%+v", e)
+	}
+
+	if e := decode(win32.GID_ZOOM, win32.GF_BEGIN, 150, 150, 100); e.Scale != 1 || e.ScaleDelta != 1 {
+		t.Fatalf("%+v", e)
+	}
+	if e := decode(win32.GID_ZOOM, 0, 150, 150, 200); e.Scale != 2 || e.ScaleDelta != 2 {
+		t.Fatalf("%+v", e)
+	}
+	if e := decode(win32.GID_ZOOM, win32.GF_END, 150, 150, 300); e.Scale != 3 || e.ScaleDelta != 1.5 {
+		t.Fatalf("%+v", e)
+	}
+
+	const epsilon = 1e-3
+	if e := decode(win32.GID_ROTATE, win32.GF_BEGIN, 150, 150, rotateArgument(1)); e.Angle != 0 {
+		t.Fatalf("%+v", e)
+	}
+	if e := decode(win32.GID_ROTATE, 0, 150, 150, rotateArgument(math.Pi/2)); math.Abs(e.Angle-math.Pi/2) > epsilon || math.Abs(e.AngleDelta-math.Pi/2) > epsilon {
+		t.Fatalf("%+v", e)
+	}
+	if e := decode(win32.GID_ROTATE, win32.GF_END, 150, 150, rotateArgument(-math.Pi/2)); math.Abs(e.Angle+math.Pi/2) > epsilon || math.Abs(e.AngleDelta+math.Pi) > epsilon {
+		t.Fatalf("%+v", e)
+	}
+}
+
+func TestAttach(t *testing.T) {
+	w, err := window.New(&window.Spec{X: metrics.Px(100), Y: metrics.Px(100), Width: metrics.Px(300), Height: metrics.Px(300)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Destroy()
+	var events []*pointer.Event
+	var gestures []*pointer.GestureEvent
+	record := func(e *pointer.Event) { events = append(events, e) }
+	if err := pointer.Attach(&w.WindowBase, &pointer.Handler{
+		OnDown:   record,
+		OnUpdate: record,
+		OnUp:     record,
+		OnGesture: func(e *pointer.GestureEvent) {
+			e.Handled = e.Gesture == pointer.Zoom
+			gestures = append(gestures, e)
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if c := backend.GestureConfig(w.HWND()); len(c) != 1 || c[0].Want != win32.GC_ALLGESTURES {
+		t.Fatal(c)
+	}
+
+	var o win32.POINT
+	win32.ClientToScreen(w.HWND(), &o)
+	pen := win32.POINTER_PEN_INFO{
+		PointerInfo: win32.POINTER_INFO{
+			PointerType:     win32.PT_PEN,
+			PointerId:       1,
+			PointerFlags:    win32.POINTER_FLAG_DOWN | win32.POINTER_FLAG_INCONTACT,
+			HwndTarget:      w.HWND(),
+			PtPixelLocation: win32.POINT{X: o.X + 10, Y: o.Y + 20},
+		},
+		PenMask:  win32.PEN_MASK_PRESSURE,
+		Pressure: 1024,
+	}
+	backend.Pointer(win32.WM_POINTERDOWN, nil, &pen, nil)
+	touch := win32.POINTER_TOUCH_INFO{
+		PointerInfo: win32.POINTER_INFO{
+			PointerType:     win32.PT_TOUCH,
+			PointerId:       2,
+			PointerFlags:    win32.POINTER_FLAG_UPDATE,
+			HwndTarget:      w.HWND(),
+			PtPixelLocation: win32.POINT{X: o.X + 30, Y: o.Y + 40},
+		},
+	}
+	backend.Pointer(win32.WM_POINTERUPDATE, nil, nil, &touch)
+	backend.Pointer(win32.WM_POINTERUP, &win32.POINTER_INFO{PointerType: win32.PT_MOUSE, PointerId: 3, HwndTarget: w.HWND(), PtPixelLocation: o}, nil, nil)
+	pump()
+	if len(events) != 3 ||
+		events[0].Message != win32.WM_POINTERDOWN || events[0].Type != pointer.Pen || events[0].X != 10 || events[0].Y != 20 || events[0].Pressure != 1 ||
+		events[1].Message != win32.WM_POINTERUPDATE || events[1].Type != pointer.Touch || events[1].X != 30 || events[1].Y != 40 ||
+		events[2].Message != win32.WM_POINTERUP || events[2].Type != pointer.Mouse || events[2].X != 0 {
+		t.Fatal(events)
+	}
+
+	backend.Gesture(w.HWND(), &win32.GESTUREINFO{ID: win32.GID_BEGIN, Flags: win32.GF_BEGIN})
+	backend.Gesture(w.HWND(), &win32.GESTUREINFO{ID: win32.GID_ZOOM, Flags: win32.GF_BEGIN, Location: win32.POINTS{X: win32.SHORT(o.X + 5), Y: win32.SHORT(o.Y + 5)}, Arguments: 50})
+	backend.Gesture(w.HWND(), &win32.GESTUREINFO{ID: win32.GID_ZOOM, Flags: win32.GF_END, Arguments: 25})
+	backend.Gesture(w.HWND(), &win32.GESTUREINFO{ID: win32.GID_TWOFINGERTAP})
+	pump()
+	if len(gestures) != 3 || gestures[0].X != 5 || gestures[0].Y != 5 || gestures[1].Scale != 0.5 || gestures[2].Gesture != pointer.TwoFingerTap {
+		t.Fatal(gestures)
+	}
+	// Handled gestures are closed by the handler, others by DefWindowProc.
+	if n := backend.GestureInfoHandles(); n != 0 {
+		t.Fatal(n)
+	}
+}
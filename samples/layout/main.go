@@ -0,0 +1,78 @@
+package main
+
+import (
+	"github.com/mkch/gg"
+	"github.com/mkch/gw/app"
+	"github.com/mkch/gw/button"
+	"github.com/mkch/gw/edit"
+	"github.com/mkch/gw/layout"
+	"github.com/mkch/gw/metrics"
+	"github.com/mkch/gw/static"
+	"github.com/mkch/gw/win32"
+	"github.com/mkch/gw/window"
+)
+
+//go:generate rsrc -arch amd64 -manifest manifest.xml
+//go:generate rsrc -arch 386 -manifest manifest.xml
+
+func main() {
+	win := gg.Must(window.New(&window.Spec{
+		Text:  "Layout demo",
+		Style: win32.WS_OVERLAPPEDWINDOW,
+		X:     metrics.Px(win32.CW_USEDEFAULT),
+		Width: metrics.Dip(400), Height: metrics.Dip(300),
+		OnDestroy: func() { app.Quit(0) },
+	}))
+
+	// The controls are created in their preferred size, the position is determined by the layout.
+	label := func(text string) layout.Element {
+		s := gg.Must(static.New(win.HWND(), &static.Spec{
+			Text:  text,
+			Style: win32.WS_VISIBLE | static.SS_CENTERIMAGE,
+			Width: metrics.Dip(60), Height: metrics.Dip(23),
+		}))
+		return gg.Must(s.LayoutElement())
+	}
+	textBox := func() layout.Element {
+		e := gg.Must(edit.New(win.HWND(), &edit.Spec{
+			Style:   win32.WS_VISIBLE | win32.WS_TABSTOP,
+			ExStyle: win32.WS_EX_CLIENTEDGE,
+			Width:   metrics.Dip(120), Height: metrics.Dip(23),
+		}))
+		return gg.Must(e.LayoutElement())
+	}
+	pushButton := func(text string, onClick func()) layout.Element {
+		b := gg.Must(button.New(win.HWND(), &button.Spec{
+			Text:    text,
+			Style:   win32.WS_VISIBLE | win32.WS_TABSTOP,
+			OnClick: onClick,
+			Width:   metrics.Dip(75), Height: metrics.Dip(25),
+		}))
+		return gg.Must(b.LayoutElement())
+	}
+
+	form := &layout.Grid{
+		Columns:       []layout.Track{{}, {Stretch: 1}},
+		ColumnSpacing: metrics.Dip(8),
+		RowSpacing:    metrics.Dip(6),
+	}
+	form.Add(0, 0, &layout.Item{Element: label("Name:")})
+	form.Add(0, 1, &layout.Item{Element: textBox()})
+	form.Add(1, 0, &layout.Item{Element: label("Email:")})
+	form.Add(1, 1, &layout.Item{Element: textBox()})
+
+	buttons := layout.HBox(
+		&layout.Item{Stretch: 1}, // Spacer.
+		&layout.Item{Element: pushButton("OK", func() { win.Destroy() })},
+		&layout.Item{Element: pushButton("Cancel", func() { win.Destroy() })},
+	)
+	buttons.Spacing = metrics.Dip(8)
+
+	root := &layout.Dock{Padding: layout.Uniform(metrics.Dip(12))}
+	root.Add(layout.DockBottom, &layout.Item{Element: buttons})
+	root.Add(layout.DockTop, &layout.Item{Element: form})
+	gg.MustOK(win.SetLayout(root))
+
+	win.Show(win32.SW_SHOW)
+	app.Run()
+}
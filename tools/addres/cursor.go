@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mkch/gw/util/icon"
+	"github.com/mkch/gw/util/winres"
+)
+
+// parseHotspot parses a hotspot in the form of "x,y".
+func parseHotspot(s string) (p image.Point, err error) {
+	xy := splitList(s)
+	if len(xy) != 2 {
+		err = fmt.Errorf("invalid hotspot %q", s)
+		return
+	}
+	if p.X, err = strconv.Atoi(xy[0]); err == nil {
+		p.Y, err = strconv.Atoi(xy[1])
+	}
+	if err != nil {
+		err = fmt.Errorf("invalid hotspot %q", s)
+	}
+	return
+}
+
+// readCursor reads a cursor file(*.cur), or a PNG file(*.png) with the hotspot.
+func readCursor(file string, hotspot image.Point) (*icon.Cursor, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if !strings.EqualFold(filepath.Ext(file), ".png") {
+		return icon.ReadCursor(f)
+	}
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+	return icon.NewCursor(img, hotspot)
+}
+
+// readAnimatedCursor reads an animated cursor file(*.ani).
+func readAnimatedCursor(file string) (*icon.AnimatedCursor, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return icon.ReadAnimatedCursor(f)
+}
+
+func addCursor(arguments []string) {
+	addCursorFlags.Parse(arguments)
+	if addCursorFlags.File == "" {
+		printError("no cursor file.\n")
+		addCursorFlags.Usage()
+		os.Exit(1)
+	}
+	args := addCursorFlags.Args()
+	if len(args) < 1 {
+		printError("not enough arguments.\n")
+		addCursorFlags.Usage()
+		os.Exit(1)
+	}
+	hotspot, err := parseHotspot(addCursorFlags.Hotspot)
+	if err != nil {
+		printError("%v.\n", err)
+		addCursorFlags.Usage()
+		os.Exit(1)
+	}
+
+	group := winres.IntID(uint16(addCursorFlags.GroupID))
+	lang := uint16(addCursorFlags.Lang)
+	if strings.EqualFold(filepath.Ext(addCursorFlags.File), ".ani") {
+		ani, err := readAnimatedCursor(addCursorFlags.File)
+		if err != nil {
+			printError("invalid animated cursor file: %v.\n", err)
+			os.Exit(2)
+		}
+		updateResource(args[0], func(res *winres.Set) error {
+			return res.AddAnimatedCursor(group, lang, ani)
+		})
+		return
+	}
+
+	cur, err := readCursor(addCursorFlags.File, hotspot)
+	if err != nil {
+		printError("invalid cursor file: %v.\n", err)
+		os.Exit(2)
+	}
+	updateResource(args[0], func(res *winres.Set) error {
+		return res.AddCursor(group, uint16(addCursorFlags.FirstCursorID), lang, cur)
+	})
+}
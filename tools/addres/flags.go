@@ -3,20 +3,26 @@ package main
 import (
 	"flag"
 	"fmt"
+
+	"github.com/mkch/gw/util/winres"
 )
 
 type addIconFlagSet struct {
 	flags       *flag.FlagSet
 	File        string
+	Sizes       string
 	GroupID     int
 	FirstIconID int
+	Lang        int
 }
 
 func (f *addIconFlagSet) Init() {
 	f.flags = flag.NewFlagSet("icon", flag.ExitOnError)
-	f.flags.StringVar(&f.File, "res", "icon.ico", "The icon file(*.ico)")
+	f.flags.StringVar(&f.File, "res", "icon.ico", "The icon file(*.ico) or PNG file(*.png)")
+	f.flags.StringVar(&f.Sizes, "sizes", defaultIconSizes, "Comma separated list of icon sizes generated from the PNG file")
 	f.flags.IntVar(&f.GroupID, "group-id", 1, "The id of icon group(RT_GROUP_ICON) resource")
 	f.flags.IntVar(&f.FirstIconID, "icon-id", 1, "The first id of icon(RT_ICON) resource")
+	f.flags.IntVar(&f.Lang, "lang", 0, "The language id of the resources")
 }
 
 func (f *addIconFlagSet) Parse(arguments []string) {
@@ -35,14 +41,73 @@ func (f *addIconFlagSet) Args() []string {
 	return f.flags.Args()
 }
 
+type addCursorFlagSet struct {
+	flags         *flag.FlagSet
+	File          string
+	Hotspot       string
+	GroupID       int
+	FirstCursorID int
+	Lang          int
+}
+
+func (f *addCursorFlagSet) Init() {
+	f.flags = flag.NewFlagSet("cursor", flag.ExitOnError)
+	f.flags.StringVar(&f.File, "res", "cursor.cur", "The cursor file(*.cur), animated cursor file(*.ani) or PNG file(*.png)")
+	f.flags.StringVar(&f.Hotspot, "hotspot", "0,0", "The hotspot x,y of the cursor generated from the PNG file")
+	f.flags.IntVar(&f.GroupID, "group-id", 1, "The id of cursor group(RT_GROUP_CURSOR) or animated cursor(RT_ANICURSOR) resource")
+	f.flags.IntVar(&f.FirstCursorID, "cursor-id", 1, "The first id of cursor(RT_CURSOR) resource")
+	f.flags.IntVar(&f.Lang, "lang", 0, "The language id of the resources")
+}
+
+func (f *addCursorFlagSet) Parse(arguments []string) {
+	err := f.flags.Parse(arguments)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (f *addCursorFlagSet) Usage() {
+	fmt.Fprintln(f.flags.Output(), "usage: addres cursor [flags] path_of_exe_file")
+	f.flags.PrintDefaults()
+}
+
+func (f *addCursorFlagSet) Args() []string {
+	return f.flags.Args()
+}
+
 type addManifestFlagSet struct {
-	flags *flag.FlagSet
-	File  string
+	flags          *flag.FlagSet
+	File           string
+	Lang           int
+	Generate       bool
+	Output         string
+	DPIAware       string
+	DPIAwareness   string
+	SupportedOS    string
+	CommonControls bool
+	ExecutionLevel string
+	UIAccess       bool
+	LongPathAware  bool
+	UTF8           bool
+	HeapType       string
 }
 
 func (f *addManifestFlagSet) Init() {
+	def := winres.DefaultManifest()
 	f.flags = flag.NewFlagSet("manifest", flag.ExitOnError)
-	f.flags.StringVar(&f.File, "res", "manifest.xml", "The icon file(*.ico)")
+	f.flags.StringVar(&f.File, "res", "manifest.xml", "The manifest file(*.xml)")
+	f.flags.IntVar(&f.Lang, "lang", 0, "The language id of the resource")
+	f.flags.BoolVar(&f.Generate, "generate", false, "Generate the manifest from the following flags instead of reading -res")
+	f.flags.StringVar(&f.Output, "o", "", "Write the generated manifest to this file, exe file is optional if set")
+	f.flags.StringVar(&f.DPIAware, "dpi-aware", def.DPIAware, `The legacy dpiAware setting: true, false, true/pm, "per monitor" or empty`)
+	f.flags.StringVar(&f.DPIAwareness, "dpi-awareness", string(def.DPIAwareness[0]), "Comma separated list of dpiAwareness: unaware, system, PerMonitor or PerMonitorV2")
+	f.flags.StringVar(&f.SupportedOS, "supported-os", "", "Comma separated list of supported OS: vista, 7, 8, 8.1 or 10(also 11)")
+	f.flags.BoolVar(&f.CommonControls, "common-controls", def.CommonControls, "Depend on Common Controls version 6")
+	f.flags.StringVar(&f.ExecutionLevel, "execution-level", string(def.ExecutionLevel), "The requested execution level: asInvoker, highestAvailable, requireAdministrator or empty")
+	f.flags.BoolVar(&f.UIAccess, "ui-access", false, "Request UI access(uiAccess)")
+	f.flags.BoolVar(&f.LongPathAware, "long-path-aware", false, "Enable paths longer than MAX_PATH(longPathAware)")
+	f.flags.BoolVar(&f.UTF8, "utf8", false, "Use UTF-8 as the process code page(activeCodePage)")
+	f.flags.StringVar(&f.HeapType, "heap-type", "", "The heap type, such as SegmentHeap")
 }
 
 func (f *addManifestFlagSet) Parse(arguments []string) {
@@ -54,9 +119,106 @@ func (f *addManifestFlagSet) Parse(arguments []string) {
 
 func (f *addManifestFlagSet) Usage() {
 	fmt.Fprintln(f.flags.Output(), "usage: addres manifest [flags] path_of_exe_file")
+	fmt.Fprintln(f.flags.Output(), "       addres manifest -generate -o manifest.xml [flags]")
 	f.flags.PrintDefaults()
 }
 
 func (f *addManifestFlagSet) Args() []string {
 	return f.flags.Args()
 }
+
+type sysoFlagSet struct {
+	flags       *flag.FlagSet
+	Icon        string
+	IconSizes   string
+	GroupID     int
+	FirstIconID int
+	Manifest    string
+	Strings     string
+	Version     string
+	Lang        int
+	Arch        string
+}
+
+func (f *sysoFlagSet) Init() {
+	f.flags = flag.NewFlagSet("syso", flag.ExitOnError)
+	f.flags.StringVar(&f.Icon, "ico", "", "The icon file(*.ico) or PNG file(*.png)")
+	f.flags.StringVar(&f.IconSizes, "ico-sizes", defaultIconSizes, "Comma separated list of icon sizes generated from the PNG file")
+	f.flags.IntVar(&f.GroupID, "group-id", 1, "The id of icon group(RT_GROUP_ICON) resource")
+	f.flags.IntVar(&f.FirstIconID, "icon-id", 1, "The first id of icon(RT_ICON) resource")
+	f.flags.StringVar(&f.Manifest, "manifest", "", "The manifest file(*.xml)")
+	f.flags.StringVar(&f.Strings, "strings", "", `The string table file(*.json), an object of id-string pairs such as {"1": "Hello"}`)
+	f.flags.StringVar(&f.Version, "version", "", "The version information file(*.json, *.yaml), see addres help version")
+	f.flags.IntVar(&f.Lang, "lang", 0, "The language id of the resources")
+	f.flags.StringVar(&f.Arch, "arch", "amd64,386", "Comma separated list of target architectures(GOARCH)")
+}
+
+func (f *sysoFlagSet) Parse(arguments []string) {
+	err := f.flags.Parse(arguments)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (f *sysoFlagSet) Usage() {
+	fmt.Fprintln(f.flags.Output(), "usage: addres syso [flags] [output_dir]")
+	fmt.Fprintln(f.flags.Output(), "rsrc_windows_$GOARCH.syso files are written to output_dir, the current directory by default.")
+	f.flags.PrintDefaults()
+}
+
+func (f *sysoFlagSet) Args() []string {
+	return f.flags.Args()
+}
+
+// versionStringFlags maps the string flags of versionFlagSet to the keys of the string table.
+var versionStringFlags = map[string]string{
+	"company":           winres.CompanyName,
+	"description":       winres.FileDescription,
+	"product-name":      winres.ProductName,
+	"copyright":         winres.LegalCopyright,
+	"trademarks":        winres.LegalTrademarks,
+	"original-filename": winres.OriginalFilename,
+	"internal-name":     winres.InternalName,
+	"comments":          winres.Comments,
+}
+
+type versionFlagSet struct {
+	flags          *flag.FlagSet
+	Config         string
+	FileVersion    string
+	ProductVersion string
+	FileType       string
+	FileFlags      string
+	Lang           int
+	CodePage       int
+}
+
+func (f *versionFlagSet) Init() {
+	f.flags = flag.NewFlagSet("version", flag.ExitOnError)
+	f.flags.StringVar(&f.Config, "config", "", "The version information file(*.json, *.yaml), overridden by other flags")
+	f.flags.StringVar(&f.FileVersion, "file-version", "", "The file version, such as 1.2.3.4")
+	f.flags.StringVar(&f.ProductVersion, "product-version", "", "The product version, the file version by default")
+	f.flags.StringVar(&f.FileType, "file-type", "app", "The file type: app, dll, drv, font or static-lib")
+	f.flags.StringVar(&f.FileFlags, "file-flags", "", "Comma separated list of file flags: debug, prerelease, patched, privatebuild or specialbuild")
+	f.flags.IntVar(&f.Lang, "lang", defaultVersionLang, "The language id of the resource and the string table")
+	f.flags.IntVar(&f.CodePage, "codepage", winres.CP_UNICODE, "The code page of the string table")
+	for name, key := range versionStringFlags {
+		f.flags.String(name, "", "The "+key+" string")
+	}
+}
+
+func (f *versionFlagSet) Parse(arguments []string) {
+	err := f.flags.Parse(arguments)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (f *versionFlagSet) Usage() {
+	fmt.Fprintln(f.flags.Output(), "usage: addres version [flags] path_of_exe_file")
+	f.flags.PrintDefaults()
+}
+
+func (f *versionFlagSet) Args() []string {
+	return f.flags.Args()
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mkch/gw/util/icon"
+)
+
+// defaultIconSizes are the default sizes of icon images generated from a PNG file.
+const defaultIconSizes = "16,24,32,48,64,256"
+
+// readIcon reads an icon file(*.ico), or a PNG file(*.png) which is scaled down to
+// sizes, a comma separated list of sizes in pixels. Sizes larger than the PNG image are ignored.
+func readIcon(file string, sizes string) (*icon.Icon, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if !strings.EqualFold(filepath.Ext(file), ".png") {
+		return icon.Read(f)
+	}
+
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+	srcSize := max(img.Bounds().Dx(), img.Bounds().Dy())
+	var imgs []image.Image
+	for _, s := range splitList(sizes) {
+		size, err := strconv.Atoi(s)
+		if err != nil || size <= 0 || size > icon.MaxSize {
+			return nil, fmt.Errorf("invalid icon size %q", s)
+		}
+		if size <= srcSize {
+			imgs = append(imgs, scaleImage(img, size))
+		}
+	}
+	if len(imgs) == 0 {
+		return nil, fmt.Errorf("image is smaller than all the icon sizes")
+	}
+	return icon.New(imgs...)
+}
+
+// scaleImage scales img to fit in a size x size square, keeping the aspect ratio.
+// Each pixel is the area weighted average of the source pixels it covers.
+func scaleImage(img image.Image, size int) *image.NRGBA {
+	src := img.Bounds()
+	scale := float64(max(src.Dx(), src.Dy())) / float64(size)
+	w, h := int(float64(src.Dx())/scale+0.5), int(float64(src.Dy())/scale+0.5)
+	dst := image.NewNRGBA(image.Rect(0, 0, size, size))
+	// Centers the scaled image.
+	offX, offY := (size-w)/2, (size-h)/2
+	for y := range h {
+		sy0, sy1 := float64(y)*scale, min(float64(y+1)*scale, float64(src.Dy()))
+		for x := range w {
+			sx0, sx1 := float64(x)*scale, min(float64(x+1)*scale, float64(src.Dx()))
+			// Sums of premultiplied colors.
+			var r, g, b, a, total float64
+			for sy := int(sy0); float64(sy) < sy1; sy++ {
+				wy := min(float64(sy+1), sy1) - max(float64(sy), sy0)
+				for sx := int(sx0); float64(sx) < sx1; sx++ {
+					wx := min(float64(sx+1), sx1) - max(float64(sx), sx0)
+					cr, cg, cb, ca := img.At(src.Min.X+sx, src.Min.Y+sy).RGBA()
+					weight := wx * wy
+					r += float64(cr) * weight
+					g += float64(cg) * weight
+					b += float64(cb) * weight
+					a += float64(ca) * weight
+					total += weight
+				}
+			}
+			c := color.RGBA64{
+				R: uint16(r/total + 0.5),
+				G: uint16(g/total + 0.5),
+				B: uint16(b/total + 0.5),
+				A: uint16(a/total + 0.5),
+			}
+			dst.Set(offX+x, offY+y, c)
+		}
+	}
+	return dst
+}
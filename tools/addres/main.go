@@ -4,20 +4,22 @@ import (
 	"flag"
 	"fmt"
 	"os"
-	"unsafe"
 
-	"github.com/mkch/gw/util/icon"
-	"github.com/mkch/gw/win32"
-	"github.com/mkch/gw/win32/win32util"
-	"golang.org/x/sys/windows"
+	"github.com/mkch/gw/util/winres"
 )
 
 var addIconFlags addIconFlagSet
+var addCursorFlags addCursorFlagSet
 var addManifestFlags addManifestFlagSet
+var addVersionFlags versionFlagSet
+var sysoFlags sysoFlagSet
 
 func main() {
 	addIconFlags.Init()
+	addCursorFlags.Init()
 	addManifestFlags.Init()
+	addVersionFlags.Init()
+	sysoFlags.Init()
 
 	flag.Usage = func() {
 		fmt.Fprintf(flag.CommandLine.Output(), `addres is a tool for adding resource to exe file.
@@ -28,7 +30,10 @@ Usage:
 The commands are:
 
     icon	add an icon resource
+    cursor	add a cursor or animated cursor resource
     manifest	add a manifest resource
+    version	add a version information resource
+    syso	compile resources into *.syso files to be linked by go build
 
  Use "addres help <command>" for more information about a command.
  `)
@@ -57,8 +62,14 @@ The commands are:
 		commandUsage(args[1])
 	case "icon":
 		addIcon(args[1:])
+	case "cursor":
+		addCursor(args[1:])
 	case "manifest":
 		addManifest(args[1:])
+	case "version":
+		addVersion(args[1:])
+	case "syso":
+		syso(args[1:])
 	default:
 		printError("unknown command %v.\n", cmd)
 		flag.Usage()
@@ -70,19 +81,22 @@ func commandUsage(cmd string) {
 	switch cmd {
 	case "icon":
 		addIconFlags.Usage()
+	case "cursor":
+		addCursorFlags.Usage()
 	case "manifest":
 		addManifestFlags.Usage()
+	case "version":
+		addVersionFlags.Usage()
+	case "syso":
+		sysoFlags.Usage()
 	}
 }
 
 func addManifest(arguments []string) {
 	addManifestFlags.Parse(arguments)
-	if addManifestFlags.File == "" {
-		printError("no manifest file.\n")
-		os.Exit(1)
-	}
 	args := addManifestFlags.Args()
-	if len(args) < 1 {
+	// The generated manifest can be written to -o only.
+	if len(args) < 1 && (!addManifestFlags.Generate || addManifestFlags.Output == "") {
 		printError("not enough arguments.\n")
 		addManifestFlags.Usage()
 		os.Exit(1)
@@ -90,47 +104,44 @@ func addManifest(arguments []string) {
 
 	var err error
 	var manifestData []byte
-	if manifestData, err = os.ReadFile(addManifestFlags.File); err != nil {
-		printError("%v\n", err)
-		os.Exit(2)
-	}
-
-	exeFile := args[0]
-	var exeFileBuf []win32.WCHAR
-	win32util.CString(exeFile, &exeFileBuf)
-	var update win32.HUPDATE
-	if update, err = win32.BeginUpdateResourceW(&exeFileBuf[0], false); err != nil {
-		printError("failed to update resource: %v\n", err)
-		os.Exit(3)
-	}
-	defer func() {
-		if err = win32.EndUpdateResourceW(update, false); err != nil {
-			printError("failed to update resource: %v\n", err)
-			os.Exit(3)
+	if addManifestFlags.Generate {
+		var m *winres.Manifest
+		if m, err = generateManifest(&addManifestFlags); err != nil {
+			printError("%v.\n", err)
+			addManifestFlags.Usage()
+			os.Exit(1)
+		}
+		manifestData = m.XML()
+		if addManifestFlags.Output != "" {
+			if err = os.WriteFile(addManifestFlags.Output, manifestData, 0644); err != nil {
+				printError("%v\n", err)
+				os.Exit(3)
+			}
+		}
+	} else {
+		if addManifestFlags.File == "" {
+			printError("no manifest file.\n")
+			os.Exit(1)
+		}
+		if manifestData, err = os.ReadFile(addManifestFlags.File); err != nil {
+			printError("%v\n", err)
+			os.Exit(2)
 		}
-	}()
-
-	var module windows.Handle
-	if module, err = windows.LoadLibraryEx(exeFile, 0, windows.LOAD_LIBRARY_AS_DATAFILE); err != nil {
-		printError("failed to load %v: %v\n", exeFile, err)
-		os.Exit(3)
-	}
-	//defer windows.FreeLibrary(module)
-
-	// RT_MANIFEST must have id 1.
-	const MANIFEST_RES_ID = 1
-
-	if h, _ := win32.FindResourceW(win32.HMODULE(module), MANIFEST_RES_ID, win32.RT_MANIFEST); h != 0 {
-		printError("manifest resource %v already exists.\n", MANIFEST_RES_ID)
-		os.Exit(2)
-	}
-
-	if err = win32.UpdateResourceW(update,
-		win32.RT_MANIFEST, MANIFEST_RES_ID, 0,
-		unsafe.Pointer(&manifestData[0]), win32.DWORD(len(manifestData))); err != nil {
-		printError("failed to update resource: %v\n", err)
-		os.Exit(3)
 	}
+	if len(args) < 1 {
+		return
+	}
+
+	updateResource(args[0], func(res *winres.Set) error {
+		// RT_MANIFEST must have id 1.
+		const MANIFEST_RES_ID = 1
+		return res.Add(&winres.Resource{
+			Type: winres.IntID(winres.RT_MANIFEST),
+			Name: winres.IntID(MANIFEST_RES_ID),
+			Lang: uint16(addManifestFlags.Lang),
+			Data: manifestData,
+		})
+	})
 }
 
 func addIcon(arguments []string) {
@@ -147,76 +158,45 @@ func addIcon(arguments []string) {
 		os.Exit(1)
 	}
 
-	var err error
-	var iconReader *os.File
-	if iconReader, err = os.Open(addIconFlags.File); err != nil {
-		printError("%v\n", err)
-		os.Exit(2)
-	}
-	defer iconReader.Close()
-	var iconData *icon.Icon
-	if iconData, err = icon.Read(iconReader); err != nil {
+	iconData, err := readIcon(addIconFlags.File, addIconFlags.Sizes)
+	if err != nil {
 		printError("invalid icon file: %v.\n", err)
 		os.Exit(2)
 	}
 
-	exeFile := args[0]
-	var exeFileBuf []win32.WCHAR
-	win32util.CString(exeFile, &exeFileBuf)
-	var update win32.HUPDATE
-	if update, err = win32.BeginUpdateResourceW(&exeFileBuf[0], false); err != nil {
-		printError("failed to update resource: %v\n", err)
+	updateResource(args[0], func(res *winres.Set) error {
+		return res.AddIcon(winres.IntID(uint16(addIconFlags.GroupID)), uint16(addIconFlags.FirstIconID), uint16(addIconFlags.Lang), iconData)
+	})
+}
+
+// updateResource reads the resources of exeFile, calls update
+// to modify them and writes them back.
+func updateResource(exeFile string, update func(res *winres.Set) error) {
+	var err error
+	var info os.FileInfo
+	if info, err = os.Stat(exeFile); err != nil {
+		printError("%v\n", err)
 		os.Exit(3)
 	}
-	defer func() {
-		if err = win32.EndUpdateResourceW(update, false); err != nil {
-			printError("failed to update resource: %v\n", err)
-			os.Exit(3)
-		}
-	}()
-
-	var module windows.Handle
-	if module, err = windows.LoadLibraryEx(exeFile, 0, windows.LOAD_LIBRARY_AS_DATAFILE); err != nil {
-		printError("failed to load %v: %v\n", exeFile, err)
+	var image []byte
+	if image, err = os.ReadFile(exeFile); err != nil {
+		printError("%v\n", err)
 		os.Exit(3)
 	}
-	defer windows.FreeLibrary(module)
-
-	var iconIDs []uint16
-	// Add icons.
-	for i := range iconData.Images {
-		id := uintptr(addIconFlags.FirstIconID + i)
-		if h, _ := win32.FindResourceW(win32.HMODULE(module), id, win32.RT_ICON); h != 0 {
-			printError("icon resource %v already exists.\n", id)
-			os.Exit(2)
-		}
-		if err = win32.UpdateResourceW(update, win32.RT_ICON, id, 0,
-			unsafe.Pointer(&iconData.Images[i].Data[0]), win32.DWORD(len(iconData.Images[i].Data))); err != nil {
-			printError("failed to update resource: %v\n", err)
-			os.Exit(3)
-		}
-		iconIDs = append(iconIDs, uint16(id))
+	var res *winres.Set
+	if res, err = winres.ReadPE(image); err != nil {
+		printError("failed to load %v: %v\n", exeFile, err)
+		os.Exit(3)
 	}
-	// Add icon group.
-	if h, _ := win32.FindResourceW(win32.HMODULE(module), uintptr(addIconFlags.GroupID), win32.RT_GROUP_ICON); h != 0 {
-		printError("group icon resource %v already exists.\n", addIconFlags.GroupID)
+	if err = update(res); err != nil {
+		printError("%v.\n", err)
 		os.Exit(2)
 	}
-	groupIconData := make([]byte, unsafe.Sizeof(icon.IconDirHeader{})+uintptr(len(iconData.Images))*unsafe.Sizeof(icon.GrpIconDirEntry{}))
-	hdr := (*icon.IconDirHeader)(unsafe.Pointer(&groupIconData[0]))
-	*hdr.Type() = iconData.Type
-	*hdr.Count() = uint16(len(iconData.Images))
-	for i := range iconData.Images {
-		entry := (*icon.GrpIconDirEntry)(unsafe.Pointer(uintptr(unsafe.Pointer(hdr)) + unsafe.Sizeof(icon.IconDirHeader{}) + uintptr(i)*unsafe.Sizeof(icon.GrpIconDirEntry{})))
-		*entry.Width() = *iconData.Images[i].Entry.Width()
-		*entry.Height() = *iconData.Images[i].Entry.Height()
-		*entry.ColorCount() = *iconData.Images[i].Entry.ColorCount()
-		*entry.Planes() = *iconData.Images[i].Entry.Planes()
-		*entry.BitCount() = *iconData.Images[i].Entry.BitCount()
-		*entry.BytesInRes() = *iconData.Images[i].Entry.BytesInRes()
-		*entry.ID() = iconIDs[i]
-	}
-	if err = win32.UpdateResourceW(update, win32.RT_GROUP_ICON, uintptr(addIconFlags.GroupID), 0, unsafe.Pointer(&groupIconData[0]), win32.DWORD(len(groupIconData))); err != nil {
+	if image, err = winres.WritePE(image, res); err != nil {
+		printError("failed to update resource: %v\n", err)
+		os.Exit(3)
+	}
+	if err = os.WriteFile(exeFile, image, info.Mode()); err != nil {
 		printError("failed to update resource: %v\n", err)
 		os.Exit(3)
 	}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mkch/gw/util/winres"
+)
+
+var supportedOS = map[string]string{
+	"vista": winres.SupportedOSWindowsVista,
+	"7":     winres.SupportedOSWindows7,
+	"8":     winres.SupportedOSWindows8,
+	"8.1":   winres.SupportedOSWindows81,
+	"10":    winres.SupportedOSWindows10,
+	"11":    winres.SupportedOSWindows10,
+}
+
+var dpiAwareness = map[string]winres.DPIAwareness{
+	"unaware":      winres.DPIUnaware,
+	"system":       winres.DPISystem,
+	"permonitor":   winres.DPIPerMonitor,
+	"permonitorv2": winres.DPIPerMonitorV2,
+}
+
+var executionLevels = map[string]winres.ExecutionLevel{
+	"asinvoker":            winres.AsInvoker,
+	"highestavailable":     winres.HighestAvailable,
+	"requireadministrator": winres.RequireAdministrator,
+}
+
+// splitList splits a comma separated list, ignoring empty items.
+func splitList(s string) (list []string) {
+	for item := range strings.SplitSeq(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return
+}
+
+// generateManifest returns the manifest specified by the flags.
+func generateManifest(f *addManifestFlagSet) (*winres.Manifest, error) {
+	m := &winres.Manifest{
+		DPIAware:       f.DPIAware,
+		CommonControls: f.CommonControls,
+		UIAccess:       f.UIAccess,
+		LongPathAware:  f.LongPathAware,
+		HeapType:       f.HeapType,
+	}
+	switch strings.ToLower(f.DPIAware) {
+	case "", "true", "false", "true/pm", "per monitor":
+	default:
+		return nil, fmt.Errorf("invalid dpiAware %q", f.DPIAware)
+	}
+	for _, os := range splitList(f.SupportedOS) {
+		id, ok := supportedOS[strings.ToLower(os)]
+		if !ok {
+			return nil, fmt.Errorf("invalid supported OS %q", os)
+		}
+		m.SupportedOS = append(m.SupportedOS, id)
+	}
+	for _, v := range splitList(f.DPIAwareness) {
+		awareness, ok := dpiAwareness[strings.ToLower(v)]
+		if !ok {
+			return nil, fmt.Errorf("invalid dpiAwareness %q", v)
+		}
+		m.DPIAwareness = append(m.DPIAwareness, awareness)
+	}
+	if f.ExecutionLevel != "" {
+		var ok bool
+		if m.ExecutionLevel, ok = executionLevels[strings.ToLower(f.ExecutionLevel)]; !ok {
+			return nil, fmt.Errorf("invalid execution level %q", f.ExecutionLevel)
+		}
+	}
+	if f.UTF8 {
+		m.ActiveCodePage = "UTF-8"
+	}
+	return m, nil
+}
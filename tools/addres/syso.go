@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mkch/gw/util/winres"
+)
+
+func syso(arguments []string) {
+	sysoFlags.Parse(arguments)
+	args := sysoFlags.Args()
+	if len(args) > 1 {
+		printError("too many arguments.\n")
+		sysoFlags.Usage()
+		os.Exit(1)
+	}
+	outputDir := "."
+	if len(args) == 1 {
+		outputDir = args[0]
+	}
+
+	var res winres.Set
+	lang := uint16(sysoFlags.Lang)
+	if sysoFlags.Icon != "" {
+		iconData, err := readIcon(sysoFlags.Icon, sysoFlags.IconSizes)
+		if err != nil {
+			printError("invalid icon file: %v.\n", err)
+			os.Exit(2)
+		}
+		if err := res.AddIcon(winres.IntID(uint16(sysoFlags.GroupID)), uint16(sysoFlags.FirstIconID), lang, iconData); err != nil {
+			printError("%v.\n", err)
+			os.Exit(2)
+		}
+	}
+	if sysoFlags.Manifest != "" {
+		manifestData, err := os.ReadFile(sysoFlags.Manifest)
+		if err != nil {
+			printError("%v\n", err)
+			os.Exit(2)
+		}
+		// RT_MANIFEST must have id 1.
+		const MANIFEST_RES_ID = 1
+		res.Put(&winres.Resource{
+			Type: winres.IntID(winres.RT_MANIFEST),
+			Name: winres.IntID(MANIFEST_RES_ID),
+			Lang: lang,
+			Data: manifestData,
+		})
+	}
+	if sysoFlags.Strings != "" {
+		strs, err := readStringTable(sysoFlags.Strings)
+		if err != nil {
+			printError("invalid string table file: %v.\n", err)
+			os.Exit(2)
+		}
+		if err := res.AddStrings(lang, strs); err != nil {
+			printError("%v.\n", err)
+			os.Exit(2)
+		}
+	}
+	if sysoFlags.Version != "" {
+		config := &versionConfig{Lang: defaultVersionLang, CodePage: winres.CP_UNICODE}
+		if err := readVersionConfig(sysoFlags.Version, config); err != nil {
+			printError("invalid version information file: %v.\n", err)
+			os.Exit(2)
+		}
+		v, err := config.VersionInfo()
+		if err != nil {
+			printError("invalid version information file: %v.\n", err)
+			os.Exit(2)
+		}
+		if err := res.AddVersionInfo(lang, v); err != nil {
+			printError("%v.\n", err)
+			os.Exit(2)
+		}
+	}
+	if res.Len() == 0 {
+		printError("no resource.\n")
+		sysoFlags.Usage()
+		os.Exit(1)
+	}
+
+	for _, arch := range strings.Split(sysoFlags.Arch, ",") {
+		arch = strings.TrimSpace(arch)
+		name := filepath.Join(outputDir, fmt.Sprintf("rsrc_windows_%v.syso", arch))
+		f, err := os.Create(name)
+		if err != nil {
+			printError("%v\n", err)
+			os.Exit(3)
+		}
+		if err = winres.WriteCOFF(f, &res, arch); err == nil {
+			err = f.Close()
+		} else {
+			f.Close()
+			os.Remove(name)
+		}
+		if err != nil {
+			printError("failed to write %v: %v\n", name, err)
+			os.Exit(3)
+		}
+	}
+}
+
+// readStringTable reads a JSON object of id-string pairs.
+func readStringTable(file string) (map[uint16]string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]string
+	if err = json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	strs := make(map[uint16]string, len(m))
+	for k, v := range m {
+		id, err := strconv.ParseUint(k, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid string id %q", k)
+		}
+		strs[uint16(id)] = v
+	}
+	return strs, nil
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mkch/gw/util/winres"
+	"gopkg.in/yaml.v3"
+)
+
+// versionConfig is the content of a version information file(*.json, *.yaml).
+type versionConfig struct {
+	FileVersion    string   `json:"fileVersion" yaml:"fileVersion"`
+	ProductVersion string   `json:"productVersion" yaml:"productVersion"`
+	FileType       string   `json:"fileType" yaml:"fileType"`
+	FileFlags      []string `json:"fileFlags" yaml:"fileFlags"`
+	// Lang and CodePage are the language and code page of Strings.
+	Lang     uint16            `json:"lang" yaml:"lang"`
+	CodePage uint16            `json:"codePage" yaml:"codePage"`
+	Strings  map[string]string `json:"strings" yaml:"strings"`
+	// Translations are string tables in other languages.
+	Translations []versionTranslation `json:"translations" yaml:"translations"`
+}
+
+type versionTranslation struct {
+	Lang     uint16            `json:"lang" yaml:"lang"`
+	CodePage uint16            `json:"codePage" yaml:"codePage"`
+	Strings  map[string]string `json:"strings" yaml:"strings"`
+}
+
+// defaultVersionLang is the default language of the string tables, U.S. English.
+const defaultVersionLang = 0x409
+
+var fileTypes = map[string]uint32{
+	"app":        winres.VFT_APP,
+	"dll":        winres.VFT_DLL,
+	"drv":        winres.VFT_DRV,
+	"font":       winres.VFT_FONT,
+	"static-lib": winres.VFT_STATIC_LIB,
+}
+
+var fileFlags = map[string]uint32{
+	"debug":        winres.VS_FF_DEBUG,
+	"prerelease":   winres.VS_FF_PRERELEASE,
+	"patched":      winres.VS_FF_PATCHED,
+	"privatebuild": winres.VS_FF_PRIVATEBUILD,
+	"specialbuild": winres.VS_FF_SPECIALBUILD,
+}
+
+// readVersionConfig reads a version information file into config.
+// Files named *.yaml or *.yml are YAML, others are JSON.
+func readVersionConfig(file string, config *versionConfig) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		return yaml.Unmarshal(data, config)
+	default:
+		return json.Unmarshal(data, config)
+	}
+}
+
+// VersionInfo converts config to VersionInfo.
+// FileVersion and ProductVersion strings default to the fixed versions.
+func (config *versionConfig) VersionInfo() (*winres.VersionInfo, error) {
+	if config.FileVersion == "" {
+		return nil, fmt.Errorf("no file version")
+	}
+	fileVersion, err := winres.ParseVersion(config.FileVersion)
+	if err != nil {
+		return nil, err
+	}
+	productVersion, productVersionStr := fileVersion, config.FileVersion
+	if config.ProductVersion != "" {
+		if productVersion, err = winres.ParseVersion(config.ProductVersion); err != nil {
+			return nil, err
+		}
+		productVersionStr = config.ProductVersion
+	}
+	v := winres.NewVersionInfo(fileVersion, productVersion)
+	if config.FileType != "" {
+		var ok bool
+		if v.Fixed.FileType, ok = fileTypes[config.FileType]; !ok {
+			return nil, fmt.Errorf("invalid file type %q", config.FileType)
+		}
+	}
+	for _, f := range config.FileFlags {
+		flag, ok := fileFlags[f]
+		if !ok {
+			return nil, fmt.Errorf("invalid file flag %q", f)
+		}
+		v.Fixed.FileFlags |= flag
+	}
+
+	tables := append([]versionTranslation{{config.Lang, config.CodePage, config.Strings}}, config.Translations...)
+	for _, table := range tables {
+		t := winres.Translation{Lang: table.Lang, CodePage: table.CodePage}
+		if t.Lang == 0 {
+			t.Lang = defaultVersionLang
+		}
+		if t.CodePage == 0 {
+			t.CodePage = winres.CP_UNICODE
+		}
+		v.SetString(t, winres.FileVersion, config.FileVersion)
+		v.SetString(t, winres.ProductVersion, productVersionStr)
+		for key, value := range table.Strings {
+			v.SetString(t, key, value)
+		}
+	}
+	return v, nil
+}
+
+// parseVersionFlags reads the version information file if any, and then overrides
+// it with the explicitly set flags.
+func parseVersionFlags(f *versionFlagSet) (*versionConfig, error) {
+	config := &versionConfig{Lang: uint16(f.Lang), CodePage: uint16(f.CodePage)}
+	if f.Config != "" {
+		if err := readVersionConfig(f.Config, config); err != nil {
+			return nil, err
+		}
+	}
+	f.flags.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "file-version":
+			config.FileVersion = f.FileVersion
+		case "product-version":
+			config.ProductVersion = f.ProductVersion
+		case "file-type":
+			config.FileType = f.FileType
+		case "file-flags":
+			config.FileFlags = strings.Split(f.FileFlags, ",")
+		case "lang":
+			config.Lang = uint16(f.Lang)
+		case "codepage":
+			config.CodePage = uint16(f.CodePage)
+		default:
+			if key, ok := versionStringFlags[fl.Name]; ok {
+				if config.Strings == nil {
+					config.Strings = make(map[string]string)
+				}
+				config.Strings[key] = fl.Value.String()
+			}
+		}
+	})
+	return config, nil
+}
+
+func addVersion(arguments []string) {
+	addVersionFlags.Parse(arguments)
+	args := addVersionFlags.Args()
+	if len(args) < 1 {
+		printError("not enough arguments.\n")
+		addVersionFlags.Usage()
+		os.Exit(1)
+	}
+	config, err := parseVersionFlags(&addVersionFlags)
+	if err != nil {
+		printError("invalid version information file: %v.\n", err)
+		os.Exit(2)
+	}
+	v, err := config.VersionInfo()
+	if err != nil {
+		printError("%v.\n", err)
+		addVersionFlags.Usage()
+		os.Exit(1)
+	}
+
+	updateResource(args[0], func(res *winres.Set) error {
+		return res.AddVersionInfo(config.Lang, v)
+	})
+}
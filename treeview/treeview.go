@@ -0,0 +1,666 @@
+package treeview
+
+import (
+	"errors"
+	"runtime"
+	"slices"
+	"unsafe"
+
+	"github.com/mkch/gg"
+	"github.com/mkch/gw/control"
+	"github.com/mkch/gw/imagelist"
+	"github.com/mkch/gw/internal/uithread"
+	"github.com/mkch/gw/metrics"
+	"github.com/mkch/gw/win32"
+	"github.com/mkch/gw/win32/win32util"
+)
+
+// ErrFailed is returned if a tree view message fails, for example, with a removed node.
+var ErrFailed = errors.New("treeview: failed")
+
+// movedStates are the states of items kept when they are moved.
+const movedStates = win32.TVIS_EXPANDED | win32.TVIS_EXPANDEDONCE | win32.TVIS_BOLD | win32.TVIS_CUT |
+	win32.TVIS_OVERLAYMASK | win32.TVIS_STATEIMAGEMASK
+
+// TreeView is a tree view control, whose nodes carry values of type T.
+type TreeView[T any] struct {
+	control.Control
+	// OnSelChange is called when the selection changes. node is nil if none is selected.
+	OnSelChange func(node *Node[T])
+	// OnExpanding is called before node is expanded or collapsed by the user, or
+	// by Node.Expand for the first time. Returning false prevents the change.
+	OnExpanding func(node *Node[T], expand bool) bool
+	// OnExpanded is called after node is expanded or collapsed, see OnExpanding.
+	OnExpanded func(node *Node[T], expand bool)
+	// HasChildren reports whether a node added by Node.AddLazy has children, before
+	// they are loaded. nil means all of them have children until loaded.
+	HasChildren func(node *Node[T]) bool
+	// LoadChildren adds the children of a node added by Node.AddLazy, when they are
+	// needed for the first time, typically before the node is expanded.
+	LoadChildren func(node *Node[T])
+	// OnCheck is called when the checkbox of node is checked or unchecked.
+	OnCheck func(node *Node[T], checked bool)
+	// OnBeginEdit is called before the label of node is edited.
+	// Returning false prevents the editing. The tree view must be TVS_EDITLABELS.
+	OnBeginEdit func(node *Node[T]) bool
+	// OnEndEdit is called when the user has edited the label of node to text.
+	// Returning false rejects the text. Not called if the editing is canceled.
+	OnEndEdit func(node *Node[T], text string) bool
+	// OnDrop is called when the user drags node and drops it to index among the
+	// children of parent, where index is counted as if node were removed.
+	// Returning false rejects the move. Dragging is enabled only if OnDrop is set.
+	OnDrop   func(node, parent *Node[T], index int) bool
+	root     Node[T]
+	nodes    map[win32.HTREEITEM]*Node[T]
+	dragging *Node[T]
+	moving   bool // In Node.MoveTo, where the selection changes are not reported.
+	images   *imagelist.ImageList
+}
+
+// Node is a node of a tree view.
+type Node[T any] struct {
+	Value    T
+	tree     *TreeView[T]
+	h        win32.HTREEITEM
+	parent   *Node[T] // nil for the root.
+	children []*Node[T]
+	lazy     bool // The children are not loaded yet.
+}
+
+type Spec struct {
+	X      metrics.Dimension
+	Y      metrics.Dimension
+	Width  metrics.Dimension
+	Height metrics.Dimension
+	// Style is the window style and tree view styles, TVS_HASBUTTONS, TVS_HASLINES,
+	// TVS_LINESATROOT, TVS_EDITLABELS, TVS_CHECKBOXES etc.
+	Style   win32.WINDOW_STYLE
+	ExStyle win32.WINDOW_EX_STYLE
+	Images  *imagelist.ImageList // See Node.SetImage.
+}
+
+// New creates a tree view.
+func New[T any](parent win32.HWND, spec *Spec) (*TreeView[T], error) {
+	uithread.Check()
+	icc := win32.INITCOMMONCONTROLSEX{ICC: win32.ICC_TREEVIEW_CLASSES}
+	icc.Size = win32.DWORD(unsafe.Sizeof(icc))
+	if err := win32.InitCommonControlsEx(&icc); err != nil {
+		return nil, err
+	}
+	dpi := gg.Must(win32.GetDpiForWindow(parent))
+	hwnd, err := win32util.CreateWindow(&win32util.Wnd{
+		ClassName: "SysTreeView32",
+		WndParent: parent,
+		X:         spec.X.Px(dpi),
+		Y:         spec.Y.Px(dpi),
+		Width:     spec.Width.Px(dpi),
+		Height:    spec.Height.Px(dpi),
+		// TVS_CHECKBOXES must be set after the tree view is created.
+		Style:   spec.Style&^win32.TVS_CHECKBOXES | win32.WS_CHILD,
+		ExStyle: spec.ExStyle,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if spec.Style&win32.TVS_CHECKBOXES != 0 {
+		if err := win32util.ModifyWindowStyle(hwnd, win32util.ModifyStyleSpec{Add: win32.TVS_CHECKBOXES}); err != nil {
+			win32.DestroyWindow(hwnd)
+			return nil, err
+		}
+	}
+	t := &TreeView[T]{nodes: make(map[win32.HTREEITEM]*Node[T])}
+	t.root = Node[T]{tree: t, h: win32.TVI_ROOT}
+	if err := control.Attach(hwnd, &t.Control); err != nil {
+		win32.DestroyWindow(hwnd)
+		return nil, err
+	}
+	t.SetWndProc(func(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM, prev win32.WndProc) win32.LRESULT {
+		switch message {
+		case win32.WM_MOUSEMOVE:
+			if t.dragging != nil {
+				target, into, ok := t.dropTarget(t.dragging, win32.GET_X_LPARAM(lParam), win32.GET_Y_LPARAM(lParam))
+				t.setDropFeedback(gg.If(ok, target, nil), into)
+				return 0
+			}
+		case win32.WM_LBUTTONUP:
+			if t.dragging != nil {
+				t.drop(win32.GET_X_LPARAM(lParam), win32.GET_Y_LPARAM(lParam))
+				return 0
+			}
+		case win32.WM_KEYDOWN:
+			if t.dragging != nil && wParam == win32.VK_ESCAPE {
+				win32.ReleaseCapture() // Ends dragging with WM_CAPTURECHANGED.
+				return 0
+			}
+		case win32.WM_CAPTURECHANGED:
+			if t.dragging != nil {
+				t.dragging = nil
+				t.setDropFeedback(nil, false)
+			}
+		case win32.WM_NCDESTROY:
+			if t.images != nil {
+				t.images.Release()
+				t.images = nil
+			}
+		}
+		return prev(hwnd, message, wParam, lParam)
+	})
+	control.HandleNotify(&t.Control, win32.TVN_GETDISPINFOW, func(nm *win32.NMTVDISPINFOW) win32.LRESULT {
+		if nm.Item.Mask&win32.TVIF_CHILDREN != 0 {
+			node := t.nodes[nm.Item.Item]
+			nm.Item.Children = gg.If[win32.INT](node == nil || t.HasChildren == nil || t.HasChildren(node), 1, 0)
+		}
+		return 0
+	})
+	control.HandleNotify(&t.Control, win32.TVN_ITEMEXPANDINGW, func(nm *win32.NMTREEVIEWW) win32.LRESULT {
+		node := t.nodes[nm.ItemNew.Item]
+		if node == nil {
+			return 0
+		}
+		expand := nm.Action&win32.TVE_EXPAND != 0
+		if t.OnExpanding != nil && !t.OnExpanding(node, expand) {
+			return 1
+		}
+		if expand {
+			node.load()
+		}
+		return 0
+	})
+	control.HandleNotify(&t.Control, win32.TVN_ITEMEXPANDEDW, func(nm *win32.NMTREEVIEWW) win32.LRESULT {
+		if node := t.nodes[nm.ItemNew.Item]; node != nil && t.OnExpanded != nil {
+			t.OnExpanded(node, nm.Action&win32.TVE_EXPAND != 0)
+		}
+		return 0
+	})
+	control.HandleNotify(&t.Control, win32.TVN_SELCHANGEDW, func(nm *win32.NMTREEVIEWW) win32.LRESULT {
+		if t.OnSelChange != nil && !t.moving {
+			t.OnSelChange(t.nodes[nm.ItemNew.Item])
+		}
+		return 0
+	})
+	control.HandleNotify(&t.Control, win32.TVN_ITEMCHANGEDW, func(nm *win32.NMTVITEMCHANGE) win32.LRESULT {
+		node := t.nodes[nm.Item]
+		if node != nil && t.OnCheck != nil && (nm.StateNew^nm.StateOld)&win32.TVIS_STATEIMAGEMASK != 0 {
+			t.OnCheck(node, nm.StateNew&win32.TVIS_STATEIMAGEMASK == win32.INDEXTOSTATEIMAGEMASK(2))
+		}
+		return 0
+	})
+	control.HandleNotify(&t.Control, win32.TVN_BEGINLABELEDITW, func(nm *win32.NMTVDISPINFOW) win32.LRESULT {
+		node := t.nodes[nm.Item.Item]
+		return gg.If[win32.LRESULT](node == nil || t.OnBeginEdit != nil && !t.OnBeginEdit(node), 1, 0)
+	})
+	control.HandleNotify(&t.Control, win32.TVN_ENDLABELEDITW, func(nm *win32.NMTVDISPINFOW) win32.LRESULT {
+		node := t.nodes[nm.Item.Item]
+		if node == nil || nm.Item.Text == nil { // Canceled.
+			return 0
+		}
+		text := goString(nm.Item.Text)
+		return gg.If[win32.LRESULT](t.OnEndEdit == nil || t.OnEndEdit(node, text), 1, 0)
+	})
+	control.HandleNotify(&t.Control, win32.TVN_BEGINDRAGW, func(nm *win32.NMTREEVIEWW) win32.LRESULT {
+		if node := t.nodes[nm.ItemNew.Item]; node != nil && t.OnDrop != nil {
+			t.dragging = node
+			win32.SetCapture(t.HWND())
+		}
+		return 0
+	})
+
+	if spec.Images != nil {
+		t.SetImages(spec.Images)
+	}
+	return t, nil
+}
+
+// goString converts a null terminated C string of unknown buffer size to go string.
+func goString(p *win32.WCHAR) string {
+	n := 0
+	for *(*win32.WCHAR)(unsafe.Add(unsafe.Pointer(p), n*2)) != 0 {
+		n++
+	}
+	return win32util.GoString(p, n+1)
+}
+
+// send sends a tree view message and converts the failures to ErrFailed.
+// Messages returning 0 on failure only can be sent.
+func (t *TreeView[T]) send(message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) (win32.LRESULT, error) {
+	// The error of SendMessageW is ignored, because tree view messages do not set the last error.
+	r, _ := win32.SendMessageW(t.HWND(), message, wParam, lParam)
+	if r == 0 {
+		return 0, ErrFailed
+	}
+	return r, nil
+}
+
+// Root returns the invisible root node, whose children are the top-level nodes.
+func (t *TreeView[T]) Root() *Node[T] {
+	return &t.root
+}
+
+// Selected returns the selected node, or nil if none.
+func (t *TreeView[T]) Selected() *Node[T] {
+	h, _ := win32.SendMessageW(t.HWND(), win32.TVM_GETNEXTITEM, win32.TVGN_CARET, 0)
+	return t.nodes[win32.HTREEITEM(h)]
+}
+
+// SetImages sets the image list of the icons of nodes, see Node.SetImage.
+// The tree view holds a clone of images. nil removes the image list.
+func (t *TreeView[T]) SetImages(images *imagelist.ImageList) {
+	uithread.CheckWindow(t.HWND())
+	var h win32.HIMAGELIST
+	if images != nil {
+		images = images.Clone()
+		h = images.HIMAGELIST()
+	}
+	win32.SendMessageW(t.HWND(), win32.TVM_SETIMAGELIST, win32.TVSIL_NORMAL, win32.LPARAM(h))
+	if t.images != nil {
+		t.images.Release()
+	}
+	t.images = images
+}
+
+// Clear removes all the nodes.
+func (t *TreeView[T]) Clear() {
+	uithread.CheckWindow(t.HWND())
+	win32.SendMessageW(t.HWND(), win32.TVM_DELETEITEM, 0, win32.LPARAM(t.root.h))
+	// Every node with an item is in t.nodes.
+	for _, node := range t.nodes {
+		node.h = 0
+		node.parent = nil
+	}
+	clear(t.nodes)
+	t.root.children = nil
+	t.root.lazy = false
+}
+
+// dropTarget returns the node at x, y of the client area to drop node, and whether
+// to drop into it as the last child, rather than before it. target is nil for the blank
+// area below the nodes, where node is dropped as the last top-level node.
+// ok is false if node can't be dropped at x, y.
+func (t *TreeView[T]) dropTarget(node *Node[T], x, y int) (target *Node[T], into, ok bool) {
+	info := win32.TVHITTESTINFO{Pt: win32.POINT{X: win32.LONG(x), Y: win32.LONG(y)}}
+	win32.SendMessageW(t.HWND(), win32.TVM_HITTEST, 0, win32.LPARAM(uintptr(unsafe.Pointer(&info))))
+	if target = t.nodes[info.Item]; target == nil {
+		return nil, false, info.Flags&win32.TVHT_NOWHERE != 0
+	}
+	for p := target; p != nil; p = p.parent {
+		if p == node {
+			return nil, false, false
+		}
+	}
+	var rect win32.RECT
+	*(*win32.HTREEITEM)(unsafe.Pointer(&rect)) = target.h
+	win32.SendMessageW(t.HWND(), win32.TVM_GETITEMRECT, 0, win32.LPARAM(uintptr(unsafe.Pointer(&rect))))
+	return target, y >= int(rect.Top+rect.Bottom)/2, true
+}
+
+// setDropFeedback highlights target to drop into it, or shows the insert mark before it.
+// nil target removes the feedback.
+func (t *TreeView[T]) setDropFeedback(target *Node[T], into bool) {
+	var hilite, mark win32.HTREEITEM
+	if target != nil {
+		hilite, mark = gg.If(into, target.h, 0), gg.If(into, 0, target.h)
+	}
+	win32.SendMessageW(t.HWND(), win32.TVM_SELECTITEM, win32.TVGN_DROPHILITE, win32.LPARAM(hilite))
+	win32.SendMessageW(t.HWND(), win32.TVM_SETINSERTMARK, 0, win32.LPARAM(mark))
+}
+
+// drop ends dragging at x, y of the client area, and moves the dragged node there
+// if OnDrop accepts.
+func (t *TreeView[T]) drop(x, y int) {
+	node := t.dragging
+	t.dragging = nil
+	t.setDropFeedback(nil, false)
+	win32.ReleaseCapture()
+	target, into, ok := t.dropTarget(node, x, y)
+	if !ok {
+		return
+	}
+	parent, index := &t.root, len(t.root.children)
+	if target != nil && into {
+		target.load()
+		parent, index = target, len(target.children)
+	} else if target != nil {
+		parent, index = target.parent, target.index()
+	}
+	if parent == node.parent {
+		if i := node.index(); i < index {
+			index--
+		}
+		if node.index() == index {
+			return // Not moved.
+		}
+	}
+	if t.OnDrop(node, parent, index) {
+		node.MoveTo(parent, index)
+	}
+}
+
+// HTREEITEM returns the item of n, or TVI_ROOT if n is the root.
+func (n *Node[T]) HTREEITEM() win32.HTREEITEM {
+	return n.h
+}
+
+// Tree returns the tree view of n.
+func (n *Node[T]) Tree() *TreeView[T] {
+	return n.tree
+}
+
+// Parent returns the parent of n, which is the root for the top-level nodes,
+// or nil if n is the root or removed.
+func (n *Node[T]) Parent() *Node[T] {
+	return n.parent
+}
+
+// Children returns the children of n. The children of a node added by AddLazy are
+// empty until loaded.
+func (n *Node[T]) Children() []*Node[T] {
+	return slices.Clone(n.children)
+}
+
+// index returns the index of n among its siblings.
+func (n *Node[T]) index() int {
+	return slices.Index(n.parent.children, n)
+}
+
+// Text returns the label of n.
+func (n *Node[T]) Text() string {
+	for size := 256; ; size *= 2 {
+		buf := make([]win32.WCHAR, size)
+		item := win32.TVITEMW{Mask: win32.TVIF_TEXT, Item: n.h, Text: &buf[0], TextMax: win32.INT(size)}
+		win32.SendMessageW(n.tree.HWND(), win32.TVM_GETITEMW, 0, win32.LPARAM(uintptr(unsafe.Pointer(&item))))
+		if i := slices.Index(buf, 0); i >= 0 && i < size-1 {
+			return win32util.GoString(&buf[0], i+1)
+		}
+	}
+}
+
+// setItem sets the fields of the item of n specified by item.Mask.
+func (n *Node[T]) setItem(item *win32.TVITEMW) error {
+	item.Mask |= win32.TVIF_HANDLE
+	item.Item = n.h
+	_, err := n.tree.send(win32.TVM_SETITEMW, 0, win32.LPARAM(uintptr(unsafe.Pointer(item))))
+	return err
+}
+
+// SetText sets the label of n.
+func (n *Node[T]) SetText(text string) error {
+	uithread.CheckWindow(n.tree.HWND())
+	var buf []win32.WCHAR
+	win32util.CString(text, &buf)
+	defer runtime.KeepAlive(buf)
+	return n.setItem(&win32.TVITEMW{Mask: win32.TVIF_TEXT, Text: &buf[0]})
+}
+
+// SetImage sets the icons of n in the image list of the tree view, see
+// TreeView.SetImages. selected is the icon when n is selected.
+func (n *Node[T]) SetImage(image, selected int) error {
+	uithread.CheckWindow(n.tree.HWND())
+	return n.setItem(&win32.TVITEMW{
+		Mask:          win32.TVIF_IMAGE | win32.TVIF_SELECTEDIMAGE,
+		Image:         win32.INT(image),
+		SelectedImage: win32.INT(selected),
+	})
+}
+
+// Add appends a child with label text and value to n. The children of n are loaded
+// before, if n is added by AddLazy.
+func (n *Node[T]) Add(text string, value T) (*Node[T], error) {
+	uithread.CheckWindow(n.tree.HWND())
+	return n.add(text, value, false)
+}
+
+// AddLazy is like Add, but the children of the new node are loaded by
+// TreeView.LoadChildren when needed, and TreeView.HasChildren decides whether
+// the node can be expanded until then.
+func (n *Node[T]) AddLazy(text string, value T) (*Node[T], error) {
+	uithread.CheckWindow(n.tree.HWND())
+	return n.add(text, value, true)
+}
+
+func (n *Node[T]) add(text string, value T, lazy bool) (*Node[T], error) {
+	n.load()
+	var buf []win32.WCHAR
+	win32util.CString(text, &buf)
+	defer runtime.KeepAlive(buf)
+	item := win32.TVITEMEXW{TVITEMW: win32.TVITEMW{Mask: win32.TVIF_TEXT, Text: &buf[0]}}
+	if lazy {
+		item.Mask |= win32.TVIF_CHILDREN
+		item.Children = win32.I_CHILDRENCALLBACK
+	}
+	child := &Node[T]{Value: value, tree: n.tree, parent: n, lazy: lazy}
+	if err := child.insert(len(n.children), &item); err != nil {
+		return nil, err
+	}
+	return child, nil
+}
+
+// insert inserts the item of n, whose parent is set, at index among the children of the parent.
+func (n *Node[T]) insert(index int, item *win32.TVITEMEXW) error {
+	ins := win32.TVINSERTSTRUCTW{Parent: n.parent.h, InsertAfter: win32.TVI_FIRST, Item: *item}
+	if index > 0 {
+		ins.InsertAfter = n.parent.children[index-1].h
+	}
+	h, err := n.tree.send(win32.TVM_INSERTITEMW, 0, win32.LPARAM(uintptr(unsafe.Pointer(&ins))))
+	if err != nil {
+		return err
+	}
+	n.h = win32.HTREEITEM(h)
+	n.tree.nodes[n.h] = n
+	n.parent.children = slices.Insert(n.parent.children, index, n)
+	return nil
+}
+
+// load loads the children of n with TreeView.LoadChildren, if n is added by AddLazy
+// and not loaded yet.
+func (n *Node[T]) load() {
+	if !n.lazy {
+		return
+	}
+	n.lazy = false
+	if n.tree.LoadChildren != nil {
+		n.tree.LoadChildren(n)
+	}
+	if n.parent != nil {
+		n.setItem(&win32.TVITEMW{Mask: win32.TVIF_CHILDREN, Children: gg.If[win32.INT](len(n.children) > 0, 1, 0)})
+	}
+}
+
+// Reload removes the children of n, and loads them again with TreeView.LoadChildren
+// when needed, as if n were added by AddLazy. n is collapsed and expanded again if expanded.
+func (n *Node[T]) Reload() error {
+	uithread.CheckWindow(n.tree.HWND())
+	expanded := n.IsExpanded()
+	for len(n.children) > 0 {
+		if err := n.children[len(n.children)-1].Remove(); err != nil {
+			return err
+		}
+	}
+	n.lazy = true
+	if n.parent == nil {
+		n.load()
+		return nil
+	}
+	if err := n.setItem(&win32.TVITEMW{Mask: win32.TVIF_CHILDREN, Children: win32.I_CHILDRENCALLBACK}); err != nil {
+		return err
+	}
+	win32.SendMessageW(n.tree.HWND(), win32.TVM_EXPAND, win32.TVE_COLLAPSE|win32.TVE_COLLAPSERESET, win32.LPARAM(n.h))
+	if expanded {
+		return n.Expand()
+	}
+	return nil
+}
+
+// Remove removes n and its descendants.
+func (n *Node[T]) Remove() error {
+	uithread.CheckWindow(n.tree.HWND())
+	if n.parent == nil || n.h == 0 {
+		return ErrFailed
+	}
+	if _, err := n.tree.send(win32.TVM_DELETEITEM, 0, win32.LPARAM(n.h)); err != nil {
+		return err
+	}
+	n.detach()
+	return nil
+}
+
+// detach removes n from its parent and forgets the items of n and its descendants,
+// after the item of n is deleted.
+func (n *Node[T]) detach() {
+	i := n.index()
+	n.parent.children = slices.Delete(n.parent.children, i, i+1)
+	n.parent = nil
+	var forget func(n *Node[T])
+	forget = func(n *Node[T]) {
+		delete(n.tree.nodes, n.h)
+		n.h = 0
+		for _, child := range n.children {
+			forget(child)
+		}
+	}
+	forget(n)
+}
+
+// MoveTo moves n with its descendants to index among the children of parent,
+// where index is counted as if n were removed. The children of parent are loaded
+// before, if parent is added by AddLazy. The selection is kept, and OnSelChange
+// is not called.
+func (n *Node[T]) MoveTo(parent *Node[T], index int) error {
+	uithread.CheckWindow(n.tree.HWND())
+	if n.parent == nil || parent.tree != n.tree {
+		return ErrFailed
+	}
+	for p := parent; p != nil; p = p.parent {
+		if p == n {
+			return ErrFailed
+		}
+	}
+	parent.load()
+	if count := len(parent.children) - gg.If(parent == n.parent, 1, 0); index < 0 || index > count {
+		return ErrFailed
+	}
+
+	// Save the items before deleted.
+	type saved struct {
+		text                 string
+		image, selectedImage win32.INT
+		state                win32.UINT
+		children             win32.INT
+	}
+	items := make(map[*Node[T]]saved)
+	var save func(n *Node[T])
+	save = func(n *Node[T]) {
+		item := win32.TVITEMW{
+			Mask:      win32.TVIF_IMAGE | win32.TVIF_SELECTEDIMAGE | win32.TVIF_STATE | win32.TVIF_CHILDREN,
+			Item:      n.h,
+			StateMask: movedStates,
+		}
+		win32.SendMessageW(n.tree.HWND(), win32.TVM_GETITEMW, 0, win32.LPARAM(uintptr(unsafe.Pointer(&item))))
+		items[n] = saved{n.Text(), item.Image, item.SelectedImage, item.State, item.Children}
+		for _, child := range n.children {
+			save(child)
+		}
+	}
+	save(n)
+	selected := n.tree.Selected()
+	// Deleting and restoring the selected node changes the selection, which is not
+	// changed by the user.
+	n.tree.moving = true
+	defer func() { n.tree.moving = false }()
+	if err := n.Remove(); err != nil {
+		return err
+	}
+
+	var restore func(n *Node[T], index int) error
+	restore = func(n *Node[T], index int) error {
+		saved := items[n]
+		var buf []win32.WCHAR
+		win32util.CString(saved.text, &buf)
+		defer runtime.KeepAlive(buf)
+		item := win32.TVITEMEXW{TVITEMW: win32.TVITEMW{
+			Mask:          win32.TVIF_TEXT | win32.TVIF_IMAGE | win32.TVIF_SELECTEDIMAGE | win32.TVIF_STATE | win32.TVIF_CHILDREN,
+			Text:          &buf[0],
+			State:         saved.state,
+			StateMask:     movedStates,
+			Image:         saved.image,
+			SelectedImage: saved.selectedImage,
+			Children:      saved.children,
+		}}
+		if err := n.insert(index, &item); err != nil {
+			return err
+		}
+		children := n.children
+		n.children = nil
+		for i, child := range children {
+			if err := restore(child, i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	n.parent = parent
+	if err := restore(n, index); err != nil {
+		return err
+	}
+	for p := selected; p != nil; p = p.parent {
+		if p == n {
+			return selected.Select()
+		}
+	}
+	return nil
+}
+
+// Expand expands n, loading the children if n is added by AddLazy.
+func (n *Node[T]) Expand() error {
+	uithread.CheckWindow(n.tree.HWND())
+	_, err := n.tree.send(win32.TVM_EXPAND, win32.TVE_EXPAND, win32.LPARAM(n.h))
+	return err
+}
+
+// Collapse collapses n.
+func (n *Node[T]) Collapse() error {
+	uithread.CheckWindow(n.tree.HWND())
+	_, err := n.tree.send(win32.TVM_EXPAND, win32.TVE_COLLAPSE, win32.LPARAM(n.h))
+	return err
+}
+
+// IsExpanded reports whether n is expanded.
+func (n *Node[T]) IsExpanded() bool {
+	r, _ := win32.SendMessageW(n.tree.HWND(), win32.TVM_GETITEMSTATE, win32.WPARAM(n.h), win32.TVIS_EXPANDED)
+	return r&win32.TVIS_EXPANDED != 0
+}
+
+// Select selects n.
+func (n *Node[T]) Select() error {
+	uithread.CheckWindow(n.tree.HWND())
+	_, err := n.tree.send(win32.TVM_SELECTITEM, win32.TVGN_CARET, win32.LPARAM(n.h))
+	return err
+}
+
+// EnsureVisible expands the ancestors of n and scrolls the tree view to make n visible.
+func (n *Node[T]) EnsureVisible() {
+	uithread.CheckWindow(n.tree.HWND())
+	win32.SendMessageW(n.tree.HWND(), win32.TVM_ENSUREVISIBLE, 0, win32.LPARAM(n.h))
+}
+
+// Checked reports whether the checkbox of n is checked. The tree view must be TVS_CHECKBOXES.
+func (n *Node[T]) Checked() bool {
+	r, _ := win32.SendMessageW(n.tree.HWND(), win32.TVM_GETITEMSTATE, win32.WPARAM(n.h), win32.TVIS_STATEIMAGEMASK)
+	return win32.UINT(r) == win32.INDEXTOSTATEIMAGEMASK(2)
+}
+
+// SetChecked checks or unchecks the checkbox of n. The tree view must be TVS_CHECKBOXES.
+func (n *Node[T]) SetChecked(checked bool) error {
+	uithread.CheckWindow(n.tree.HWND())
+	return n.setItem(&win32.TVITEMW{
+		Mask:      win32.TVIF_STATE,
+		State:     win32.INDEXTOSTATEIMAGEMASK(gg.If[win32.UINT](checked, 2, 1)),
+		StateMask: win32.TVIS_STATEIMAGEMASK,
+	})
+}
+
+// EditLabel begins editing the label of n. The tree view must be TVS_EDITLABELS.
+func (n *Node[T]) EditLabel() error {
+	uithread.CheckWindow(n.tree.HWND())
+	_, err := n.tree.send(win32.TVM_EDITLABELW, 0, win32.LPARAM(n.h))
+	return err
+}
@@ -0,0 +1,411 @@
+package treeview_test
+
+import (
+	"slices"
+	"testing"
+	"unsafe"
+
+	"github.com/mkch/gw/imagelist"
+	"github.com/mkch/gw/treeview"
+	"github.com/mkch/gw/win32"
+	"github.com/mkch/gw/win32/fake"
+	"github.com/mkch/gw/win32/fake/faketest"
+)
+
+var backend = fake.New()
+
+func TestMain(m *testing.M) {
+	faketest.Main(m, backend)
+}
+
+// newTreeView creates a parent window and a tree view in it.
+func newTreeView[T any](t *testing.T, spec *treeview.Spec) *treeview.TreeView[T] {
+	parent := faketest.NewParent(t)
+	tv, err := treeview.New[T](parent.HWND(), spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tv
+}
+
+// texts returns the labels of nodes.
+func texts[T any](nodes []*treeview.Node[T]) (s []string) {
+	for _, node := range nodes {
+		s = append(s, node.Text())
+	}
+	return
+}
+
+func itemCount[T any](tv *treeview.TreeView[T]) int {
+	n, _ := win32.SendMessageW(tv.HWND(), win32.TVM_GETCOUNT, 0, 0)
+	return int(n)
+}
+
+// The rows of the fake tree views are 16 pixels high, and the items of level l
+// are indented by (l+1)*19 pixels, including the button.
+
+func TestNodes(t *testing.T) {
+	tv := newTreeView[int](t, &treeview.Spec{})
+	root := tv.Root()
+	a, _ := root.Add("a", 1)
+	b, _ := root.Add("b", 2)
+	b1, err := b.Add("b1", 21)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := texts(root.Children()); !slices.Equal(got, []string{"a", "b"}) {
+		t.Fatal(got)
+	}
+	if b1.Parent() != b || b.Parent() != root || root.Parent() != nil || b1.Value != 21 || b1.Tree() != tv {
+		t.Fatal(b1.Parent(), b.Parent(), root.Parent(), b1.Value)
+	}
+	if err := a.SetText("A"); err != nil || a.Text() != "A" {
+		t.Fatal(err, a.Text())
+	}
+	if itemCount(tv) != 3 {
+		t.Fatal(itemCount(tv))
+	}
+
+	if err := b.MoveTo(root, 0); err != nil {
+		t.Fatal(err)
+	}
+	if got := texts(root.Children()); !slices.Equal(got, []string{"b", "A"}) || b.Children()[0] != b1 || b1.Text() != "b1" {
+		t.Fatal(got, b.Children())
+	}
+	if err := a.MoveTo(b, 0); err != nil {
+		t.Fatal(err)
+	}
+	if got := texts(b.Children()); !slices.Equal(got, []string{"A", "b1"}) || a.Parent() != b {
+		t.Fatal(got)
+	}
+	if err := b.MoveTo(a, 0); err != treeview.ErrFailed {
+		t.Fatal(err)
+	}
+	if err := a.MoveTo(root, 2); err != treeview.ErrFailed {
+		t.Fatal(err)
+	}
+
+	if err := b1.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	if got := texts(b.Children()); !slices.Equal(got, []string{"A"}) || itemCount(tv) != 2 {
+		t.Fatal(got, itemCount(tv))
+	}
+	if err := b1.Remove(); err != treeview.ErrFailed {
+		t.Fatal(err)
+	}
+	tv.Clear()
+	if len(root.Children()) != 0 || itemCount(tv) != 0 {
+		t.Fatal(root.Children(), itemCount(tv))
+	}
+}
+
+func TestClear(t *testing.T) {
+	tv := newTreeView[int](t, &treeview.Spec{})
+	a, _ := tv.Root().Add("a", 0)
+	a1, _ := a.Add("a1", 1)
+	tv.Clear()
+	if a.Parent() != nil || a1.Parent() != nil || a.HTREEITEM() != 0 || a1.HTREEITEM() != 0 {
+		t.Fatal(a.Parent(), a1.Parent(), a.HTREEITEM(), a1.HTREEITEM())
+	}
+	if err := a.Remove(); err != treeview.ErrFailed {
+		t.Fatal(err)
+	}
+	if err := a1.Remove(); err != treeview.ErrFailed {
+		t.Fatal(err)
+	}
+	if err := a1.MoveTo(tv.Root(), 0); err != treeview.ErrFailed {
+		t.Fatal(err)
+	}
+	if b, err := tv.Root().Add("b", 2); err != nil || len(tv.Root().Children()) != 1 || itemCount(tv) != 1 {
+		t.Fatal(b, err, itemCount(tv))
+	}
+}
+
+func TestLazyLoading(t *testing.T) {
+	tv := newTreeView[string](t, &treeview.Spec{Style: win32.TVS_HASBUTTONS})
+	var loads []string
+	tv.HasChildren = func(node *treeview.Node[string]) bool { return node.Value != "" }
+	tv.LoadChildren = func(node *treeview.Node[string]) {
+		loads = append(loads, node.Text())
+		for _, c := range node.Value {
+			node.AddLazy(node.Text()+string(c), "")
+		}
+	}
+	var expanded []bool
+	tv.OnExpanded = func(node *treeview.Node[string], expand bool) { expanded = append(expanded, expand) }
+	dir, _ := tv.Root().AddLazy("dir", "xy")
+	empty, _ := tv.Root().AddLazy("empty", "")
+
+	backend.TreeViewClick(tv.HWND(), 4, 4, false) // The button of dir.
+	if !dir.IsExpanded() || !slices.Equal(loads, []string{"dir"}) || !slices.Equal(texts(dir.Children()), []string{"dirx", "diry"}) {
+		t.Fatal(dir.IsExpanded(), loads, texts(dir.Children()))
+	}
+	backend.TreeViewClick(tv.HWND(), 4, 4, false)
+	backend.TreeViewClick(tv.HWND(), 4, 4, false)
+	if !dir.IsExpanded() || len(loads) != 1 || !slices.Equal(expanded, []bool{true, false, true}) {
+		t.Fatal(dir.IsExpanded(), loads, expanded)
+	}
+	backend.TreeViewClick(tv.HWND(), 4, 16*3+4, false) // The button of empty, below dirx and diry.
+	if empty.IsExpanded() || len(loads) != 1 {
+		t.Fatal(empty.IsExpanded(), loads)
+	}
+
+	tv.OnExpanding = func(node *treeview.Node[string], expand bool) bool { return node.Text() != "dirx" }
+	dirx := dir.Children()[0]
+	dirx.Value = "q"
+	if err := dirx.Expand(); err != treeview.ErrFailed || len(loads) != 1 {
+		t.Fatal(err, loads)
+	}
+	tv.OnExpanding = nil
+	dir.Value = "z"
+	if err := dir.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if !dir.IsExpanded() || !slices.Equal(loads, []string{"dir", "dir"}) || !slices.Equal(texts(dir.Children()), []string{"dirz"}) {
+		t.Fatal(dir.IsExpanded(), loads, texts(dir.Children()))
+	}
+	if itemCount(tv) != 3 {
+		t.Fatal(itemCount(tv))
+	}
+	// Adding to a node not loaded loads it first.
+	dirz := dir.Children()[0]
+	dirz.Value = "w"
+	dirz.Add("new", "")
+	if !slices.Equal(texts(dirz.Children()), []string{"dirzw", "new"}) {
+		t.Fatal(texts(dirz.Children()))
+	}
+}
+
+func TestSelection(t *testing.T) {
+	tv := newTreeView[int](t, &treeview.Spec{})
+	var changes []*treeview.Node[int]
+	tv.OnSelChange = func(node *treeview.Node[int]) { changes = append(changes, node) }
+	a, _ := tv.Root().Add("a", 0)
+	a1, _ := a.Add("a1", 1)
+	b, _ := tv.Root().Add("b", 2)
+	if tv.Selected() != nil {
+		t.Fatal(tv.Selected())
+	}
+	backend.TreeViewClick(tv.HWND(), 40, 16+4, false) // The label of b.
+	if tv.Selected() != b || !slices.Equal(changes, []*treeview.Node[int]{b}) {
+		t.Fatal(tv.Selected(), changes)
+	}
+	if err := a1.Select(); err != nil {
+		t.Fatal(err)
+	}
+	if tv.Selected() != a1 || len(changes) != 2 || changes[1] != a1 {
+		t.Fatal(tv.Selected(), changes)
+	}
+	a1.EnsureVisible()
+	if !a.IsExpanded() {
+		t.Fatal("not expanded")
+	}
+	backend.TreeViewClick(tv.HWND(), 40, 4, true) // Double-click a.
+	if tv.Selected() != a || a.IsExpanded() {
+		t.Fatal(tv.Selected(), a.IsExpanded())
+	}
+
+	changes = nil
+	if err := a1.Select(); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.MoveTo(tv.Root(), 1); err != nil {
+		t.Fatal(err)
+	}
+	if tv.Selected() != a1 || !slices.Equal(changes, []*treeview.Node[int]{a1}) {
+		t.Fatal(tv.Selected(), changes)
+	}
+	if err := a1.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	if tv.Selected() != a || !slices.Equal(changes, []*treeview.Node[int]{a1, a}) {
+		t.Fatal(tv.Selected(), changes)
+	}
+}
+
+func TestCheckboxes(t *testing.T) {
+	tv := newTreeView[int](t, &treeview.Spec{Style: win32.TVS_CHECKBOXES})
+	if style, _ := win32.GetWindowLongPtrW(tv.HWND(), win32.GWL_STYLE); style&win32.TVS_CHECKBOXES == 0 {
+		t.Fatalf("%#x", style)
+	}
+	checked := map[int]bool{}
+	tv.OnCheck = func(node *treeview.Node[int], c bool) { checked[node.Value] = c }
+	a, _ := tv.Root().Add("a", 0)
+	b, _ := tv.Root().Add("b", 1)
+	if a.Checked() || b.Checked() {
+		t.Fatal(a.Checked(), b.Checked())
+	}
+	backend.TreeViewClick(tv.HWND(), 19+4, 4, false) // The checkbox of a.
+	if !a.Checked() || !checked[0] {
+		t.Fatal(a.Checked(), checked)
+	}
+	backend.TreeViewClick(tv.HWND(), 60, 4, false) // The label of a.
+	if !a.Checked() {
+		t.Fatal(a.Checked())
+	}
+	backend.TreeViewKeyDown(tv.HWND(), win32.VK_SPACE)
+	if a.Checked() || checked[0] {
+		t.Fatal(a.Checked(), checked)
+	}
+	if err := b.SetChecked(true); err != nil {
+		t.Fatal(err)
+	}
+	if !b.Checked() || !checked[1] {
+		t.Fatal(b.Checked(), checked)
+	}
+	// Checkboxes are kept when moved.
+	b.MoveTo(tv.Root(), 0)
+	if !b.Checked() {
+		t.Fatal(b.Checked())
+	}
+}
+
+func TestEditLabel(t *testing.T) {
+	tv := newTreeView[int](t, &treeview.Spec{Style: win32.TVS_EDITLABELS})
+	a, _ := tv.Root().Add("a", 0)
+	var edited []string
+	tv.OnEndEdit = func(node *treeview.Node[int], text string) bool {
+		edited = append(edited, text)
+		return text != ""
+	}
+	if err := backend.TreeViewEditLabel(tv.HWND(), a.HTREEITEM(), "A"); err != nil {
+		t.Fatal(err)
+	}
+	if a.Text() != "A" || !slices.Equal(edited, []string{"A"}) {
+		t.Fatal(a.Text(), edited)
+	}
+	backend.TreeViewEditLabel(tv.HWND(), a.HTREEITEM(), "")
+	if a.Text() != "A" || len(edited) != 2 {
+		t.Fatal(a.Text(), edited)
+	}
+	tv.OnBeginEdit = func(node *treeview.Node[int]) bool { return false }
+	backend.TreeViewEditLabel(tv.HWND(), a.HTREEITEM(), "x")
+	if a.Text() != "A" || len(edited) != 2 {
+		t.Fatal(a.Text(), edited)
+	}
+	if err := a.EditLabel(); err != treeview.ErrFailed {
+		t.Fatal(err)
+	}
+	tv.OnBeginEdit = nil
+	if err := a.EditLabel(); err != nil {
+		t.Fatal(err)
+	}
+	// Canceled.
+	win32.SendMessageW(tv.HWND(), win32.TVM_ENDEDITLABELNOW, 1, 0)
+	if a.Text() != "A" || len(edited) != 2 {
+		t.Fatal(a.Text(), edited)
+	}
+}
+
+func TestImages(t *testing.T) {
+	images, err := imagelist.New(16, 16, win32.ILC_COLOR32|win32.ILC_MASK)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer images.Release()
+	tv := newTreeView[int](t, &treeview.Spec{Images: images})
+	if h, _ := win32.SendMessageW(tv.HWND(), win32.TVM_GETIMAGELIST, win32.TVSIL_NORMAL, 0); win32.HIMAGELIST(h) != images.HIMAGELIST() {
+		t.Fatal(h)
+	}
+	a, _ := tv.Root().Add("a", 0)
+	if err := a.SetImage(1, 2); err != nil {
+		t.Fatal(err)
+	}
+	a.Add("a1", 1)
+	a.MoveTo(tv.Root(), 0)
+	item := win32.TVITEMW{Mask: win32.TVIF_IMAGE | win32.TVIF_SELECTEDIMAGE, Item: a.HTREEITEM()}
+	win32.SendMessageW(tv.HWND(), win32.TVM_GETITEMW, 0, win32.LPARAM(uintptr(unsafe.Pointer(&item))))
+	if item.Image != 1 || item.SelectedImage != 2 {
+		t.Fatal(item.Image, item.SelectedImage)
+	}
+}
+
+func TestDragDrop(t *testing.T) {
+	tv := newTreeView[int](t, &treeview.Spec{})
+	root := tv.Root()
+	a, _ := root.Add("a", 0)
+	b, _ := root.Add("b", 1)
+	c, _ := root.Add("c", 2)
+	type drop struct {
+		node, parent string
+		index        int
+	}
+	var drops []drop
+	accept := true
+	tv.OnDrop = func(node, parent *treeview.Node[int], index int) bool {
+		drops = append(drops, drop{node.Text(), parent.Text(), index})
+		return accept
+	}
+	drag := func(node *treeview.Node[int], x, y win32.LONG) {
+		t.Helper()
+		if err := backend.TreeViewBeginDrag(tv.HWND(), node.HTREEITEM()); err != nil {
+			t.Fatal(err)
+		}
+		if win32.GetCapture() != tv.HWND() {
+			t.Fatal(win32.GetCapture())
+		}
+		backend.MouseMove(tv.HWND(), x, y)
+		backend.Pump()
+	}
+	dropHilite := func() win32.HTREEITEM {
+		h, _ := win32.SendMessageW(tv.HWND(), win32.TVM_GETNEXTITEM, win32.TVGN_DROPHILITE, 0)
+		return win32.HTREEITEM(h)
+	}
+
+	drag(c, 40, 2) // The upper half of a.
+	backend.MouseUp(tv.HWND(), win32.MK_LBUTTON, 40, 2)
+	backend.Pump()
+	if got := texts(root.Children()); !slices.Equal(got, []string{"c", "a", "b"}) || win32.GetCapture() != 0 {
+		t.Fatal(got, win32.GetCapture())
+	}
+
+	drag(c, 40, 16+12) // The lower half of a.
+	if dropHilite() != a.HTREEITEM() {
+		t.Fatal(dropHilite())
+	}
+	backend.MouseUp(tv.HWND(), win32.MK_LBUTTON, 40, 16+12)
+	backend.Pump()
+	if got := texts(root.Children()); !slices.Equal(got, []string{"a", "b"}) || c.Parent() != a || dropHilite() != 0 {
+		t.Fatal(got, c.Parent().Text(), dropHilite())
+	}
+	if !slices.Equal(drops, []drop{{"c", "", 0}, {"c", "a", 0}}) {
+		t.Fatal(drops)
+	}
+
+	// Into itself.
+	a.Expand()
+	drag(a, 60, 16+12) // The lower half of c.
+	if dropHilite() != 0 {
+		t.Fatal(dropHilite())
+	}
+	backend.MouseUp(tv.HWND(), win32.MK_LBUTTON, 60, 16+12)
+	backend.Pump()
+	if len(drops) != 2 {
+		t.Fatal(drops)
+	}
+
+	// The blank area, rejected.
+	accept = false
+	drag(a, 40, 100)
+	backend.MouseUp(tv.HWND(), win32.MK_LBUTTON, 40, 100)
+	backend.Pump()
+	if got := texts(root.Children()); !slices.Equal(got, []string{"a", "b"}) || drops[2] != (drop{"a", "", 1}) {
+		t.Fatal(got, drops)
+	}
+
+	// Canceled.
+	drag(b, 40, 12)
+	backend.KeyDown(tv.HWND(), win32.VK_ESCAPE)
+	backend.Pump()
+	backend.MouseUp(tv.HWND(), win32.MK_LBUTTON, 40, 12)
+	backend.Pump()
+	if len(drops) != 3 || dropHilite() != 0 || win32.GetCapture() != 0 {
+		t.Fatal(drops, dropHilite(), win32.GetCapture())
+	}
+
+	tv.OnDrop = nil
+	if err := backend.TreeViewBeginDrag(tv.HWND(), b.HTREEITEM()); err != nil || win32.GetCapture() != 0 {
+		t.Fatal(err, win32.GetCapture())
+	}
+}
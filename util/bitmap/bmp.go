@@ -2,7 +2,10 @@ package bitmap
 
 import (
 	"fmt"
+	"image"
+	"image/color"
 	"io"
+	"math"
 	"unsafe"
 )
 
@@ -205,7 +208,9 @@ func (r *countedReader) BytesRead() int {
 
 type Bitmap struct {
 	Info   []byte // BITMAPINFO, info header + color table.
-	Pixels []byte
+	Pixels []byte // The pixel data, which is compressed if the info header says so.
+	// decoded is the decoded image of compressed pixel data.
+	decoded image.Image
 }
 
 // InfoHeaderSize returns the size of bitmap info header(BITMAPINFOHEADER, BITMAPV4HEADER or BITMAPV5HEADER).
@@ -241,8 +246,20 @@ func (bmp *Bitmap) Width() uint32 {
 	return *bmp.InfoHeader().Width()
 }
 
+// Height returns the height of bitmap, which is the absolute value of
+// the height in the info header.
 func (bmp *Bitmap) Height() uint32 {
-	return *bmp.InfoHeader().Height()
+	h := int32(*bmp.InfoHeader().Height())
+	if h < 0 {
+		return uint32(-h)
+	}
+	return uint32(h)
+}
+
+// TopDown returns whether the bitmap is a top-down DIB, whose origin is the upper-left corner.
+// A bottom-up DIB, whose origin is the lower-left corner, has positive height in the info header.
+func (bmp *Bitmap) TopDown() bool {
+	return int32(*bmp.InfoHeader().Height()) < 0
 }
 
 // BitCount returns the number of bits that define each pixel and the maximum number of colors in the bitmap.
@@ -266,66 +283,17 @@ func (bmp *Bitmap) VerticalResolution() int32 {
 // The optimization information is returned if bit count is 16, 24, or 32. See https://msdn.microsoft.com/en-us/library/windows/desktop/dd183376(v=vs.85).aspx
 func (bmp *Bitmap) ColorTable() ColorTable {
 	const rgbQuadSize = unsafe.Sizeof(RgbQuad{})
-	infoHeaderSize := uintptr(bmp.InfoHeaderSize())
-	colorTableLen := (uintptr(len(bmp.Info)) - infoHeaderSize) / rgbQuadSize
-	return (*[_MAX_ADDR_SPACE / rgbQuadSize]RgbQuad)(unsafe.Pointer(uintptr(unsafe.Pointer(&bmp.Info[0])) + infoHeaderSize))[:colorTableLen]
+	offset := uintptr(bmp.InfoHeaderSize()) + uintptr(bitfieldsLen(bmp.InfoHeader()))*unsafe.Sizeof(uint32(0))
+	colorTableLen := (uintptr(len(bmp.Info)) - offset) / rgbQuadSize
+	return (*[_MAX_ADDR_SPACE / rgbQuadSize]RgbQuad)(unsafe.Pointer(uintptr(unsafe.Pointer(&bmp.Info[0])) + offset))[:colorTableLen]
 }
 
+// ColorAt returns the color of pixel at (x, y), where (0, 0) is the upper-left corner.
+//
+// Deprecated: Use At, which supports alpha channel.
 func (bmp *Bitmap) ColorAt(x, y uint32) RGB {
-	height := *bmp.InfoHeader().Height()
-	width := *bmp.InfoHeader().Width()
-	bitCount := *bmp.InfoHeader().BitCount()
-
-	// Flips y.
-	// BMP file has pixels data up side down.
-	y = height - 1 - y
-	bytesPerLine := multipleOf32(width*uint32(bitCount)) / 8
-	lineOffset := bytesPerLine * y
-
-	switch bitCount {
-	case 1:
-		// The byte coutains our bit.
-		b := bmp.Pixels[lineOffset+x/8]
-		i := (b >> (7 - x%8)) & byte(1)
-		q := bmp.ColorTable()[i]
-		return RGB{Red: q.Red, Green: q.Green, Blue: q.Blue}
-	case 4:
-		// The byte coutains our bit.
-		b := bmp.Pixels[lineOffset+x/2]
-		// s is the right shift bits.
-		var s byte
-		if x%2 == 0 {
-			// We want the most significant 4 bits. Shift right 4 bits.
-			s = 4
-		}
-		i := (b >> s) & byte(0xF)
-		q := bmp.ColorTable()[i]
-		return RGB{Red: q.Red, Green: q.Green, Blue: q.Blue}
-	case 8:
-		// The byte coutains our bit.
-		b := bmp.Pixels[lineOffset+x]
-		q := bmp.ColorTable()[b]
-		return RGB{Red: q.Red, Green: q.Green, Blue: q.Blue}
-	case 16:
-		b1 := bmp.Pixels[lineOffset+x*2]
-		b2 := bmp.Pixels[lineOffset+x*2+1]
-		blue := b2 & byte(0x1F)
-		green := ((b2 >> 5) & byte(0x7)) | ((b1 & 0x3) << 3)
-		red := (b1 >> 2) & byte(0x1F)
-		return RGB{Red: red, Green: green, Blue: blue}
-	case 24:
-		blue := bmp.Pixels[lineOffset+x*3]
-		green := bmp.Pixels[lineOffset+x*3+1]
-		red := bmp.Pixels[lineOffset+x*3+2]
-		return RGB{Red: red, Green: green, Blue: blue}
-	case 32:
-		blue := bmp.Pixels[lineOffset+x*4]
-		green := bmp.Pixels[lineOffset+x*4+1]
-		red := bmp.Pixels[lineOffset+x*4+2]
-		return RGB{Red: red, Green: green, Blue: blue}
-	default:
-		panic("Bit count not supported.")
-	}
+	c := color.NRGBAModel.Convert(bmp.At(int(x), int(y))).(color.NRGBA)
+	return RGB{Red: c.R, Green: c.G, Blue: c.B}
 }
 
 // ReadBitmapInfo reads the BITMAPINFO(BITMAPINFOHEADER+Color table).
@@ -367,18 +335,40 @@ func ReadBitmapInfo(r io.Reader) (info []byte, err error) {
 		return
 	}
 	bitCount := *ihdr.BitCount()
-	if bitCount == 0 {
-		err = &FileFormatError{err: "JPEG or PNG compression is not supported."}
+	compression := *ihdr.Compression()
+	switch compression {
+	case BI_RGB:
+		switch bitCount {
+		case 1, 4, 8, 16, 24, 32:
+		default:
+			err = &FileFormatError{err: fmt.Sprintf("Unsupported bit count %v.", bitCount)}
+			return
+		}
+	case BI_BITFIELDS, BI_ALPHABITFIELDS:
+		if bitCount != 16 && bitCount != 32 {
+			err = &FileFormatError{err: fmt.Sprintf("Bit fields of %v bpp bitmap.", bitCount)}
+			return
+		}
+	case BI_RLE8, BI_RLE4:
+		if compression == BI_RLE8 && bitCount != 8 || compression == BI_RLE4 && bitCount != 4 {
+			err = &FileFormatError{err: fmt.Sprintf("RLE compression of %v bpp bitmap.", bitCount)}
+			return
+		}
+		if int32(*ihdr.Height()) < 0 {
+			err = &FileFormatError{err: "Top-down bitmap can't be compressed."}
+			return
+		}
+	case BI_JPEG, BI_PNG:
+	default:
+		err = &FileFormatError{err: fmt.Sprintf("Unsupported compression %v.", compression)}
 		return
 	}
-	compression := *ihdr.Compression()
-	if compression != 0 {
-		err = &FileFormatError{err: "Compressed BMP is not supported."}
+	if compression != BI_RGB && compression != BI_BITFIELDS && compression != BI_ALPHABITFIELDS && *ihdr.SizeImage() == 0 {
+		err = &FileFormatError{err: "No image size of compressed bitmap."}
 		return
 	}
-	sizeImage := *ihdr.SizeImage()
-	if sizeImage == 0 {
-		err = &FileFormatError{err: "JPEG or PNG compression is not supported."}
+	if width, height := int32(*ihdr.Width()), int32(*ihdr.Height()); width <= 0 || height == 0 || height == math.MinInt32 {
+		err = &FileFormatError{err: fmt.Sprintf("Invalid bitmap size %vx%v.", width, height)}
 		return
 	}
 
@@ -392,20 +382,28 @@ func ReadBitmapInfo(r io.Reader) (info []byte, err error) {
 		fallthrough
 	case 8:
 		colorTableEntryCount = 2 << (bitCount - 1)
+		// ClrUsed specifies the actual number of colors, if not 0.
+		if clrUsed := *ihdr.ClrUsed(); clrUsed != 0 && clrUsed < colorTableEntryCount {
+			colorTableEntryCount = clrUsed
+		}
 	default:
 		// Not a real color table. optimization information.
 		colorTableEntryCount = *ihdr.ClrUsed()
+		if colorTableEntryCount > maxColorTableLen {
+			err = &FileFormatError{err: fmt.Sprintf("Too many colors %v.", colorTableEntryCount)}
+			return
+		}
 	}
 
 	// Alloc BITMAPINFO
-	info = make([]byte, uintptr(infoHeaderSize)+uintptr(colorTableEntryCount)*unsafe.Sizeof(RgbQuad{}))
+	masksSize := uintptr(bitfieldsLen(ihdr)) * unsafe.Sizeof(uint32(0))
+	info = make([]byte, uintptr(infoHeaderSize)+masksSize+uintptr(colorTableEntryCount)*unsafe.Sizeof(RgbQuad{}))
 	// copy the already read info header to the head of info.
 	copy(info, (*[_MAX_ADDR_SPACE]byte)(infoHeader)[:uintptr(infoHeaderSize)])
 
-	// Read color table if necessary.
-	if colorTableEntryCount > 0 {
-		colorTable := info[infoHeaderSize:]
-		if _, err = io.ReadFull(r, colorTable); err != nil {
+	// Read color masks and color table if necessary.
+	if len(info) > int(infoHeaderSize) {
+		if _, err = io.ReadFull(r, info[infoHeaderSize:]); err != nil {
 			return
 		}
 	}
@@ -430,23 +428,57 @@ func Read(reader io.Reader) (result *Bitmap, err error) {
 		return
 	}
 
-	ihdr := (*BitmapInfoHeader)(unsafe.Pointer(&info[0]))
-	// Calculate the size of pixel data.
-	pixelsByteCount := PixelDataLen(*ihdr.Width(), *ihdr.Height(), *ihdr.BitCount())
-	// Seek to pixel data.
-	if _, err = io.CopyN(io.Discard, r, int64(*fileHeader.OffBits()-uint32(r.BytesRead()))); err != nil {
+	if err = skipToPixels(r, fileHeader); err != nil {
 		return
 	}
-	pixels := make([]byte, pixelsByteCount)
-	if _, err = io.ReadFull(r, pixels); err != nil {
-		return
+	return readPixels(r, info)
+}
+
+// skipToPixels skips the bytes between the bitmap info and the pixel data.
+func skipToPixels(r *countedReader, fileHeader *BitmapFileHeader) (err error) {
+	if *fileHeader.OffBits() < uint32(r.BytesRead()) {
+		return &FileFormatError{err: fmt.Sprintf("Wrong pixel data offset 0x%X", *fileHeader.OffBits())}
 	}
+	_, err = io.CopyN(io.Discard, r, int64(*fileHeader.OffBits()-uint32(r.BytesRead())))
+	return
+}
 
-	result = &Bitmap{
-		Info:   info,
-		Pixels: pixels,
+// ReadDIB reads a packed device-independent bitmap, which is a BITMAPINFO
+// followed by the pixel data, such as the CF_DIB clipboard format.
+func ReadDIB(r io.Reader) (result *Bitmap, err error) {
+	var info []byte
+	if info, err = ReadBitmapInfo(r); err != nil {
+		return
 	}
+	return readPixels(r, info)
+}
 
+// readPixels reads the pixel data of bitmap info.
+func readPixels(r io.Reader, info []byte) (result *Bitmap, err error) {
+	ihdr := (*BitmapInfoHeader)(unsafe.Pointer(&info[0]))
+	result = &Bitmap{Info: info}
+	// Calculate the size of pixel data.
+	var pixelsByteCount uint64
+	if result.compressed() {
+		pixelsByteCount = uint64(*ihdr.SizeImage())
+		if pixelsByteCount > maxPixelDataLen {
+			err = &FileFormatError{err: fmt.Sprintf("Compressed pixel data too large: %v", pixelsByteCount)}
+			return
+		}
+	} else {
+		pixelsByteCount = uint64(multipleOf32(result.Width()*uint32(result.BitCount()))>>3) * uint64(result.Height())
+		if result.Width() > maxWidth || pixelsByteCount > maxPixelDataLen {
+			err = &FileFormatError{err: fmt.Sprintf("Bitmap too large: %vx%v", result.Width(), result.Height())}
+			return
+		}
+	}
+	result.Pixels = make([]byte, pixelsByteCount)
+	if _, err = io.ReadFull(r, result.Pixels); err != nil {
+		return
+	}
+	if err = result.decode(); err != nil {
+		return
+	}
 	return
 }
 
@@ -0,0 +1,118 @@
+package bitmap
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+)
+
+// Limits of bitmaps to read, to avoid allocating too much memory for invalid files.
+const (
+	maxWidth         = 1 << 16
+	maxPixelDataLen  = 1 << 30
+	maxColorTableLen = 1 << 16
+)
+
+// compressed returns whether the pixel data is compressed.
+func (bmp *Bitmap) compressed() bool {
+	switch *bmp.InfoHeader().Compression() {
+	case BI_RLE8, BI_RLE4, BI_JPEG, BI_PNG:
+		return true
+	}
+	return false
+}
+
+// decode decodes the compressed pixel data.
+func (bmp *Bitmap) decode() (err error) {
+	switch *bmp.InfoHeader().Compression() {
+	case BI_RLE8, BI_RLE4:
+		if uint64(bmp.Width())*uint64(bmp.Height()) > maxPixelDataLen {
+			return &FileFormatError{err: fmt.Sprintf("Bitmap too large: %vx%v", bmp.Width(), bmp.Height())}
+		}
+		bmp.decoded, err = bmp.decodeRLE()
+	case BI_JPEG:
+		if bmp.decoded, err = jpeg.Decode(bytes.NewReader(bmp.Pixels)); err != nil {
+			err = &FileFormatError{err: fmt.Sprintf("Invalid JPEG data: %v", err)}
+		}
+	case BI_PNG:
+		if bmp.decoded, err = png.Decode(bytes.NewReader(bmp.Pixels)); err != nil {
+			err = &FileFormatError{err: fmt.Sprintf("Invalid PNG data: %v", err)}
+		}
+	}
+	return
+}
+
+// decodeRLE decodes BI_RLE8 or BI_RLE4 pixel data.
+// https://learn.microsoft.com/en-us/windows/win32/gdi/bitmap-compression
+// Pixels skipped by delta or end of line escapes are color 0.
+// Pixels beyond the width are ignored.
+func (bmp *Bitmap) decodeRLE() (*image.Paletted, error) {
+	rle4 := *bmp.InfoHeader().Compression() == BI_RLE4
+	width, height := int(bmp.Width()), int(bmp.Height())
+	palette := bmp.palette()
+	// Indexes out of the color table are black.
+	for len(palette) < 1<<bmp.BitCount() {
+		palette = append(palette, color.RGBA{A: 0xFF})
+	}
+	img := image.NewPaletted(image.Rect(0, 0, width, height), palette)
+	// x, y are the coordinates of bottom-up bitmap.
+	x, y := 0, 0
+	set := func(index byte) {
+		if x < width && y < height {
+			img.Pix[(height-1-y)*img.Stride+x] = index
+		}
+		x++
+	}
+	truncated := &FileFormatError{err: "Truncated RLE data."}
+	data := bmp.Pixels
+	for len(data) >= 2 && y < height {
+		count, value := int(data[0]), data[1]
+		data = data[2:]
+		if count > 0 {
+			// Encoded mode: count pixels of value.
+			for i := range count {
+				if rle4 {
+					set(value >> (4 * (1 - i%2)) & 0xF)
+				} else {
+					set(value)
+				}
+			}
+			continue
+		}
+		switch value {
+		case 0: // End of line.
+			x, y = 0, y+1
+		case 1: // End of bitmap.
+			return img, nil
+		case 2: // Delta.
+			if len(data) < 2 {
+				return nil, truncated
+			}
+			x, y = x+int(data[0]), y+int(data[1])
+			data = data[2:]
+		default:
+			// Absolute mode: value pixels, padded to word boundary.
+			n := int(value)
+			size := n
+			if rle4 {
+				size = (n + 1) / 2
+			}
+			size += size % 2
+			if len(data) < size {
+				return nil, truncated
+			}
+			for i := range n {
+				if rle4 {
+					set(data[i/2] >> (4 * (1 - i%2)) & 0xF)
+				} else {
+					set(data[i])
+				}
+			}
+			data = data[size:]
+		}
+	}
+	return img, nil
+}
@@ -0,0 +1,170 @@
+package bitmap_test
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/mkch/gw/util/bitmap"
+)
+
+func readBitmap(t *testing.T, name string) *bitmap.Bitmap {
+	t.Helper()
+	file, err := os.Open("test_data/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	bmp, err := bitmap.Read(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bmp
+}
+
+var (
+	red   = color.RGBA{0xFF, 0, 0, 0xFF}
+	green = color.RGBA{0, 0xFF, 0, 0xFF}
+	blue  = color.RGBA{0, 0, 0xFF, 0xFF}
+	white = color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}
+)
+
+// checkColors checks the colors of img, rows from top to bottom.
+func checkColors(t *testing.T, img image.Image, want [][]color.RGBA) {
+	t.Helper()
+	if size := img.Bounds().Size(); size.Y != len(want) || size.X != len(want[0]) {
+		t.Fatalf("wrong size: %v", size)
+	}
+	for y, row := range want {
+		for x, c := range row {
+			if got := color.RGBAModel.Convert(img.At(x, y)); got != c {
+				t.Errorf("wrong color at %v,%v: %v, want %v", x, y, got, c)
+			}
+		}
+	}
+}
+
+func TestRLE8(t *testing.T) {
+	bmp := readBitmap(t, "rle8.bmp")
+	if _, ok := bmp.ColorModel().(color.Palette); !ok {
+		t.Errorf("wrong color model: %v", bmp.ColorModel())
+	}
+	checkColors(t, bmp, [][]color.RGBA{
+		{red, blue, blue, blue},
+		{red, green, blue, blue},
+		{green, green, green, green},
+	})
+}
+
+func TestRLE4(t *testing.T) {
+	checkColors(t, readBitmap(t, "rle4.bmp"), [][]color.RGBA{
+		{red, green, blue, white, white},
+		{green, blue, green, blue, green},
+	})
+}
+
+func TestTopDown(t *testing.T) {
+	bmp := readBitmap(t, "topdown.bmp")
+	if !bmp.TopDown() || bmp.Height() != 2 {
+		t.Errorf("wrong height: %v, top-down: %v", bmp.Height(), bmp.TopDown())
+	}
+	checkColors(t, bmp, [][]color.RGBA{
+		{red, green},
+		{blue, white},
+	})
+}
+
+func TestEmbeddedPNG(t *testing.T) {
+	bmp := readBitmap(t, "png.bmp")
+	if bmp.ColorModel() != color.NRGBAModel {
+		t.Errorf("wrong color model: %v", bmp.ColorModel())
+	}
+	if bmp.Bounds() != image.Rect(0, 0, 3, 2) {
+		t.Fatalf("wrong bounds: %v", bmp.Bounds())
+	}
+	for y := range 2 {
+		for x := range 3 {
+			i := uint8((y*3 + x) * 4 * 10)
+			if c := bmp.At(x, y); c != (color.NRGBA{i, i + 10, i + 20, i + 30}) {
+				t.Errorf("wrong color at %v,%v: %v", x, y, c)
+			}
+		}
+	}
+}
+
+func TestEmbeddedJPEG(t *testing.T) {
+	data, err := os.ReadFile("test_data/jpeg.bmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds() != image.Rect(0, 0, 16, 8) {
+		t.Fatalf("wrong bounds: %v", img.Bounds())
+	}
+	// JPEG is lossy.
+	near := func(a, b uint8) bool { return a-b < 4 || b-a < 4 }
+	c := color.RGBAModel.Convert(img.At(5, 5)).(color.RGBA)
+	if !near(c.R, 200) || !near(c.G, 100) || !near(c.B, 50) {
+		t.Errorf("wrong color: %v", c)
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width != 16 || config.Height != 8 || config.ColorModel != color.YCbCrModel {
+		t.Errorf("wrong config: %v %v", config, err)
+	}
+}
+
+func TestReadDIB(t *testing.T) {
+	data, err := os.ReadFile("test_data/rle8.bmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Strip BITMAPFILEHEADER.
+	bmp, err := bitmap.ReadDIB(bytes.NewReader(data[14:]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := bmp.At(0, 2); c != green {
+		t.Errorf("wrong color: %v", c)
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	for _, name := range []string{
+		"invalid_bitcount.bmp",
+		"invalid_compression.bmp",
+		"invalid_offbits.bmp",
+		"invalid_png.bmp",
+		"invalid_rle_bitcount.bmp",
+		"invalid_rle_nosize.bmp",
+		"invalid_rle_too_large.bmp",
+		"invalid_rle_topdown.bmp",
+		"invalid_rle_truncated.bmp",
+		"invalid_size.bmp",
+		"invalid_too_large.bmp",
+		"invalid_truncated.bmp",
+	} {
+		t.Run(name, func(t *testing.T) {
+			file, err := os.Open("test_data/" + name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+			_, err = bitmap.Read(file)
+			var formatErr *bitmap.FileFormatError
+			if name == "invalid_truncated.bmp" {
+				if !errors.Is(err, io.ErrUnexpectedEOF) {
+					t.Errorf("got %v, want ErrUnexpectedEOF", err)
+				}
+			} else if !errors.As(err, &formatErr) {
+				t.Errorf("got %v, want FileFormatError", err)
+			}
+		})
+	}
+}
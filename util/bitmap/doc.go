@@ -1,2 +1,2 @@
-// Package bitmap implements *.BMP bitmap image file reading.
+// Package bitmap implements *.BMP bitmap image file reading and writing.
 package bitmap
@@ -0,0 +1,118 @@
+package bitmap
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"unsafe"
+)
+
+const (
+	// LCS_sRGB is the sRGB color space of BitmapV4Header.CSType.
+	LCS_sRGB = 0x73524742
+	// LCS_GM_IMAGES is the image rendering intent of BitmapV5Header.Intent.
+	LCS_GM_IMAGES = 4
+)
+
+// Options are the encoding options.
+type Options struct {
+	// BitCount is the bits per pixel: 8, 24 or 32.
+	// If 0, 8 is used for *image.Paletted of at most 256 colors,
+	// and 32 for others.
+	// Images encoded in 8 bpp must be *image.Paletted.
+	// Images encoded in 24 bpp lose the alpha channel.
+	BitCount uint16
+}
+
+// Encode writes img to w in BMP format with BITMAPV5HEADER.
+// 32 bpp bitmaps have alpha channel, in BI_BITFIELDS compression.
+// opts can be nil to use the default options.
+func Encode(w io.Writer, img image.Image, opts *Options) error {
+	var bitCount uint16
+	if opts != nil {
+		bitCount = opts.BitCount
+	}
+	paletted, _ := img.(*image.Paletted)
+	if bitCount == 0 {
+		if paletted != nil && len(paletted.Palette) <= 256 {
+			bitCount = 8
+		} else {
+			bitCount = 32
+		}
+	}
+	var palette color.Palette
+	switch bitCount {
+	case 8:
+		if paletted == nil || len(paletted.Palette) > 256 {
+			return fmt.Errorf("8 bpp bitmap of non-paletted image")
+		}
+		palette = paletted.Palette
+	case 24, 32:
+	default:
+		return fmt.Errorf("unsupported bit count: %v", bitCount)
+	}
+
+	const fileHeaderSize, infoHeaderSize = unsafe.Sizeof(BitmapFileHeader{}), unsafe.Sizeof(BitmapV5Header{})
+	const rgbQuadSize = unsafe.Sizeof(RgbQuad{})
+	bounds := img.Bounds()
+	width, height := uint32(bounds.Dx()), uint32(bounds.Dy())
+	pixelsSize := PixelDataLen(width, height, bitCount)
+	offBits := uint32(fileHeaderSize+infoHeaderSize) + uint32(len(palette))*uint32(rgbQuadSize)
+	data := make([]byte, offBits+pixelsSize)
+
+	fileHeader := (*BitmapFileHeader)(data)
+	*fileHeader.Type() = 0x4D42 // "BM"
+	*fileHeader.Size() = uint32(len(data))
+	*fileHeader.OffBits() = offBits
+
+	v5 := (*BitmapV5Header)(data[fileHeaderSize:])
+	v4 := v5.BitmapV4Header()
+	hdr := v4.BitmapInfoHeader()
+	*hdr.Size() = uint32(infoHeaderSize)
+	*hdr.Width() = width
+	*hdr.Height() = height
+	*hdr.Planes() = 1
+	*hdr.BitCount() = bitCount
+	*hdr.Compression() = BI_RGB
+	*hdr.SizeImage() = pixelsSize
+	*hdr.ClrUsed() = uint32(len(palette))
+	if bitCount == 32 {
+		*hdr.Compression() = BI_BITFIELDS
+		*v4.RedMask() = 0x00FF0000
+		*v4.GreenMask() = 0x0000FF00
+		*v4.BlueMask() = 0x000000FF
+		*v4.AlphaMask() = 0xFF000000
+	}
+	*v4.CSType() = LCS_sRGB
+	*v5.Intent() = LCS_GM_IMAGES
+
+	table := data[fileHeaderSize+infoHeaderSize:]
+	for i, c := range palette {
+		r, g, b, _ := c.RGBA()
+		q := table[uintptr(i)*rgbQuadSize:]
+		q[0], q[1], q[2] = uint8(b>>8), uint8(g>>8), uint8(r>>8)
+	}
+
+	pixels := data[offBits:]
+	bytesPerLine := pixelsSize / max(height, 1)
+	for y := range height {
+		// Bottom-up.
+		line := pixels[(height-1-y)*bytesPerLine:]
+		for x := range width {
+			px, py := bounds.Min.X+int(x), bounds.Min.Y+int(y)
+			switch bitCount {
+			case 8:
+				line[x] = paletted.ColorIndexAt(px, py)
+			case 24:
+				c := color.NRGBAModel.Convert(img.At(px, py)).(color.NRGBA)
+				line[x*3], line[x*3+1], line[x*3+2] = c.B, c.G, c.R
+			case 32:
+				c := color.NRGBAModel.Convert(img.At(px, py)).(color.NRGBA)
+				line[x*4], line[x*4+1], line[x*4+2], line[x*4+3] = c.B, c.G, c.R, c.A
+			}
+		}
+	}
+	_, err := w.Write(data)
+	return err
+}
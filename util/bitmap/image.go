@@ -0,0 +1,218 @@
+package bitmap
+
+import (
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"math/bits"
+	"unsafe"
+)
+
+// Compression values of BitmapInfoHeader.
+const (
+	BI_RGB            = 0
+	BI_RLE8           = 1
+	BI_RLE4           = 2
+	BI_BITFIELDS      = 3
+	BI_JPEG           = 4
+	BI_PNG            = 5
+	BI_ALPHABITFIELDS = 6
+)
+
+func init() {
+	image.RegisterFormat("bmp", "BM", Decode, DecodeConfig)
+}
+
+// bitfieldsLen returns the number of color masks following the info header.
+// Only BITMAPINFOHEADER has separate masks, the V4 and V5 headers contain them.
+func bitfieldsLen(hdr *BitmapInfoHeader) int {
+	if uintptr(*hdr.Size()) != unsafe.Sizeof(BitmapInfoHeader{}) {
+		return 0
+	}
+	switch *hdr.Compression() {
+	case BI_BITFIELDS:
+		return 3
+	case BI_ALPHABITFIELDS:
+		return 4
+	}
+	return 0
+}
+
+// Masks returns the color masks of 16 and 32 bpp bitmap.
+// Alpha is 0 if the bitmap has no alpha channel.
+func (bmp *Bitmap) Masks() (red, green, blue, alpha uint32) {
+	hdr := bmp.InfoHeader()
+	switch *hdr.Compression() {
+	case BI_BITFIELDS, BI_ALPHABITFIELDS:
+		if v4 := bmp.V4Header(); v4 != nil {
+			return *v4.RedMask(), *v4.GreenMask(), *v4.BlueMask(), *v4.AlphaMask()
+		}
+		masks := bmp.Info[bmp.InfoHeaderSize():]
+		red = binary.LittleEndian.Uint32(masks)
+		green = binary.LittleEndian.Uint32(masks[4:])
+		blue = binary.LittleEndian.Uint32(masks[8:])
+		if bitfieldsLen(hdr) == 4 {
+			alpha = binary.LittleEndian.Uint32(masks[12:])
+		}
+		return
+	}
+	switch bmp.BitCount() {
+	case 16:
+		return 0x7C00, 0x03E0, 0x001F, 0
+	case 32:
+		return 0xFF0000, 0xFF00, 0xFF, 0
+	}
+	return
+}
+
+// hasAlpha returns whether the bitmap has alpha channel.
+func (bmp *Bitmap) hasAlpha() bool {
+	if bmp.BitCount() != 16 && bmp.BitCount() != 32 {
+		return false
+	}
+	_, _, _, alpha := bmp.Masks()
+	return alpha != 0
+}
+
+// ColorModel implements image.Image.
+// It returns the color model of the decoded image for JPEG or PNG compressed bitmaps,
+// a color.Palette for bitmaps with color table,
+// color.NRGBAModel for bitmaps with alpha channel, and color.RGBAModel otherwise.
+func (bmp *Bitmap) ColorModel() color.Model {
+	if bmp.decoded != nil {
+		return bmp.decoded.ColorModel()
+	}
+	switch bmp.BitCount() {
+	case 1, 4, 8:
+		return bmp.palette()
+	}
+	if bmp.hasAlpha() {
+		return color.NRGBAModel
+	}
+	return color.RGBAModel
+}
+
+func (bmp *Bitmap) palette() color.Palette {
+	table := bmp.ColorTable()
+	p := make(color.Palette, len(table))
+	for i, q := range table {
+		p[i] = color.RGBA{R: q.Red, G: q.Green, B: q.Blue, A: 0xFF}
+	}
+	return p
+}
+
+// Bounds implements image.Image.
+func (bmp *Bitmap) Bounds() image.Rectangle {
+	if bmp.decoded != nil {
+		return image.Rectangle{Max: bmp.decoded.Bounds().Size()}
+	}
+	return image.Rect(0, 0, int(bmp.Width()), int(bmp.Height()))
+}
+
+// extract returns the value of v masked by mask, scaled to 8 bits.
+func extract(v, mask uint32) uint8 {
+	if mask == 0 {
+		return 0
+	}
+	v = (v & mask) >> bits.TrailingZeros32(mask)
+	max := mask >> bits.TrailingZeros32(mask)
+	return uint8((uint64(v)*0xFF + uint64(max)/2) / uint64(max))
+}
+
+// At implements image.Image.
+// (0, 0) is the upper-left corner.
+// Compressed bitmaps not returned by Read or ReadDIB are transparent.
+func (bmp *Bitmap) At(x, y int) color.Color {
+	if !(image.Point{x, y}.In(bmp.Bounds())) {
+		return color.RGBA{}
+	}
+	if bmp.decoded != nil {
+		min := bmp.decoded.Bounds().Min
+		return bmp.decoded.At(min.X+x, min.Y+y)
+	}
+	bitCount := bmp.BitCount()
+	if !bmp.TopDown() {
+		// Flips y.
+		// Bottom-up bitmap has pixels data up side down.
+		y = int(bmp.Height()) - 1 - y
+	}
+	bytesPerLine := int(multipleOf32(bmp.Width()*uint32(bitCount)) / 8)
+	if bmp.compressed() || len(bmp.Pixels) < bytesPerLine*(y+1) {
+		// Not decoded or invalid.
+		return color.RGBA{}
+	}
+	line := bmp.Pixels[bytesPerLine*y:]
+
+	var index int
+	switch bitCount {
+	case 1:
+		index = int(line[x/8]>>(7-x%8)) & 1
+	case 4:
+		index = int(line[x/2]>>(4*(1-x%2))) & 0xF
+	case 8:
+		index = int(line[x])
+	case 24:
+		return color.RGBA{R: line[x*3+2], G: line[x*3+1], B: line[x*3], A: 0xFF}
+	case 16, 32:
+		var v uint32
+		if bitCount == 16 {
+			v = uint32(binary.LittleEndian.Uint16(line[x*2:]))
+		} else {
+			v = binary.LittleEndian.Uint32(line[x*4:])
+		}
+		red, green, blue, alpha := bmp.Masks()
+		if alpha == 0 {
+			return color.RGBA{R: extract(v, red), G: extract(v, green), B: extract(v, blue), A: 0xFF}
+		}
+		return color.NRGBA{R: extract(v, red), G: extract(v, green), B: extract(v, blue), A: extract(v, alpha)}
+	default:
+		return color.RGBA{}
+	}
+	table := bmp.ColorTable()
+	if index >= len(table) {
+		return color.RGBA{A: 0xFF}
+	}
+	q := table[index]
+	return color.RGBA{R: q.Red, G: q.Green, B: q.Blue, A: 0xFF}
+}
+
+// Decode reads a BMP file from r and returns it as an image.Image,
+// which is a *Bitmap.
+func Decode(r io.Reader) (image.Image, error) {
+	bmp, err := Read(r)
+	if err != nil {
+		return nil, err
+	}
+	return bmp, nil
+}
+
+// DecodeConfig returns the color model and dimensions of a BMP file
+// without decoding the entire image.
+func DecodeConfig(reader io.Reader) (config image.Config, err error) {
+	r := &countedReader{R: reader}
+	var fileHeader *BitmapFileHeader
+	if fileHeader, err = readFileHeader(r); err != nil {
+		return
+	}
+	var info []byte
+	if info, err = ReadBitmapInfo(r); err != nil {
+		return
+	}
+	bmp := &Bitmap{Info: info}
+	var decodeConfig func(io.Reader) (image.Config, error)
+	switch *bmp.InfoHeader().Compression() {
+	case BI_JPEG:
+		decodeConfig = jpeg.DecodeConfig
+	case BI_PNG:
+		decodeConfig = png.DecodeConfig
+	default:
+		return image.Config{ColorModel: bmp.ColorModel(), Width: int(bmp.Width()), Height: int(bmp.Height())}, nil
+	}
+	if err = skipToPixels(r, fileHeader); err != nil {
+		return
+	}
+	return decodeConfig(r)
+}
@@ -0,0 +1,169 @@
+package bitmap_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"os"
+	"testing"
+
+	"github.com/mkch/gw/util/bitmap"
+)
+
+func TestImageDecode(t *testing.T) {
+	for _, test := range []struct {
+		file       string
+		paletteLen int
+	}{
+		{"mono.bmp", 2},
+		{"16colors.bmp", 16},
+		{"256colors.bmp", 256},
+		{"24bits.bmp", -1},
+	} {
+		data, err := os.ReadFile("test_data/" + test.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		img, format, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if format != "bmp" {
+			t.Errorf("%v: wrong format %v", test.file, format)
+		}
+		if img.Bounds() != image.Rect(0, 0, 100, 100) {
+			t.Errorf("%v: wrong bounds %v", test.file, img.Bounds())
+		}
+		if palette, ok := img.ColorModel().(color.Palette); test.paletteLen == -1 {
+			if img.ColorModel() != color.RGBAModel {
+				t.Errorf("%v: wrong color model", test.file)
+			}
+		} else if !ok || len(palette) != test.paletteLen {
+			t.Errorf("%v: wrong palette", test.file)
+		}
+		config, format, err := image.DecodeConfig(bytes.NewReader(data))
+		if err != nil || format != "bmp" || config.Width != 100 || config.Height != 100 {
+			t.Errorf("%v: wrong config %v %v %v", test.file, config, format, err)
+		}
+	}
+}
+
+func testImage() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(10, 20, 15, 23))
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{uint8(x * 10), uint8(y * 10), 0x80, uint8(x * y)})
+		}
+	}
+	return img
+}
+
+// checkPixels compares got, whose origin is (0, 0), with want.
+func checkPixels(t *testing.T, got, want image.Image, model color.Model) {
+	t.Helper()
+	if got.Bounds().Size() != want.Bounds().Size() {
+		t.Fatalf("wrong size: %v", got.Bounds())
+	}
+	min := want.Bounds().Min
+	for y := range want.Bounds().Dy() {
+		for x := range want.Bounds().Dx() {
+			if g, w := model.Convert(got.At(x, y)), model.Convert(want.At(min.X+x, min.Y+y)); g != w {
+				t.Fatalf("wrong color at %v,%v: %v, want %v", x, y, g, w)
+			}
+		}
+	}
+}
+
+func TestEncode(t *testing.T) {
+	img := testImage()
+	paletted := image.NewPaletted(image.Rect(0, 0, 9, 3), color.Palette{color.RGBA{0xFF, 0, 0, 0xFF}, color.RGBA{0, 0xFF, 0, 0xFF}, color.RGBA{0, 0, 0xFF, 0xFF}})
+	for i := range paletted.Pix {
+		paletted.Pix[i] = uint8(i % 3)
+	}
+	opaque := func(c color.Color) color.Color {
+		nrgba := color.NRGBAModel.Convert(c).(color.NRGBA)
+		nrgba.A = 0xFF
+		return nrgba
+	}
+	for _, test := range []struct {
+		name     string
+		img      image.Image
+		opts     *bitmap.Options
+		bitCount uint16
+		model    color.Model
+	}{
+		{"32", img, nil, 32, color.NRGBAModel},
+		{"24", img, &bitmap.Options{BitCount: 24}, 24, color.ModelFunc(opaque)},
+		{"8", paletted, nil, 8, color.NRGBAModel},
+		{"paletted32", paletted, &bitmap.Options{BitCount: 32}, 32, color.NRGBAModel},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := bitmap.Encode(&buf, test.img, test.opts); err != nil {
+				t.Fatal(err)
+			}
+			bmp, err := bitmap.Read(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bmp.BitCount() != test.bitCount || bmp.V5Header() == nil {
+				t.Fatalf("wrong header: %v bpp, %v bytes", bmp.BitCount(), bmp.InfoHeaderSize())
+			}
+			checkPixels(t, bmp, test.img, test.model)
+		})
+	}
+
+	if err := bitmap.Encode(&bytes.Buffer{}, img, &bitmap.Options{BitCount: 8}); err == nil {
+		t.Error("no error for 8 bpp non-paletted image")
+	}
+	if err := bitmap.Encode(&bytes.Buffer{}, img, &bitmap.Options{BitCount: 16}); err == nil {
+		t.Error("no error for 16 bpp")
+	}
+}
+
+// bitfieldsBMP returns a 2x1 16 bpp BMP with BITMAPINFOHEADER and RGB565 masks.
+func bitfieldsBMP() []byte {
+	le := binary.LittleEndian
+	var b []byte
+	b = append(b, 'B', 'M')
+	b = le.AppendUint32(b, 14+40+12+4)
+	b = le.AppendUint32(b, 0)
+	b = le.AppendUint32(b, 14+40+12)
+	b = le.AppendUint32(b, 40)
+	b = le.AppendUint32(b, 2) // Width
+	b = le.AppendUint32(b, 1) // Height
+	b = le.AppendUint16(b, 1)
+	b = le.AppendUint16(b, 16)
+	b = le.AppendUint32(b, bitmap.BI_BITFIELDS)
+	b = le.AppendUint32(b, 4)
+	b = append(b, make([]byte, 16)...)
+	b = le.AppendUint32(b, 0xF800)
+	b = le.AppendUint32(b, 0x07E0)
+	b = le.AppendUint32(b, 0x001F)
+	b = le.AppendUint16(b, 0xF800) // Red
+	b = le.AppendUint16(b, 0x07E0) // Green
+	return b
+}
+
+func TestBitfields(t *testing.T) {
+	bmp, err := bitmap.Read(bytes.NewReader(bitfieldsBMP()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, g, b, a := bmp.Masks(); r != 0xF800 || g != 0x07E0 || b != 0x001F || a != 0 {
+		t.Errorf("wrong masks: %X %X %X %X", r, g, b, a)
+	}
+	if bmp.ColorModel() != color.RGBAModel {
+		t.Error("wrong color model")
+	}
+	if c := bmp.At(0, 0); c != (color.RGBA{0xFF, 0, 0, 0xFF}) {
+		t.Errorf("wrong color at 0,0: %v", c)
+	}
+	if c := bmp.At(1, 0); c != (color.RGBA{0, 0xFF, 0, 0xFF}) {
+		t.Errorf("wrong color at 1,0: %v", c)
+	}
+	if c := bmp.ColorAt(1, 0); c != (bitmap.RGB{0, 0xFF, 0}) {
+		t.Errorf("wrong ColorAt 1,0: %v", c)
+	}
+}
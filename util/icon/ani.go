@@ -0,0 +1,238 @@
+package icon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// Animated cursor file format(*.ani), a RIFF file of form type "ACON".
+// https://en.wikipedia.org/wiki/ANI_(file_format)
+
+const (
+	// Flags of the ANIHEADER.
+	AF_ICON     = 0x1 // Frames are icon or cursor data, rather than raw bitmaps.
+	AF_SEQUENCE = 0x2 // The "seq " chunk is present.
+
+	aniHeaderSize = 36
+)
+
+// AnimatedCursor is an animated cursor, the content of an animated cursor file(*.ani).
+type AnimatedCursor struct {
+	// Title and Artist are the INAM and IART information, if any.
+	Title, Artist string
+	// Frames are the images of the animation, which are usually cursors(Type 2).
+	Frames []*Icon
+	// DisplayRate is the default display duration of each step, in jiffies(1/60 second).
+	DisplayRate uint32
+	// Sequence is the indexes of frames displayed in each step.
+	// If nil, each frame is a step in order.
+	Sequence []uint32
+	// Rates are the display durations of each step, in jiffies.
+	// If nil, DisplayRate is used for all the steps.
+	Rates []uint32
+}
+
+// Steps returns the number of steps of the animation.
+func (ani *AnimatedCursor) Steps() int {
+	if ani.Sequence != nil {
+		return len(ani.Sequence)
+	}
+	return len(ani.Frames)
+}
+
+// appendChunk appends a RIFF chunk to b, padded to even size.
+func appendChunk(b []byte, id string, data []byte) []byte {
+	b = append(b, id...)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(data)))
+	b = append(b, data...)
+	if len(data)%2 != 0 {
+		b = append(b, 0)
+	}
+	return b
+}
+
+func appendUint32s(b []byte, values []uint32) []byte {
+	for _, v := range values {
+		b = binary.LittleEndian.AppendUint32(b, v)
+	}
+	return b
+}
+
+// WriteAnimatedCursor writes ani to w in the animated cursor file format.
+func WriteAnimatedCursor(w io.Writer, ani *AnimatedCursor) error {
+	steps := ani.Steps()
+	if ani.Rates != nil && len(ani.Rates) != steps {
+		return fmt.Errorf("got %v rates for %v steps", len(ani.Rates), steps)
+	}
+	for _, i := range ani.Sequence {
+		if int(i) >= len(ani.Frames) {
+			return fmt.Errorf("frame %v out of range", i)
+		}
+	}
+
+	le := binary.LittleEndian
+	content := []byte("ACON")
+	if ani.Title != "" || ani.Artist != "" {
+		info := []byte("INFO")
+		if ani.Title != "" {
+			info = appendChunk(info, "INAM", append([]byte(ani.Title), 0))
+		}
+		if ani.Artist != "" {
+			info = appendChunk(info, "IART", append([]byte(ani.Artist), 0))
+		}
+		content = appendChunk(content, "LIST", info)
+	}
+
+	var flags uint32 = AF_ICON
+	if ani.Sequence != nil {
+		flags |= AF_SEQUENCE
+	}
+	header := appendUint32s(nil, []uint32{aniHeaderSize, uint32(len(ani.Frames)), uint32(steps), 0, 0, 0, 0, ani.DisplayRate, flags})
+	content = appendChunk(content, "anih", header)
+	if ani.Rates != nil {
+		content = appendChunk(content, "rate", appendUint32s(nil, ani.Rates))
+	}
+	if ani.Sequence != nil {
+		content = appendChunk(content, "seq ", appendUint32s(nil, ani.Sequence))
+	}
+
+	frames := []byte("fram")
+	for _, frame := range ani.Frames {
+		var buf bytes.Buffer
+		if err := Write(&buf, frame); err != nil {
+			return err
+		}
+		frames = appendChunk(frames, "icon", buf.Bytes())
+	}
+	content = appendChunk(content, "LIST", frames)
+
+	riff := make([]byte, 0, 8+len(content))
+	riff = append(riff, "RIFF"...)
+	riff = le.AppendUint32(riff, uint32(len(content)))
+	riff = append(riff, content...)
+	_, err := w.Write(riff)
+	return err
+}
+
+// riffChunks calls f with the id and data of each chunk in data.
+func riffChunks(data []byte, f func(id string, data []byte) error) error {
+	for len(data) > 0 {
+		if len(data) < 8 {
+			return &FileFormatError{"Truncated RIFF chunk"}
+		}
+		id, size := string(data[:4]), uint64(binary.LittleEndian.Uint32(data[4:]))
+		data = data[8:]
+		if size > uint64(len(data)) {
+			return &FileFormatError{fmt.Sprintf("Truncated RIFF chunk %q", id)}
+		}
+		if err := f(id, data[:size]); err != nil {
+			return err
+		}
+		data = data[min(size+size%2, uint64(len(data))):]
+	}
+	return nil
+}
+
+func readUint32s(data []byte) []uint32 {
+	values := make([]uint32, len(data)/4)
+	for i := range values {
+		values[i] = binary.LittleEndian.Uint32(data[4*i:])
+	}
+	return values
+}
+
+// cString returns the null-terminated string in data.
+func cString(data []byte) string {
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		data = data[:i]
+	}
+	return string(data)
+}
+
+// ReadAnimatedCursor reads an animated cursor file.
+func ReadAnimatedCursor(r io.Reader) (*AnimatedCursor, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "ACON" {
+		return nil, &FileFormatError{"Not an animated cursor file"}
+	}
+	if size := binary.LittleEndian.Uint32(data[4:]); uint64(size)+8 < uint64(len(data)) {
+		data = data[:size+8]
+	}
+
+	ani := &AnimatedCursor{}
+	var header []uint32
+	err = riffChunks(data[12:], func(id string, data []byte) error {
+		switch id {
+		case "anih":
+			if len(data) < aniHeaderSize {
+				return &FileFormatError{"Truncated animated cursor header"}
+			}
+			header = readUint32s(data[:aniHeaderSize])
+		case "rate":
+			ani.Rates = readUint32s(data)
+		case "seq ":
+			ani.Sequence = readUint32s(data)
+		case "LIST":
+			if len(data) < 4 {
+				return &FileFormatError{"Truncated RIFF list"}
+			}
+			switch string(data[:4]) {
+			case "INFO":
+				return riffChunks(data[4:], func(id string, data []byte) error {
+					switch id {
+					case "INAM":
+						ani.Title = cString(data)
+					case "IART":
+						ani.Artist = cString(data)
+					}
+					return nil
+				})
+			case "fram":
+				return riffChunks(data[4:], func(id string, data []byte) error {
+					if id != "icon" {
+						return nil
+					}
+					frame, err := Read(bytes.NewReader(data))
+					if err != nil {
+						return err
+					}
+					ani.Frames = append(ani.Frames, frame)
+					return nil
+				})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if header == nil {
+		return nil, &FileFormatError{"No animated cursor header"}
+	}
+	frames, steps, rate, flags := header[1], header[2], header[7], header[8]
+	if flags&AF_ICON == 0 {
+		return nil, &FileFormatError{"Raw bitmap frames are not supported"}
+	}
+	if int(frames) != len(ani.Frames) {
+		return nil, &FileFormatError{fmt.Sprintf("Got %v frames, want %v", len(ani.Frames), frames)}
+	}
+	ani.DisplayRate = rate
+	if flags&AF_SEQUENCE == 0 {
+		ani.Sequence = nil
+	} else if len(ani.Sequence) != int(steps) {
+		return nil, &FileFormatError{fmt.Sprintf("Got %v sequence steps, want %v", len(ani.Sequence), steps)}
+	} else if slices.ContainsFunc(ani.Sequence, func(i uint32) bool { return i >= frames }) {
+		return nil, &FileFormatError{"Frame index out of range"}
+	}
+	if ani.Rates != nil && len(ani.Rates) != int(steps) {
+		return nil, &FileFormatError{fmt.Sprintf("Got %v rates, want %v", len(ani.Rates), steps)}
+	}
+	return ani, nil
+}
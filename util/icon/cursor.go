@@ -0,0 +1,46 @@
+package icon
+
+import (
+	"fmt"
+	"image"
+	"io"
+)
+
+// Cursor is a static cursor, the content of a cursor file(*.cur).
+// The hotspots of the images are in Entry.CursorHotspot().
+type Cursor struct {
+	Images []Image
+}
+
+// ReadCursor reads a cursor file.
+func ReadCursor(r io.Reader) (*Cursor, error) {
+	ico, err := Read(r)
+	if err != nil {
+		return nil, err
+	}
+	if ico.Type != 2 {
+		return nil, &FileFormatError{fmt.Sprintf("Not a cursor file: type %v", ico.Type)}
+	}
+	return &Cursor{Images: ico.Images}, nil
+}
+
+// WriteCursor writes cur to w in the cursor file format.
+func WriteCursor(w io.Writer, cur *Cursor) error {
+	return Write(w, &Icon{Type: 2, Images: cur.Images})
+}
+
+// NewCursor returns a cursor of img, whose hotspot is the point of img
+// where the click happens. See New for the format of the image data.
+func NewCursor(img image.Image, hotspot image.Point) (*Cursor, error) {
+	if !hotspot.In(img.Bounds()) {
+		return nil, fmt.Errorf("hotspot %v out of image bounds %v", hotspot, img.Bounds())
+	}
+	cursorImage, err := NewImage(img)
+	if err != nil {
+		return nil, err
+	}
+	x, y := cursorImage.Entry.CursorHotspot()
+	*x = uint16(hotspot.X - img.Bounds().Min.X)
+	*y = uint16(hotspot.Y - img.Bounds().Min.Y)
+	return &Cursor{Images: []Image{cursorImage}}, nil
+}
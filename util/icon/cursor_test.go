@@ -0,0 +1,136 @@
+package icon_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/mkch/gw/util/icon"
+)
+
+func testCursor(t *testing.T, size int, hotspot image.Point) *icon.Cursor {
+	t.Helper()
+	cur, err := icon.NewCursor(testImage(size), hotspot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cur
+}
+
+// equalIconImages compares the entries except ImageOffset and the data of images.
+func equalIconImages(a, b []icon.Image) bool {
+	return slices.EqualFunc(a, b, func(a, b icon.Image) bool {
+		return bytes.Equal(a.Entry[:12], b.Entry[:12]) && bytes.Equal(a.Data, b.Data)
+	})
+}
+
+func TestCursor(t *testing.T) {
+	cur := testCursor(t, 32, image.Pt(3, 30))
+	var buf bytes.Buffer
+	if err := icon.WriteCursor(&buf, cur); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	// The type in ICONDIR, and the hotspot in ICONDIRENTRY.
+	if typ := binary.LittleEndian.Uint16(data[2:]); typ != 2 {
+		t.Errorf("wrong type: %v", typ)
+	}
+	if x, y := binary.LittleEndian.Uint16(data[6+4:]), binary.LittleEndian.Uint16(data[6+6:]); x != 3 || y != 30 {
+		t.Errorf("wrong hotspot in file: %v,%v", x, y)
+	}
+
+	got, err := icon.ReadCursor(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equalIconImages(got.Images, cur.Images) {
+		t.Error("cursor differs after round trip")
+	}
+	if x, y := got.Images[0].Entry.CursorHotspot(); *x != 3 || *y != 30 {
+		t.Errorf("wrong hotspot: %v,%v", *x, *y)
+	}
+
+	// An icon is not a cursor.
+	buf.Reset()
+	ico, _ := icon.New(testImage(16))
+	icon.Write(&buf, ico)
+	var formatErr *icon.FileFormatError
+	if _, err := icon.ReadCursor(&buf); !errors.As(err, &formatErr) {
+		t.Errorf("got %v, want FileFormatError", err)
+	}
+
+	if _, err := icon.NewCursor(testImage(16), image.Pt(16, 0)); err == nil {
+		t.Error("no error for hotspot out of image")
+	}
+}
+
+func TestAnimatedCursor(t *testing.T) {
+	frames := []*icon.Icon{
+		{Type: 2, Images: testCursor(t, 16, image.Pt(1, 1)).Images},
+		{Type: 2, Images: testCursor(t, 32, image.Pt(2, 2)).Images},
+	}
+	for _, ani := range []*icon.AnimatedCursor{
+		{Frames: frames, DisplayRate: 10},
+		{
+			Title:       "Busy",
+			Artist:      "mkch",
+			Frames:      frames,
+			DisplayRate: 6,
+			Sequence:    []uint32{0, 1, 1, 0},
+			Rates:       []uint32{1, 2, 3, 4},
+		},
+	} {
+		var buf bytes.Buffer
+		if err := icon.WriteAnimatedCursor(&buf, ani); err != nil {
+			t.Fatal(err)
+		}
+		data := buf.Bytes()
+		if string(data[:4]) != "RIFF" || string(data[8:12]) != "ACON" || int(binary.LittleEndian.Uint32(data[4:])) != len(data)-8 {
+			t.Fatalf("wrong RIFF header: % X", data[:12])
+		}
+		got, err := icon.ReadAnimatedCursor(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		gotFrames, wantFrames := got.Frames, ani.Frames
+		got.Frames, ani.Frames = nil, nil
+		if !reflect.DeepEqual(got, ani) {
+			t.Errorf("got %+v, want %+v", got, ani)
+		}
+		if len(gotFrames) != len(wantFrames) {
+			t.Fatalf("got %v frames, want %v", len(gotFrames), len(wantFrames))
+		}
+		for i := range gotFrames {
+			if gotFrames[i].Type != 2 || !equalIconImages(gotFrames[i].Images, wantFrames[i].Images) {
+				t.Errorf("frame %v differs after round trip", i)
+			}
+		}
+	}
+
+	if err := icon.WriteAnimatedCursor(&bytes.Buffer{}, &icon.AnimatedCursor{Frames: frames, Sequence: []uint32{2}}); err == nil {
+		t.Error("no error for frame index out of range")
+	}
+	if err := icon.WriteAnimatedCursor(&bytes.Buffer{}, &icon.AnimatedCursor{Frames: frames, Rates: []uint32{1}}); err == nil {
+		t.Error("no error for wrong number of rates")
+	}
+}
+
+func TestReadAnimatedCursorInvalid(t *testing.T) {
+	var buf bytes.Buffer
+	icon.WriteAnimatedCursor(&buf, &icon.AnimatedCursor{Frames: []*icon.Icon{{Type: 2}}})
+	valid := buf.Bytes()
+	for name, data := range map[string][]byte{
+		"not RIFF":  []byte("RIFX\x04\x00\x00\x00ACON"),
+		"truncated": valid[:len(valid)-4],
+		"no header": []byte("RIFF\x04\x00\x00\x00ACON"),
+	} {
+		var formatErr *icon.FileFormatError
+		if _, err := icon.ReadAnimatedCursor(bytes.NewReader(data)); !errors.As(err, &formatErr) {
+			t.Errorf("%v: got %v, want FileFormatError", name, err)
+		}
+	}
+}
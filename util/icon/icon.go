@@ -50,11 +50,19 @@ func (entry *IconDirEntry) Planes() *uint16 {
 	return (*uint16)(unsafe.Pointer(&entry[4]))
 }
 
-// Hotspot returns the hotspot of cursor(type == 2).
+// Hotspot returns the first 2 bytes of Planes.
+//
+// Deprecated: The hotspot of cursor is 16-bit, use CursorHotspot.
 func (entry *IconDirEntry) Hotspot() (x, y *uint8) {
 	return &entry[4], &entry[5]
 }
 
+// CursorHotspot returns the hotspot of cursor(type == 2),
+// which takes the place of Planes and BitCount.
+func (entry *IconDirEntry) CursorHotspot() (x, y *uint16) {
+	return (*uint16)(unsafe.Pointer(&entry[4])), (*uint16)(unsafe.Pointer(&entry[6]))
+}
+
 func (entry *IconDirEntry) BitCount() *uint16 {
 	return (*uint16)(unsafe.Pointer(&entry[6]))
 }
@@ -92,7 +100,9 @@ func (entry *GrpIconDirEntry) Planes() *uint16 {
 	return (*uint16)(unsafe.Pointer(&entry[4]))
 }
 
-// Hotspot returns the hotspot of cursor(type == 2).
+// Hotspot returns the first 2 bytes of Planes.
+//
+// Deprecated: RT_GROUP_CURSOR entries have no hotspot, see CursorDirEntry.
 func (entry *GrpIconDirEntry) Hotspot() (x, y *uint8) {
 	return &entry[4], &entry[5]
 }
@@ -109,6 +119,36 @@ func (entry *GrpIconDirEntry) ID() *uint16 {
 	return (*uint16)(unsafe.Pointer(&entry[12]))
 }
 
+// RT_GROUP_CURSOR resource dir entry.
+// The hotspot is stored in the RT_CURSOR resource data.
+type CursorDirEntry [14]byte
+
+func (entry *CursorDirEntry) Width() *uint16 {
+	return (*uint16)(unsafe.Pointer(&entry[0]))
+}
+
+// Height returns the height of cursor, which is doubled for DIB images
+// as the height in the bitmap info header.
+func (entry *CursorDirEntry) Height() *uint16 {
+	return (*uint16)(unsafe.Pointer(&entry[2]))
+}
+
+func (entry *CursorDirEntry) Planes() *uint16 {
+	return (*uint16)(unsafe.Pointer(&entry[4]))
+}
+
+func (entry *CursorDirEntry) BitCount() *uint16 {
+	return (*uint16)(unsafe.Pointer(&entry[6]))
+}
+
+func (entry *CursorDirEntry) BytesInRes() *uint32 {
+	return (*uint32)(unsafe.Pointer(&entry[8]))
+}
+
+func (entry *CursorDirEntry) ID() *uint16 {
+	return (*uint16)(unsafe.Pointer(&entry[12]))
+}
+
 type Image struct {
 	Entry IconDirEntry
 	Data  []byte
@@ -162,8 +202,13 @@ func Read(r io.Reader) (result *Icon, err error) {
 	}
 
 	for i := uint16(0); i < *header.Count(); i++ {
-		start := *result.Images[i].Entry.ImageOffset() - uint32(bytesBeforeData)
-		size := *result.Images[i].Entry.BytesInRes()
+		offset := uint64(*result.Images[i].Entry.ImageOffset())
+		size := uint64(*result.Images[i].Entry.BytesInRes())
+		if offset < uint64(bytesBeforeData) || offset-uint64(bytesBeforeData)+size > uint64(len(data)) {
+			err = &FileFormatError{fmt.Sprintf("Wrong image data of image %v", i)}
+			return
+		}
+		start := offset - uint64(bytesBeforeData)
 		result.Images[i].Data = data[start : start+size]
 	}
 
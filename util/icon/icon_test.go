@@ -1,9 +1,14 @@
 package icon_test
 
 import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
 	"os"
 	"testing"
 
+	"github.com/mkch/gw/util/bitmap"
 	"github.com/mkch/gw/util/icon"
 )
 
@@ -27,3 +32,122 @@ func Test(t *testing.T) {
 	}
 	t.Logf("Total data size: %v\n", totalBytes)
 }
+
+func TestWrite(t *testing.T) {
+	data, err := os.ReadFile("test_data/ico128.ico")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ico, err := icon.Read(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err = icon.Write(&buf, ico); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Error("written icon differs from the original")
+	}
+}
+
+func testImage(size int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for y := range size {
+		for x := range size {
+			// Transparent upper left corner.
+			if x+y >= size/2 {
+				img.SetNRGBA(x, y, color.NRGBA{uint8(x), uint8(y), 0x80, uint8(0xFF - x)})
+			}
+		}
+	}
+	return img
+}
+
+func TestNew(t *testing.T) {
+	imgs := []*image.NRGBA{testImage(16), testImage(20), testImage(256)}
+	ico, err := icon.New(imgs[0], imgs[1], imgs[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err = icon.Write(&buf, ico); err != nil {
+		t.Fatal(err)
+	}
+	if ico, err = icon.Read(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if ico.Type != 1 || len(ico.Images) != 3 {
+		t.Fatalf("got type %v and %v images", ico.Type, len(ico.Images))
+	}
+
+	for i, size := range []uint8{16, 20, 0} {
+		entry := &ico.Images[i].Entry
+		if *entry.Width() != size || *entry.Height() != size || *entry.BitCount() != 32 || *entry.Planes() != 1 {
+			t.Errorf("image %v: wrong entry %v", i, entry)
+		}
+	}
+
+	// The 256px image is PNG.
+	img, err := png.Decode(bytes.NewReader(ico.Images[2].Data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equalImages(img, imgs[2]) {
+		t.Error("wrong PNG image")
+	}
+
+	// The small ones are DIBs.
+	for i, want := range imgs[:2] {
+		if got := decodeDIB(t, ico.Images[i].Data); !equalImages(got, want) {
+			t.Errorf("image %v: wrong DIB", i)
+		}
+	}
+
+	if _, err := icon.New(image.NewNRGBA(image.Rect(0, 0, 257, 16))); err == nil {
+		t.Error("no error for image larger than 256")
+	}
+}
+
+func equalImages(a, b image.Image) bool {
+	if a.Bounds() != b.Bounds() {
+		return false
+	}
+	for y := a.Bounds().Min.Y; y < a.Bounds().Max.Y; y++ {
+		for x := a.Bounds().Min.X; x < a.Bounds().Max.X; x++ {
+			if color.NRGBAModel.Convert(a.At(x, y)) != color.NRGBAModel.Convert(b.At(x, y)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// decodeDIB decodes a 32-bit icon DIB, checking that the AND mask matches the alpha channel.
+func decodeDIB(t *testing.T, data []byte) image.Image {
+	t.Helper()
+	header := (*bitmap.BitmapInfoHeader)(data)
+	width, height := int(*header.Width()), int(*header.Height())/2
+	if *header.BitCount() != 32 || *header.Size() != 40 {
+		t.Fatalf("wrong DIB header: %v", header)
+	}
+	xor := data[40:]
+	and := xor[width*height*4:]
+	andStride := (width + 31) / 32 * 4
+	if len(and) != andStride*height {
+		t.Fatalf("wrong AND mask size: %v", len(and))
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		row := height - 1 - y
+		for x := range width {
+			p := xor[(row*width+x)*4:]
+			c := color.NRGBA{p[2], p[1], p[0], p[3]}
+			img.SetNRGBA(x, y, c)
+			if transparent := and[row*andStride+x/8]&(0x80>>(x%8)) != 0; transparent != (c.A == 0) {
+				t.Fatalf("wrong AND mask at %v,%v", x, y)
+			}
+		}
+	}
+	return img
+}
@@ -0,0 +1,122 @@
+package icon
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"unsafe"
+
+	"github.com/mkch/gw/util/bitmap"
+)
+
+// Write writes ico to w in the icon(or cursor) file format.
+// The BytesInRes and ImageOffset of the entries are computed from the image data.
+func Write(w io.Writer, ico *Icon) (err error) {
+	const headerSize, entrySize = unsafe.Sizeof(IconDirHeader{}), unsafe.Sizeof(IconDirEntry{})
+	if len(ico.Images) > 0xFFFF {
+		return fmt.Errorf("too many images: %v", len(ico.Images))
+	}
+	var header IconDirHeader
+	*header.Type() = ico.Type
+	*header.Count() = uint16(len(ico.Images))
+	if _, err = w.Write(header[:]); err != nil {
+		return
+	}
+	offset := uint32(headerSize + entrySize*uintptr(len(ico.Images)))
+	for i := range ico.Images {
+		entry := ico.Images[i].Entry
+		*entry.BytesInRes() = uint32(len(ico.Images[i].Data))
+		*entry.ImageOffset() = offset
+		offset += *entry.BytesInRes()
+		if _, err = w.Write(entry[:]); err != nil {
+			return
+		}
+	}
+	for i := range ico.Images {
+		if _, err = w.Write(ico.Images[i].Data); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// MaxSize is the max width and height of icon images.
+const MaxSize = 256
+
+// New returns an icon(type 1) of imgs, one Image for each.
+// Images of MaxSize pixels wide or high are PNG compressed, smaller ones are
+// 32-bit DIBs with AND masks.
+func New(imgs ...image.Image) (*Icon, error) {
+	ico := &Icon{Type: 1, Images: make([]Image, len(imgs))}
+	for i, img := range imgs {
+		var err error
+		if ico.Images[i], err = NewImage(img); err != nil {
+			return nil, err
+		}
+	}
+	return ico, nil
+}
+
+// NewImage returns the icon image of img.
+// See New for the format of the image data.
+func NewImage(img image.Image) (result Image, err error) {
+	size := img.Bounds().Size()
+	if size.X <= 0 || size.Y <= 0 || size.X > MaxSize || size.Y > MaxSize {
+		err = fmt.Errorf("invalid icon image size: %vx%v", size.X, size.Y)
+		return
+	}
+	// Width and height of 256 are stored as 0.
+	*result.Entry.Width() = uint8(size.X % MaxSize)
+	*result.Entry.Height() = uint8(size.Y % MaxSize)
+	*result.Entry.Planes() = 1
+	*result.Entry.BitCount() = 32
+	if size.X == MaxSize || size.Y == MaxSize {
+		var buf bytes.Buffer
+		if err = png.Encode(&buf, img); err != nil {
+			return
+		}
+		result.Data = buf.Bytes()
+	} else {
+		result.Data = dib(img)
+	}
+	*result.Entry.BytesInRes() = uint32(len(result.Data))
+	return
+}
+
+// dib returns the 32-bit DIB of img: BITMAPINFOHEADER, bottom-up BGRA pixels(XOR mask)
+// and the 1-bit AND mask, in which 1 means transparent.
+func dib(img image.Image) []byte {
+	const headerSize = unsafe.Sizeof(bitmap.BitmapInfoHeader{})
+	bounds := img.Bounds()
+	width, height := uint32(bounds.Dx()), uint32(bounds.Dy())
+	xorSize := bitmap.PixelDataLen(width, height, 32)
+	andStride := bitmap.PixelDataLen(width, 1, 1)
+	data := make([]byte, uint32(headerSize)+xorSize+andStride*height)
+
+	header := (*bitmap.BitmapInfoHeader)(data)
+	*header.Size() = uint32(headerSize)
+	*header.Width() = width
+	// The height of the XOR and AND masks.
+	*header.Height() = height * 2
+	*header.Planes() = 1
+	*header.BitCount() = 32
+	*header.SizeImage() = uint32(len(data)) - uint32(headerSize)
+
+	xor := data[headerSize:]
+	and := xor[xorSize:]
+	for y := range height {
+		row := height - 1 - y // Bottom-up.
+		for x := range width {
+			c := color.NRGBAModel.Convert(img.At(bounds.Min.X+int(x), bounds.Min.Y+int(y))).(color.NRGBA)
+			p := xor[row*width*4+x*4:]
+			p[0], p[1], p[2], p[3] = c.B, c.G, c.R, c.A
+			if c.A == 0 {
+				and[row*andStride+x/8] |= 0x80 >> (x % 8)
+			}
+		}
+	}
+	return data
+}
@@ -0,0 +1,92 @@
+package winres
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// COFF object file layout.
+// https://learn.microsoft.com/en-us/windows/win32/debug/pe-format#coff-file-header-object-and-image
+
+const (
+	relocationSize    = 10
+	symbolSize        = 18
+	symClassStatic    = 3
+	machineI386       = 0x14C
+	machineAMD64      = 0x8664
+	machineARM64      = 0xAA64
+	relI386DIR32NB    = 0x0007
+	relAMD64ADDR32NB  = 0x0003
+	relARM64ADDR32NB  = 0x0002
+	rsrcSectionNumber = 1
+)
+
+var archs = map[string]struct {
+	machine uint16
+	relType uint16
+}{
+	"386":   {machineI386, relI386DIR32NB},
+	"amd64": {machineAMD64, relAMD64ADDR32NB},
+	"arm64": {machineARM64, relARM64ADDR32NB},
+}
+
+// WriteCOFF writes res to w as a COFF object file for arch, a GOARCH value:
+// "386", "amd64" or "arm64".
+//
+// The Go linker links *.syso files in the package directory automatically,
+// so the output is usually written to a file named rsrc_windows_$GOARCH.syso.
+func WriteCOFF(w io.Writer, res *Set, arch string) error {
+	a, ok := archs[arch]
+	if !ok {
+		return fmt.Errorf("unsupported arch: %v", arch)
+	}
+	// The RVAs in the section are offsets from the start of the section,
+	// the linker adds the section RVA when relocating.
+	content, relocs, err := res.encode(0)
+	if err != nil {
+		return err
+	}
+
+	le := binary.LittleEndian
+	const rawDataOffset = fileHeaderSize + sectionHeaderSize
+	relocOffset := rawDataOffset + uint32(len(content))
+	symOffset := relocOffset + relocationSize*uint32(len(relocs))
+	out := make([]byte, symOffset+symbolSize+4)
+
+	fileHeader := out
+	le.PutUint16(fileHeader, a.machine)
+	le.PutUint16(fileHeader[2:], 1) // NumberOfSections
+	le.PutUint32(fileHeader[8:], symOffset)
+	le.PutUint32(fileHeader[12:], 1) // NumberOfSymbols
+
+	header := out[fileHeaderSize:]
+	copy(header, ".rsrc")
+	le.PutUint32(header[16:], uint32(len(content)))
+	le.PutUint32(header[20:], rawDataOffset)
+	le.PutUint32(header[24:], relocOffset)
+	if len(relocs) > 0xFFFF {
+		return fmt.Errorf("too many resources: %v", len(relocs))
+	}
+	le.PutUint16(header[32:], uint16(len(relocs)))
+	le.PutUint32(header[36:], scnCntInitializedData|scnMemRead)
+
+	copy(out[rawDataOffset:], content)
+
+	for i, offset := range relocs {
+		reloc := out[relocOffset+relocationSize*uint32(i):]
+		le.PutUint32(reloc, offset)
+		le.PutUint32(reloc[4:], 0) // The section symbol.
+		le.PutUint16(reloc[8:], a.relType)
+	}
+
+	sym := out[symOffset:]
+	copy(sym, ".rsrc")
+	le.PutUint16(sym[12:], rsrcSectionNumber)
+	sym[16] = symClassStatic
+	// The string table is empty, only its size(4) is written.
+	le.PutUint32(out[symOffset+symbolSize:], 4)
+
+	_, err = w.Write(out)
+	return err
+}
@@ -0,0 +1,91 @@
+package winres_test
+
+import (
+	"bytes"
+	"debug/pe"
+	"errors"
+	"maps"
+	"testing"
+
+	"github.com/mkch/gw/util/winres"
+)
+
+func TestWriteCOFF(t *testing.T) {
+	var res winres.Set
+	if err := res.AddIcon(winres.IntID(1), 1, 0, testIcon()); err != nil {
+		t.Fatal(err)
+	}
+	res.Put(&winres.Resource{Type: winres.IntID(winres.RT_MANIFEST), Name: winres.IntID(1), Data: []byte("<assembly></assembly>")})
+	if err := res.AddStrings(0x409, map[uint16]string{1: "one", 17: "seventeen"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		arch    string
+		machine uint16
+		relType uint16
+	}{
+		{"amd64", pe.IMAGE_FILE_MACHINE_AMD64, 3},
+		{"386", pe.IMAGE_FILE_MACHINE_I386, 7},
+		{"arm64", pe.IMAGE_FILE_MACHINE_ARM64, 2},
+	} {
+		t.Run(test.arch, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := winres.WriteCOFF(&buf, &res, test.arch); err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, "rsrc_windows_"+test.arch+".syso", buf.Bytes())
+
+			f, err := pe.NewFile(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			if f.Machine != test.machine {
+				t.Errorf("wrong machine: 0x%X", f.Machine)
+			}
+			s := f.Section(".rsrc")
+			if s == nil || len(f.Sections) != 1 {
+				t.Fatal("no .rsrc section")
+			}
+			// 2 icons, 1 group icon, 1 manifest and 2 string tables.
+			if len(s.Relocs) != 6 {
+				t.Errorf("got %v relocations, want 6", len(s.Relocs))
+			}
+			for _, r := range s.Relocs {
+				if r.Type != test.relType || r.SymbolTableIndex != 0 {
+					t.Errorf("wrong relocation: %+v", r)
+				}
+			}
+			if len(f.Symbols) != 1 || f.Symbols[0].Name != ".rsrc" || f.Symbols[0].SectionNumber != 1 {
+				t.Errorf("wrong symbols: %v", f.Symbols)
+			}
+		})
+	}
+
+	if err := winres.WriteCOFF(&bytes.Buffer{}, &res, "mips"); err == nil {
+		t.Error("no error for unsupported arch")
+	}
+}
+
+func TestStrings(t *testing.T) {
+	strs := map[uint16]string{0: "zero", 15: "fifteen", 16: "sixteen", 100: "一百"}
+	var res winres.Set
+	if err := res.AddStrings(0x409, strs); err != nil {
+		t.Fatal(err)
+	}
+	if res.Len() != 3 {
+		t.Errorf("got %v string tables, want 3", res.Len())
+	}
+	if got, err := res.Strings(0x409); err != nil {
+		t.Fatal(err)
+	} else if !maps.Equal(got, strs) {
+		t.Errorf("got %v, want %v", got, strs)
+	}
+	if got, err := res.Strings(0x804); err != nil || len(got) != 0 {
+		t.Errorf("got %v %v, want empty table", got, err)
+	}
+	if err := res.AddStrings(0x409, map[uint16]string{1: "one"}); !errors.Is(err, winres.ErrExist) {
+		t.Errorf("got %v, want ErrExist", err)
+	}
+}
@@ -0,0 +1,79 @@
+package winres
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"unsafe"
+
+	"github.com/mkch/gw/util/icon"
+)
+
+// pngSignature is the first 8 bytes of PNG files.
+const pngSignature = "\x89PNG\r\n\x1a\n"
+
+// AddCursor adds the images of cur as RT_CURSOR resources with consecutive IDs starting
+// from firstID, and a RT_GROUP_CURSOR resource named group referring to them.
+// The data of a RT_CURSOR resource is the hotspot followed by the image data.
+// It returns ErrExist if any of the resources exists.
+func (s *Set) AddCursor(group ID, firstID uint16, lang uint16, cur *icon.Cursor) error {
+	if len(s.Lookup(IntID(RT_GROUP_CURSOR), group)) != 0 {
+		return fmt.Errorf("%w: group cursor %v", ErrExist, group)
+	}
+	for i := range cur.Images {
+		if id := IntID(firstID + uint16(i)); len(s.Lookup(IntID(RT_CURSOR), id)) != 0 {
+			return fmt.Errorf("%w: cursor %v", ErrExist, id)
+		}
+	}
+
+	const hdrSize, entrySize = unsafe.Sizeof(icon.IconDirHeader{}), unsafe.Sizeof(icon.CursorDirEntry{})
+	groupData := make([]byte, hdrSize+uintptr(len(cur.Images))*entrySize)
+	hdr := (*icon.IconDirHeader)(groupData)
+	*hdr.Type() = 2
+	*hdr.Count() = uint16(len(cur.Images))
+	for i := range cur.Images {
+		img := &cur.Images[i]
+		id := firstID + uint16(i)
+		x, y := img.Entry.CursorHotspot()
+		data := binary.LittleEndian.AppendUint16(nil, *x)
+		data = binary.LittleEndian.AppendUint16(data, *y)
+		data = append(data, img.Data...)
+		s.Put(&Resource{Type: IntID(RT_CURSOR), Name: IntID(id), Lang: lang, Data: data})
+
+		width, height := uint16(*img.Entry.Width()), uint16(*img.Entry.Height())
+		// Width and height of 256 are stored as 0.
+		if width == 0 {
+			width = icon.MaxSize
+		}
+		if height == 0 {
+			height = icon.MaxSize
+		}
+		var bitCount uint16 = 32
+		if !bytes.HasPrefix(img.Data, []byte(pngSignature)) {
+			// The height of the XOR and AND masks, as in the bitmap info header.
+			height *= 2
+			if len(img.Data) >= 16 {
+				bitCount = binary.LittleEndian.Uint16(img.Data[14:])
+			}
+		}
+		entry := (*icon.CursorDirEntry)(groupData[hdrSize+uintptr(i)*entrySize:])
+		*entry.Width() = width
+		*entry.Height() = height
+		*entry.Planes() = 1
+		*entry.BitCount() = bitCount
+		*entry.BytesInRes() = uint32(len(data))
+		*entry.ID() = id
+	}
+	s.Put(&Resource{Type: IntID(RT_GROUP_CURSOR), Name: group, Lang: lang, Data: groupData})
+	return nil
+}
+
+// AddAnimatedCursor adds ani as the RT_ANICURSOR resource named name.
+// It returns ErrExist if the resource exists.
+func (s *Set) AddAnimatedCursor(name ID, lang uint16, ani *icon.AnimatedCursor) error {
+	var buf bytes.Buffer
+	if err := icon.WriteAnimatedCursor(&buf, ani); err != nil {
+		return err
+	}
+	return s.Add(&Resource{Type: IntID(RT_ANICURSOR), Name: name, Lang: lang, Data: buf.Bytes()})
+}
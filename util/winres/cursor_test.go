@@ -0,0 +1,94 @@
+package winres_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/mkch/gw/util/icon"
+	"github.com/mkch/gw/util/winres"
+)
+
+func testCursor() *icon.Cursor {
+	ico := testIcon()
+	for i := range ico.Images {
+		x, y := ico.Images[i].Entry.CursorHotspot()
+		*x, *y = uint16(i), uint16(i+1)
+	}
+	// A DIB image header with 32 bpp.
+	binary.LittleEndian.PutUint16(ico.Images[0].Data[14:], 32)
+	// A PNG image of 256x256.
+	ico.Images[1].Data = append([]byte("\x89PNG\r\n\x1a\n"), ico.Images[1].Data...)
+	*ico.Images[1].Entry.Width() = 0
+	*ico.Images[1].Entry.Height() = 0
+	return &icon.Cursor{Images: ico.Images}
+}
+
+func TestAddCursor(t *testing.T) {
+	cur := testCursor()
+	var res winres.Set
+	if err := res.AddCursor(winres.IntID(1), 10, 0x409, cur); err != nil {
+		t.Fatal(err)
+	}
+	for i := range cur.Images {
+		r := res.Get(winres.IntID(winres.RT_CURSOR), winres.IntID(uint16(10+i)), 0x409)
+		if r == nil {
+			t.Fatalf("no cursor %v", 10+i)
+		}
+		want := append([]byte{byte(i), 0, byte(i + 1), 0}, cur.Images[i].Data...)
+		if !bytes.Equal(r.Data, want) {
+			t.Errorf("cursor %v: got %v, want %v", i, r.Data, want)
+		}
+	}
+
+	group := res.Get(winres.IntID(winres.RT_GROUP_CURSOR), winres.IntID(1), 0x409)
+	if group == nil {
+		t.Fatal("no group cursor")
+	}
+	hdr := (*icon.IconDirHeader)(group.Data)
+	if *hdr.Type() != 2 || *hdr.Count() != 2 {
+		t.Fatalf("got type %v count %v", *hdr.Type(), *hdr.Count())
+	}
+	for i, want := range []struct{ width, height, bitCount uint16 }{{16, 32, 32}, {256, 256, 32}} {
+		entry := (*icon.CursorDirEntry)(group.Data[6+14*i:])
+		if *entry.Width() != want.width || *entry.Height() != want.height || *entry.Planes() != 1 ||
+			*entry.BitCount() != want.bitCount || *entry.ID() != uint16(10+i) ||
+			*entry.BytesInRes() != uint32(len(cur.Images[i].Data)+4) {
+			t.Errorf("entry %v: got %v", i, *entry)
+		}
+	}
+
+	if err := res.AddCursor(winres.IntID(1), 20, 0x409, cur); !errors.Is(err, winres.ErrExist) {
+		t.Errorf("got %v, want ErrExist", err)
+	}
+	if err := res.AddCursor(winres.IntID(2), 11, 0x409, cur); !errors.Is(err, winres.ErrExist) {
+		t.Errorf("got %v, want ErrExist", err)
+	}
+}
+
+func TestAddAnimatedCursor(t *testing.T) {
+	cur := testCursor()
+	ani := &icon.AnimatedCursor{
+		Frames:      []*icon.Icon{{Type: 2, Images: cur.Images[:1]}, {Type: 2, Images: cur.Images[1:]}},
+		DisplayRate: 10,
+	}
+	var res winres.Set
+	if err := res.AddAnimatedCursor(winres.StrID("BUSY"), 0x409, ani); err != nil {
+		t.Fatal(err)
+	}
+	r := res.Get(winres.IntID(winres.RT_ANICURSOR), winres.StrID("BUSY"), 0x409)
+	if r == nil {
+		t.Fatal("no animated cursor")
+	}
+	got, err := icon.ReadAnimatedCursor(bytes.NewReader(r.Data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Frames) != 2 || got.DisplayRate != 10 {
+		t.Errorf("got %+v", got)
+	}
+	if err := res.AddAnimatedCursor(winres.StrID("BUSY"), 0x804, ani); !errors.Is(err, winres.ErrExist) {
+		t.Errorf("got %v, want ErrExist", err)
+	}
+}
@@ -0,0 +1,44 @@
+package winres
+
+import (
+	"fmt"
+	"unsafe"
+
+	"github.com/mkch/gw/util/icon"
+)
+
+// AddIcon adds the images of ico as RT_ICON resources with consecutive IDs starting
+// from firstID, and a RT_GROUP_ICON resource named group referring to them.
+// It returns ErrExist if any of the resources exists.
+func (s *Set) AddIcon(group ID, firstID uint16, lang uint16, ico *icon.Icon) error {
+	if len(s.Lookup(IntID(RT_GROUP_ICON), group)) != 0 {
+		return fmt.Errorf("%w: group icon %v", ErrExist, group)
+	}
+	for i := range ico.Images {
+		if id := IntID(firstID + uint16(i)); len(s.Lookup(IntID(RT_ICON), id)) != 0 {
+			return fmt.Errorf("%w: icon %v", ErrExist, id)
+		}
+	}
+
+	const hdrSize, entrySize = unsafe.Sizeof(icon.IconDirHeader{}), unsafe.Sizeof(icon.GrpIconDirEntry{})
+	groupData := make([]byte, hdrSize+uintptr(len(ico.Images))*entrySize)
+	hdr := (*icon.IconDirHeader)(groupData)
+	*hdr.Type() = ico.Type
+	*hdr.Count() = uint16(len(ico.Images))
+	for i := range ico.Images {
+		img := &ico.Images[i]
+		id := firstID + uint16(i)
+		s.Put(&Resource{Type: IntID(RT_ICON), Name: IntID(id), Lang: lang, Data: img.Data})
+
+		entry := (*icon.GrpIconDirEntry)(groupData[hdrSize+uintptr(i)*entrySize:])
+		*entry.Width() = *img.Entry.Width()
+		*entry.Height() = *img.Entry.Height()
+		*entry.ColorCount() = *img.Entry.ColorCount()
+		*entry.Planes() = *img.Entry.Planes()
+		*entry.BitCount() = *img.Entry.BitCount()
+		*entry.BytesInRes() = uint32(len(img.Data))
+		*entry.ID() = id
+	}
+	s.Put(&Resource{Type: IntID(RT_GROUP_ICON), Name: group, Lang: lang, Data: groupData})
+	return nil
+}
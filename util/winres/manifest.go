@@ -0,0 +1,203 @@
+package winres
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// Application manifest.
+// https://learn.microsoft.com/en-us/windows/win32/sbscs/application-manifests
+
+// DPIAwareness is a value of the dpiAwareness element.
+type DPIAwareness string
+
+const (
+	DPIUnaware      DPIAwareness = "unaware"
+	DPISystem       DPIAwareness = "system"
+	DPIPerMonitor   DPIAwareness = "PerMonitor"
+	DPIPerMonitorV2 DPIAwareness = "PerMonitorV2"
+)
+
+// ExecutionLevel is a value of the level attribute of requestedExecutionLevel.
+type ExecutionLevel string
+
+const (
+	AsInvoker            ExecutionLevel = "asInvoker"
+	HighestAvailable     ExecutionLevel = "highestAvailable"
+	RequireAdministrator ExecutionLevel = "requireAdministrator"
+)
+
+// IDs of the supportedOS element.
+const (
+	SupportedOSWindowsVista = "{e2011457-1546-43c5-a5fe-008deee3d3f0}"
+	SupportedOSWindows7     = "{35138b9a-5d96-4fbd-8e2d-a2440225f93a}"
+	SupportedOSWindows8     = "{4a2f28e3-53b9-4441-ba9c-d69d4a4a6e38}"
+	SupportedOSWindows81    = "{1f676c76-80e1-4239-95bb-83d0f6d0da78}"
+	SupportedOSWindows10    = "{8e0f7a12-bfb3-4fe8-b9a5-48fd50a15a9a}" // Also Windows 11.
+)
+
+// Manifest is an application manifest.
+// The zero value is a valid manifest without any settings.
+type Manifest struct {
+	// Name and Version are the assembly identity. Omitted if Name is empty.
+	Name    string
+	Version Version
+	// Description is the description element. Omitted if empty.
+	Description string
+	// SupportedOS are the IDs of the supported OS, SupportedOSWindows10 etc.
+	SupportedOS []string
+	// DPIAware is the legacy dpiAware setting: "true", "false", "true/pm" or "per monitor".
+	// It is ignored by Windows 10 1607 and later if DPIAwareness is not empty.
+	DPIAware string
+	// DPIAwareness is the dpiAwareness setting, a list of values to fall back in order.
+	DPIAwareness []DPIAwareness
+	// LongPathAware enables paths longer than MAX_PATH.
+	LongPathAware bool
+	// ActiveCodePage is the process code page, such as "UTF-8".
+	ActiveCodePage string
+	// HeapType is the heap implementation, such as "SegmentHeap".
+	HeapType string
+	// ExecutionLevel is the requested execution level. trustInfo is omitted if empty.
+	ExecutionLevel ExecutionLevel
+	UIAccess       bool
+	// CommonControls adds the dependency on Common Controls version 6,
+	// which is required for visual styles and some of the controls.
+	CommonControls bool
+}
+
+// DefaultManifest returns the manifest recommended for gw applications:
+// per-monitor(v2) DPI aware, running as invoker and using Common Controls version 6.
+func DefaultManifest() *Manifest {
+	return &Manifest{
+		DPIAware:       "true",
+		DPIAwareness:   []DPIAwareness{DPIPerMonitorV2},
+		ExecutionLevel: AsInvoker,
+		CommonControls: true,
+	}
+}
+
+const (
+	nsWindowsSettings2005 = "http://schemas.microsoft.com/SMI/2005/WindowsSettings"
+	nsWindowsSettings2016 = "http://schemas.microsoft.com/SMI/2016/WindowsSettings"
+	nsWindowsSettings2019 = "http://schemas.microsoft.com/SMI/2019/WindowsSettings"
+	nsWindowsSettings2020 = "http://schemas.microsoft.com/SMI/2020/WindowsSettings"
+)
+
+// manifestWriter writes indented XML.
+type manifestWriter struct {
+	strings.Builder
+	indent int
+}
+
+func escapeXML(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// line writes an indented line of a, where the even elements are markup and
+// the odd ones are text to be escaped.
+func (w *manifestWriter) line(a ...string) {
+	w.WriteString(strings.Repeat("  ", w.indent))
+	for i, s := range a {
+		if i%2 == 1 {
+			s = escapeXML(s)
+		}
+		w.WriteString(s)
+	}
+	w.WriteByte('\n')
+}
+
+// setting writes a windowsSettings element.
+func (w *manifestWriter) setting(name, ns, value string) {
+	w.line("<"+name+` xmlns="`+ns+`">`, value, "</"+name+">")
+}
+
+// XML returns the manifest XML document.
+func (m *Manifest) XML() []byte {
+	var w manifestWriter
+	w.line(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`)
+	w.line(`<assembly xmlns="urn:schemas-microsoft-com:asm.v1" manifestVersion="1.0">`)
+	w.indent++
+	if m.Name != "" {
+		w.line(`<assemblyIdentity type="win32" name="`, m.Name, `" version="`, m.Version.String(), `" processorArchitecture="*"/>`)
+	}
+	if m.Description != "" {
+		w.line("<description>", m.Description, "</description>")
+	}
+	if len(m.SupportedOS) > 0 {
+		w.line(`<compatibility xmlns="urn:schemas-microsoft-com:compatibility.v1">`)
+		w.indent++
+		w.line("<application>")
+		w.indent++
+		for _, id := range m.SupportedOS {
+			w.line(`<supportedOS Id="`, id, `"/>`)
+		}
+		w.indent--
+		w.line("</application>")
+		w.indent--
+		w.line("</compatibility>")
+	}
+	if m.DPIAware != "" || len(m.DPIAwareness) > 0 || m.LongPathAware || m.ActiveCodePage != "" || m.HeapType != "" {
+		w.line(`<application xmlns="urn:schemas-microsoft-com:asm.v3">`)
+		w.indent++
+		w.line("<windowsSettings>")
+		w.indent++
+		if m.DPIAware != "" {
+			w.setting("dpiAware", nsWindowsSettings2005, m.DPIAware)
+		}
+		if len(m.DPIAwareness) > 0 {
+			values := make([]string, len(m.DPIAwareness))
+			for i, v := range m.DPIAwareness {
+				values[i] = string(v)
+			}
+			w.setting("dpiAwareness", nsWindowsSettings2016, strings.Join(values, ", "))
+		}
+		if m.LongPathAware {
+			w.setting("longPathAware", nsWindowsSettings2016, "true")
+		}
+		if m.ActiveCodePage != "" {
+			w.setting("activeCodePage", nsWindowsSettings2019, m.ActiveCodePage)
+		}
+		if m.HeapType != "" {
+			w.setting("heapType", nsWindowsSettings2020, m.HeapType)
+		}
+		w.indent--
+		w.line("</windowsSettings>")
+		w.indent--
+		w.line("</application>")
+	}
+	if m.ExecutionLevel != "" {
+		uiAccess := "false"
+		if m.UIAccess {
+			uiAccess = "true"
+		}
+		w.line(`<trustInfo xmlns="urn:schemas-microsoft-com:asm.v3">`)
+		w.indent++
+		w.line("<security>")
+		w.indent++
+		w.line("<requestedPrivileges>")
+		w.indent++
+		w.line(`<requestedExecutionLevel level="`, string(m.ExecutionLevel), `" uiAccess="`, uiAccess, `"/>`)
+		w.indent--
+		w.line("</requestedPrivileges>")
+		w.indent--
+		w.line("</security>")
+		w.indent--
+		w.line("</trustInfo>")
+	}
+	if m.CommonControls {
+		w.line("<dependency>")
+		w.indent++
+		w.line("<dependentAssembly>")
+		w.indent++
+		w.line(`<assemblyIdentity type="win32" name="Microsoft.Windows.Common-Controls" version="6.0.0.0" processorArchitecture="*" publicKeyToken="6595b64144ccf1df" language="*"/>`)
+		w.indent--
+		w.line("</dependentAssembly>")
+		w.indent--
+		w.line("</dependency>")
+	}
+	w.indent--
+	w.line("</assembly>")
+	return []byte(w.String())
+}
@@ -0,0 +1,58 @@
+package winres_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+
+	"github.com/mkch/gw/util/winres"
+)
+
+// checkWellFormed checks whether data is a well-formed XML document.
+func checkWellFormed(t *testing.T, data []byte) {
+	t.Helper()
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		if _, err := d.Token(); err == io.EOF {
+			return
+		} else if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestManifest(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		manifest *winres.Manifest
+	}{
+		{"empty", &winres.Manifest{}},
+		{"default", winres.DefaultManifest()},
+		{"full", &winres.Manifest{
+			Name:        "mkch.gw.Test",
+			Version:     winres.Version{1, 2, 3, 4},
+			Description: "Tom & Jerry's <app>",
+			SupportedOS: []string{
+				winres.SupportedOSWindows10,
+				winres.SupportedOSWindows81,
+				winres.SupportedOSWindows8,
+				winres.SupportedOSWindows7,
+				winres.SupportedOSWindowsVista,
+			},
+			DPIAware:       "true/pm",
+			DPIAwareness:   []winres.DPIAwareness{winres.DPIPerMonitorV2, winres.DPIPerMonitor},
+			LongPathAware:  true,
+			ActiveCodePage: "UTF-8",
+			HeapType:       "SegmentHeap",
+			ExecutionLevel: winres.RequireAdministrator,
+			UIAccess:       true,
+		}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			data := test.manifest.XML()
+			checkWellFormed(t, data)
+			checkGolden(t, test.name+".manifest", data)
+		})
+	}
+}
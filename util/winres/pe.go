@@ -0,0 +1,315 @@
+package winres
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"slices"
+)
+
+// PE image layout.
+// https://learn.microsoft.com/en-us/windows/win32/debug/pe-format
+
+const (
+	peMagic32             = 0x10B // PE32
+	peMagic64             = 0x20B // PE32+
+	fileHeaderSize        = 20
+	sectionHeaderSize     = 40
+	dirEntryResource      = 2
+	dirEntrySecurity      = 4
+	scnCntInitializedData = 0x00000040
+	scnMemRead            = 0x40000000
+)
+
+// ErrSigned is returned by WritePE if the image has a digital signature,
+// which would be invalidated by modifying the image.
+var ErrSigned = errors.New("signed image is not supported")
+
+type section struct {
+	header []byte // Slice of the image, 40 bytes.
+}
+
+func (s section) virtualSize() uint32 {
+	return binary.LittleEndian.Uint32(s.header[8:])
+}
+
+func (s section) virtualAddress() uint32 {
+	return binary.LittleEndian.Uint32(s.header[12:])
+}
+
+func (s section) sizeOfRawData() uint32 {
+	return binary.LittleEndian.Uint32(s.header[16:])
+}
+
+func (s section) pointerToRawData() uint32 {
+	return binary.LittleEndian.Uint32(s.header[20:])
+}
+
+// peImage is a parsed PE image.
+type peImage struct {
+	fileHeader       []byte // COFF file header.
+	optHeader        []byte
+	dataDirs         []byte // The data directories in the optional header.
+	fileHeaderOffset int
+	optHeaderOffset  int
+	dataDirsOffset   int
+	sectionTable     int // Offset of the section table.
+	sections         []section
+	checksumOffset   int
+}
+
+func (img *peImage) numberOfRvaAndSizes() int {
+	return len(img.dataDirs) / 8
+}
+
+// dataDir returns the RVA(or file offset for security directory) and size
+// of data directory i.
+func (img *peImage) dataDir(i int) (rva, size uint32) {
+	if i >= img.numberOfRvaAndSizes() {
+		return 0, 0
+	}
+	return binary.LittleEndian.Uint32(img.dataDirs[8*i:]), binary.LittleEndian.Uint32(img.dataDirs[8*i+4:])
+}
+
+func (img *peImage) sectionAlignment() uint32 {
+	return binary.LittleEndian.Uint32(img.optHeader[32:])
+}
+
+func (img *peImage) fileAlignment() uint32 {
+	return binary.LittleEndian.Uint32(img.optHeader[36:])
+}
+
+func (img *peImage) sizeOfHeaders() uint32 {
+	return binary.LittleEndian.Uint32(img.optHeader[60:])
+}
+
+// sectionOf returns the index of the section containing rva, or -1.
+func (img *peImage) sectionOf(rva uint32) int {
+	for i, s := range img.sections {
+		size := max(s.virtualSize(), s.sizeOfRawData())
+		if rva >= s.virtualAddress() && rva-s.virtualAddress() < size {
+			return i
+		}
+	}
+	return -1
+}
+
+func parsePE(data []byte) (*peImage, error) {
+	le := binary.LittleEndian
+	if len(data) < 0x40 || !bytes.Equal(data[:2], []byte("MZ")) {
+		return nil, formatError("not a PE image: no MZ signature")
+	}
+	peOffset := uint64(le.Uint32(data[0x3C:]))
+	if peOffset+4+fileHeaderSize > uint64(len(data)) || !bytes.Equal(data[peOffset:peOffset+4], []byte("PE\x00\x00")) {
+		return nil, formatError("not a PE image: no PE signature")
+	}
+	img := &peImage{fileHeaderOffset: int(peOffset) + 4}
+	img.fileHeader = data[peOffset+4 : peOffset+4+fileHeaderSize]
+	numSections := uint64(le.Uint16(img.fileHeader[2:]))
+	optSize := uint64(le.Uint16(img.fileHeader[16:]))
+	optOffset := peOffset + 4 + fileHeaderSize
+	if optOffset+optSize > uint64(len(data)) || optSize < 2 {
+		return nil, formatError("optional header out of range")
+	}
+	img.optHeaderOffset = int(optOffset)
+	img.optHeader = data[optOffset : optOffset+optSize]
+	var dirsOffset uint64
+	switch le.Uint16(img.optHeader) {
+	case peMagic32:
+		dirsOffset = 96
+	case peMagic64:
+		dirsOffset = 112
+	default:
+		return nil, formatError("unknown optional header magic: 0x%X", le.Uint16(img.optHeader))
+	}
+	if optSize < dirsOffset {
+		return nil, formatError("optional header too small: %v", optSize)
+	}
+	img.checksumOffset = int(optOffset) + 64
+	numDirs := uint64(le.Uint32(img.optHeader[dirsOffset-4:]))
+	if dirsOffset+8*numDirs > optSize {
+		return nil, formatError("too many data directories: %v", numDirs)
+	}
+	img.dataDirsOffset = int(optOffset + dirsOffset)
+	img.dataDirs = img.optHeader[dirsOffset : dirsOffset+8*numDirs]
+	img.sectionTable = int(optOffset + optSize)
+	if uint64(img.sectionTable)+numSections*sectionHeaderSize > uint64(len(data)) {
+		return nil, formatError("section table out of range")
+	}
+	for i := 0; i < int(numSections); i++ {
+		offset := img.sectionTable + i*sectionHeaderSize
+		s := section{data[offset : offset+sectionHeaderSize]}
+		if uint64(s.pointerToRawData())+uint64(s.sizeOfRawData()) > uint64(len(data)) {
+			return nil, formatError("section %v out of range", i)
+		}
+		img.sections = append(img.sections, s)
+	}
+	return img, nil
+}
+
+// ReadPE reads the resources in a PE image(*.exe, *.dll etc).
+// An empty Set is returned if the image has no resource.
+func ReadPE(image []byte) (*Set, error) {
+	img, err := parsePE(image)
+	if err != nil {
+		return nil, err
+	}
+	rva, size := img.dataDir(dirEntryResource)
+	if rva == 0 || size == 0 {
+		return &Set{}, nil
+	}
+	i := img.sectionOf(rva)
+	if i == -1 {
+		return nil, formatError("resource directory RVA 0x%X is not in any section", rva)
+	}
+	s := img.sections[i]
+	raw := image[s.pointerToRawData() : s.pointerToRawData()+s.sizeOfRawData()]
+	if s.virtualSize() > s.sizeOfRawData() {
+		// The remaining bytes are zero-padded when loaded.
+		raw = append(slices.Clip(raw), make([]byte, s.virtualSize()-s.sizeOfRawData())...)
+	}
+	offset := rva - s.virtualAddress()
+	return decode(raw[offset:], rva)
+}
+
+// WritePE returns a copy of image with all its resources replaced by res.
+//
+// If the existing resource section is the last section of the image, it is
+// rewritten in place. Otherwise a new resource section is appended and the
+// old one is left unreferenced. Data after the last section, such as the COFF
+// symbol table written by the Go linker, is preserved.
+// Signed images are not supported, ErrSigned is returned.
+func WritePE(image []byte, res *Set) ([]byte, error) {
+	img, err := parsePE(image)
+	if err != nil {
+		return nil, err
+	}
+	if _, size := img.dataDir(dirEntrySecurity); size != 0 {
+		return nil, ErrSigned
+	}
+	if img.numberOfRvaAndSizes() <= dirEntryResource {
+		return nil, formatError("no resource data directory")
+	}
+	le := binary.LittleEndian
+
+	// The end of all section data in the file.
+	// Anything after that is overlay.
+	var rawEnd uint32 = img.sizeOfHeaders()
+	// The end of the virtual memory of the image.
+	var virtualEnd uint32
+	for _, s := range img.sections {
+		if s.sizeOfRawData() != 0 {
+			rawEnd = max(rawEnd, s.pointerToRawData()+s.sizeOfRawData())
+		}
+		virtualEnd = max(virtualEnd, s.virtualAddress()+max(s.virtualSize(), s.sizeOfRawData()))
+	}
+
+	// Reuse the resource section if it is the last one both in file and in memory.
+	reuse := -1
+	if rva, size := img.dataDir(dirEntryResource); rva != 0 && size != 0 {
+		if i := img.sectionOf(rva); i != -1 && rva == img.sections[i].virtualAddress() {
+			s := img.sections[i]
+			if s.pointerToRawData()+s.sizeOfRawData() == rawEnd &&
+				align(s.virtualAddress()+max(s.virtualSize(), s.sizeOfRawData()), img.sectionAlignment()) == align(virtualEnd, img.sectionAlignment()) {
+				reuse = i
+			}
+		}
+	}
+
+	var header []byte
+	var rsrcRVA, rsrcRaw uint32
+	var oldRawSize uint32
+	if reuse != -1 {
+		s := img.sections[reuse]
+		rsrcRVA, rsrcRaw, oldRawSize = s.virtualAddress(), s.pointerToRawData(), s.sizeOfRawData()
+		header = slices.Clone(s.header)
+	} else {
+		tableEnd := img.sectionTable + (len(img.sections)+1)*sectionHeaderSize
+		if uint32(tableEnd) > img.sizeOfHeaders() {
+			return nil, formatError("no room for a new section header")
+		}
+		for _, s := range img.sections {
+			if s.sizeOfRawData() != 0 && uint32(tableEnd) > s.pointerToRawData() {
+				return nil, formatError("no room for a new section header")
+			}
+		}
+		rsrcRVA = align(virtualEnd, img.sectionAlignment())
+		rsrcRaw = align(rawEnd, img.fileAlignment())
+		header = make([]byte, sectionHeaderSize)
+		copy(header, ".rsrc")
+		le.PutUint32(header[36:], scnCntInitializedData|scnMemRead)
+	}
+
+	content, _, err := res.encode(rsrcRVA)
+	if err != nil {
+		return nil, err
+	}
+	rawSize := align(uint32(len(content)), img.fileAlignment())
+	le.PutUint32(header[8:], uint32(len(content)))
+	le.PutUint32(header[12:], rsrcRVA)
+	le.PutUint32(header[16:], rawSize)
+	le.PutUint32(header[20:], rsrcRaw)
+
+	overlay := image[rawEnd:]
+	var out []byte
+	if reuse != -1 {
+		out = slices.Clone(image[:rsrcRaw])
+	} else {
+		out = make([]byte, rsrcRaw)
+		copy(out, image[:rawEnd])
+	}
+	out = append(out, content...)
+	out = append(out, make([]byte, rawSize-uint32(len(content)))...)
+	overlayOffset := uint32(len(out))
+	out = append(out, overlay...)
+
+	// Update headers in out. They are at the same offsets as in image.
+	fileHeader := out[img.fileHeaderOffset:]
+	optHeader := out[img.optHeaderOffset:]
+	dataDirs := out[img.dataDirsOffset:]
+	if reuse != -1 {
+		copy(out[img.sectionTable+reuse*sectionHeaderSize:], header)
+	} else {
+		copy(out[img.sectionTable+len(img.sections)*sectionHeaderSize:], header)
+		le.PutUint16(fileHeader[2:], uint16(len(img.sections)+1))
+	}
+	if ptr := le.Uint32(fileHeader[8:]); ptr != 0 && ptr >= rawEnd {
+		// Move the COFF symbol table along with overlay.
+		le.PutUint32(fileHeader[8:], ptr-rawEnd+overlayOffset)
+	}
+	le.PutUint32(dataDirs[8*dirEntryResource:], rsrcRVA)
+	le.PutUint32(dataDirs[8*dirEntryResource+4:], uint32(len(content)))
+	initData := le.Uint32(optHeader[8:])
+	le.PutUint32(optHeader[8:], initData-oldRawSize+rawSize)
+	sizeOfImage := max(align(virtualEnd, img.sectionAlignment()), align(rsrcRVA+uint32(len(content)), img.sectionAlignment()))
+	if reuse != -1 {
+		// The resource section may shrink.
+		sizeOfImage = align(rsrcRVA+uint32(len(content)), img.sectionAlignment())
+	}
+	le.PutUint32(optHeader[56:], sizeOfImage)
+	if le.Uint32(out[img.checksumOffset:]) != 0 {
+		le.PutUint32(out[img.checksumOffset:], checksum(out, img.checksumOffset))
+	}
+	return out, nil
+}
+
+// checksum computes the PE image checksum as CheckSumMappedFile does.
+// checksumOffset is the offset of the CheckSum field, which is excluded.
+func checksum(data []byte, checksumOffset int) uint32 {
+	var sum uint64
+	for i := 0; i < len(data); i += 2 {
+		if i == checksumOffset || i == checksumOffset+2 {
+			continue
+		}
+		var w uint64
+		if i+1 < len(data) {
+			w = uint64(binary.LittleEndian.Uint16(data[i:]))
+		} else {
+			w = uint64(data[i])
+		}
+		sum += w
+		sum = (sum & 0xFFFF) + (sum >> 16)
+	}
+	sum = (sum & 0xFFFF) + (sum >> 16)
+	return uint32(sum) + uint32(len(data))
+}
@@ -0,0 +1,221 @@
+package winres_test
+
+import (
+	"bytes"
+	"debug/pe"
+	"encoding/binary"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mkch/gw/util/icon"
+	"github.com/mkch/gw/util/winres"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func readFile(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("test_data", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// checkGolden compares data with the golden file, or updates it if -update is set.
+func checkGolden(t *testing.T, name string, data []byte) {
+	t.Helper()
+	golden := filepath.Join("test_data", name+".golden")
+	if *update {
+		if err := os.WriteFile(golden, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, want) {
+		t.Errorf("%v: output differs from %v", name, golden)
+	}
+}
+
+func testIcon() *icon.Icon {
+	ico := &icon.Icon{Type: 1, Images: make([]icon.Image, 2)}
+	for i, size := range []uint8{16, 32} {
+		img := &ico.Images[i]
+		*img.Entry.Width() = size
+		*img.Entry.Height() = size
+		*img.Entry.Planes() = 1
+		*img.Entry.BitCount() = 32
+		img.Data = bytes.Repeat([]byte{size}, int(size))
+	}
+	return ico
+}
+
+func equalSets(t *testing.T, got, want *winres.Set) {
+	t.Helper()
+	g, w := got.Resources(), want.Resources()
+	if len(g) != len(w) {
+		t.Fatalf("got %v resources, want %v", len(g), len(w))
+	}
+	for i := range g {
+		if g[i].Type != w[i].Type || g[i].Name != w[i].Name || g[i].Lang != w[i].Lang ||
+			g[i].CodePage != w[i].CodePage || !bytes.Equal(g[i].Data, w[i].Data) {
+			t.Errorf("resource %v: got %v/%v/%v, want %v/%v/%v", i, g[i].Type, g[i].Name, g[i].Lang, w[i].Type, w[i].Name, w[i].Lang)
+		}
+	}
+}
+
+// utf16le encodes ASCII s in UTF-16LE.
+func utf16le(s string) []byte {
+	var b []byte
+	for _, c := range []byte(s) {
+		b = append(b, c, 0)
+	}
+	return b
+}
+
+func TestReadPE(t *testing.T) {
+	res, err := winres.ReadPE(readFile(t, "rsrc64.exe"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Len() != 2 {
+		t.Fatalf("got %v resources, want 2", res.Len())
+	}
+	if r := res.Get(winres.IntID(winres.RT_MANIFEST), winres.IntID(1), 0x409); r == nil || string(r.Data) != "<assembly/>" {
+		t.Errorf("wrong manifest: %v", r)
+	}
+	if r := res.Get(winres.StrID("data"), winres.StrID("hello"), 0); r == nil || string(r.Data) != "hello, world" {
+		t.Errorf("wrong data: %v", r)
+	}
+
+	// Lowercase names are looked up as StrID uppercases them.
+	data := bytes.Replace(readFile(t, "rsrc64.exe"), utf16le("HELLO"), utf16le("hello"), 1)
+	if res, err := winres.ReadPE(data); err != nil {
+		t.Fatal(err)
+	} else if r := res.Get(winres.StrID("data"), winres.StrID("hello"), 0); r == nil || string(r.Data) != "hello, world" {
+		t.Errorf("wrong data: %v", r)
+	}
+
+	for _, name := range []string{"min32.exe", "min64.exe"} {
+		if res, err := winres.ReadPE(readFile(t, name)); err != nil {
+			t.Fatal(err)
+		} else if res.Len() != 0 {
+			t.Errorf("%v: got %v resources, want 0", name, res.Len())
+		}
+	}
+}
+
+func TestWritePE(t *testing.T) {
+	for _, name := range []string{"min32.exe", "min64.exe", "rsrc64.exe"} {
+		t.Run(name, func(t *testing.T) {
+			image := readFile(t, name)
+			res, err := winres.ReadPE(image)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := res.Add(&winres.Resource{
+				Type: winres.IntID(winres.RT_MANIFEST), Name: winres.IntID(2), Lang: 0x804, Data: []byte("<assembly></assembly>"),
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if err := res.AddIcon(winres.StrID("MainIcon"), 1, 0x409, testIcon()); err != nil {
+				t.Fatal(err)
+			}
+			out, err := winres.WritePE(image, res)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, name, out)
+
+			f, err := pe.NewFile(bytes.NewReader(out))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			if orig, err := pe.NewFile(bytes.NewReader(image)); err != nil {
+				t.Fatal(err)
+			} else if len(f.Symbols) != len(orig.Symbols) {
+				t.Errorf("got %v symbols, want %v", len(f.Symbols), len(orig.Symbols))
+			} else if len(f.Symbols) != 0 && f.Symbols[0].Name != "main" {
+				t.Errorf("wrong symbol: %v", f.Symbols[0].Name)
+			}
+			if s := f.Section(".text"); s == nil {
+				t.Error("no .text section")
+			} else if code, err := s.Data(); err != nil || code[0] != 0xC3 {
+				t.Errorf("wrong .text section: %v", err)
+			}
+
+			got, err := winres.ReadPE(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			equalSets(t, got, res)
+		})
+	}
+}
+
+func TestWritePEReuse(t *testing.T) {
+	image := readFile(t, "min64.exe")
+	var res winres.Set
+	res.Put(&winres.Resource{Type: winres.IntID(winres.RT_RCDATA), Name: winres.IntID(1), Data: bytes.Repeat([]byte{1}, 0x1000)})
+	out1, err := winres.WritePE(image, &res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Put(&winres.Resource{Type: winres.IntID(winres.RT_RCDATA), Name: winres.IntID(1), Data: []byte{2}})
+	out2, err := winres.WritePE(out1, &res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The resource section is the last one and is rewritten in place.
+	f, err := pe.NewFile(bytes.NewReader(out2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if len(f.Sections) != 2 {
+		t.Fatalf("got %v sections, want 2", len(f.Sections))
+	}
+	if len(out2) >= len(out1) {
+		t.Errorf("image not shrunk: %v >= %v", len(out2), len(out1))
+	}
+	if size := f.OptionalHeader.(*pe.OptionalHeader64).SizeOfImage; size != 0x3000 {
+		t.Errorf("wrong SizeOfImage: 0x%X", size)
+	}
+	got, err := winres.ReadPE(out2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	equalSets(t, got, &res)
+}
+
+func TestWritePESigned(t *testing.T) {
+	image := readFile(t, "min64.exe")
+	// Fake a security directory(the 5th data directory).
+	const dirsOffset = 0x40 + 4 + 20 + 112
+	binary.LittleEndian.PutUint32(image[dirsOffset+4*8:], 0x400)
+	binary.LittleEndian.PutUint32(image[dirsOffset+4*8+4:], 8)
+	if _, err := winres.WritePE(image, &winres.Set{}); !errors.Is(err, winres.ErrSigned) {
+		t.Errorf("got %v, want ErrSigned", err)
+	}
+}
+
+func TestReadPEInvalid(t *testing.T) {
+	image := readFile(t, "rsrc64.exe")
+	// Corrupt the root resource directory entry count.
+	binary.LittleEndian.PutUint16(image[0x400+14:], 0xFFFF)
+	var formatErr *winres.FormatError
+	if _, err := winres.ReadPE(image); !errors.As(err, &formatErr) {
+		t.Errorf("got %v, want FormatError", err)
+	}
+	if _, err := winres.ReadPE([]byte("not a PE file")); !errors.As(err, &formatErr) {
+		t.Errorf("got %v, want FormatError", err)
+	}
+}
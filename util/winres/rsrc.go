@@ -0,0 +1,264 @@
+package winres
+
+import (
+	"encoding/binary"
+	"fmt"
+	"unicode/utf16"
+)
+
+// Layout of the resource section(.rsrc).
+// https://learn.microsoft.com/en-us/windows/win32/debug/pe-format#the-rsrc-section
+
+const (
+	dirHeaderSize  = 16 // IMAGE_RESOURCE_DIRECTORY
+	dirEntrySize   = 8  // IMAGE_RESOURCE_DIRECTORY_ENTRY
+	dataEntrySize  = 16 // IMAGE_RESOURCE_DATA_ENTRY
+	highBit        = 0x80000000
+	dataAlignment  = 8
+	maxDirEntryNum = 0xFFFF
+)
+
+// dirNode is a resource directory in the type/name/language tree.
+type dirNode struct {
+	entries []dirEntry
+	offset  uint32
+}
+
+type dirEntry struct {
+	id    ID
+	sub   *dirNode   // Subdirectory, nil at the language level.
+	entry *dataEntry // Data entry, at the language level only.
+}
+
+type dataEntry struct {
+	res        *Resource
+	offset     uint32 // Offset of IMAGE_RESOURCE_DATA_ENTRY.
+	dataOffset uint32 // Offset of the resource data.
+}
+
+func align(n, a uint32) uint32 {
+	return (n + a - 1) &^ (a - 1)
+}
+
+// encode encodes s as the content of a resource section whose virtual address is rva.
+// It also returns the offsets of all IMAGE_RESOURCE_DATA_ENTRY.OffsetToData fields
+// which hold RVAs, for relocation.
+func (s *Set) encode(rva uint32) (section []byte, relocs []uint32, err error) {
+	// Build the three-level tree. s.res is sorted in directory order.
+	root := &dirNode{}
+	var nameDirs, langDirs []*dirNode
+	var leaves []*dataEntry
+	for _, r := range s.res {
+		if n := len(root.entries); n == 0 || root.entries[n-1].id != r.Type {
+			d := &dirNode{}
+			nameDirs = append(nameDirs, d)
+			root.entries = append(root.entries, dirEntry{id: r.Type, sub: d})
+		}
+		nameDir := root.entries[len(root.entries)-1].sub
+		if n := len(nameDir.entries); n == 0 || nameDir.entries[n-1].id != r.Name {
+			d := &dirNode{}
+			langDirs = append(langDirs, d)
+			nameDir.entries = append(nameDir.entries, dirEntry{id: r.Name, sub: d})
+		}
+		langDir := nameDir.entries[len(nameDir.entries)-1].sub
+		leaf := &dataEntry{res: r}
+		leaves = append(leaves, leaf)
+		langDir.entries = append(langDir.entries, dirEntry{id: IntID(r.Lang), entry: leaf})
+	}
+
+	// Assign offsets: directories, data entries, strings, data.
+	var offset uint32
+	for _, dirs := range [][]*dirNode{{root}, nameDirs, langDirs} {
+		for _, d := range dirs {
+			if len(d.entries) > maxDirEntryNum {
+				return nil, nil, fmt.Errorf("too many resource directory entries: %v", len(d.entries))
+			}
+			d.offset = offset
+			offset += dirHeaderSize + dirEntrySize*uint32(len(d.entries))
+		}
+	}
+	for _, leaf := range leaves {
+		leaf.offset = offset
+		offset += dataEntrySize
+	}
+	strOffsets := make(map[string]uint32)
+	var strs [][]uint16
+	for _, dirs := range [][]*dirNode{{root}, nameDirs} {
+		for _, d := range dirs {
+			for _, e := range d.entries {
+				if str, ok := e.id.Str(); ok {
+					if _, ok := strOffsets[str]; !ok {
+						strOffsets[str] = offset
+						u := utf16.Encode([]rune(str))
+						strs = append(strs, u)
+						offset += 2 + 2*uint32(len(u))
+					}
+				}
+			}
+		}
+	}
+	for _, leaf := range leaves {
+		offset = align(offset, dataAlignment)
+		leaf.dataOffset = offset
+		offset += uint32(len(leaf.res.Data))
+	}
+
+	// Write.
+	section = make([]byte, offset)
+	le := binary.LittleEndian
+	for _, dirs := range [][]*dirNode{{root}, nameDirs, langDirs} {
+		for _, d := range dirs {
+			var named uint16
+			for _, e := range d.entries {
+				if _, ok := e.id.Str(); ok {
+					named++
+				}
+			}
+			hdr := section[d.offset:]
+			le.PutUint16(hdr[12:], named)
+			le.PutUint16(hdr[14:], uint16(len(d.entries))-named)
+			for i, e := range d.entries {
+				entry := hdr[dirHeaderSize+dirEntrySize*i:]
+				if str, ok := e.id.Str(); ok {
+					le.PutUint32(entry, highBit|strOffsets[str])
+				} else {
+					id, _ := e.id.Int()
+					le.PutUint32(entry, uint32(id))
+				}
+				if e.sub != nil {
+					le.PutUint32(entry[4:], highBit|e.sub.offset)
+				} else {
+					le.PutUint32(entry[4:], e.entry.offset)
+				}
+			}
+		}
+	}
+	for _, leaf := range leaves {
+		entry := section[leaf.offset:]
+		le.PutUint32(entry, rva+leaf.dataOffset)
+		le.PutUint32(entry[4:], uint32(len(leaf.res.Data)))
+		le.PutUint32(entry[8:], leaf.res.CodePage)
+		relocs = append(relocs, leaf.offset)
+		copy(section[leaf.dataOffset:], leaf.res.Data)
+	}
+	for _, u := range strs {
+		str := section[strOffsets[string(utf16.Decode(u))]:]
+		le.PutUint16(str, uint16(len(u)))
+		for i, c := range u {
+			le.PutUint16(str[2+2*i:], c)
+		}
+	}
+	return
+}
+
+// FormatError is returned when the data being parsed is malformed.
+type FormatError struct {
+	err string
+}
+
+func (e *FormatError) Error() string {
+	return e.err
+}
+
+func formatError(format string, a ...any) error {
+	return &FormatError{fmt.Sprintf(format, a...)}
+}
+
+// decode parses the resource directory in section, the content of
+// a resource section whose virtual address is rva.
+func decode(section []byte, rva uint32) (*Set, error) {
+	le := binary.LittleEndian
+	var s Set
+	// readDir returns the entries of the directory at offset.
+	readDir := func(offset uint32) ([]byte, error) {
+		if uint64(offset)+dirHeaderSize > uint64(len(section)) {
+			return nil, formatError("resource directory out of range: 0x%X", offset)
+		}
+		hdr := section[offset:]
+		n := uint64(le.Uint16(hdr[12:])) + uint64(le.Uint16(hdr[14:]))
+		end := uint64(offset) + dirHeaderSize + n*dirEntrySize
+		if end > uint64(len(section)) {
+			return nil, formatError("resource directory entries out of range: 0x%X", offset)
+		}
+		return section[offset+dirHeaderSize : end], nil
+	}
+	readID := func(v uint32) (ID, error) {
+		if v&highBit == 0 {
+			if v > 0xFFFF {
+				return ID{}, formatError("invalid resource ID: 0x%X", v)
+			}
+			return IntID(uint16(v)), nil
+		}
+		offset := v &^ highBit
+		if uint64(offset)+2 > uint64(len(section)) {
+			return ID{}, formatError("resource name out of range: 0x%X", offset)
+		}
+		n := uint64(le.Uint16(section[offset:]))
+		if uint64(offset)+2+2*n > uint64(len(section)) || n == 0 {
+			return ID{}, formatError("invalid resource name at 0x%X", offset)
+		}
+		u := make([]uint16, n)
+		for i := range u {
+			u[i] = le.Uint16(section[offset+2+2*uint32(i):])
+		}
+		// Names are uppercased as StrID does, so that they can be looked up.
+		return StrID(string(utf16.Decode(u))), nil
+	}
+	// readSub returns the entries of the subdirectory pointed by entry.
+	readSub := func(entry []byte) ([]byte, error) {
+		v := le.Uint32(entry[4:])
+		if v&highBit == 0 {
+			return nil, formatError("resource directory expected")
+		}
+		return readDir(v &^ highBit)
+	}
+
+	types, err := readDir(0)
+	if err != nil {
+		return nil, err
+	}
+	for ; len(types) > 0; types = types[dirEntrySize:] {
+		typ, err := readID(le.Uint32(types))
+		if err != nil {
+			return nil, err
+		}
+		names, err := readSub(types)
+		if err != nil {
+			return nil, err
+		}
+		for ; len(names) > 0; names = names[dirEntrySize:] {
+			name, err := readID(le.Uint32(names))
+			if err != nil {
+				return nil, err
+			}
+			langs, err := readSub(names)
+			if err != nil {
+				return nil, err
+			}
+			for ; len(langs) > 0; langs = langs[dirEntrySize:] {
+				lang := le.Uint32(langs)
+				if lang > 0xFFFF {
+					return nil, formatError("invalid resource language: 0x%X", lang)
+				}
+				offset := le.Uint32(langs[4:])
+				if offset&highBit != 0 || uint64(offset)+dataEntrySize > uint64(len(section)) {
+					return nil, formatError("invalid resource data entry: 0x%X", offset)
+				}
+				entry := section[offset:]
+				dataRVA, size := le.Uint32(entry), le.Uint32(entry[4:])
+				if dataRVA < rva || uint64(dataRVA-rva)+uint64(size) > uint64(len(section)) {
+					return nil, formatError("resource data out of range: RVA 0x%X size 0x%X", dataRVA, size)
+				}
+				start := dataRVA - rva
+				s.Put(&Resource{
+					Type:     typ,
+					Name:     name,
+					Lang:     uint16(lang),
+					CodePage: le.Uint32(entry[8:]),
+					Data:     append([]byte(nil), section[start:start+size]...),
+				})
+			}
+		}
+	}
+	return &s, nil
+}
@@ -0,0 +1,81 @@
+package winres
+
+import (
+	"encoding/binary"
+	"fmt"
+	"unicode/utf16"
+)
+
+// stringsPerBlock is the number of strings in a RT_STRING resource.
+const stringsPerBlock = 16
+
+// AddStrings adds a string table, where the keys of strs are string IDs
+// used by LoadString.
+// Strings are stored in RT_STRING resources of 16 strings each,
+// the resource of string ID id is named id/16+1.
+// It returns ErrExist if any of the RT_STRING resources exists in lang.
+func (s *Set) AddStrings(lang uint16, strs map[uint16]string) error {
+	blocks := make(map[uint16]*[stringsPerBlock][]uint16)
+	for id, str := range strs {
+		name := id/stringsPerBlock + 1
+		if s.Get(IntID(RT_STRING), IntID(name), lang) != nil {
+			return fmt.Errorf("%w: string table %v", ErrExist, name)
+		}
+		block := blocks[name]
+		if block == nil {
+			block = new([stringsPerBlock][]uint16)
+			blocks[name] = block
+		}
+		u := utf16.Encode([]rune(str))
+		if len(u) > 0xFFFF {
+			return fmt.Errorf("string %v too long", id)
+		}
+		block[id%stringsPerBlock] = u
+	}
+	for name, block := range blocks {
+		var data []byte
+		for _, u := range block {
+			data = binary.LittleEndian.AppendUint16(data, uint16(len(u)))
+			for _, c := range u {
+				data = binary.LittleEndian.AppendUint16(data, c)
+			}
+		}
+		s.Put(&Resource{Type: IntID(RT_STRING), Name: IntID(name), Lang: lang, Data: data})
+	}
+	return nil
+}
+
+// Strings returns the string table in lang.
+func (s *Set) Strings(lang uint16) (map[uint16]string, error) {
+	strs := make(map[uint16]string)
+	for _, r := range s.res {
+		if typ, _ := r.Type.Int(); typ != RT_STRING || r.Lang != lang {
+			continue
+		}
+		name, ok := r.Name.Int()
+		if !ok || name == 0 {
+			return nil, formatError("invalid string table name: %v", r.Name)
+		}
+		data := r.Data
+		for i := range stringsPerBlock {
+			if len(data) < 2 {
+				return nil, formatError("string table %v too short", name)
+			}
+			n := int(binary.LittleEndian.Uint16(data))
+			data = data[2:]
+			if len(data) < 2*n {
+				return nil, formatError("string table %v too short", name)
+			}
+			if n == 0 {
+				continue
+			}
+			u := make([]uint16, n)
+			for j := range u {
+				u[j] = binary.LittleEndian.Uint16(data[2*j:])
+			}
+			data = data[2*n:]
+			strs[(name-1)*stringsPerBlock+uint16(i)] = string(utf16.Decode(u))
+		}
+	}
+	return strs, nil
+}
@@ -0,0 +1,420 @@
+package winres
+
+import (
+	"encoding/binary"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Layout of the version information resource(RT_VERSION).
+// https://learn.microsoft.com/en-us/windows/win32/menurc/vs-versioninfo
+
+// VS_FIXEDFILEINFO.dwFileFlags values.
+const (
+	VS_FF_DEBUG        = 0x01
+	VS_FF_PRERELEASE   = 0x02
+	VS_FF_PATCHED      = 0x04
+	VS_FF_PRIVATEBUILD = 0x08
+	VS_FF_INFOINFERRED = 0x10
+	VS_FF_SPECIALBUILD = 0x20
+
+	VS_FFI_FILEFLAGSMASK = 0x3F
+)
+
+// VS_FIXEDFILEINFO.dwFileOS values.
+const (
+	VOS_UNKNOWN      = 0x00000000
+	VOS_NT           = 0x00040000
+	VOS__WINDOWS32   = 0x00000004
+	VOS_NT_WINDOWS32 = VOS_NT | VOS__WINDOWS32
+)
+
+// VS_FIXEDFILEINFO.dwFileType values.
+const (
+	VFT_UNKNOWN    = 0
+	VFT_APP        = 1
+	VFT_DLL        = 2
+	VFT_DRV        = 3
+	VFT_FONT       = 4
+	VFT_VXD        = 5
+	VFT_STATIC_LIB = 7
+)
+
+// Predefined keys of the StringFileInfo string tables.
+// https://learn.microsoft.com/en-us/windows/win32/menurc/stringtable
+const (
+	Comments         = "Comments"
+	CompanyName      = "CompanyName"
+	FileDescription  = "FileDescription"
+	FileVersion      = "FileVersion"
+	InternalName     = "InternalName"
+	LegalCopyright   = "LegalCopyright"
+	LegalTrademarks  = "LegalTrademarks"
+	OriginalFilename = "OriginalFilename"
+	PrivateBuild     = "PrivateBuild"
+	ProductName      = "ProductName"
+	ProductVersion   = "ProductVersion"
+	SpecialBuild     = "SpecialBuild"
+)
+
+// CP_UNICODE is the code page of UTF-16 string tables.
+const CP_UNICODE = 1200
+
+const (
+	fixedFileInfoSize      = 52
+	fixedFileInfoSignature = 0xFEEF04BD
+	fixedFileInfoVersion   = 0x00010000
+	versionInfoResID       = 1
+	versionTypeBinary      = 0
+	versionTypeText        = 1
+)
+
+// Version is a four-part version number such as 1.2.3.4.
+type Version [4]uint16
+
+// ParseVersion parses a version number of up to four dot-separated parts.
+// Missing parts are zero.
+func ParseVersion(s string) (v Version, err error) {
+	parts := strings.Split(s, ".")
+	if len(parts) > len(v) {
+		return v, fmt.Errorf("invalid version %q", s)
+	}
+	for i, p := range parts {
+		n, err := strconv.ParseUint(p, 10, 16)
+		if err != nil {
+			return v, fmt.Errorf("invalid version %q", s)
+		}
+		v[i] = uint16(n)
+	}
+	return
+}
+
+// String returns v in the form 1.2.3.4.
+func (v Version) String() string {
+	return fmt.Sprintf("%v.%v.%v.%v", v[0], v[1], v[2], v[3])
+}
+
+// FixedFileInfo is the language independent part of the version information,
+// VS_FIXEDFILEINFO.
+type FixedFileInfo struct {
+	FileVersion    Version
+	ProductVersion Version
+	FileFlagsMask  uint32 // Bits of FileFlags that are valid, usually VS_FFI_FILEFLAGSMASK.
+	FileFlags      uint32 // VS_FF_* values.
+	FileOS         uint32 // VOS_* values.
+	FileType       uint32 // VFT_* values.
+	FileSubtype    uint32
+	FileDate       uint64
+}
+
+// Translation is a language and code page pair.
+type Translation struct {
+	Lang     uint16
+	CodePage uint16
+}
+
+// String returns the string table key of t, such as "040904B0".
+func (t Translation) String() string {
+	return fmt.Sprintf("%04X%04X", t.Lang, t.CodePage)
+}
+
+// VersionInfo is the version information of a file, VS_VERSIONINFO.
+type VersionInfo struct {
+	Fixed FixedFileInfo
+	// Strings are the string tables of StringFileInfo, keyed by language and code page.
+	// Each table maps keys such as CompanyName to values.
+	Strings map[Translation]map[string]string
+	// Translations are the languages and code pages supported, in VarFileInfo.
+	// If nil, the keys of Strings are used.
+	Translations []Translation
+}
+
+// NewVersionInfo returns a VersionInfo of an application for Windows
+// with FileVersion and ProductVersion set.
+func NewVersionInfo(fileVersion, productVersion Version) *VersionInfo {
+	return &VersionInfo{
+		Fixed: FixedFileInfo{
+			FileVersion:    fileVersion,
+			ProductVersion: productVersion,
+			FileFlagsMask:  VS_FFI_FILEFLAGSMASK,
+			FileOS:         VOS_NT_WINDOWS32,
+			FileType:       VFT_APP,
+		},
+	}
+}
+
+// SetString sets the value of key in the string table of t.
+func (v *VersionInfo) SetString(t Translation, key, value string) {
+	if v.Strings == nil {
+		v.Strings = make(map[Translation]map[string]string)
+	}
+	table := v.Strings[t]
+	if table == nil {
+		table = make(map[string]string)
+		v.Strings[t] = table
+	}
+	table[key] = value
+}
+
+// appendUTF16 appends s and the terminating null to b.
+func appendUTF16(b []byte, s string) []byte {
+	for _, c := range utf16.Encode([]rune(s)) {
+		b = binary.LittleEndian.AppendUint16(b, c)
+	}
+	return binary.LittleEndian.AppendUint16(b, 0)
+}
+
+func pad32(b []byte) []byte {
+	for len(b)%4 != 0 {
+		b = append(b, 0)
+	}
+	return b
+}
+
+// appendBlock appends a version information block to b.
+// valueLength is in words for text values, or bytes for binary values.
+// children appends the child blocks, it can be nil.
+func appendBlock(b []byte, key string, typ uint16, valueLength uint16, value []byte, children func([]byte) []byte) ([]byte, error) {
+	start := len(b)
+	b = binary.LittleEndian.AppendUint16(b, 0) // wLength, set below.
+	b = binary.LittleEndian.AppendUint16(b, valueLength)
+	b = binary.LittleEndian.AppendUint16(b, typ)
+	b = appendUTF16(b, key)
+	if len(value) > 0 {
+		b = pad32(b)
+		b = append(b, value...)
+	}
+	if children != nil {
+		b = children(b)
+	}
+	length := len(b) - start
+	if length > 0xFFFF {
+		return nil, fmt.Errorf("version information block %q too long", key)
+	}
+	binary.LittleEndian.PutUint16(b[start:], uint16(length))
+	return b, nil
+}
+
+// encode encodes v as the data of a RT_VERSION resource.
+func (v *VersionInfo) encode() (data []byte, err error) {
+	le := binary.LittleEndian
+	f := &v.Fixed
+	fixed := make([]byte, 0, fixedFileInfoSize)
+	for _, n := range []uint32{
+		fixedFileInfoSignature, fixedFileInfoVersion,
+		uint32(f.FileVersion[0])<<16 | uint32(f.FileVersion[1]), uint32(f.FileVersion[2])<<16 | uint32(f.FileVersion[3]),
+		uint32(f.ProductVersion[0])<<16 | uint32(f.ProductVersion[1]), uint32(f.ProductVersion[2])<<16 | uint32(f.ProductVersion[3]),
+		f.FileFlagsMask, f.FileFlags, f.FileOS, f.FileType, f.FileSubtype,
+		uint32(f.FileDate >> 32), uint32(f.FileDate),
+	} {
+		fixed = le.AppendUint32(fixed, n)
+	}
+
+	translations := v.Translations
+	if translations == nil {
+		translations = slices.SortedFunc(maps.Keys(v.Strings), compareTranslation)
+	}
+
+	// Errors of nested blocks are recorded in err, the outermost block
+	// is longer than any of them and fails too.
+	child := func(b []byte, key string, typ uint16, valueLength uint16, value []byte, children func([]byte) []byte) []byte {
+		b = pad32(b)
+		out, e := appendBlock(b, key, typ, valueLength, value, children)
+		if e != nil {
+			err = e
+			return b
+		}
+		return out
+	}
+	stringFileInfo := func(b []byte) []byte {
+		for _, t := range slices.SortedFunc(maps.Keys(v.Strings), compareTranslation) {
+			table := v.Strings[t]
+			b = child(b, t.String(), versionTypeText, 0, nil, func(b []byte) []byte {
+				for _, key := range slices.Sorted(maps.Keys(table)) {
+					value := appendUTF16(nil, table[key])
+					b = child(b, key, versionTypeText, uint16(len(value)/2), value, nil)
+				}
+				return b
+			})
+		}
+		return b
+	}
+	varFileInfo := func(b []byte) []byte {
+		var value []byte
+		for _, t := range translations {
+			value = le.AppendUint32(value, uint32(t.CodePage)<<16|uint32(t.Lang))
+		}
+		return child(b, "Translation", versionTypeBinary, uint16(len(value)), value, nil)
+	}
+
+	data, e := appendBlock(nil, "VS_VERSION_INFO", versionTypeBinary, fixedFileInfoSize, fixed, func(b []byte) []byte {
+		if len(v.Strings) > 0 {
+			b = child(b, "StringFileInfo", versionTypeText, 0, nil, stringFileInfo)
+		}
+		if len(translations) > 0 {
+			b = child(b, "VarFileInfo", versionTypeText, 0, nil, varFileInfo)
+		}
+		return b
+	})
+	if err == nil {
+		err = e
+	}
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func compareTranslation(a, b Translation) int {
+	if a.Lang != b.Lang {
+		return int(a.Lang) - int(b.Lang)
+	}
+	return int(a.CodePage) - int(b.CodePage)
+}
+
+// versionBlock is a decoded version information block.
+type versionBlock struct {
+	key      string
+	typ      uint16
+	value    []byte
+	children []versionBlock
+}
+
+// parseVersionBlock parses the block at the start of data, which is 32-bit aligned.
+func parseVersionBlock(data []byte) (blk versionBlock, rest []byte, err error) {
+	le := binary.LittleEndian
+	if len(data) < 6 {
+		return blk, nil, formatError("version information block too short")
+	}
+	length := int(le.Uint16(data))
+	valueLength := int(le.Uint16(data[2:]))
+	blk.typ = le.Uint16(data[4:])
+	if length < 6 || length > len(data) {
+		return blk, nil, formatError("invalid version information block length: %v", length)
+	}
+	rest = data[min(int(align(uint32(length), 4)), len(data)):]
+	data = data[:length]
+
+	var key []uint16
+	i := 6
+	for ; ; i += 2 {
+		if i+2 > len(data) {
+			return blk, nil, formatError("unterminated version information key")
+		}
+		c := le.Uint16(data[i:])
+		if c == 0 {
+			break
+		}
+		key = append(key, c)
+	}
+	blk.key = string(utf16.Decode(key))
+	i = int(align(uint32(i+2), 4))
+
+	if blk.typ == versionTypeText {
+		valueLength *= 2
+	}
+	if valueLength > 0 {
+		if i+valueLength > len(data) {
+			return blk, nil, formatError("version information value of %q too long", blk.key)
+		}
+		blk.value = data[i : i+valueLength]
+		i = int(align(uint32(i+valueLength), 4))
+	}
+	for i < len(data) {
+		var child versionBlock
+		var r []byte
+		if child, r, err = parseVersionBlock(data[i:]); err != nil {
+			return
+		}
+		blk.children = append(blk.children, child)
+		i = len(data) - len(r)
+	}
+	return
+}
+
+// decodeUTF16 decodes a null-terminated UTF-16 string.
+func decodeUTF16(b []byte) string {
+	u := make([]uint16, 0, len(b)/2)
+	for i := 0; i+2 <= len(b); i += 2 {
+		c := binary.LittleEndian.Uint16(b[i:])
+		if c == 0 {
+			break
+		}
+		u = append(u, c)
+	}
+	return string(utf16.Decode(u))
+}
+
+// decodeVersionInfo decodes the data of a RT_VERSION resource.
+func decodeVersionInfo(data []byte) (*VersionInfo, error) {
+	le := binary.LittleEndian
+	root, _, err := parseVersionBlock(data)
+	if err != nil {
+		return nil, err
+	}
+	if root.key != "VS_VERSION_INFO" || len(root.value) != fixedFileInfoSize || le.Uint32(root.value) != fixedFileInfoSignature {
+		return nil, formatError("invalid version information")
+	}
+	var v VersionInfo
+	dw := func(i int) uint32 { return le.Uint32(root.value[4*i:]) }
+	v.Fixed = FixedFileInfo{
+		FileVersion:    Version{uint16(dw(2) >> 16), uint16(dw(2)), uint16(dw(3) >> 16), uint16(dw(3))},
+		ProductVersion: Version{uint16(dw(4) >> 16), uint16(dw(4)), uint16(dw(5) >> 16), uint16(dw(5))},
+		FileFlagsMask:  dw(6),
+		FileFlags:      dw(7),
+		FileOS:         dw(8),
+		FileType:       dw(9),
+		FileSubtype:    dw(10),
+		FileDate:       uint64(dw(11))<<32 | uint64(dw(12)),
+	}
+	for _, info := range root.children {
+		switch info.key {
+		case "StringFileInfo":
+			for _, table := range info.children {
+				t, err := strconv.ParseUint(table.key, 16, 32)
+				if err != nil || len(table.key) != 8 {
+					return nil, formatError("invalid string table key %q", table.key)
+				}
+				for _, s := range table.children {
+					v.SetString(Translation{Lang: uint16(t >> 16), CodePage: uint16(t)}, s.key, decodeUTF16(s.value))
+				}
+			}
+		case "VarFileInfo":
+			for _, vr := range info.children {
+				if vr.key != "Translation" {
+					continue
+				}
+				v.Translations = make([]Translation, 0, len(vr.value)/4)
+				for i := 0; i+4 <= len(vr.value); i += 4 {
+					n := le.Uint32(vr.value[i:])
+					v.Translations = append(v.Translations, Translation{Lang: uint16(n), CodePage: uint16(n >> 16)})
+				}
+			}
+		}
+	}
+	return &v, nil
+}
+
+// AddVersionInfo adds v as the RT_VERSION resource with ID 1.
+// It returns ErrExist if the resource exists in any language.
+func (s *Set) AddVersionInfo(lang uint16, v *VersionInfo) error {
+	data, err := v.encode()
+	if err != nil {
+		return err
+	}
+	return s.Add(&Resource{Type: IntID(RT_VERSION), Name: IntID(versionInfoResID), Lang: lang, Data: data})
+}
+
+// VersionInfo returns the RT_VERSION resource with ID 1 in lang,
+// or nil if there is no such resource.
+func (s *Set) VersionInfo(lang uint16) (*VersionInfo, error) {
+	r := s.Get(IntID(RT_VERSION), IntID(versionInfoResID), lang)
+	if r == nil {
+		return nil, nil
+	}
+	return decodeVersionInfo(r.Data)
+}
@@ -0,0 +1,100 @@
+package winres_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mkch/gw/util/winres"
+)
+
+func TestParseVersion(t *testing.T) {
+	for _, test := range []struct {
+		s    string
+		want winres.Version
+		ok   bool
+	}{
+		{"1", winres.Version{1}, true},
+		{"1.2.3", winres.Version{1, 2, 3}, true},
+		{"1.2.3.65535", winres.Version{1, 2, 3, 65535}, true},
+		{"1.2.3.4.5", winres.Version{}, false},
+		{"1.x", winres.Version{}, false},
+		{"65536", winres.Version{}, false},
+		{"", winres.Version{}, false},
+	} {
+		v, err := winres.ParseVersion(test.s)
+		if (err == nil) != test.ok || (test.ok && v != test.want) {
+			t.Errorf("ParseVersion(%q) = %v %v", test.s, v, err)
+		}
+	}
+	if s := (winres.Version{1, 2, 0, 4}).String(); s != "1.2.0.4" {
+		t.Errorf("got %v", s)
+	}
+}
+
+func testVersionInfo() *winres.VersionInfo {
+	v := winres.NewVersionInfo(winres.Version{1, 2, 3, 4}, winres.Version{1, 2})
+	v.Fixed.FileFlags = winres.VS_FF_PRERELEASE
+	en := winres.Translation{Lang: 0x409, CodePage: winres.CP_UNICODE}
+	zh := winres.Translation{Lang: 0x804, CodePage: winres.CP_UNICODE}
+	v.SetString(en, winres.CompanyName, "mkch")
+	v.SetString(en, winres.FileDescription, "Test application")
+	v.SetString(en, winres.FileVersion, "1.2.3.4")
+	v.SetString(en, winres.Comments, "")
+	v.SetString(zh, winres.FileDescription, "测试程序")
+	return v
+}
+
+func TestVersionInfo(t *testing.T) {
+	v := testVersionInfo()
+	var res winres.Set
+	if err := res.AddVersionInfo(0x409, v); err != nil {
+		t.Fatal(err)
+	}
+	r := res.Get(winres.IntID(winres.RT_VERSION), winres.IntID(1), 0x409)
+	if r == nil {
+		t.Fatal("no RT_VERSION resource")
+	}
+	checkGolden(t, "version.res", r.Data)
+
+	got, err := res.VersionInfo(0x409)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.Translations = []winres.Translation{{Lang: 0x409, CodePage: winres.CP_UNICODE}, {Lang: 0x804, CodePage: winres.CP_UNICODE}}
+	if !reflect.DeepEqual(got, v) {
+		t.Errorf("got %+v, want %+v", got, v)
+	}
+
+	if err := res.AddVersionInfo(0x804, v); !errors.Is(err, winres.ErrExist) {
+		t.Errorf("got %v, want ErrExist", err)
+	}
+	if v, err := res.VersionInfo(0x804); v != nil || err != nil {
+		t.Errorf("got %v %v, want nil", v, err)
+	}
+}
+
+func TestVersionInfoNoStrings(t *testing.T) {
+	v := winres.NewVersionInfo(winres.Version{1}, winres.Version{1})
+	v.Translations = []winres.Translation{{Lang: 0x409, CodePage: winres.CP_UNICODE}}
+	var res winres.Set
+	if err := res.AddVersionInfo(0, v); err != nil {
+		t.Fatal(err)
+	}
+	got, err := res.VersionInfo(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, v) {
+		t.Errorf("got %+v, want %+v", got, v)
+	}
+}
+
+func TestVersionInfoInvalid(t *testing.T) {
+	var res winres.Set
+	res.Put(&winres.Resource{Type: winres.IntID(winres.RT_VERSION), Name: winres.IntID(1), Data: []byte{0xFF, 0xFF, 0, 0}})
+	var formatErr *winres.FormatError
+	if _, err := res.VersionInfo(0); !errors.As(err, &formatErr) {
+		t.Errorf("got %v, want FormatError", err)
+	}
+}
@@ -0,0 +1,199 @@
+// Package winres implements reading and writing of Windows resources
+// without calling any Windows API, so it can be used on any OS.
+package winres
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Predefined resource types.
+// https://learn.microsoft.com/en-us/windows/win32/menurc/resource-types
+const (
+	RT_CURSOR       = 1
+	RT_BITMAP       = 2
+	RT_ICON         = 3
+	RT_MENU         = 4
+	RT_DIALOG       = 5
+	RT_STRING       = 6
+	RT_FONTDIR      = 7
+	RT_FONT         = 8
+	RT_ACCELERATOR  = 9
+	RT_RCDATA       = 10
+	RT_MESSAGETABLE = 11
+	RT_GROUP_CURSOR = RT_CURSOR + 11
+	RT_GROUP_ICON   = RT_ICON + 11
+	RT_VERSION      = 16
+	RT_DLGINCLUDE   = 17
+	RT_PLUGPLAY     = 19
+	RT_VXD          = 20
+	RT_ANICURSOR    = 21
+	RT_ANIICON      = 22
+	RT_HTML         = 23
+	RT_MANIFEST     = 24
+)
+
+// LANG_NEUTRAL is the neutral language ID.
+const LANG_NEUTRAL = 0
+
+// ErrExist is returned when adding a resource that already exists.
+var ErrExist = errors.New("resource already exists")
+
+// ID identifies a resource type or name.
+// An ID is either an integer(MAKEINTRESOURCE) or a string.
+type ID struct {
+	num uint16
+	str string
+}
+
+// IntID returns an integer ID.
+func IntID(n uint16) ID {
+	return ID{num: n}
+}
+
+// StrID returns a string ID. Names are case-insensitive,
+// so str is converted to upper case as the resource compiler does.
+// It panics if str is empty.
+func StrID(str string) ID {
+	if str == "" {
+		panic(errors.New("empty resource name"))
+	}
+	return ID{str: strings.ToUpper(str)}
+}
+
+// ParseID parses s as an ID. A decimal number is parsed as an integer ID,
+// optionally prefixed by "#". Anything else is a string ID.
+func ParseID(s string) (ID, error) {
+	if n, err := strconv.ParseUint(strings.TrimPrefix(s, "#"), 10, 16); err == nil {
+		return IntID(uint16(n)), nil
+	}
+	if s == "" {
+		return ID{}, errors.New("empty resource ID")
+	}
+	return StrID(s), nil
+}
+
+// Int returns the integer value of id and whether id is an integer ID.
+func (id ID) Int() (uint16, bool) {
+	return id.num, id.str == ""
+}
+
+// Str returns the string value of id and whether id is a string ID.
+func (id ID) Str() (string, bool) {
+	return id.str, id.str != ""
+}
+
+func (id ID) String() string {
+	if id.str != "" {
+		return id.str
+	}
+	return fmt.Sprintf("#%d", id.num)
+}
+
+// compare orders IDs as they are in a resource directory:
+// string IDs first, in ascending order, then integer IDs in ascending order.
+func (id ID) compare(other ID) int {
+	if id.str != "" && other.str != "" {
+		return slices.Compare(utf16.Encode([]rune(id.str)), utf16.Encode([]rune(other.str)))
+	}
+	if id.str != "" {
+		return -1
+	}
+	if other.str != "" {
+		return 1
+	}
+	return int(id.num) - int(other.num)
+}
+
+// Resource is a single resource.
+type Resource struct {
+	Type     ID
+	Name     ID
+	Lang     uint16
+	CodePage uint32 // Usually 0.
+	Data     []byte
+}
+
+func (r *Resource) compare(other *Resource) int {
+	if c := r.Type.compare(other.Type); c != 0 {
+		return c
+	}
+	if c := r.Name.compare(other.Name); c != 0 {
+		return c
+	}
+	return int(r.Lang) - int(other.Lang)
+}
+
+// Set is a set of resources keyed by type, name and language.
+// The zero value is an empty set ready to use.
+type Set struct {
+	res []*Resource // Sorted by Resource.compare.
+}
+
+// Len returns the number of resources in s.
+func (s *Set) Len() int {
+	return len(s.res)
+}
+
+// Resources returns all resources in s, in the order they appear in
+// the resource directory.
+func (s *Set) Resources() []*Resource {
+	return slices.Clone(s.res)
+}
+
+func (s *Set) search(typ, name ID, lang uint16) (int, bool) {
+	return slices.BinarySearchFunc(s.res, &Resource{Type: typ, Name: name, Lang: lang}, (*Resource).compare)
+}
+
+// Get returns the resource with the type, name and language,
+// or nil if not found.
+func (s *Set) Get(typ, name ID, lang uint16) *Resource {
+	if i, found := s.search(typ, name, lang); found {
+		return s.res[i]
+	}
+	return nil
+}
+
+// Lookup returns the resources of all languages with the type and name.
+func (s *Set) Lookup(typ, name ID) []*Resource {
+	i, _ := s.search(typ, name, 0)
+	var result []*Resource
+	for ; i < len(s.res) && s.res[i].Type == typ && s.res[i].Name == name; i++ {
+		result = append(result, s.res[i])
+	}
+	return result
+}
+
+// Put adds r to s, replacing the resource with the same type, name and
+// language if any.
+func (s *Set) Put(r *Resource) {
+	if i, found := s.search(r.Type, r.Name, r.Lang); found {
+		s.res[i] = r
+	} else {
+		s.res = slices.Insert(s.res, i, r)
+	}
+}
+
+// Add adds r to s. It returns ErrExist if a resource with the same
+// type and name exists in any language.
+func (s *Set) Add(r *Resource) error {
+	if len(s.Lookup(r.Type, r.Name)) != 0 {
+		return fmt.Errorf("%w: type %v name %v", ErrExist, r.Type, r.Name)
+	}
+	s.Put(r)
+	return nil
+}
+
+// Remove removes the resource with the type, name and language.
+// It reports whether the resource was found.
+func (s *Set) Remove(typ, name ID, lang uint16) bool {
+	if i, found := s.search(typ, name, lang); found {
+		s.res = slices.Delete(s.res, i, i+1)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,587 @@
+package win32
+
+// Backend implements the win32 functions to create and manage windows,
+// dispatch messages, manipulate menus, GDI objects and timers.
+//
+// The functions of the same names in this package call the current backend.
+// On Windows, the default backend calls the Windows API.
+// A fake backend can be installed by SetBackend to run GUI code
+// without Windows, for example in unit tests.
+type Backend interface {
+	// Windows.
+	RegisterClassExW(cls *WNDCLASSEXW) (ATOM, error)
+	CreateWindowExW(exStyle WINDOW_EX_STYLE, className *WCHAR, windowName *WCHAR, style WINDOW_STYLE,
+		x INT, y INT, width INT, height INT,
+		wndParent HWND, menu HMENU, instance HINSTANCE, param UINT_PTR) (HWND, error)
+	DestroyWindow(hwnd HWND) error
+	DefWindowProcW(hwnd HWND, message UINT, wParam WPARAM, lParam LPARAM) LRESULT
+	CallWindowProcW(proc uintptr, hwnd HWND, msg UINT, wParam WPARAM, lParam LPARAM) LRESULT
+	SetWindowLongPtrW(hwnd HWND, index int, newLong LONG_PTR) (LONG_PTR, error)
+	GetWindowLongPtrW(hwnd HWND, index int) (LONG_PTR, error)
+	ShowWindow(hwnd HWND, cmdShow SHOW_WINDOW_CMD) error
+	SetWindowPos(hwnd HWND, hwndInsertAfter HWND, x INT, y INT, cx INT, cy INT, flags UINT) error
+	BeginDeferWindowPos(numWindows INT) (HDWP, error)
+	DeferWindowPos(winPosInfo HDWP, hwnd HWND, hwndInsertAfter HWND, x INT, y INT, cx INT, cy INT, flags UINT) (HDWP, error)
+	EndDeferWindowPos(winPosInfo HDWP) error
+	GetWindowRect(hwnd HWND, rect *RECT) error
+	GetClientRect(hwnd HWND, rect *RECT) error
+	ClientToScreen(hwnd HWND, pt *POINT) error
+	ScreenToClient(hwnd HWND, pt *POINT) error
+	GetParent(hwnd HWND) (HWND, error)
+	GetDlgItem(hwnd HWND, id INT) (HWND, error)
+	GetAncestor(hwnd HWND, flags GET_ANCESTOR_FLAG) (HWND, error)
+	GetActiveWindow() HWND
+	EnableWindow(hwnd HWND, enable bool) bool
+	IsWindowEnabled(hwnd HWND) bool
+	SetWindowTextW(hwnd HWND, str *WCHAR) error
+	GetWindowTextLengthW(hwnd HWND) (int, error)
+	GetWindowTextW(hwnd HWND, buffer *WCHAR, maxCount int) (int, error)
+	GetClassNameW(hwnd HWND, className *WCHAR, maxCount int) (int, error)
+	InvalidateRect(hwnd HWND, rect *RECT, erase bool) error
+	GetDpiForWindow(hwnd HWND) (UINT, error)
+	GetModuleHandleW(moduleName *WCHAR) (HMODULE, error)
+	LoadImageW(instance HINSTANCE, name *WCHAR, imageType UINT, cx INT, cy INT, flag UINT) (HANDLE, error)
+	LoadImageW_uintptr(instance HINSTANCE, name uintptr, imageType UINT, cx INT, cy INT, flag UINT) (HANDLE, error)
+	GetSystemMetrics(index SystemMetricsIndex) INT
+	GetSysColor(index int) DWORD
+	GetCursorPos() (*POINT, error)
+	SetCapture(hwnd HWND) HWND
+	ReleaseCapture() error
+	GetCapture() HWND
+	TrackMouseEvent(tme *TRACKMOUSEEVENT) error
+	GetDialogBaseUnits() LONG
+	SystemParametersInfoForDpi(action UINT, param UINT, p PVOID, winIni UINT, dpi UINT) error
+	MessageBoxExW(owner HWND, text *WCHAR, caption *WCHAR, typ MESSAGE_BOX_TYPE, lang WORD) (INT, error)
+
+	// Messages.
+	SendMessageW(hwnd HWND, message UINT, wParam WPARAM, lParam LPARAM) (LRESULT, error)
+	PostMessageW(hwnd HWND, message UINT, wParam WPARAM, lParam LPARAM) error
+	PostThreadMessageW(threadId DWORD, msg UINT, wParam WPARAM, lParam LPARAM) error
+	GetMessageW(msg *MSG, hwnd HWND, msgFilterMin UINT, msgFilterMax UINT) BOOL
+	PeekMessageW(msg *MSG, hwnd HWND, msgFilterMin UINT, msgFilterMax UINT, flags PeekMessageFlag) BOOL
+	TranslateMessage(msg *MSG) bool
+	GetKeyState(virtKey INT) SHORT
+	DispatchMessageW(msg *MSG) LRESULT
+	PostQuitMessage(code int)
+	SetWindowsHookExW(idHook HookID, lpfn uintptr, hMod HINSTANCE, dwThreadId DWORD) (HHOOK, error)
+	UnhookWindowsHookEx(hhk HHOOK) error
+	CallNextHookEx(hhk HHOOK, nCode HookCode, wParam WPARAM, lParam LPARAM) LRESULT
+
+	// Menus and accelerators.
+	CreateMenu() (HMENU, error)
+	CreatePopupMenu() (HMENU, error)
+	DestroyMenu(menu HMENU) error
+	DeleteMenu(menu HMENU, pos UINT, flags UINT) error
+	RemoveMenu(menu HMENU, pos UINT, flags UINT) error
+	InsertMenuItemW(menu HMENU, item UINT, byPos bool, mii *MENUITEMINFOW) error
+	GetMenuItemCount(menu HMENU) (INT, error)
+	GetMenuItemInfoW(menu HMENU, item UINT, byPos bool, mii *MENUITEMINFOW) error
+	SetMenuItemInfoW(menu HMENU, item UINT, byPos bool, mmi *MENUITEMINFOW) error
+	SetMenu(hwnd HWND, menu HMENU) error
+	GetMenu(hwnd HWND) (HMENU, error)
+	DrawMenuBar(hwnd HWND) error
+	TrackPopupMenuEx(menu HMENU, flags TRACK_POPUP_MENU_FLAG, x INT, y INT, hwnd HWND, params *TPMPARAMS) (int, error)
+	CreateAcceleratorTableW(accel []ACCEL) (HACCEL, error)
+	DestroyAcceleratorTable(table HACCEL) error
+	TranslateAcceleratorW(hwnd HWND, accTable HACCEL, msg *MSG) (bool, error)
+
+	// GDI objects and painting.
+	BeginPaint(hwnd HWND, p *PAINTSTRUCT) (HDC, error)
+	EndPaint(hwnd HWND, p *PAINTSTRUCT) error
+	GetDC(hwnd HWND) (HDC, error)
+	ReleaseDC(hwnd HWND, hdc HDC) bool
+	CreateCompatibleDC(hdc HDC) (HDC, error)
+	DeleteDC(hdc HDC) error
+	CreateCompatibleBitmap(hdc HDC, cx INT, cy INT) (HBITMAP, error)
+	BitBlt(hdc HDC, x int, y int, cx int, cy int, srcDC HDC, srcX int, srcY int, op DWORD) error
+	DeleteObject(h HANDLE) error
+	SelectObject(hdc HDC, obj HANDLE) (HANDLE, error)
+	GetStockObject(object StockObjectType) HANDLE
+	CreateBrushIndirect(p *LOGBRUSH) (HBRUSH, error)
+	ExtCreatePen(style PEN_STYLE, width DWORD, brush *LOGBRUSH, userStyles []DWORD) (HPEN, error)
+	CreatePenIndirect(p *LOGPEN) (HPEN, error)
+	CreateFontIndirectW(f *LOGFONTW) (HFONT, error)
+	FillRect(hdc HDC, rect *RECT, brush HBRUSH) error
+	SetBkMode(hdc HDC, mode BK_MODE) (BK_MODE, error)
+
+	// Timers.
+	SetTimer(hwnd HWND, idEvent UINT_PTR, elapse UINT, timerFunc uintptr) (UINT_PTR, error)
+	KillTimer(hwnd HWND, idEvent UINT_PTR) error
+	SetCoalescableTimer(hwnd HWND, idEvent UINT_PTR, elapse UINT, timerFunc uintptr, tolerance ULONG) (UINT_PTR, error)
+
+	// Pointer input and gestures.
+	GetPointerType(pointerId UINT32) (POINTER_INPUT_TYPE, error)
+	GetPointerInfo(pointerId UINT32, info *POINTER_INFO) error
+	GetPointerPenInfo(pointerId UINT32, info *POINTER_PEN_INFO) error
+	GetPointerTouchInfo(pointerId UINT32, info *POINTER_TOUCH_INFO) error
+	GetGestureInfo(h HGESTUREINFO, info *GESTUREINFO) error
+	CloseGestureInfoHandle(h HGESTUREINFO) error
+	SetGestureConfig(hwnd HWND, configs []GESTURECONFIG) error
+
+	// Common controls.
+	InitCommonControlsEx(icc *INITCOMMONCONTROLSEX) error
+	ImageList_Create(cx, cy INT, flags UINT, initial, grow INT) (HIMAGELIST, error)
+	ImageList_Destroy(himl HIMAGELIST) error
+	ImageList_ReplaceIcon(himl HIMAGELIST, i INT, icon HICON) (INT, error)
+	ImageList_GetImageCount(himl HIMAGELIST) INT
+
+	// Threads and callbacks.
+	GetCurrentThreadId() DWORD
+	GetWindowThreadProcessId(hwnd HWND, processId *DWORD) (DWORD, error)
+	// NewCallback converts a Go function to a function pointer that can be
+	// used as a window procedure, hook procedure or timer procedure.
+	NewCallback(fn any) uintptr
+}
+
+// backend is the current backend.
+// It is nil if there is no default backend on this platform.
+var backend Backend
+
+// SetBackend installs b as the current backend and returns the previous one.
+// It must be called before any window, menu or GDI object is created,
+// typically in TestMain.
+func SetBackend(b Backend) (prev Backend) {
+	prev, backend = backend, b
+	return
+}
+
+func RegisterClassExW(cls *WNDCLASSEXW) (ATOM, error) {
+	return backend.RegisterClassExW(cls)
+}
+
+func CreateWindowExW(
+	exStyle WINDOW_EX_STYLE,
+	className *WCHAR,
+	windowName *WCHAR,
+	style WINDOW_STYLE,
+	x INT, y INT, width INT, height INT,
+	wndParent HWND,
+	menu HMENU,
+	instance HINSTANCE,
+	param UINT_PTR,
+) (HWND, error) {
+	return backend.CreateWindowExW(exStyle, className, windowName, style, x, y, width, height, wndParent, menu, instance, param)
+}
+
+func DestroyWindow(hwnd HWND) error {
+	return backend.DestroyWindow(hwnd)
+}
+
+func DefWindowProcW(hwnd HWND, message UINT, wParam WPARAM, lParam LPARAM) LRESULT {
+	return backend.DefWindowProcW(hwnd, message, wParam, lParam)
+}
+
+func CallWindowProcW(proc uintptr, hwnd HWND, msg UINT, wParam WPARAM, lParam LPARAM) LRESULT {
+	return backend.CallWindowProcW(proc, hwnd, msg, wParam, lParam)
+}
+
+func SetWindowLongPtrW(hwnd HWND, index int, newLong LONG_PTR) (LONG_PTR, error) {
+	return backend.SetWindowLongPtrW(hwnd, index, newLong)
+}
+
+func GetWindowLongPtrW(hwnd HWND, index int) (LONG_PTR, error) {
+	return backend.GetWindowLongPtrW(hwnd, index)
+}
+
+func ShowWindow(hwnd HWND, cmdShow SHOW_WINDOW_CMD) error {
+	return backend.ShowWindow(hwnd, cmdShow)
+}
+
+func SetWindowPos(hwnd HWND, hwndInsertAfter HWND, x INT, y INT, cx INT, cy INT, flags UINT) error {
+	return backend.SetWindowPos(hwnd, hwndInsertAfter, x, y, cx, cy, flags)
+}
+
+func BeginDeferWindowPos(numWindows INT) (HDWP, error) {
+	return backend.BeginDeferWindowPos(numWindows)
+}
+
+func DeferWindowPos(winPosInfo HDWP, hwnd HWND, hwndInsertAfter HWND, x INT, y INT, cx INT, cy INT, flags UINT) (HDWP, error) {
+	return backend.DeferWindowPos(winPosInfo, hwnd, hwndInsertAfter, x, y, cx, cy, flags)
+}
+
+func EndDeferWindowPos(winPosInfo HDWP) error {
+	return backend.EndDeferWindowPos(winPosInfo)
+}
+
+func GetWindowRect(hwnd HWND, rect *RECT) error {
+	return backend.GetWindowRect(hwnd, rect)
+}
+
+func GetClientRect(hwnd HWND, rect *RECT) error {
+	return backend.GetClientRect(hwnd, rect)
+}
+
+func ClientToScreen(hwnd HWND, pt *POINT) error {
+	return backend.ClientToScreen(hwnd, pt)
+}
+
+func ScreenToClient(hwnd HWND, pt *POINT) error {
+	return backend.ScreenToClient(hwnd, pt)
+}
+
+func GetParent(hwnd HWND) (HWND, error) {
+	return backend.GetParent(hwnd)
+}
+
+func GetDlgItem(hwnd HWND, id INT) (HWND, error) {
+	return backend.GetDlgItem(hwnd, id)
+}
+
+func GetAncestor(hwnd HWND, flags GET_ANCESTOR_FLAG) (HWND, error) {
+	return backend.GetAncestor(hwnd, flags)
+}
+
+func GetActiveWindow() HWND {
+	return backend.GetActiveWindow()
+}
+
+func EnableWindow(hwnd HWND, enable bool) bool {
+	return backend.EnableWindow(hwnd, enable)
+}
+
+func IsWindowEnabled(hwnd HWND) bool {
+	return backend.IsWindowEnabled(hwnd)
+}
+
+func SetWindowTextW(hwnd HWND, str *WCHAR) error {
+	return backend.SetWindowTextW(hwnd, str)
+}
+
+func GetWindowTextLengthW(hwnd HWND) (int, error) {
+	return backend.GetWindowTextLengthW(hwnd)
+}
+
+func GetWindowTextW(hwnd HWND, buffer *WCHAR, maxCount int) (int, error) {
+	return backend.GetWindowTextW(hwnd, buffer, maxCount)
+}
+
+func GetClassNameW(hwnd HWND, className *WCHAR, maxCount int) (int, error) {
+	return backend.GetClassNameW(hwnd, className, maxCount)
+}
+
+func InvalidateRect(hwnd HWND, rect *RECT, erase bool) error {
+	return backend.InvalidateRect(hwnd, rect, erase)
+}
+
+func GetDpiForWindow(hwnd HWND) (UINT, error) {
+	return backend.GetDpiForWindow(hwnd)
+}
+
+func GetModuleHandleW[H HMODULE | HINSTANCE](moduleName *WCHAR) (H, error) {
+	h, err := backend.GetModuleHandleW(moduleName)
+	return H(h), err
+}
+
+func LoadImageW[H HBITMAP | HCURSOR | HICON](instance HINSTANCE, name *WCHAR, imageType UINT, cx INT, cy INT, flag UINT) (H, error) {
+	h, err := backend.LoadImageW(instance, name, imageType, cx, cy, flag)
+	return H(h), err
+}
+
+func LoadImageW_uintptr[H HBITMAP | HCURSOR | HICON](instance HINSTANCE, name uintptr, imageType UINT, cx INT, cy INT, flag UINT) (H, error) {
+	h, err := backend.LoadImageW_uintptr(instance, name, imageType, cx, cy, flag)
+	return H(h), err
+}
+
+func GetSystemMetrics(index SystemMetricsIndex) INT {
+	return backend.GetSystemMetrics(index)
+}
+
+func GetSysColor(index int) DWORD {
+	return backend.GetSysColor(index)
+}
+
+func GetCursorPos() (*POINT, error) {
+	return backend.GetCursorPos()
+}
+
+func SetCapture(hwnd HWND) HWND {
+	return backend.SetCapture(hwnd)
+}
+
+func ReleaseCapture() error {
+	return backend.ReleaseCapture()
+}
+
+func GetCapture() HWND {
+	return backend.GetCapture()
+}
+
+func TrackMouseEvent(tme *TRACKMOUSEEVENT) error {
+	return backend.TrackMouseEvent(tme)
+}
+
+func GetDialogBaseUnits() LONG {
+	return backend.GetDialogBaseUnits()
+}
+
+func SystemParametersInfoForDpi(action UINT, param UINT, p PVOID, winIni UINT, dpi UINT) error {
+	return backend.SystemParametersInfoForDpi(action, param, p, winIni, dpi)
+}
+
+func MessageBoxExW(owner HWND, text *WCHAR, caption *WCHAR, typ MESSAGE_BOX_TYPE, lang WORD) (INT, error) {
+	return backend.MessageBoxExW(owner, text, caption, typ, lang)
+}
+
+func SendMessageW(hwnd HWND, message UINT, wParam WPARAM, lParam LPARAM) (LRESULT, error) {
+	return backend.SendMessageW(hwnd, message, wParam, lParam)
+}
+
+func PostMessageW(hwnd HWND, message UINT, wParam WPARAM, lParam LPARAM) error {
+	return backend.PostMessageW(hwnd, message, wParam, lParam)
+}
+
+func PostThreadMessageW(threadId DWORD, msg UINT, wParam WPARAM, lParam LPARAM) error {
+	return backend.PostThreadMessageW(threadId, msg, wParam, lParam)
+}
+
+func GetMessageW(msg *MSG, hwnd HWND, msgFilterMin UINT, msgFilterMax UINT) BOOL {
+	return backend.GetMessageW(msg, hwnd, msgFilterMin, msgFilterMax)
+}
+
+func PeekMessageW(msg *MSG, hwnd HWND, msgFilterMin UINT, msgFilterMax UINT, flags PeekMessageFlag) BOOL {
+	return backend.PeekMessageW(msg, hwnd, msgFilterMin, msgFilterMax, flags)
+}
+
+func TranslateMessage(msg *MSG) bool {
+	return backend.TranslateMessage(msg)
+}
+
+func GetKeyState(virtKey INT) SHORT {
+	return backend.GetKeyState(virtKey)
+}
+
+func DispatchMessageW(msg *MSG) LRESULT {
+	return backend.DispatchMessageW(msg)
+}
+
+func PostQuitMessage(code int) {
+	backend.PostQuitMessage(code)
+}
+
+func SetWindowsHookExW(idHook HookID, lpfn uintptr, hMod HINSTANCE, dwThreadId DWORD) (HHOOK, error) {
+	return backend.SetWindowsHookExW(idHook, lpfn, hMod, dwThreadId)
+}
+
+func UnhookWindowsHookEx(hhk HHOOK) error {
+	return backend.UnhookWindowsHookEx(hhk)
+}
+
+func CallNextHookEx(hhk HHOOK, nCode HookCode, wParam WPARAM, lParam LPARAM) LRESULT {
+	return backend.CallNextHookEx(hhk, nCode, wParam, lParam)
+}
+
+func CreateMenu() (HMENU, error) {
+	return backend.CreateMenu()
+}
+
+func CreatePopupMenu() (HMENU, error) {
+	return backend.CreatePopupMenu()
+}
+
+func DestroyMenu(menu HMENU) error {
+	return backend.DestroyMenu(menu)
+}
+
+func DeleteMenu(menu HMENU, pos UINT, flags UINT) error {
+	return backend.DeleteMenu(menu, pos, flags)
+}
+
+func RemoveMenu(menu HMENU, pos UINT, flags UINT) error {
+	return backend.RemoveMenu(menu, pos, flags)
+}
+
+func InsertMenuItemW(menu HMENU, item UINT, byPos bool, mii *MENUITEMINFOW) error {
+	return backend.InsertMenuItemW(menu, item, byPos, mii)
+}
+
+func GetMenuItemCount(menu HMENU) (INT, error) {
+	return backend.GetMenuItemCount(menu)
+}
+
+func GetMenuItemInfoW(menu HMENU, item UINT, byPos bool, mii *MENUITEMINFOW) error {
+	return backend.GetMenuItemInfoW(menu, item, byPos, mii)
+}
+
+func SetMenuItemInfoW(menu HMENU, item UINT, byPos bool, mmi *MENUITEMINFOW) error {
+	return backend.SetMenuItemInfoW(menu, item, byPos, mmi)
+}
+
+func SetMenu(hwnd HWND, menu HMENU) error {
+	return backend.SetMenu(hwnd, menu)
+}
+
+func GetMenu(hwnd HWND) (HMENU, error) {
+	return backend.GetMenu(hwnd)
+}
+
+func DrawMenuBar(hwnd HWND) error {
+	return backend.DrawMenuBar(hwnd)
+}
+
+func TrackPopupMenuEx(menu HMENU, flags TRACK_POPUP_MENU_FLAG, x INT, y INT, hwnd HWND, params *TPMPARAMS) (int, error) {
+	return backend.TrackPopupMenuEx(menu, flags, x, y, hwnd, params)
+}
+
+func CreateAcceleratorTableW(accel []ACCEL) (HACCEL, error) {
+	return backend.CreateAcceleratorTableW(accel)
+}
+
+func DestroyAcceleratorTable(table HACCEL) error {
+	return backend.DestroyAcceleratorTable(table)
+}
+
+func TranslateAcceleratorW(hwnd HWND, accTable HACCEL, msg *MSG) (bool, error) {
+	return backend.TranslateAcceleratorW(hwnd, accTable, msg)
+}
+
+func BeginPaint(hwnd HWND, p *PAINTSTRUCT) (HDC, error) {
+	return backend.BeginPaint(hwnd, p)
+}
+
+func EndPaint(hwnd HWND, p *PAINTSTRUCT) error {
+	return backend.EndPaint(hwnd, p)
+}
+
+func GetDC(hwnd HWND) (HDC, error) {
+	return backend.GetDC(hwnd)
+}
+
+func ReleaseDC(hwnd HWND, hdc HDC) bool {
+	return backend.ReleaseDC(hwnd, hdc)
+}
+
+func CreateCompatibleDC(hdc HDC) (HDC, error) {
+	return backend.CreateCompatibleDC(hdc)
+}
+
+func DeleteDC(hdc HDC) error {
+	return backend.DeleteDC(hdc)
+}
+
+func CreateCompatibleBitmap(hdc HDC, cx INT, cy INT) (HBITMAP, error) {
+	return backend.CreateCompatibleBitmap(hdc, cx, cy)
+}
+
+func BitBlt(hdc HDC, x int, y int, cx int, cy int, srcDC HDC, srcX int, srcY int, op DWORD) error {
+	return backend.BitBlt(hdc, x, y, cx, cy, srcDC, srcX, srcY, op)
+}
+
+func DeleteObject[H HGDIOBJ](h H) error {
+	return backend.DeleteObject(HANDLE(h))
+}
+
+func SelectObject[H HGDIOBJ](hdc HDC, obj H) (H, error) {
+	h, err := backend.SelectObject(hdc, HANDLE(obj))
+	return H(h), err
+}
+
+// GetStockObject retrieves a handle to one of the stock pens, brushes, fonts, or
+// palettes.
+//
+// Returns 0 if it fails, no additional error information is available.
+func GetStockObject[H HGDIOBJ](object StockObjectType) H {
+	return H(backend.GetStockObject(object))
+}
+
+func CreateBrushIndirect(p *LOGBRUSH) (HBRUSH, error) {
+	return backend.CreateBrushIndirect(p)
+}
+
+func ExtCreatePen(style PEN_STYLE, width DWORD, brush *LOGBRUSH, userStyles []DWORD) (HPEN, error) {
+	return backend.ExtCreatePen(style, width, brush, userStyles)
+}
+
+func CreatePenIndirect(p *LOGPEN) (HPEN, error) {
+	return backend.CreatePenIndirect(p)
+}
+
+func CreateFontIndirectW(f *LOGFONTW) (HFONT, error) {
+	return backend.CreateFontIndirectW(f)
+}
+
+func FillRect(hdc HDC, rect *RECT, brush HBRUSH) error {
+	return backend.FillRect(hdc, rect, brush)
+}
+
+func SetBkMode(hdc HDC, mode BK_MODE) (BK_MODE, error) {
+	return backend.SetBkMode(hdc, mode)
+}
+
+func SetTimer(hwnd HWND, idEvent UINT_PTR, elapse UINT, timerFunc uintptr) (UINT_PTR, error) {
+	return backend.SetTimer(hwnd, idEvent, elapse, timerFunc)
+}
+
+func KillTimer(hwnd HWND, idEvent UINT_PTR) error {
+	return backend.KillTimer(hwnd, idEvent)
+}
+
+func SetCoalescableTimer(hwnd HWND, idEvent UINT_PTR, elapse UINT, timerFunc uintptr, tolerance ULONG) (UINT_PTR, error) {
+	return backend.SetCoalescableTimer(hwnd, idEvent, elapse, timerFunc, tolerance)
+}
+
+func GetPointerType(pointerId UINT32) (POINTER_INPUT_TYPE, error) {
+	return backend.GetPointerType(pointerId)
+}
+
+func GetPointerInfo(pointerId UINT32, info *POINTER_INFO) error {
+	return backend.GetPointerInfo(pointerId, info)
+}
+
+func GetPointerPenInfo(pointerId UINT32, info *POINTER_PEN_INFO) error {
+	return backend.GetPointerPenInfo(pointerId, info)
+}
+
+func GetPointerTouchInfo(pointerId UINT32, info *POINTER_TOUCH_INFO) error {
+	return backend.GetPointerTouchInfo(pointerId, info)
+}
+
+func GetGestureInfo(h HGESTUREINFO, info *GESTUREINFO) error {
+	return backend.GetGestureInfo(h, info)
+}
+
+func CloseGestureInfoHandle(h HGESTUREINFO) error {
+	return backend.CloseGestureInfoHandle(h)
+}
+
+func SetGestureConfig(hwnd HWND, configs []GESTURECONFIG) error {
+	return backend.SetGestureConfig(hwnd, configs)
+}
+
+func InitCommonControlsEx(icc *INITCOMMONCONTROLSEX) error {
+	return backend.InitCommonControlsEx(icc)
+}
+
+func ImageList_Create(cx, cy INT, flags UINT, initial, grow INT) (HIMAGELIST, error) {
+	return backend.ImageList_Create(cx, cy, flags, initial, grow)
+}
+
+func ImageList_Destroy(himl HIMAGELIST) error {
+	return backend.ImageList_Destroy(himl)
+}
+
+// ImageList_ReplaceIcon replaces the image at i with icon, or appends icon if i is -1,
+// and returns the index of the image.
+func ImageList_ReplaceIcon(himl HIMAGELIST, i INT, icon HICON) (INT, error) {
+	return backend.ImageList_ReplaceIcon(himl, i, icon)
+}
+
+func ImageList_GetImageCount(himl HIMAGELIST) INT {
+	return backend.ImageList_GetImageCount(himl)
+}
+
+// GetCurrentThreadId retrieves the thread identifier of the calling thread.
+func GetCurrentThreadId() DWORD {
+	return backend.GetCurrentThreadId()
+}
+
+// GetWindowThreadProcessId retrieves the identifier of the thread that created hwnd, and the
+// identifier of the process in processId if not nil.
+func GetWindowThreadProcessId(hwnd HWND, processId *DWORD) (DWORD, error) {
+	return backend.GetWindowThreadProcessId(hwnd, processId)
+}
+
+// NewCallback converts a Go function to a function pointer conforming to the
+// stdcall calling convention. See [Backend.NewCallback].
+func NewCallback(fn any) uintptr {
+	return backend.NewCallback(fn)
+}
@@ -0,0 +1,42 @@
+//go:build windows
+
+package win32
+
+import (
+	"unsafe"
+
+	"github.com/mkch/gw/win32/sysutil"
+	"golang.org/x/sys/windows"
+)
+
+var lzComctl32 = windows.NewLazySystemDLL("comctl32.dll")
+
+var lzInitCommonControlsEx = lzComctl32.NewProc("InitCommonControlsEx")
+
+func (sysBackend) InitCommonControlsEx(icc *INITCOMMONCONTROLSEX) error {
+	return sysutil.MustTrue(lzInitCommonControlsEx.Call(uintptr(unsafe.Pointer(icc))))
+}
+
+var lzImageList_Create = lzComctl32.NewProc("ImageList_Create")
+
+func (sysBackend) ImageList_Create(cx, cy INT, flags UINT, initial, grow INT) (HIMAGELIST, error) {
+	return sysutil.MustNotZero[HIMAGELIST](lzImageList_Create.Call(uintptr(cx), uintptr(cy), uintptr(flags), uintptr(initial), uintptr(grow)))
+}
+
+var lzImageList_Destroy = lzComctl32.NewProc("ImageList_Destroy")
+
+func (sysBackend) ImageList_Destroy(himl HIMAGELIST) error {
+	return sysutil.MustTrue(lzImageList_Destroy.Call(uintptr(himl)))
+}
+
+var lzImageList_ReplaceIcon = lzComctl32.NewProc("ImageList_ReplaceIcon")
+
+func (sysBackend) ImageList_ReplaceIcon(himl HIMAGELIST, i INT, icon HICON) (INT, error) {
+	return sysutil.MustNotNegativeOne[INT](lzImageList_ReplaceIcon.Call(uintptr(himl), uintptr(i), uintptr(icon)))
+}
+
+var lzImageList_GetImageCount = lzComctl32.NewProc("ImageList_GetImageCount")
+
+func (sysBackend) ImageList_GetImageCount(himl HIMAGELIST) INT {
+	return sysutil.As[INT](lzImageList_GetImageCount.Call(uintptr(himl)))
+}
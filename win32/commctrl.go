@@ -0,0 +1,80 @@
+package win32
+
+type HIMAGELIST HANDLE // ImageList_Destroy
+
+type INITCOMMONCONTROLSEX struct {
+	Size DWORD
+	ICC  DWORD
+}
+
+const (
+	ICC_LISTVIEW_CLASSES = 0x00000001
+	ICC_TREEVIEW_CLASSES = 0x00000002
+	ICC_BAR_CLASSES      = 0x00000004
+	ICC_TAB_CLASSES      = 0x00000008
+	ICC_UPDOWN_CLASS     = 0x00000010
+	ICC_PROGRESS_CLASS   = 0x00000020
+	ICC_WIN95_CLASSES    = 0x000000FF
+	ICC_STANDARD_CLASSES = 0x00004000
+)
+
+// Image list creation flags.
+const (
+	ILC_MASK     = 0x00000001
+	ILC_COLOR    = 0x00000000
+	ILC_COLORDDB = 0x000000FE
+	ILC_COLOR4   = 0x00000004
+	ILC_COLOR8   = 0x00000008
+	ILC_COLOR16  = 0x00000010
+	ILC_COLOR24  = 0x00000018
+	ILC_COLOR32  = 0x00000020
+	ILC_MIRROR   = 0x00002000
+)
+
+// Header control messages.
+const (
+	HDM_FIRST        = 0x1200
+	HDM_GETITEMCOUNT = HDM_FIRST + 0
+	HDM_GETITEMW     = HDM_FIRST + 11
+	HDM_SETITEMW     = HDM_FIRST + 12
+)
+
+// Header item masks.
+const (
+	HDI_WIDTH  = 0x0001
+	HDI_TEXT   = 0x0002
+	HDI_FORMAT = 0x0004
+	HDI_LPARAM = 0x0008
+	HDI_IMAGE  = 0x0020
+	HDI_ORDER  = 0x0080
+)
+
+// Header item formats.
+const (
+	HDF_LEFT     = 0x0000
+	HDF_RIGHT    = 0x0001
+	HDF_CENTER   = 0x0002
+	HDF_SORTDOWN = 0x0200
+	HDF_SORTUP   = 0x0400
+	HDF_STRING   = 0x4000
+)
+
+type HDITEMW struct {
+	Mask    UINT
+	Cxy     INT
+	Text    *WCHAR
+	Bitmap  HBITMAP
+	TextMax INT
+	Fmt     INT
+	LParam  LPARAM
+	Image   INT
+	Order   INT
+	Type    UINT
+	Filter  PVOID
+	State   UINT
+}
+
+// INDEXTOSTATEIMAGEMASK converts the index of a state image to the state bits of an item.
+func INDEXTOSTATEIMAGEMASK(i UINT) UINT {
+	return i << 12
+}
@@ -0,0 +1,3 @@
+package win32
+
+const USER_DEFAULT_SCREEN_DPI = 96
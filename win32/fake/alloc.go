@@ -0,0 +1,85 @@
+package fake
+
+import (
+	"sync"
+	"unsafe"
+
+	"github.com/mkch/gw/win32"
+)
+
+// The structures whose addresses are passed as LPARAM are allocated outside of
+// the Go heap, as the ones of the system are. The receivers convert LPARAM back
+// to pointers, which the pointer checks of -race only allow for memory outside
+// of the Go heap.
+
+const (
+	allocAlign = 16       // Alignment and granularity of the blocks.
+	chunkSize  = 64 << 10 // Size of the memory mapped at a time.
+)
+
+var arena struct {
+	sync.Mutex
+	free  map[uintptr][]unsafe.Pointer // Freed blocks by size.
+	chunk unsafe.Pointer               // Unused part of the last mapped memory.
+	left  uintptr                      // Size of chunk.
+}
+
+// blockSize returns the size of the blocks holding T.
+func blockSize[T any]() uintptr {
+	var zero T
+	size := (unsafe.Sizeof(zero) + allocAlign - 1) &^ (allocAlign - 1)
+	return max(size, allocAlign)
+}
+
+// Alloc returns a pointer to a zero T allocated outside of the Go heap, which
+// is released by Free. Tests use Alloc for the structures passed as LPARAM to
+// the code converting LPARAM back to pointers, such as the NMHDR of WM_NOTIFY.
+// The memory is not scanned by the garbage collector, the objects referenced
+// by the pointers stored in it must be kept alive by other means.
+func Alloc[T any]() *T {
+	size := blockSize[T]()
+	if size > chunkSize {
+		panic("fake: Alloc: type too large")
+	}
+	arena.Lock()
+	var p unsafe.Pointer
+	if free := arena.free[size]; len(free) > 0 {
+		p = free[len(free)-1]
+		arena.free[size] = free[:len(free)-1]
+	} else {
+		if arena.left < size {
+			arena.chunk, arena.left = mapMemory(chunkSize), chunkSize
+		}
+		p = arena.chunk
+		arena.chunk = unsafe.Add(arena.chunk, size)
+		arena.left -= size
+	}
+	arena.Unlock()
+	var zero T
+	*(*T)(p) = zero
+	return (*T)(p)
+}
+
+// Free releases p allocated by Alloc.
+func Free[T any](p *T) {
+	var zero T
+	*p = zero
+	arena.Lock()
+	defer arena.Unlock()
+	if arena.free == nil {
+		arena.free = make(map[uintptr][]unsafe.Pointer)
+	}
+	size := blockSize[T]()
+	arena.free[size] = append(arena.free[size], unsafe.Pointer(p))
+}
+
+// withLParam calls f with an LPARAM pointing to a copy of *p allocated by Alloc,
+// and copies the copy, which f may modify, back to *p after f returns.
+func withLParam[T, R any](p *T, f func(lParam win32.LPARAM) R) R {
+	q := Alloc[T]()
+	defer Free(q)
+	*q = *p
+	r := f(win32.LPARAM(uintptr(unsafe.Pointer(q))))
+	*p = *q
+	return r
+}
@@ -0,0 +1,11 @@
+//go:build !unix && !windows
+
+package fake
+
+import "unsafe"
+
+// mapMemory returns size bytes of memory. Without memory mapping, the memory
+// is allocated in the Go heap.
+func mapMemory(size uintptr) unsafe.Pointer {
+	return unsafe.Pointer(&make([]byte, size)[0])
+}
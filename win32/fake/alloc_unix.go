@@ -0,0 +1,17 @@
+//go:build unix
+
+package fake
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// mapMemory returns size bytes of anonymous memory outside of the Go heap.
+func mapMemory(size uintptr) unsafe.Pointer {
+	b, err := syscall.Mmap(-1, 0, int(size), syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_ANON|syscall.MAP_PRIVATE)
+	if err != nil {
+		panic(err)
+	}
+	return unsafe.Pointer(&b[0])
+}
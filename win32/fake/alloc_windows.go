@@ -0,0 +1,20 @@
+package fake
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// mapMemory returns size bytes of memory outside of the Go heap, backed by the paging file.
+func mapMemory(size uintptr) unsafe.Pointer {
+	h, err := syscall.CreateFileMapping(syscall.InvalidHandle, nil, syscall.PAGE_READWRITE, 0, uint32(size), nil)
+	if err != nil {
+		panic(err)
+	}
+	defer syscall.CloseHandle(h) // The view keeps the mapping.
+	addr, err := syscall.MapViewOfFile(h, syscall.FILE_MAP_WRITE, 0, 0, size)
+	if err != nil {
+		panic(err)
+	}
+	return unsafe.Add(nil, addr)
+}
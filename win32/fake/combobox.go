@@ -0,0 +1,211 @@
+package fake
+
+import (
+	"fmt"
+	"unicode/utf16"
+
+	"github.com/mkch/gw/win32"
+)
+
+// comboBox is the state of a COMBOBOX window. The list is kept in listBox.
+type comboBox struct {
+	cue                []uint16
+	dropped            bool
+	editStart, editEnd int
+}
+
+// comboBox returns the combo box state of w. b.mu must be held.
+func (w *window) comboBox() *comboBox {
+	if w.cb == nil {
+		w.cb = &comboBox{}
+	}
+	return w.cb
+}
+
+// listStyle returns the list box style of a LISTBOX window, or the equivalent
+// list box style of the list of a COMBOBOX window.
+func (w *window) listStyle() win32.WINDOW_STYLE {
+	if !w.isClass("COMBOBOX") {
+		return w.style
+	}
+	// CBS_OWNERDRAW* are the same as LBS_OWNERDRAW*.
+	style := w.style & (win32.CBS_OWNERDRAWFIXED | win32.CBS_OWNERDRAWVARIABLE)
+	if w.style&win32.CBS_SORT != 0 {
+		style |= win32.LBS_SORT
+	}
+	if w.style&win32.CBS_HASSTRINGS != 0 {
+		style |= win32.LBS_HASSTRINGS
+	}
+	return style
+}
+
+// comboListMessages maps the combo box messages to the list box messages
+// handling the list of the combo box.
+var comboListMessages = map[win32.UINT]win32.UINT{
+	win32.CB_ADDSTRING:       win32.LB_ADDSTRING,
+	win32.CB_INSERTSTRING:    win32.LB_INSERTSTRING,
+	win32.CB_DELETESTRING:    win32.LB_DELETESTRING,
+	win32.CB_RESETCONTENT:    win32.LB_RESETCONTENT,
+	win32.CB_GETCOUNT:        win32.LB_GETCOUNT,
+	win32.CB_GETLBTEXT:       win32.LB_GETTEXT,
+	win32.CB_GETLBTEXTLEN:    win32.LB_GETTEXTLEN,
+	win32.CB_SETCURSEL:       win32.LB_SETCURSEL,
+	win32.CB_GETCURSEL:       win32.LB_GETCURSEL,
+	win32.CB_FINDSTRINGEXACT: win32.LB_FINDSTRINGEXACT,
+	win32.CB_GETITEMDATA:     win32.LB_GETITEMDATA,
+	win32.CB_SETITEMDATA:     win32.LB_SETITEMDATA,
+	win32.CB_SETITEMHEIGHT:   win32.LB_SETITEMHEIGHT,
+	win32.CB_GETITEMHEIGHT:   win32.LB_GETITEMHEIGHT,
+}
+
+// comboType returns CBS_SIMPLE, CBS_DROPDOWN or CBS_DROPDOWNLIST.
+func (w *window) comboType() win32.WINDOW_STYLE {
+	return w.style & 0x3
+}
+
+// comboBoxProc handles the CB_* messages of COMBOBOX windows.
+// The edit text is the window text.
+func (b *Backend) comboBoxProc(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) (win32.LRESULT, bool) {
+	if lbMessage, ok := comboListMessages[message]; ok {
+		r, _ := b.listBoxProc(hwnd, lbMessage, wParam, lParam)
+		switch message {
+		case win32.CB_SETCURSEL:
+			b.mu.Lock()
+			if w := b.windows[hwnd]; w != nil {
+				w.text = nil
+				if r >= 0 {
+					w.text = w.listBox().items[r].text
+				}
+			}
+			b.mu.Unlock()
+		case win32.CB_RESETCONTENT:
+			b.mu.Lock()
+			if w := b.windows[hwnd]; w != nil {
+				w.text = nil
+			}
+			b.mu.Unlock()
+		}
+		return r, true
+	}
+	switch message {
+	case win32.CB_SHOWDROPDOWN:
+		b.mu.Lock()
+		w := b.windows[hwnd]
+		if w == nil || w.comboType() == win32.CBS_SIMPLE {
+			b.mu.Unlock()
+			return 1, true
+		}
+		cb := w.comboBox()
+		changed := cb.dropped != (wParam != 0)
+		cb.dropped = wParam != 0
+		b.mu.Unlock()
+		if changed {
+			b.command(hwnd, gg(wParam != 0, win32.CBN_DROPDOWN, win32.CBN_CLOSEUP))
+		}
+		return 1, true
+	case win32.CB_GETDROPPEDSTATE:
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if w := b.windows[hwnd]; w != nil {
+			return win32.LRESULT(boolToInt(w.comboBox().dropped)), true
+		}
+		return 0, true
+	case win32.CB_SETCUEBANNER:
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if w := b.windows[hwnd]; w != nil {
+			w.comboBox().cue = cString((*win32.WCHAR)(pointer(lParam)))
+		}
+		return 1, true
+	case win32.CB_GETCUEBANNER:
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if w := b.windows[hwnd]; w != nil {
+			copyCString((*win32.WCHAR)(pointer(win32.LPARAM(wParam))), int(lParam), w.comboBox().cue)
+		}
+		return 1, true
+	case win32.CB_SETEDITSEL:
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		w := b.windows[hwnd]
+		if w == nil || w.comboType() == win32.CBS_DROPDOWNLIST {
+			return win32.CB_ERR, true
+		}
+		cb := w.comboBox()
+		cb.editStart, cb.editEnd = int(int16(lParam)), int(int16(lParam>>16))
+		return 1, true
+	case win32.CB_GETEDITSEL:
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		w := b.windows[hwnd]
+		if w == nil || w.comboType() == win32.CBS_DROPDOWNLIST {
+			return win32.CB_ERR, true
+		}
+		cb := w.comboBox()
+		start, end := cb.editStart, cb.editEnd
+		if end < 0 || end > len(w.text) {
+			end = len(w.text)
+		}
+		start = min(max(start, 0), end)
+		if wParam != 0 {
+			*(*win32.DWORD)(pointer(win32.LPARAM(wParam))) = win32.DWORD(start)
+		}
+		if lParam != 0 {
+			*(*win32.DWORD)(pointer(lParam)) = win32.DWORD(end)
+		}
+		return win32.LRESULT(makeLParam(start, end)), true
+	}
+	return 0, false
+}
+
+// ComboBoxSelect simulates choosing the item at index from the list of a COMBOBOX window.
+// The item is selected and its text becomes the edit text, CBN_SELCHANGE and CBN_SELENDOK
+// are sent, then the drop-down list, if shown, is closed.
+func (b *Backend) ComboBoxSelect(hwnd win32.HWND, index int) error {
+	b.mu.Lock()
+	w, err := b.control(hwnd, "COMBOBOX")
+	if err != nil {
+		b.mu.Unlock()
+		return err
+	}
+	lb := w.listBox()
+	if index < 0 || index >= len(lb.items) {
+		b.mu.Unlock()
+		return fmt.Errorf("%w: combo box item %v", ErrNotFound, index)
+	}
+	for i, item := range lb.items {
+		item.selected = i == index
+	}
+	w.text = lb.items[index].text
+	dropped := w.comboBox().dropped
+	b.mu.Unlock()
+	b.command(hwnd, win32.CBN_SELCHANGE)
+	b.command(hwnd, win32.CBN_SELENDOK)
+	if dropped {
+		b.send(hwnd, win32.CB_SHOWDROPDOWN, 0, 0)
+	}
+	return nil
+}
+
+// ComboBoxType simulates typing in the edit control of a COMBOBOX window of
+// CBS_SIMPLE or CBS_DROPDOWN. The edit text is replaced with text, the caret is
+// moved to the end, then CBN_EDITUPDATE and CBN_EDITCHANGE are sent.
+func (b *Backend) ComboBoxType(hwnd win32.HWND, text string) error {
+	b.mu.Lock()
+	w, err := b.control(hwnd, "COMBOBOX")
+	if err != nil {
+		b.mu.Unlock()
+		return err
+	}
+	if w.comboType() == win32.CBS_DROPDOWNLIST {
+		b.mu.Unlock()
+		return fmt.Errorf("%w: typing in CBS_DROPDOWNLIST combo box", ErrNotSupported)
+	}
+	w.text = utf16.Encode([]rune(text))
+	cb := w.comboBox()
+	cb.editStart, cb.editEnd = len(w.text), len(w.text)
+	b.mu.Unlock()
+	b.command(hwnd, win32.CBN_EDITUPDATE)
+	b.command(hwnd, win32.CBN_EDITCHANGE)
+	return nil
+}
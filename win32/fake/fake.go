@@ -0,0 +1,326 @@
+// Package fake implements a headless win32.Backend in pure Go.
+//
+// The fake backend simulates windows, message queues, menus, GDI objects and
+// timers in memory, so that GUI code can run without Windows, for example in
+// unit tests on Linux:
+//
+//	var backend = fake.New()
+//
+//	func TestMain(m *testing.M) {
+//		win32.SetBackend(backend)
+//		os.Exit(m.Run())
+//	}
+//
+// Package faketest does the same with faketest.Main, and creates parent windows
+// for controls.
+//
+// Every goroutine is a thread with its own message queue. A window belongs to
+// the goroutine which created it. SendMessageW calls the window procedure
+// directly if the window belongs to the calling goroutine, otherwise it waits
+// until the owner goroutine retrieves messages, as Windows does.
+//
+// Windows have no non-client area, the client area is the whole window.
+// The predefined control classes store fonts, LISTBOX and COMBOBOX windows
+// keep their items and selection, SysListView32 windows simulate the
+// LVS_OWNERDATA report view, and SysTreeView32 windows keep their items;
+// user interaction with controls is simulated by methods such as Backend.ListBoxClick.
+// Timers run on a virtual clock which is advanced by Backend.Advance.
+// The structures passed as LPARAM live outside of the Go heap, see Alloc.
+package fake
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf16"
+	"unsafe"
+
+	"github.com/mkch/gw/win32"
+)
+
+var (
+	// ErrInvalidHandle is returned if a handle is not valid.
+	ErrInvalidHandle = errors.New("fake: invalid handle")
+	// ErrNotFound is returned if a window class or menu item is not found.
+	ErrNotFound = errors.New("fake: not found")
+	// ErrExist is returned if a window class already exists.
+	ErrExist = errors.New("fake: already exists")
+	// ErrWrongThread is returned if a window is used in a thread other than its owner.
+	ErrWrongThread = errors.New("fake: wrong thread")
+	// ErrNotSupported is returned by the features not supported by the fake backend.
+	ErrNotSupported = errors.New("fake: not supported")
+)
+
+// Default position and size of the windows created with CW_USEDEFAULT.
+const (
+	DefaultX      = 100
+	DefaultY      = 100
+	DefaultWidth  = 800
+	DefaultHeight = 600
+)
+
+// Backend is an in-memory win32.Backend. Create one with New.
+type Backend struct {
+	// TrackPopupMenu is called by TrackPopupMenuEx to choose a command from the popup menu
+	// shown at screen coordinates x, y. It returns the command ID chosen, or 0 to cancel the menu.
+	// If TrackPopupMenu is nil, all popup menus are canceled.
+	TrackPopupMenu func(menu win32.HMENU, x, y win32.INT) win32.UINT
+	// MessageBox is called by MessageBoxExW and returns the ID of the button pressed.
+	// If MessageBox is nil, MessageBoxExW returns IDOK.
+	MessageBox func(owner win32.HWND, text, caption string, typ win32.MESSAGE_BOX_TYPE) win32.INT
+
+	mu         sync.Mutex
+	lastHandle win32.HANDLE
+	lastAtom   win32.ATOM
+	classes    map[string]*class
+	windows    map[win32.HWND]*window
+	threads    map[win32.DWORD]*thread
+	hooks      []*hook
+	menus      map[win32.HMENU]*menu
+	accels     map[win32.HACCEL][]win32.ACCEL
+	objects    map[win32.HANDLE]*object
+	stock      map[win32.StockObjectType]win32.HANDLE
+	dcs        map[win32.HDC]*dc
+	deferred   map[win32.HDWP][]windowPos
+	timers     []*timer
+	lastTimer  win32.UINT_PTR
+	now        time.Duration
+	dpi        win32.UINT
+	cursorPos  win32.POINT
+	metrics    map[win32.SystemMetricsIndex]win32.INT
+	// keys is the key state as of the last key message retrieved, and
+	// asyncKeys is the state as of the last key message posted.
+	keys      [256]bool
+	asyncKeys [256]bool
+	delivered []win32.MSG
+	// Mouse state, see MouseMove.
+	capture    win32.HWND
+	mouseWnd   win32.HWND // The window under the cursor.
+	buttons    win32.WPARAM
+	tracks     map[win32.HWND]*mouseTrack
+	lastClick  click
+	pointers   map[win32.UINT32]*pointerInput
+	gestures   map[win32.HGESTUREINFO]win32.GESTUREINFO
+	imageLists map[win32.HIMAGELIST]*imageList
+}
+
+var _ win32.Backend = (*Backend)(nil)
+
+// New creates a Backend with a screen of 1920x1080 pixels at 96 DPI.
+func New() *Backend {
+	b := &Backend{
+		lastHandle: 0x10000,
+		lastAtom:   0xC000,
+		classes:    make(map[string]*class),
+		windows:    make(map[win32.HWND]*window),
+		threads:    make(map[win32.DWORD]*thread),
+		menus:      make(map[win32.HMENU]*menu),
+		accels:     make(map[win32.HACCEL][]win32.ACCEL),
+		objects:    make(map[win32.HANDLE]*object),
+		stock:      make(map[win32.StockObjectType]win32.HANDLE),
+		dcs:        make(map[win32.HDC]*dc),
+		deferred:   make(map[win32.HDWP][]windowPos),
+		tracks:     make(map[win32.HWND]*mouseTrack),
+		pointers:   make(map[win32.UINT32]*pointerInput),
+		gestures:   make(map[win32.HGESTUREINFO]win32.GESTUREINFO),
+		imageLists: make(map[win32.HIMAGELIST]*imageList),
+		dpi:        win32.USER_DEFAULT_SCREEN_DPI,
+		metrics: map[win32.SystemMetricsIndex]win32.INT{
+			win32.SM_CXSCREEN: 1920,
+			win32.SM_CYSCREEN: 1080,
+		},
+	}
+	b.registerSystemClasses()
+	return b
+}
+
+// newHandle allocates a new handle. b.mu must be held.
+func (b *Backend) newHandle() win32.HANDLE {
+	b.lastHandle += 4
+	return b.lastHandle
+}
+
+// SetCursorPos sets the cursor position returned by GetCursorPos.
+func (b *Backend) SetCursorPos(x, y win32.LONG) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cursorPos = win32.POINT{X: x, Y: y}
+}
+
+// SetSystemMetrics sets the value returned by GetSystemMetrics(index).
+func (b *Backend) SetSystemMetrics(index win32.SystemMetricsIndex, value win32.INT) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.metrics[index] = value
+}
+
+// Delivered returns the messages delivered to the window procedure of hwnd,
+// sent or dispatched, in the order of delivery.
+// If hwnd is 0, the messages delivered to all windows are returned.
+func (b *Backend) Delivered(hwnd win32.HWND) []win32.MSG {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var msgs []win32.MSG
+	for _, msg := range b.delivered {
+		if hwnd == 0 || msg.Hwnd == hwnd {
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs
+}
+
+// ResetDelivered clears the record of delivered messages.
+func (b *Backend) ResetDelivered() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.delivered = nil
+}
+
+// GetCurrentThreadId returns the ID of the calling goroutine.
+func (b *Backend) GetCurrentThreadId() win32.DWORD {
+	return currentThreadID()
+}
+
+// GetWindowThreadProcessId returns the ID of the goroutine which created hwnd.
+// The process ID is the one of the test process.
+func (b *Backend) GetWindowThreadProcessId(hwnd win32.HWND, processId *win32.DWORD) (win32.DWORD, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	w, err := b.window(hwnd)
+	if err != nil {
+		return 0, err
+	}
+	if processId != nil {
+		*processId = win32.DWORD(os.Getpid())
+	}
+	return w.thread, nil
+}
+
+// currentThreadID returns the ID of the calling goroutine, parsed from
+// the header "goroutine N [...]" of its stack trace.
+func currentThreadID() win32.DWORD {
+	var buf [64]byte
+	s := strings.TrimPrefix(string(buf[:runtime.Stack(buf[:], false)]), "goroutine ")
+	id, err := strconv.ParseUint(s[:strings.IndexByte(s, ' ')], 10, 32)
+	if err != nil {
+		panic(err)
+	}
+	return win32.DWORD(id)
+}
+
+// callbacks are the functions registered by NewCallback.
+// They are shared by all backends, as the callbacks of windows.NewCallback are.
+var callbacks struct {
+	sync.Mutex
+	fns []reflect.Value
+}
+
+const (
+	callbackBase = 0x7F000000
+	callbackSize = 16
+)
+
+// NewCallback registers fn and returns its fake address.
+// Fn must be a function with one uintptr-sized result and uintptr-sized integer arguments.
+func (b *Backend) NewCallback(fn any) uintptr {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func {
+		panic("fake: NewCallback: fn is not a function")
+	}
+	if v.Type().NumOut() != 1 {
+		panic("fake: NewCallback: fn must have one result")
+	}
+	callbacks.Lock()
+	defer callbacks.Unlock()
+	callbacks.fns = append(callbacks.fns, v)
+	return callbackBase + uintptr(len(callbacks.fns)-1)*callbackSize
+}
+
+// call calls the callback at addr with args.
+func call(addr uintptr, args ...uintptr) uintptr {
+	callbacks.Lock()
+	i := int(addr-callbackBase) / callbackSize
+	if addr < callbackBase || (addr-callbackBase)%callbackSize != 0 || i >= len(callbacks.fns) {
+		callbacks.Unlock()
+		panic(fmt.Sprintf("fake: invalid callback address %#x", addr))
+	}
+	fn := callbacks.fns[i]
+	callbacks.Unlock()
+
+	t := fn.Type()
+	if t.NumIn() != len(args) {
+		panic(fmt.Sprintf("fake: callback %v called with %v arguments", t, len(args)))
+	}
+	in := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		in[i] = reflect.ValueOf(arg).Convert(t.In(i))
+	}
+	switch out := fn.Call(in)[0]; out.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return uintptr(out.Int())
+	case reflect.Bool:
+		if out.Bool() {
+			return 1
+		}
+		return 0
+	default:
+		return uintptr(out.Uint())
+	}
+}
+
+// sink keeps the pointers passed to addr escaping to the heap.
+var sink unsafe.Pointer
+
+// addr returns p as an LPARAM. The object p points to is moved to the heap,
+// where objects are never moved, so the address remains valid while p is alive.
+// The caller must keep p alive until the LPARAM is no longer used.
+func addr(p unsafe.Pointer) win32.LPARAM {
+	atomic.StorePointer(&sink, p)
+	return win32.LPARAM(uintptr(p))
+}
+
+// pointer returns the pointer passed as lParam. The bits are reinterpreted,
+// instead of converted from uintptr, to pass the pointer checks of -race.
+func pointer(lParam win32.LPARAM) unsafe.Pointer {
+	return *(*unsafe.Pointer)(unsafe.Pointer(&lParam))
+}
+
+// makeLParam combines two 16-bit values, as MAKELPARAM does.
+func makeLParam[T ~int32 | ~int](lo, hi T) win32.LPARAM {
+	return win32.LPARAM(uintptr(uint16(lo)) | uintptr(uint16(hi))<<16)
+}
+
+// goString converts a null terminated C string to a go string.
+func goString(p *win32.WCHAR) string {
+	return string(utf16.Decode(cString(p)))
+}
+
+// cString returns the UTF-16 characters of a null terminated C string, excluding the terminating null.
+func cString(p *win32.WCHAR) []uint16 {
+	if p == nil {
+		return nil
+	}
+	n := 0
+	for *(*win32.WCHAR)(unsafe.Add(unsafe.Pointer(p), n*2)) != 0 {
+		n++
+	}
+	return append([]uint16(nil), unsafe.Slice((*uint16)(unsafe.Pointer(p)), n)...)
+}
+
+// copyCString copies s to the buffer of size characters at p, truncated and null terminated.
+// It returns the number of characters copied, excluding the terminating null.
+func copyCString(p *win32.WCHAR, size int, s []uint16) int {
+	if p == nil || size <= 0 {
+		return 0
+	}
+	n := copy(unsafe.Slice((*uint16)(unsafe.Pointer(p)), size-1), s)
+	*(*win32.WCHAR)(unsafe.Add(unsafe.Pointer(p), n*2)) = 0
+	return n
+}
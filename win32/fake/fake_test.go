@@ -0,0 +1,145 @@
+package fake_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mkch/gw/win32"
+	"github.com/mkch/gw/win32/fake"
+	"github.com/mkch/gw/win32/win32util"
+)
+
+const WM_APP_TEST = win32.WM_USER + 1
+
+// newWindow registers a window class of proc and creates a window of it.
+func newWindow(t *testing.T, b *fake.Backend, proc func(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) win32.LRESULT) win32.HWND {
+	t.Helper()
+	var className []win32.WCHAR
+	win32util.CString(t.Name(), &className)
+	if _, err := b.RegisterClassExW(&win32.WNDCLASSEXW{
+		ClassName: &className[0],
+		WndProc:   b.NewCallback(proc),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	hwnd, err := b.CreateWindowExW(0, &className[0], nil, win32.WS_OVERLAPPEDWINDOW,
+		win32.CW_USEDEFAULT, 0, win32.CW_USEDEFAULT, 0, 0, 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hwnd
+}
+
+func TestSendMessage(t *testing.T) {
+	b := fake.New()
+	type result struct {
+		hwnd   win32.HWND
+		thread win32.DWORD
+	}
+	created := make(chan result)
+	var procThread win32.DWORD
+	go func() {
+		hwnd := newWindow(t, b, func(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) win32.LRESULT {
+			if message == WM_APP_TEST {
+				procThread = b.GetCurrentThreadId()
+				return win32.LRESULT(wParam + 1)
+			}
+			return b.DefWindowProcW(hwnd, message, wParam, lParam)
+		})
+		created <- result{hwnd, b.GetCurrentThreadId()}
+		var msg win32.MSG
+		for b.GetMessageW(&msg, 0, 0, 0) > 0 {
+			b.DispatchMessageW(&msg)
+		}
+		close(created)
+	}()
+	r := <-created
+
+	// Sent from another goroutine, processed in the owner goroutine.
+	if ret, err := b.SendMessageW(r.hwnd, WM_APP_TEST, 41, 0); err != nil || ret != 42 {
+		t.Fatal(ret, err)
+	}
+	if procThread != r.thread {
+		t.Fatal(procThread, r.thread)
+	}
+	if err := b.DestroyWindow(r.hwnd); err == nil {
+		t.Fatal("destroyed in wrong thread")
+	}
+	if err := b.PostThreadMessageW(r.thread, win32.WM_QUIT, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	<-created
+}
+
+func TestTimer(t *testing.T) {
+	b := fake.New()
+	var ticks []win32.WPARAM
+	hwnd := newWindow(t, b, func(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) win32.LRESULT {
+		if message == win32.WM_TIMER {
+			ticks = append(ticks, wParam)
+		}
+		return b.DefWindowProcW(hwnd, message, wParam, lParam)
+	})
+	if _, err := b.SetTimer(hwnd, 7, 100, 0); err != nil {
+		t.Fatal(err)
+	}
+	b.Pump()
+	if len(ticks) != 0 {
+		t.Fatal(ticks)
+	}
+	b.Advance(100 * time.Millisecond)
+	b.Pump()
+	if !slices.Equal(ticks, []win32.WPARAM{7}) {
+		t.Fatal(ticks)
+	}
+	// Elapsed intervals are coalesced.
+	b.Advance(time.Second)
+	b.Pump()
+	if len(ticks) != 2 {
+		t.Fatal(ticks)
+	}
+	if err := b.KillTimer(hwnd, 7); err != nil {
+		t.Fatal(err)
+	}
+	b.Advance(time.Second)
+	b.Pump()
+	if len(ticks) != 2 {
+		t.Fatal(ticks)
+	}
+
+	// Timers are killed with the window.
+	b.SetTimer(hwnd, 8, 100, 0)
+	b.DestroyWindow(hwnd)
+	if n := b.Timers(); n != 0 {
+		t.Fatal(n)
+	}
+}
+
+func TestQuit(t *testing.T) {
+	b := fake.New()
+	b.PostMessageW(0, WM_APP_TEST, 0, 0)
+	b.PostQuitMessage(3)
+	var msg win32.MSG
+	if r := b.GetMessageW(&msg, 0, 0, 0); r != 1 || msg.Message != WM_APP_TEST {
+		t.Fatal(r, msg)
+	}
+	if r := b.GetMessageW(&msg, 0, 0, 0); r != 0 || msg.Message != win32.WM_QUIT || msg.WParam != 3 {
+		t.Fatal(r, msg)
+	}
+}
+
+func TestAlloc(t *testing.T) {
+	rect := fake.Alloc[win32.RECT]()
+	*rect = win32.RECT{Left: 1, Top: 2, Right: 3, Bottom: 4}
+	fake.Free(rect)
+	if again := fake.Alloc[win32.RECT](); again != rect || *again != (win32.RECT{}) {
+		t.Fatal(again, *again)
+	}
+	defer fake.Free(rect)
+	if other := fake.Alloc[win32.RECT](); other == rect {
+		t.Fatal(other)
+	} else {
+		fake.Free(other)
+	}
+}
@@ -0,0 +1,42 @@
+// Package faketest implements the fixtures shared by the tests running on the
+// fake backend:
+//
+//	var backend = fake.New()
+//
+//	func TestMain(m *testing.M) {
+//		faketest.Main(m, backend)
+//	}
+//
+//	func TestControl(t *testing.T) {
+//		parent := faketest.NewParent(t)
+//		...
+//	}
+package faketest
+
+import (
+	"os"
+	"testing"
+
+	"github.com/mkch/gw/win32"
+	"github.com/mkch/gw/win32/fake"
+	"github.com/mkch/gw/window"
+)
+
+// Main sets backend as the win32 backend, runs the tests and exits.
+// It is called by TestMain.
+func Main(m *testing.M, backend *fake.Backend) {
+	win32.SetBackend(backend)
+	os.Exit(m.Run())
+}
+
+// NewParent creates a top-level window to create controls in, which is destroyed
+// when t finishes.
+func NewParent(t testing.TB) *window.Window {
+	t.Helper()
+	parent, err := window.New(&window.Spec{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { parent.Destroy() })
+	return parent
+}
@@ -0,0 +1,348 @@
+package fake
+
+import (
+	"fmt"
+
+	"github.com/mkch/gw/win32"
+)
+
+type objectKind int
+
+const (
+	brushObject objectKind = iota
+	penObject
+	fontObject
+	bitmapObject
+	paletteObject
+)
+
+func (k objectKind) String() string {
+	return [...]string{"brush", "pen", "font", "bitmap", "palette"}[k]
+}
+
+type object struct {
+	kind  objectKind
+	stock bool
+	// selected is the number of DCs the object is selected into.
+	selected int
+}
+
+type dc struct {
+	hwnd   win32.HWND // 0 for memory DCs.
+	memory bool
+	paint  bool // Retrieved by BeginPaint.
+	// objects are the objects selected into the DC, by kind.
+	objects map[objectKind]win32.HANDLE
+	bkMode  win32.BK_MODE
+}
+
+// GDIObjects returns the number of GDI objects and DCs not deleted, excluding stock objects.
+// It can be used to detect leaks.
+func (b *Backend) GDIObjects() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n := len(b.dcs)
+	for _, obj := range b.objects {
+		if !obj.stock {
+			n++
+		}
+	}
+	return n
+}
+
+// newObject creates a GDI object. b.mu must be held.
+func (b *Backend) newObject(kind objectKind) win32.HANDLE {
+	h := b.newHandle()
+	b.objects[h] = &object{kind: kind}
+	return h
+}
+
+// stockObject returns the stock object of typ. b.mu must be held.
+func (b *Backend) stockObject(typ win32.StockObjectType) win32.HANDLE {
+	if h, ok := b.stock[typ]; ok {
+		return h
+	}
+	var kind objectKind
+	switch typ {
+	case win32.WHITE_BRUSH, win32.LTGRAY_BRUSH, win32.GRAY_BRUSH, win32.DKGRAY_BRUSH, win32.BLACK_BRUSH, win32.NULL_BRUSH, win32.DC_BRUSH:
+		kind = brushObject
+	case win32.WHITE_PEN, win32.BLACK_PEN, win32.NULL_PEN, win32.DC_PEN:
+		kind = penObject
+	case win32.OEM_FIXED_FONT, win32.ANSI_FIXED_FONT, win32.ANSI_VAR_FONT, win32.SYSTEM_FONT, win32.DEVICE_DEFAULT_FONT, win32.SYSTEM_FIXED_FONT, win32.DEFAULT_GUI_FONT:
+		kind = fontObject
+	case win32.DEFAULT_PALETTE:
+		kind = paletteObject
+	default:
+		return 0
+	}
+	h := b.newHandle()
+	b.objects[h] = &object{kind: kind, stock: true}
+	b.stock[typ] = h
+	return h
+}
+
+// defaultBitmap is the stock 1x1 monochrome bitmap selected into new memory DCs.
+// It is not a stock object of GetStockObject. b.mu must be held.
+func (b *Backend) defaultBitmap() win32.HANDLE {
+	const typ = win32.STOCK_LAST + 1
+	if h, ok := b.stock[typ]; ok {
+		return h
+	}
+	h := b.newHandle()
+	b.objects[h] = &object{kind: bitmapObject, stock: true}
+	b.stock[typ] = h
+	return h
+}
+
+// newDC creates a DC with the default objects selected. b.mu must be held.
+func (b *Backend) newDC(hwnd win32.HWND, memory, paint bool) win32.HDC {
+	h := win32.HDC(b.newHandle())
+	d := &dc{hwnd: hwnd, memory: memory, paint: paint, bkMode: win32.OPAQUE, objects: map[objectKind]win32.HANDLE{
+		brushObject: b.stockObject(win32.WHITE_BRUSH),
+		penObject:   b.stockObject(win32.BLACK_PEN),
+		fontObject:  b.stockObject(win32.SYSTEM_FONT),
+	}}
+	if memory {
+		d.objects[bitmapObject] = b.defaultBitmap()
+	}
+	for _, obj := range d.objects {
+		b.objects[obj].selected++
+	}
+	b.dcs[h] = d
+	return h
+}
+
+// deleteDC deletes a DC and deselects its objects. b.mu must be held.
+func (b *Backend) deleteDC(hdc win32.HDC) {
+	for _, obj := range b.dcs[hdc].objects {
+		b.objects[obj].selected--
+	}
+	delete(b.dcs, hdc)
+}
+
+// dc returns the DC of h. b.mu must be held.
+func (b *Backend) dc(h win32.HDC) (*dc, error) {
+	if d := b.dcs[h]; d != nil {
+		return d, nil
+	}
+	return nil, fmt.Errorf("%w: HDC %#x", ErrInvalidHandle, h)
+}
+
+// BeginPaint sends WM_ERASEBKGND if the background needs erasing, and validates the window.
+func (b *Backend) BeginPaint(hwnd win32.HWND, p *win32.PAINTSTRUCT) (win32.HDC, error) {
+	b.mu.Lock()
+	w, err := b.window(hwnd)
+	if err != nil {
+		b.mu.Unlock()
+		return 0, err
+	}
+	erase, rect := w.erase, w.updateRect
+	if !w.invalid {
+		rect = win32.RECT{}
+	}
+	w.invalid, w.erase, w.updateRect = false, false, win32.RECT{}
+	hdc := b.newDC(hwnd, false, true)
+	b.mu.Unlock()
+
+	erased := false
+	if erase {
+		erased = b.deliver(hwnd, win32.WM_ERASEBKGND, win32.WPARAM(hdc), 0) != 0
+	}
+	*p = win32.PAINTSTRUCT{HDC: hdc, Erase: win32.BOOL(boolToInt(erase && !erased)), RcPaint: rect}
+	return hdc, nil
+}
+
+func (b *Backend) EndPaint(hwnd win32.HWND, p *win32.PAINTSTRUCT) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	d, err := b.dc(p.HDC)
+	if err != nil {
+		return err
+	}
+	if !d.paint || d.hwnd != hwnd {
+		return fmt.Errorf("%w: HDC %#x is not a paint DC of HWND %#x", ErrInvalidHandle, p.HDC, hwnd)
+	}
+	b.deleteDC(p.HDC)
+	return nil
+}
+
+func (b *Backend) GetDC(hwnd win32.HWND) (win32.HDC, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if hwnd != 0 {
+		if _, err := b.window(hwnd); err != nil {
+			return 0, err
+		}
+	}
+	return b.newDC(hwnd, false, false), nil
+}
+
+func (b *Backend) ReleaseDC(hwnd win32.HWND, hdc win32.HDC) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	d := b.dcs[hdc]
+	if d == nil || d.memory || d.paint || d.hwnd != hwnd {
+		return false
+	}
+	b.deleteDC(hdc)
+	return true
+}
+
+// CreateCompatibleDC creates a memory DC. Hdc can be 0.
+func (b *Backend) CreateCompatibleDC(hdc win32.HDC) (win32.HDC, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if hdc != 0 {
+		if _, err := b.dc(hdc); err != nil {
+			return 0, err
+		}
+	}
+	return b.newDC(0, true, false), nil
+}
+
+// DeleteDC deletes a memory DC.
+func (b *Backend) DeleteDC(hdc win32.HDC) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	d, err := b.dc(hdc)
+	if err != nil {
+		return err
+	}
+	if !d.memory {
+		return fmt.Errorf("%w: HDC %#x is not a memory DC", ErrInvalidHandle, hdc)
+	}
+	b.deleteDC(hdc)
+	return nil
+}
+
+func (b *Backend) CreateCompatibleBitmap(hdc win32.HDC, cx win32.INT, cy win32.INT) (win32.HBITMAP, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, err := b.dc(hdc); err != nil {
+		return 0, err
+	}
+	return win32.HBITMAP(b.newObject(bitmapObject)), nil
+}
+
+// BitBlt does nothing but validates the DCs.
+func (b *Backend) BitBlt(hdc win32.HDC, x int, y int, cx int, cy int, srcDC win32.HDC, srcX int, srcY int, op win32.DWORD) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, err := b.dc(hdc); err != nil {
+		return err
+	}
+	if srcDC != 0 {
+		if _, err := b.dc(srcDC); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteObject deletes a GDI object. It is an error to delete an object selected into a DC.
+// Deleting a stock object does nothing.
+func (b *Backend) DeleteObject(h win32.HANDLE) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	obj := b.objects[h]
+	if obj == nil {
+		return fmt.Errorf("%w: HGDIOBJ %#x", ErrInvalidHandle, h)
+	}
+	if obj.stock {
+		return nil
+	}
+	if obj.selected > 0 {
+		return fmt.Errorf("fake: %v %#x is selected into a DC", obj.kind, h)
+	}
+	delete(b.objects, h)
+	return nil
+}
+
+// SelectObject selects a brush, pen, font or bitmap into hdc. Bitmaps can be
+// selected into memory DCs only, and into one DC at a time.
+func (b *Backend) SelectObject(hdc win32.HDC, h win32.HANDLE) (win32.HANDLE, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	d, err := b.dc(hdc)
+	if err != nil {
+		return 0, err
+	}
+	obj := b.objects[h]
+	if obj == nil {
+		return 0, fmt.Errorf("%w: HGDIOBJ %#x", ErrInvalidHandle, h)
+	}
+	switch obj.kind {
+	case paletteObject:
+		return 0, fmt.Errorf("%w: SelectObject of palette", ErrNotSupported)
+	case bitmapObject:
+		if !d.memory {
+			return 0, fmt.Errorf("fake: bitmap %#x selected into non-memory DC %#x", h, hdc)
+		}
+		if obj.selected > 0 && d.objects[bitmapObject] != h && !obj.stock {
+			return 0, fmt.Errorf("fake: bitmap %#x is selected into another DC", h)
+		}
+	}
+	old := d.objects[obj.kind]
+	b.objects[old].selected--
+	obj.selected++
+	d.objects[obj.kind] = h
+	return old, nil
+}
+
+func (b *Backend) GetStockObject(object win32.StockObjectType) win32.HANDLE {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.stockObject(object)
+}
+
+func (b *Backend) CreateBrushIndirect(p *win32.LOGBRUSH) (win32.HBRUSH, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return win32.HBRUSH(b.newObject(brushObject)), nil
+}
+
+func (b *Backend) ExtCreatePen(style win32.PEN_STYLE, width win32.DWORD, brush *win32.LOGBRUSH, userStyles []win32.DWORD) (win32.HPEN, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return win32.HPEN(b.newObject(penObject)), nil
+}
+
+func (b *Backend) CreatePenIndirect(p *win32.LOGPEN) (win32.HPEN, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return win32.HPEN(b.newObject(penObject)), nil
+}
+
+func (b *Backend) CreateFontIndirectW(f *win32.LOGFONTW) (win32.HFONT, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return win32.HFONT(b.newObject(fontObject)), nil
+}
+
+// FillRect does nothing but validates the handles.
+func (b *Backend) FillRect(hdc win32.HDC, rect *win32.RECT, brush win32.HBRUSH) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, err := b.dc(hdc); err != nil {
+		return err
+	}
+	// Brush can be a system color index plus one.
+	if brush > win32.COLOR_MENUBAR+1 {
+		if obj := b.objects[win32.HANDLE(brush)]; obj == nil || obj.kind != brushObject {
+			return fmt.Errorf("%w: HBRUSH %#x", ErrInvalidHandle, brush)
+		}
+	}
+	return nil
+}
+
+func (b *Backend) SetBkMode(hdc win32.HDC, mode win32.BK_MODE) (win32.BK_MODE, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	d, err := b.dc(hdc)
+	if err != nil {
+		return 0, err
+	}
+	old := d.bkMode
+	d.bkMode = mode
+	return old, nil
+}
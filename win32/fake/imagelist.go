@@ -0,0 +1,63 @@
+package fake
+
+import (
+	"fmt"
+
+	"github.com/mkch/gw/win32"
+)
+
+// imageList is an image list. The images are kept as the icons added.
+type imageList struct {
+	cx, cy win32.INT
+	icons  []win32.HICON
+}
+
+// InitCommonControlsEx does nothing, the common control classes are always registered.
+func (b *Backend) InitCommonControlsEx(icc *win32.INITCOMMONCONTROLSEX) error {
+	return nil
+}
+
+func (b *Backend) ImageList_Create(cx, cy win32.INT, flags win32.UINT, initial, grow win32.INT) (win32.HIMAGELIST, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	h := win32.HIMAGELIST(b.newHandle())
+	b.imageLists[h] = &imageList{cx: cx, cy: cy}
+	return h, nil
+}
+
+func (b *Backend) ImageList_Destroy(himl win32.HIMAGELIST) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.imageLists[himl] == nil {
+		return fmt.Errorf("%w: HIMAGELIST %#x", ErrInvalidHandle, himl)
+	}
+	delete(b.imageLists, himl)
+	return nil
+}
+
+func (b *Backend) ImageList_ReplaceIcon(himl win32.HIMAGELIST, i win32.INT, icon win32.HICON) (win32.INT, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	il := b.imageLists[himl]
+	if il == nil {
+		return -1, fmt.Errorf("%w: HIMAGELIST %#x", ErrInvalidHandle, himl)
+	}
+	if i == -1 {
+		il.icons = append(il.icons, icon)
+		return win32.INT(len(il.icons) - 1), nil
+	}
+	if i < 0 || int(i) >= len(il.icons) {
+		return -1, fmt.Errorf("%w: image %v", ErrNotFound, i)
+	}
+	il.icons[i] = icon
+	return i, nil
+}
+
+func (b *Backend) ImageList_GetImageCount(himl win32.HIMAGELIST) win32.INT {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if il := b.imageLists[himl]; il != nil {
+		return win32.INT(len(il.icons))
+	}
+	return 0
+}
@@ -0,0 +1,347 @@
+package fake
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf16"
+	"unsafe"
+
+	"github.com/mkch/gw/win32"
+)
+
+// defaultItemHeight is the item height of list boxes at 96 DPI.
+const defaultItemHeight = 16
+
+// listBox is the state of a LISTBOX window.
+type listBox struct {
+	items      []*listItem
+	itemHeight win32.UINT // Height of all items, unless LBS_OWNERDRAWVARIABLE.
+	caret      int        // The item which has the focus.
+}
+
+type listItem struct {
+	text     []uint16
+	data     win32.LPARAM
+	height   win32.UINT // LBS_OWNERDRAWVARIABLE only.
+	selected bool
+}
+
+// multiSel reports whether w is a list box of multiple selection.
+func (w *window) multiSel() bool {
+	return w.listStyle()&(win32.LBS_MULTIPLESEL|win32.LBS_EXTENDEDSEL) != 0
+}
+
+// hasStrings reports whether the items of list box w have strings.
+func (w *window) hasStrings() bool {
+	style := w.listStyle()
+	return style&(win32.LBS_OWNERDRAWFIXED|win32.LBS_OWNERDRAWVARIABLE) == 0 || style&win32.LBS_HASSTRINGS != 0
+}
+
+// listBox returns the list box state of w. b.mu must be held.
+func (w *window) listBox() *listBox {
+	if w.lb == nil {
+		w.lb = &listBox{itemHeight: defaultItemHeight}
+	}
+	return w.lb
+}
+
+// insertItem inserts an item of LB_ADDSTRING or LB_INSERTSTRING at index i, -1 for the end.
+// It returns the index of the item inserted. b.mu must be held.
+func (w *window) insertItem(i int, lParam win32.LPARAM) int {
+	lb := w.listBox()
+	item := &listItem{height: lb.itemHeight}
+	if w.hasStrings() {
+		item.text = cString((*win32.WCHAR)(pointer(lParam)))
+	} else {
+		item.data = lParam
+	}
+	if i < 0 || i > len(lb.items) {
+		i = len(lb.items)
+	}
+	lb.items = slices.Insert(lb.items, i, item)
+	return i
+}
+
+// sortedIndex returns the index to add text to the sorted list box w. b.mu must be held.
+func (w *window) sortedIndex(text *win32.WCHAR) int {
+	s := strings.ToLower(goString(text))
+	i, _ := slices.BinarySearchFunc(w.listBox().items, s, func(item *listItem, s string) int {
+		return strings.Compare(strings.ToLower(string(utf16.Decode(item.text))), s)
+	})
+	return i
+}
+
+// listBoxProc handles the LB_* messages of LISTBOX windows.
+// Tab stops, columns, locales and LB_DIR are not supported.
+func (b *Backend) listBoxProc(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) (win32.LRESULT, bool) {
+	if message < win32.LB_ADDSTRING || message > win32.LB_ITEMFROMPOINT {
+		return 0, false
+	}
+	b.mu.Lock()
+	w := b.windows[hwnd]
+	if w == nil {
+		b.mu.Unlock()
+		return win32.LB_ERR, true
+	}
+	lb := w.listBox()
+	index := int(wParam)
+	valid := index >= 0 && index < len(lb.items)
+	switch message {
+	case win32.LB_ADDSTRING, win32.LB_INSERTSTRING:
+		i := -1
+		if message == win32.LB_INSERTSTRING {
+			i = int(int32(wParam))
+			if i > len(lb.items) {
+				b.mu.Unlock()
+				return win32.LB_ERR, true
+			}
+		} else if w.listStyle()&win32.LBS_SORT != 0 && w.hasStrings() {
+			i = w.sortedIndex((*win32.WCHAR)(pointer(lParam)))
+		}
+		i = w.insertItem(i, lParam)
+		b.mu.Unlock()
+		b.measureItem(w, i)
+		return win32.LRESULT(i), true
+	case win32.LB_DELETESTRING:
+		defer b.mu.Unlock()
+		if !valid {
+			return win32.LB_ERR, true
+		}
+		lb.items = slices.Delete(lb.items, index, index+1)
+		return win32.LRESULT(len(lb.items)), true
+	case win32.LB_RESETCONTENT:
+		defer b.mu.Unlock()
+		lb.items = nil
+		return 0, true
+	case win32.LB_GETCOUNT:
+		defer b.mu.Unlock()
+		return win32.LRESULT(len(lb.items)), true
+	case win32.LB_GETTEXT:
+		defer b.mu.Unlock()
+		if !valid || !w.hasStrings() {
+			return win32.LB_ERR, true
+		}
+		text := lb.items[index].text
+		return win32.LRESULT(copyCString((*win32.WCHAR)(pointer(lParam)), len(text)+1, text)), true
+	case win32.LB_GETTEXTLEN:
+		defer b.mu.Unlock()
+		if !valid || !w.hasStrings() {
+			return win32.LB_ERR, true
+		}
+		return win32.LRESULT(len(lb.items[index].text)), true
+	case win32.LB_SETCURSEL:
+		defer b.mu.Unlock()
+		if w.multiSel() {
+			return win32.LB_ERR, true
+		}
+		for i, item := range lb.items {
+			item.selected = i == index
+		}
+		if valid {
+			lb.caret = index
+		}
+		return gg[win32.LRESULT](valid, win32.LRESULT(index), win32.LB_ERR), true
+	case win32.LB_GETCURSEL:
+		defer b.mu.Unlock()
+		for i, item := range lb.items {
+			if item.selected {
+				return win32.LRESULT(i), true
+			}
+		}
+		return win32.LB_ERR, true
+	case win32.LB_SETSEL:
+		defer b.mu.Unlock()
+		i := int(int32(lParam))
+		if !w.multiSel() || i < -1 || i >= len(lb.items) {
+			return win32.LB_ERR, true
+		}
+		for j, item := range lb.items {
+			if i == -1 || i == j {
+				item.selected = wParam != 0
+			}
+		}
+		return 0, true
+	case win32.LB_GETSEL:
+		defer b.mu.Unlock()
+		if !valid {
+			return win32.LB_ERR, true
+		}
+		return gg[win32.LRESULT](lb.items[index].selected, 1, 0), true
+	case win32.LB_GETSELCOUNT:
+		defer b.mu.Unlock()
+		if !w.multiSel() {
+			return win32.LB_ERR, true
+		}
+		n := 0
+		for _, item := range lb.items {
+			n += boolToInt(item.selected)
+		}
+		return win32.LRESULT(n), true
+	case win32.LB_GETSELITEMS:
+		defer b.mu.Unlock()
+		if !w.multiSel() {
+			return win32.LB_ERR, true
+		}
+		buf := unsafe.Slice((*win32.INT)(pointer(lParam)), index)
+		n := 0
+		for i, item := range lb.items {
+			if item.selected && n < len(buf) {
+				buf[n] = win32.INT(i)
+				n++
+			}
+		}
+		return win32.LRESULT(n), true
+	case win32.LB_GETCARETINDEX:
+		defer b.mu.Unlock()
+		return win32.LRESULT(lb.caret), true
+	case win32.LB_SETCARETINDEX:
+		defer b.mu.Unlock()
+		if !valid {
+			return win32.LB_ERR, true
+		}
+		lb.caret = index
+		return 0, true
+	case win32.LB_GETITEMDATA:
+		defer b.mu.Unlock()
+		if !valid {
+			return win32.LB_ERR, true
+		}
+		return win32.LRESULT(lb.items[index].data), true
+	case win32.LB_SETITEMDATA:
+		defer b.mu.Unlock()
+		if !valid {
+			return win32.LB_ERR, true
+		}
+		lb.items[index].data = lParam
+		return 0, true
+	case win32.LB_SETITEMHEIGHT:
+		defer b.mu.Unlock()
+		height := win32.UINT(uint16(lParam))
+		if w.listStyle()&win32.LBS_OWNERDRAWVARIABLE == 0 {
+			lb.itemHeight = height
+			for _, item := range lb.items {
+				item.height = height
+			}
+			return 0, true
+		} else if !valid {
+			return win32.LB_ERR, true
+		}
+		lb.items[index].height = height
+		return 0, true
+	case win32.LB_GETITEMHEIGHT:
+		defer b.mu.Unlock()
+		if w.listStyle()&win32.LBS_OWNERDRAWVARIABLE == 0 {
+			return win32.LRESULT(lb.itemHeight), true
+		} else if !valid {
+			return win32.LB_ERR, true
+		}
+		return win32.LRESULT(lb.items[index].height), true
+	case win32.LB_FINDSTRINGEXACT:
+		defer b.mu.Unlock()
+		s := strings.ToLower(goString((*win32.WCHAR)(pointer(lParam))))
+		start := int(int32(wParam))
+		for n := range len(lb.items) {
+			i := (max(start, -1) + 1 + n) % len(lb.items)
+			if strings.ToLower(string(utf16.Decode(lb.items[i].text))) == s {
+				return win32.LRESULT(i), true
+			}
+		}
+		return win32.LB_ERR, true
+	}
+	b.mu.Unlock()
+	return 0, false
+}
+
+// measureItem sends WM_MEASUREITEM to the parent of list box or combo box w to
+// measure the item at index i, if w is owner-drawn of variable height.
+func (b *Backend) measureItem(w *window, i int) {
+	b.mu.Lock()
+	if w.listStyle()&win32.LBS_OWNERDRAWVARIABLE == 0 {
+		b.mu.Unlock()
+		return
+	}
+	item := w.listBox().items[i]
+	mis := &win32.MEASUREITEMSTRUCT{
+		CtlType:    gg[win32.UINT](w.isClass("COMBOBOX"), win32.ODT_COMBOBOX, win32.ODT_LISTBOX),
+		CtlID:      win32.UINT(w.id),
+		ItemID:     win32.UINT(i),
+		ItemHeight: item.height,
+		ItemData:   win32.ULONG_PTR(item.data),
+	}
+	b.mu.Unlock()
+	withLParam(mis, func(lParam win32.LPARAM) win32.LRESULT {
+		r, _ := b.send(w.parent, win32.WM_MEASUREITEM, win32.WPARAM(w.id), lParam)
+		return r
+	})
+	b.mu.Lock()
+	item.height = mis.ItemHeight
+	b.mu.Unlock()
+}
+
+// ListBoxClick simulates clicking the item at index of a LISTBOX window, or
+// double-clicking if double is true. The selection is changed the way mouse clicks
+// without modifier keys do, and the notifications are sent if the list box is LBS_NOTIFY.
+func (b *Backend) ListBoxClick(hwnd win32.HWND, index int, double bool) error {
+	b.mu.Lock()
+	w, err := b.control(hwnd, "LISTBOX")
+	if err != nil {
+		b.mu.Unlock()
+		return err
+	}
+	lb := w.listBox()
+	if index < 0 || index >= len(lb.items) {
+		b.mu.Unlock()
+		return fmt.Errorf("%w: list box item %v", ErrNotFound, index)
+	}
+	lb.caret = index
+	if w.style&win32.LBS_NOSEL == 0 {
+		for i, item := range lb.items {
+			if i == index {
+				item.selected = w.style&win32.LBS_MULTIPLESEL == 0 || !item.selected
+			} else if w.style&win32.LBS_MULTIPLESEL == 0 {
+				item.selected = false
+			}
+		}
+	}
+	notify := w.style&win32.LBS_NOTIFY != 0
+	b.mu.Unlock()
+	if !notify {
+		return nil
+	}
+	b.command(hwnd, win32.LBN_SELCHANGE)
+	if double {
+		b.command(hwnd, win32.LBN_DBLCLK)
+	}
+	return nil
+}
+
+// isClass reports whether w is of the window class name.
+func (w *window) isClass(name string) bool {
+	return strings.EqualFold(w.class.name, name)
+}
+
+// control returns the window hwnd of the window class name. b.mu must be held.
+func (b *Backend) control(hwnd win32.HWND, class string) (*window, error) {
+	w, err := b.window(hwnd)
+	if err != nil {
+		return nil, err
+	}
+	if !w.isClass(class) {
+		return nil, fmt.Errorf("%w: HWND %#x is not of window class %v", ErrInvalidHandle, hwnd, class)
+	}
+	return w, nil
+}
+
+// command sends the WM_COMMAND notification code of control hwnd to its parent.
+func (b *Backend) command(hwnd win32.HWND, code int) {
+	b.mu.Lock()
+	w := b.windows[hwnd]
+	if w == nil {
+		b.mu.Unlock()
+		return
+	}
+	parent, id := w.parent, w.id
+	b.mu.Unlock()
+	b.send(parent, win32.WM_COMMAND, win32.WPARAM(makeLParam(int(id), code)), win32.LPARAM(hwnd))
+}
@@ -0,0 +1,499 @@
+package fake
+
+import (
+	"fmt"
+	"runtime"
+	"slices"
+	"unsafe"
+
+	"github.com/mkch/gw/win32"
+)
+
+// listView is the state of a SysListView32 window. Only the report view of
+// LVS_OWNERDATA is simulated: the items are counted, and their texts, images and
+// state images are retrieved from the parent with LVN_GETDISPINFOW.
+type listView struct {
+	count      int
+	columns    []*lvColumn
+	exStyle    win32.DWORD
+	selected   map[int]bool
+	focused    int
+	imageLists [3]win32.HIMAGELIST // Indexed by LVSIL_*.
+	// callbackMask is the item states retrieved from the parent, see LVM_SETCALLBACKMASK.
+	callbackMask win32.UINT
+	header       win32.HWND
+}
+
+type lvColumn struct {
+	text  []uint16
+	width win32.INT
+	fmt   win32.INT // LVCFMT_* and HDF_*.
+}
+
+// listView returns the list view state of w. b.mu must be held.
+func (w *window) listView() *listView {
+	if w.lv == nil {
+		w.lv = &listView{selected: make(map[int]bool), focused: -1}
+	}
+	return w.lv
+}
+
+// hitTest returns the item and subitem at x, y of the client area, and the LVHT_* flags.
+// Rows are defaultItemHeight high from the top, and the state icon of column 0 is
+// 16 pixels wide if the list view has LVS_EX_CHECKBOXES.
+func (lv *listView) hitTest(x, y int) (item, subItem int, flags win32.UINT) {
+	if y < 0 {
+		return -1, -1, win32.LVHT_ABOVE
+	}
+	item = y / defaultItemHeight
+	if item >= lv.count {
+		return -1, -1, win32.LVHT_NOWHERE
+	}
+	if x < 0 {
+		return -1, -1, win32.LVHT_TOLEFT
+	}
+	left := 0
+	for i, col := range lv.columns {
+		right := left + int(col.width)
+		if x < right {
+			if i == 0 && lv.exStyle&win32.LVS_EX_CHECKBOXES != 0 && x < left+16 {
+				return item, i, win32.LVHT_ONITEMSTATEICON
+			}
+			return item, i, win32.LVHT_ONITEMLABEL
+		}
+		left = right
+	}
+	return -1, -1, win32.LVHT_NOWHERE
+}
+
+// state returns the LVIS_SELECTED and LVIS_FOCUSED state of item i.
+func (lv *listView) state(i int) win32.UINT {
+	return win32.UINT(boolToInt(lv.selected[i]))*win32.LVIS_SELECTED | win32.UINT(boolToInt(lv.focused == i))*win32.LVIS_FOCUSED
+}
+
+// listViewProc handles the LVM_* messages of SysListView32 windows.
+func (b *Backend) listViewProc(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) (win32.LRESULT, bool) {
+	switch message {
+	case win32.LVM_GETITEMTEXTW, win32.LVM_GETITEMW, win32.LVM_GETITEMSTATE,
+		win32.LVM_SETITEMSTATE, win32.LVM_GETHEADER:
+		// Handled without b.mu held, because they send messages.
+		return b.listViewSend(hwnd, message, wParam, lParam), true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	w := b.windows[hwnd]
+	if w == nil {
+		return 0, false
+	}
+	lv := w.listView()
+	switch message {
+	case win32.LVM_SETITEMCOUNT:
+		lv.count = int(wParam)
+		for i := range lv.selected {
+			if i >= lv.count {
+				delete(lv.selected, i)
+			}
+		}
+		if lv.focused >= lv.count {
+			lv.focused = -1
+		}
+		return 1, true
+	case win32.LVM_GETITEMCOUNT:
+		return win32.LRESULT(lv.count), true
+	case win32.LVM_GETSELECTEDCOUNT:
+		return win32.LRESULT(len(lv.selected)), true
+	case win32.LVM_GETNEXTITEM:
+		for i := int(int32(wParam)) + 1; i < lv.count; i++ {
+			if lParam&win32.LVNI_SELECTED != 0 && !lv.selected[i] ||
+				lParam&win32.LVNI_FOCUSED != 0 && lv.focused != i {
+				continue
+			}
+			return win32.LRESULT(i), true
+		}
+		return -1, true
+	case win32.LVM_INSERTCOLUMNW:
+		col := (*win32.LVCOLUMNW)(pointer(lParam))
+		c := &lvColumn{}
+		setColumn(c, col)
+		i := min(int(wParam), len(lv.columns))
+		lv.columns = slices.Insert(lv.columns, i, c)
+		return win32.LRESULT(i), true
+	case win32.LVM_DELETECOLUMN:
+		if int(wParam) >= len(lv.columns) {
+			return 0, true
+		}
+		lv.columns = slices.Delete(lv.columns, int(wParam), int(wParam)+1)
+		return 1, true
+	case win32.LVM_SETCOLUMNW:
+		if int(wParam) >= len(lv.columns) {
+			return 0, true
+		}
+		setColumn(lv.columns[wParam], (*win32.LVCOLUMNW)(pointer(lParam)))
+		return 1, true
+	case win32.LVM_GETCOLUMNW:
+		if int(wParam) >= len(lv.columns) {
+			return 0, true
+		}
+		c, col := lv.columns[wParam], (*win32.LVCOLUMNW)(pointer(lParam))
+		if col.Mask&win32.LVCF_FMT != 0 {
+			col.Fmt = c.fmt & (win32.LVCFMT_RIGHT | win32.LVCFMT_CENTER)
+		}
+		if col.Mask&win32.LVCF_WIDTH != 0 {
+			col.Cx = c.width
+		}
+		if col.Mask&win32.LVCF_TEXT != 0 {
+			copyCString(col.Text, int(col.TextMax), c.text)
+		}
+		return 1, true
+	case win32.LVM_GETCOLUMNWIDTH:
+		if int(wParam) >= len(lv.columns) {
+			return 0, true
+		}
+		return win32.LRESULT(lv.columns[wParam].width), true
+	case win32.LVM_SETCOLUMNWIDTH:
+		if int(wParam) >= len(lv.columns) {
+			return 0, true
+		}
+		lv.columns[wParam].width = win32.INT(int16(lParam))
+		return 1, true
+	case win32.LVM_SETEXTENDEDLISTVIEWSTYLE:
+		old := lv.exStyle
+		mask := gg(wParam == 0, ^win32.DWORD(0), win32.DWORD(wParam))
+		lv.exStyle = old&^mask | win32.DWORD(lParam)&mask
+		return win32.LRESULT(old), true
+	case win32.LVM_GETEXTENDEDLISTVIEWSTYLE:
+		return win32.LRESULT(lv.exStyle), true
+	case win32.LVM_SETIMAGELIST:
+		if wParam > win32.LVSIL_STATE {
+			return 0, true
+		}
+		old := lv.imageLists[wParam]
+		lv.imageLists[wParam] = win32.HIMAGELIST(lParam)
+		return win32.LRESULT(old), true
+	case win32.LVM_HITTEST, win32.LVM_SUBITEMHITTEST:
+		info := (*win32.LVHITTESTINFO)(pointer(lParam))
+		item, subItem, flags := lv.hitTest(int(info.Pt.X), int(info.Pt.Y))
+		info.Item, info.Flags = win32.INT(item), flags
+		if message == win32.LVM_SUBITEMHITTEST {
+			info.SubItem = win32.INT(subItem)
+		}
+		return win32.LRESULT(item), true
+	case win32.LVM_SETCALLBACKMASK:
+		lv.callbackMask = win32.UINT(wParam)
+		return 1, true
+	case win32.LVM_GETCALLBACKMASK:
+		return win32.LRESULT(lv.callbackMask), true
+	case win32.LVM_ENSUREVISIBLE:
+		return 1, true
+	case win32.LVM_REDRAWITEMS:
+		return 1, true
+	case win32.LVM_DELETEALLITEMS:
+		lv.count, lv.focused = 0, -1
+		clear(lv.selected)
+		return 1, true
+	case win32.LVM_INSERTITEMW:
+		// Not supported in LVS_OWNERDATA.
+		return -1, true
+	}
+	return 0, false
+}
+
+// setColumn sets the fields of c specified by col.
+func setColumn(c *lvColumn, col *win32.LVCOLUMNW) {
+	if col.Mask&win32.LVCF_FMT != 0 {
+		c.fmt = c.fmt&^(win32.LVCFMT_RIGHT|win32.LVCFMT_CENTER) | col.Fmt
+	}
+	if col.Mask&win32.LVCF_WIDTH != 0 {
+		c.width = col.Cx
+	}
+	if col.Mask&win32.LVCF_TEXT != 0 {
+		c.text = cString(col.Text)
+	}
+}
+
+// listViewSend handles the LVM_* messages of SysListView32 window hwnd which send
+// messages. b.mu must not be held.
+func (b *Backend) listViewSend(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) win32.LRESULT {
+	switch message {
+	case win32.LVM_GETITEMTEXTW:
+		item := (*win32.LVITEMW)(pointer(lParam))
+		text, _, _ := b.dispInfo(hwnd, int(wParam), int(item.SubItem), win32.LVIF_TEXT)
+		return win32.LRESULT(copyCString(item.Text, int(item.TextMax), text))
+	case win32.LVM_GETITEMW:
+		item := (*win32.LVITEMW)(pointer(lParam))
+		text, image, state := b.dispInfo(hwnd, int(item.Item), int(item.SubItem), item.Mask&(win32.LVIF_TEXT|win32.LVIF_IMAGE|win32.LVIF_STATE))
+		if item.Mask&win32.LVIF_TEXT != 0 {
+			copyCString(item.Text, int(item.TextMax), text)
+		}
+		if item.Mask&win32.LVIF_IMAGE != 0 {
+			item.Image = image
+		}
+		if item.Mask&win32.LVIF_STATE != 0 {
+			item.State = state & item.StateMask
+		}
+		return 1
+	case win32.LVM_GETITEMSTATE:
+		var mask win32.UINT
+		if lParam&win32.LVIS_STATEIMAGEMASK != 0 {
+			mask = win32.LVIF_STATE
+		}
+		_, _, state := b.dispInfo(hwnd, int(wParam), 0, mask)
+		return win32.LRESULT(state & win32.UINT(lParam))
+	case win32.LVM_SETITEMSTATE:
+		item := (*win32.LVITEMW)(pointer(lParam))
+		return win32.LRESULT(boolToInt(b.setItemState(hwnd, int(int32(wParam)), item.State, item.StateMask)))
+	case win32.LVM_GETHEADER:
+		b.mu.Lock()
+		w := b.windows[hwnd]
+		if w == nil {
+			b.mu.Unlock()
+			return 0
+		}
+		lv := w.listView()
+		if lv.header != 0 {
+			b.mu.Unlock()
+			return win32.LRESULT(lv.header)
+		}
+		b.mu.Unlock()
+		header, err := b.createControl("SysHeader32", hwnd, nil)
+		if err != nil {
+			return 0
+		}
+		b.mu.Lock()
+		lv.header = header
+		b.mu.Unlock()
+		return win32.LRESULT(header)
+	}
+	return 0
+}
+
+// dispInfo retrieves the text, image and state of an item of list view hwnd.
+// The text, image and state image are retrieved from the parent with LVN_GETDISPINFOW,
+// as specified by mask. The state image is retrieved only if LVIS_STATEIMAGEMASK is
+// in the callback mask. b.mu must not be held.
+func (b *Backend) dispInfo(hwnd win32.HWND, item, subItem int, mask win32.UINT) (text []uint16, image win32.INT, state win32.UINT) {
+	b.mu.Lock()
+	w := b.windows[hwnd]
+	if w == nil || item < 0 || item >= w.listView().count {
+		b.mu.Unlock()
+		return
+	}
+	state = w.listView().state(item)
+	if w.listView().callbackMask&win32.LVIS_STATEIMAGEMASK == 0 {
+		mask &^= win32.LVIF_STATE
+	}
+	b.mu.Unlock()
+	if mask == 0 {
+		return
+	}
+	var buf [260]win32.WCHAR
+	info := &win32.NMLVDISPINFOW{Item: win32.LVITEMW{
+		Mask:      mask,
+		Item:      win32.INT(item),
+		SubItem:   win32.INT(subItem),
+		StateMask: gg[win32.UINT](mask&win32.LVIF_STATE != 0, win32.LVIS_STATEIMAGEMASK, 0),
+		Text:      &buf[0],
+		TextMax:   win32.INT(len(buf)),
+	}}
+	sendNotify(b, hwnd, info, win32.LVN_GETDISPINFOW)
+	runtime.KeepAlive(info)
+	if mask&win32.LVIF_TEXT != 0 {
+		text = cString(info.Item.Text)
+	}
+	image = info.Item.Image
+	state |= info.Item.State & info.Item.StateMask & win32.LVIS_STATEIMAGEMASK
+	return
+}
+
+// setItemState sets the selection and focus state of item i of list view hwnd, or all
+// items if i is -1, and sends LVN_ITEMCHANGED if changed. b.mu must not be held.
+func (b *Backend) setItemState(hwnd win32.HWND, i int, state, mask win32.UINT) bool {
+	b.mu.Lock()
+	w := b.windows[hwnd]
+	if w == nil || i < -1 || i >= w.listView().count {
+		b.mu.Unlock()
+		return false
+	}
+	lv := w.listView()
+	var old win32.UINT
+	if i == -1 {
+		old = gg[win32.UINT](len(lv.selected) > 0, win32.LVIS_SELECTED, 0)
+	} else {
+		old = lv.state(i)
+	}
+	if mask&win32.LVIS_SELECTED != 0 {
+		if i == -1 {
+			if state&win32.LVIS_SELECTED != 0 {
+				for j := range lv.count {
+					lv.selected[j] = true
+				}
+			} else {
+				clear(lv.selected)
+			}
+		} else if state&win32.LVIS_SELECTED != 0 {
+			lv.selected[i] = true
+		} else {
+			delete(lv.selected, i)
+		}
+	}
+	if mask&win32.LVIS_FOCUSED != 0 && i != -1 {
+		if state&win32.LVIS_FOCUSED != 0 {
+			lv.focused = i
+		} else if lv.focused == i {
+			lv.focused = -1
+		}
+	}
+	var new win32.UINT
+	if i == -1 {
+		new = gg[win32.UINT](len(lv.selected) > 0, win32.LVIS_SELECTED, 0)
+	} else {
+		new = lv.state(i)
+	}
+	b.mu.Unlock()
+	if new != old {
+		nm := &win32.NMLISTVIEW{Item: win32.INT(i), NewState: new, OldState: old, Changed: win32.LVIF_STATE}
+		sendNotify(b, hwnd, nm, win32.LVN_ITEMCHANGED)
+	}
+	return true
+}
+
+// headerProc handles the HDM_* messages of SysHeader32 windows. The items of a
+// header are the columns of its parent list view.
+func (b *Backend) headerProc(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) (win32.LRESULT, bool) {
+	switch message {
+	case win32.HDM_GETITEMCOUNT, win32.HDM_GETITEMW, win32.HDM_SETITEMW:
+	default:
+		return 0, false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	w := b.windows[hwnd]
+	if w == nil {
+		return 0, false
+	}
+	parent := b.windows[w.parent]
+	if parent == nil || !parent.isClass("SysListView32") {
+		return gg[win32.LRESULT](message == win32.HDM_GETITEMCOUNT, 0, -1), true
+	}
+	columns := parent.listView().columns
+	if message == win32.HDM_GETITEMCOUNT {
+		return win32.LRESULT(len(columns)), true
+	}
+	if int(wParam) >= len(columns) {
+		return 0, true
+	}
+	c, item := columns[wParam], (*win32.HDITEMW)(pointer(lParam))
+	if message == win32.HDM_GETITEMW {
+		if item.Mask&win32.HDI_FORMAT != 0 {
+			item.Fmt = c.fmt | win32.HDF_STRING
+		}
+		if item.Mask&win32.HDI_WIDTH != 0 {
+			item.Cxy = c.width
+		}
+		if item.Mask&win32.HDI_TEXT != 0 {
+			copyCString(item.Text, int(item.TextMax), c.text)
+		}
+		return 1, true
+	}
+	if item.Mask&win32.HDI_FORMAT != 0 {
+		c.fmt = item.Fmt &^ win32.HDF_STRING
+	}
+	if item.Mask&win32.HDI_WIDTH != 0 {
+		c.width = item.Cxy
+	}
+	if item.Mask&win32.HDI_TEXT != 0 {
+		c.text = cString(item.Text)
+	}
+	return 1, true
+}
+
+// sendNotify fills the header of nm, a notification structure beginning with
+// NMHDR, and sends WM_NOTIFY of code from control hwnd to its parent. b.mu must not be held.
+func sendNotify[T any](b *Backend, hwnd win32.HWND, nm *T, code win32.UINT) win32.LRESULT {
+	b.mu.Lock()
+	w := b.windows[hwnd]
+	if w == nil {
+		b.mu.Unlock()
+		return 0
+	}
+	parent, id := w.parent, w.id
+	b.mu.Unlock()
+	*(*win32.NMHDR)(unsafe.Pointer(nm)) = win32.NMHDR{HwndFrom: hwnd, IdFrom: win32.UINT_PTR(id), Code: code}
+	return withLParam(nm, func(lParam win32.LPARAM) win32.LRESULT {
+		r, _ := b.send(parent, win32.WM_NOTIFY, win32.WPARAM(id), lParam)
+		return r
+	})
+}
+
+// ListViewClick simulates clicking at x, y of the client area of a SysListView32
+// window, or double-clicking if double is true. The item clicked, if any, becomes
+// the only selected item and the focused item, then NM_CLICK is sent. Double-clicking
+// sends NM_DBLCLK and LVN_ITEMACTIVATE in addition. See LVM_HITTEST for the layout.
+func (b *Backend) ListViewClick(hwnd win32.HWND, x, y int, double bool) error {
+	b.mu.Lock()
+	w, err := b.control(hwnd, "SysListView32")
+	if err != nil {
+		b.mu.Unlock()
+		return err
+	}
+	lv := w.listView()
+	item, subItem, _ := lv.hitTest(x, y)
+	deselect := len(lv.selected) > 0 && !(len(lv.selected) == 1 && lv.selected[item])
+	b.mu.Unlock()
+
+	if deselect {
+		b.setItemState(hwnd, -1, 0, win32.LVIS_SELECTED)
+	}
+	if item >= 0 {
+		b.setItemState(hwnd, item, win32.LVIS_SELECTED|win32.LVIS_FOCUSED, win32.LVIS_SELECTED|win32.LVIS_FOCUSED)
+	}
+	activate := func(code win32.UINT) {
+		nm := &win32.NMITEMACTIVATE{
+			Item:    win32.INT(item),
+			SubItem: win32.INT(subItem),
+			Action:  win32.POINT{X: win32.LONG(x), Y: win32.LONG(y)},
+		}
+		sendNotify(b, hwnd, nm, code)
+	}
+	activate(win32.NM_CLICK)
+	if double {
+		activate(win32.NM_DBLCLK)
+		if item >= 0 {
+			activate(win32.LVN_ITEMACTIVATE)
+		}
+	}
+	return nil
+}
+
+// ListViewColumnClick simulates clicking the header of column col of a SysListView32
+// window, which sends LVN_COLUMNCLICK.
+func (b *Backend) ListViewColumnClick(hwnd win32.HWND, col int) error {
+	b.mu.Lock()
+	w, err := b.control(hwnd, "SysListView32")
+	if err != nil {
+		b.mu.Unlock()
+		return err
+	}
+	if col < 0 || col >= len(w.listView().columns) {
+		b.mu.Unlock()
+		return fmt.Errorf("%w: list view column %v", ErrNotFound, col)
+	}
+	b.mu.Unlock()
+	nm := &win32.NMLISTVIEW{Item: -1, SubItem: win32.INT(col)}
+	sendNotify(b, hwnd, nm, win32.LVN_COLUMNCLICK)
+	return nil
+}
+
+// ListViewKeyDown simulates pressing the key vk in a SysListView32 window,
+// which sends LVN_KEYDOWN. The selection is not changed.
+func (b *Backend) ListViewKeyDown(hwnd win32.HWND, vk win32.WORD) error {
+	b.mu.Lock()
+	_, err := b.control(hwnd, "SysListView32")
+	b.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	nm := &win32.NMLVKEYDOWN{VKey: vk}
+	sendNotify(b, hwnd, nm, win32.LVN_KEYDOWN)
+	return nil
+}
@@ -0,0 +1,457 @@
+package fake
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"unicode/utf16"
+
+	"github.com/mkch/gw/win32"
+)
+
+type menu struct {
+	popup bool
+	items []*menuItem
+}
+
+type menuItem struct {
+	typ       win32.UINT
+	state     win32.UINT
+	id        win32.UINT
+	sub       win32.HMENU
+	checked   win32.HBITMAP
+	unchecked win32.HBITMAP
+	data      win32.ULONG_PTR
+	text      []uint16
+	bitmap    win32.HBITMAP
+}
+
+// MenuItem is the state of a menu item.
+type MenuItem struct {
+	ID        win32.UINT
+	Text      string
+	Separator bool
+	Checked   bool
+	Disabled  bool
+	Submenu   win32.HMENU
+}
+
+// MenuItems returns the items of menu.
+func (b *Backend) MenuItems(menu win32.HMENU) ([]MenuItem, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	m, err := b.menu(menu)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]MenuItem, len(m.items))
+	for i, item := range m.items {
+		items[i] = MenuItem{
+			ID:        item.id,
+			Text:      string(utf16.Decode(item.text)),
+			Separator: item.typ&win32.MFT_SEPARATOR != 0,
+			Checked:   item.state&win32.MFS_CHECKED != 0,
+			Disabled:  item.state&win32.MFS_DISABLED != 0,
+			Submenu:   item.sub,
+		}
+	}
+	return items, nil
+}
+
+// menu returns the menu of h. b.mu must be held.
+func (b *Backend) menu(h win32.HMENU) (*menu, error) {
+	if m := b.menus[h]; m != nil {
+		return m, nil
+	}
+	return nil, fmt.Errorf("%w: HMENU %#x", ErrInvalidHandle, h)
+}
+
+// findItem finds the item at position pos of menu h if byPos is true,
+// otherwise the item of command ID pos in h and its submenus.
+// It returns the menu containing the item and the position of the item in it.
+// b.mu must be held.
+func (b *Backend) findItem(h win32.HMENU, pos win32.UINT, byPos bool) (*menu, int, error) {
+	m, err := b.menu(h)
+	if err != nil {
+		return nil, 0, err
+	}
+	if byPos {
+		if int(pos) >= len(m.items) {
+			return nil, 0, fmt.Errorf("%w: menu item at position %v", ErrNotFound, pos)
+		}
+		return m, int(pos), nil
+	}
+	for i, item := range m.items {
+		if item.sub == 0 && item.id == pos {
+			return m, i, nil
+		}
+	}
+	for _, item := range m.items {
+		if item.sub != 0 {
+			if m, i, err := b.findItem(item.sub, pos, false); err == nil {
+				return m, i, nil
+			}
+		}
+	}
+	// Items having submenus are searched last, as Windows does.
+	for i, item := range m.items {
+		if item.sub != 0 && item.id == pos {
+			return m, i, nil
+		}
+	}
+	return nil, 0, fmt.Errorf("%w: menu item of command %v", ErrNotFound, pos)
+}
+
+func (b *Backend) CreateMenu() (win32.HMENU, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	h := win32.HMENU(b.newHandle())
+	b.menus[h] = &menu{}
+	return h, nil
+}
+
+func (b *Backend) CreatePopupMenu() (win32.HMENU, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	h := win32.HMENU(b.newHandle())
+	b.menus[h] = &menu{popup: true}
+	return h, nil
+}
+
+// DestroyMenu destroys menu and its submenus recursively.
+func (b *Backend) DestroyMenu(menu win32.HMENU) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, err := b.menu(menu); err != nil {
+		return err
+	}
+	b.destroyMenu(menu)
+	return nil
+}
+
+// destroyMenu destroys h recursively and removes it from the windows using it as
+// menu bar. b.mu must be held.
+func (b *Backend) destroyMenu(h win32.HMENU) {
+	m := b.menus[h]
+	if m == nil {
+		return
+	}
+	delete(b.menus, h)
+	for _, item := range m.items {
+		b.destroyMenu(item.sub)
+	}
+	for _, w := range b.windows {
+		if w.menu == h {
+			w.menu = 0
+		}
+	}
+}
+
+// DeleteMenu removes a menu item and destroys its submenu, if any.
+func (b *Backend) DeleteMenu(menu win32.HMENU, pos win32.UINT, flags win32.UINT) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	m, i, err := b.findItem(menu, pos, flags&win32.MF_BYPOSITION != 0)
+	if err != nil {
+		return err
+	}
+	b.destroyMenu(m.items[i].sub)
+	m.items = slices.Delete(m.items, i, i+1)
+	return nil
+}
+
+// RemoveMenu removes a menu item without destroying its submenu.
+func (b *Backend) RemoveMenu(menu win32.HMENU, pos win32.UINT, flags win32.UINT) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	m, i, err := b.findItem(menu, pos, flags&win32.MF_BYPOSITION != 0)
+	if err != nil {
+		return err
+	}
+	m.items = slices.Delete(m.items, i, i+1)
+	return nil
+}
+
+// setItemInfo sets the members of item specified by mii.Mask. b.mu must be held.
+func (b *Backend) setItemInfo(item *menuItem, mii *win32.MENUITEMINFOW) error {
+	if mii.Mask&win32.MIIM_SUBMENU != 0 && mii.SubMenu != 0 {
+		if _, err := b.menu(mii.SubMenu); err != nil {
+			return err
+		}
+	}
+	if mii.Mask&win32.MIIM_TYPE != 0 {
+		item.typ = mii.Type
+		if mii.Type&(win32.MFT_BITMAP|win32.MFT_SEPARATOR|win32.MFT_OWNERDRAW) == 0 {
+			item.text = cString(mii.TypeData)
+		}
+	}
+	if mii.Mask&win32.MIIM_FTYPE != 0 {
+		item.typ = mii.Type
+	}
+	if mii.Mask&win32.MIIM_STRING != 0 {
+		item.text = cString(mii.TypeData)
+	}
+	if mii.Mask&win32.MIIM_STATE != 0 {
+		item.state = mii.State
+	}
+	if mii.Mask&win32.MIIM_ID != 0 {
+		item.id = mii.ID
+	}
+	if mii.Mask&win32.MIIM_SUBMENU != 0 {
+		if item.sub != mii.SubMenu {
+			// The old submenu is destroyed when replaced, as Windows does.
+			b.destroyMenu(item.sub)
+		}
+		item.sub = mii.SubMenu
+	}
+	if mii.Mask&win32.MIIM_CHECKMARKS != 0 {
+		item.checked, item.unchecked = mii.CheckedBitmap, mii.UncheckedBitmap
+	}
+	if mii.Mask&win32.MIIM_DATA != 0 {
+		item.data = mii.ItemData
+	}
+	if mii.Mask&win32.MIIM_BITMAP != 0 {
+		item.bitmap = mii.ItemBitmap
+	}
+	return nil
+}
+
+// InsertMenuItemW inserts an item before item. The item is appended if item is out of range.
+func (b *Backend) InsertMenuItemW(menu win32.HMENU, item win32.UINT, byPos bool, mii *win32.MENUITEMINFOW) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	m, i, err := b.findItem(menu, item, byPos)
+	if byPos && errors.Is(err, ErrNotFound) {
+		m, i, err = b.menus[menu], len(b.menus[menu].items), nil
+	}
+	if err != nil {
+		return err
+	}
+	newItem := &menuItem{}
+	if err := b.setItemInfo(newItem, mii); err != nil {
+		return err
+	}
+	m.items = slices.Insert(m.items, i, newItem)
+	return nil
+}
+
+func (b *Backend) GetMenuItemCount(menu win32.HMENU) (win32.INT, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	m, err := b.menu(menu)
+	if err != nil {
+		return 0, err
+	}
+	return win32.INT(len(m.items)), nil
+}
+
+// GetMenuItemInfoW retrieves the members specified by mii.Mask.
+// If the text is requested and mii.TypeData is nil, the length of the text is
+// returned in mii.Cch. Otherwise at most mii.Cch-1 characters are copied to
+// mii.TypeData and mii.Cch is set to the number of characters copied.
+func (b *Backend) GetMenuItemInfoW(menu win32.HMENU, item win32.UINT, byPos bool, mii *win32.MENUITEMINFOW) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	m, i, err := b.findItem(menu, item, byPos)
+	if err != nil {
+		return err
+	}
+	it := m.items[i]
+	text := false
+	if mii.Mask&win32.MIIM_TYPE != 0 {
+		mii.Type = it.typ
+		text = it.typ&(win32.MFT_BITMAP|win32.MFT_SEPARATOR|win32.MFT_OWNERDRAW) == 0
+	}
+	if mii.Mask&win32.MIIM_FTYPE != 0 {
+		mii.Type = it.typ
+	}
+	if mii.Mask&win32.MIIM_STRING != 0 {
+		text = true
+	}
+	if text {
+		if mii.TypeData == nil {
+			mii.Cch = win32.UINT(len(it.text))
+		} else {
+			mii.Cch = win32.UINT(copyCString(mii.TypeData, int(mii.Cch), it.text))
+		}
+	}
+	if mii.Mask&win32.MIIM_STATE != 0 {
+		mii.State = it.state
+	}
+	if mii.Mask&win32.MIIM_ID != 0 {
+		mii.ID = it.id
+	}
+	if mii.Mask&win32.MIIM_SUBMENU != 0 {
+		mii.SubMenu = it.sub
+	}
+	if mii.Mask&win32.MIIM_CHECKMARKS != 0 {
+		mii.CheckedBitmap, mii.UncheckedBitmap = it.checked, it.unchecked
+	}
+	if mii.Mask&win32.MIIM_DATA != 0 {
+		mii.ItemData = it.data
+	}
+	if mii.Mask&win32.MIIM_BITMAP != 0 {
+		mii.ItemBitmap = it.bitmap
+	}
+	return nil
+}
+
+// SetMenuItemInfoW sets the members specified by mii.Mask.
+// The old submenu is destroyed if replaced by MIIM_SUBMENU.
+func (b *Backend) SetMenuItemInfoW(menu win32.HMENU, item win32.UINT, byPos bool, mii *win32.MENUITEMINFOW) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	m, i, err := b.findItem(menu, item, byPos)
+	if err != nil {
+		return err
+	}
+	return b.setItemInfo(m.items[i], mii)
+}
+
+// SetMenu sets the menu bar of hwnd. The old menu is not destroyed.
+func (b *Backend) SetMenu(hwnd win32.HWND, menu win32.HMENU) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	w, err := b.window(hwnd)
+	if err != nil {
+		return err
+	}
+	if menu != 0 {
+		if _, err := b.menu(menu); err != nil {
+			return err
+		}
+	}
+	if w.child() {
+		return fmt.Errorf("%w: menu bar of child window", ErrNotSupported)
+	}
+	w.menu = menu
+	return nil
+}
+
+func (b *Backend) GetMenu(hwnd win32.HWND) (win32.HMENU, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	w, err := b.window(hwnd)
+	if err != nil {
+		return 0, err
+	}
+	return w.menu, nil
+}
+
+// DrawMenuBar does nothing but validates hwnd.
+func (b *Backend) DrawMenuBar(hwnd win32.HWND) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, err := b.window(hwnd)
+	return err
+}
+
+// TrackPopupMenuEx sends WM_INITMENUPOPUP to hwnd and calls Backend.TrackPopupMenu
+// to choose a command. If the command chosen is not an enabled item of menu,
+// the menu is canceled. The command is returned if flags has TPM_RETURNCMD,
+// otherwise WM_COMMAND is posted to hwnd unless flags has TPM_NONOTIFY.
+func (b *Backend) TrackPopupMenuEx(menu win32.HMENU, flags win32.TRACK_POPUP_MENU_FLAG, x win32.INT, y win32.INT, hwnd win32.HWND, params *win32.TPMPARAMS) (int, error) {
+	b.mu.Lock()
+	_, err := b.menu(menu)
+	if err == nil {
+		_, err = b.window(hwnd)
+	}
+	b.mu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+	if flags&win32.TPM_NONOTIFY == 0 {
+		b.send(hwnd, win32.WM_INITMENUPOPUP, win32.WPARAM(menu), 0)
+	}
+	var cmd win32.UINT
+	if b.TrackPopupMenu != nil {
+		cmd = b.TrackPopupMenu(menu, x, y)
+	}
+	if cmd != 0 {
+		b.mu.Lock()
+		m, i, err := b.findItem(menu, cmd, false)
+		if err != nil || m.items[i].state&win32.MFS_DISABLED != 0 || m.items[i].sub != 0 {
+			cmd = 0
+		}
+		b.mu.Unlock()
+	}
+	if flags&win32.TPM_RETURNCMD != 0 {
+		return int(cmd), nil
+	}
+	if cmd != 0 && flags&win32.TPM_NONOTIFY == 0 {
+		b.PostMessageW(hwnd, win32.WM_COMMAND, win32.WPARAM(cmd&0xFFFF), 0)
+	}
+	return 1, nil
+}
+
+func (b *Backend) CreateAcceleratorTableW(accel []win32.ACCEL) (win32.HACCEL, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	h := win32.HACCEL(b.newHandle())
+	b.accels[h] = slices.Clone(accel)
+	return h, nil
+}
+
+func (b *Backend) DestroyAcceleratorTable(table win32.HACCEL) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.accels[table]; !ok {
+		return fmt.Errorf("%w: HACCEL %#x", ErrInvalidHandle, table)
+	}
+	delete(b.accels, table)
+	return nil
+}
+
+// TranslateAcceleratorW matches WM_KEYDOWN and WM_SYSKEYDOWN messages against the
+// FVIRTKEY entries, and WM_CHAR messages against the other entries. The modifier
+// keys must be down as of the last key message retrieved, see KeyState. WM_COMMAND is sent to hwnd
+// for a match, unless the command is a disabled item of the menu bar of hwnd.
+func (b *Backend) TranslateAcceleratorW(hwnd win32.HWND, accTable win32.HACCEL, msg *win32.MSG) (bool, error) {
+	b.mu.Lock()
+	table, ok := b.accels[accTable]
+	if !ok {
+		b.mu.Unlock()
+		return false, fmt.Errorf("%w: HACCEL %#x", ErrInvalidHandle, accTable)
+	}
+	w, err := b.window(hwnd)
+	if err != nil {
+		b.mu.Unlock()
+		return false, err
+	}
+	var virt bool
+	switch msg.Message {
+	case win32.WM_KEYDOWN, win32.WM_SYSKEYDOWN:
+		virt = true
+	case win32.WM_CHAR, win32.WM_SYSCHAR:
+	default:
+		b.mu.Unlock()
+		return false, nil
+	}
+	modifiers := func(a win32.ACCEL_FVIRT) bool {
+		return a&win32.FSHIFT != 0 == b.keys[win32.VK_SHIFT] &&
+			a&win32.FCONTROL != 0 == b.keys[win32.VK_CONTROL] &&
+			a&win32.FALT != 0 == b.keys[win32.VK_MENU]
+	}
+	i := slices.IndexFunc(table, func(a win32.ACCEL) bool {
+		if virt {
+			return a.Virt&win32.FVIRTKEY != 0 && win32.WPARAM(a.Key) == msg.WParam && modifiers(a.Virt)
+		}
+		return a.Virt&win32.FVIRTKEY == 0 && win32.WPARAM(a.Key) == msg.WParam
+	})
+	if i == -1 {
+		b.mu.Unlock()
+		return false, nil
+	}
+	cmd := table[i].Cmd
+	disabled := false
+	if w.menu != 0 {
+		if m, i, err := b.findItem(w.menu, win32.UINT(cmd), false); err == nil {
+			disabled = m.items[i].state&win32.MFS_DISABLED != 0
+		}
+	}
+	b.mu.Unlock()
+	if !disabled {
+		b.send(hwnd, win32.WM_COMMAND, win32.WPARAM(win32.MAKELONG(cmd, 1)), 0)
+	}
+	return true, nil
+}
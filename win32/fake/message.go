@@ -0,0 +1,403 @@
+package fake
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/mkch/gw/win32"
+)
+
+// thread is the message queue of a goroutine.
+type thread struct {
+	id       win32.DWORD
+	posted   []win32.MSG
+	sent     []*sentMessage
+	quit     bool
+	quitCode int
+	active   win32.HWND
+	wake     chan struct{}
+}
+
+// sentMessage is a message sent from another thread.
+type sentMessage struct {
+	msg    win32.MSG
+	result win32.LRESULT
+	done   chan struct{}
+}
+
+type hook struct {
+	h      win32.HHOOK
+	id     win32.HookID
+	proc   uintptr
+	thread win32.DWORD
+}
+
+// thread returns the message queue of the calling goroutine, creating it if needed.
+// b.mu must be held.
+func (b *Backend) thread() *thread {
+	id := currentThreadID()
+	t := b.threads[id]
+	if t == nil {
+		t = &thread{id: id, wake: make(chan struct{}, 1)}
+		b.threads[id] = t
+	}
+	return t
+}
+
+// signal wakes up the goroutine waiting for messages of t, if any.
+func (t *thread) signal() {
+	select {
+	case t.wake <- struct{}{}:
+	default:
+	}
+}
+
+// post puts a message into the queue of t. b.mu must be held.
+func (b *Backend) post(t *thread, hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) {
+	t.posted = append(t.posted, b.newMsg(hwnd, message, wParam, lParam))
+	t.signal()
+}
+
+// newMsg creates a MSG stamped with the current time and cursor position. b.mu must be held.
+func (b *Backend) newMsg(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) win32.MSG {
+	return win32.MSG{
+		Hwnd:    hwnd,
+		Message: message,
+		WParam:  wParam,
+		LParam:  lParam,
+		Time:    win32.DWORD(b.now.Milliseconds()),
+		Point:   win32.Point{X: b.cursorPos.X, Y: b.cursorPos.Y},
+	}
+}
+
+// deliver calls the window procedure of hwnd in the calling goroutine.
+func (b *Backend) deliver(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) win32.LRESULT {
+	b.mu.Lock()
+	w := b.windows[hwnd]
+	if w == nil {
+		b.mu.Unlock()
+		return 0
+	}
+	proc := w.proc
+	b.delivered = append(b.delivered, b.newMsg(hwnd, message, wParam, lParam))
+	b.mu.Unlock()
+	return win32.LRESULT(call(proc, uintptr(hwnd), uintptr(message), uintptr(wParam), uintptr(lParam)))
+}
+
+// send sends a message to the window procedure of hwnd and waits for the result.
+// Messages sent to the calling goroutine are delivered while waiting.
+func (b *Backend) send(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) (win32.LRESULT, error) {
+	b.mu.Lock()
+	w := b.windows[hwnd]
+	if w == nil {
+		b.mu.Unlock()
+		return 0, fmt.Errorf("%w: HWND %#x", ErrInvalidHandle, hwnd)
+	}
+	self := b.thread()
+	if w.thread == self.id {
+		b.mu.Unlock()
+		return b.deliver(hwnd, message, wParam, lParam), nil
+	}
+	sm := &sentMessage{msg: b.newMsg(hwnd, message, wParam, lParam), done: make(chan struct{})}
+	t := b.threads[w.thread]
+	t.sent = append(t.sent, sm)
+	t.signal()
+	b.mu.Unlock()
+	for {
+		select {
+		case <-sm.done:
+			return sm.result, nil
+		case <-self.wake:
+			b.receiveSent(self)
+		}
+	}
+}
+
+// receiveSent delivers the messages sent to t from other goroutines.
+func (b *Backend) receiveSent(t *thread) {
+	for {
+		b.mu.Lock()
+		if len(t.sent) == 0 {
+			b.mu.Unlock()
+			return
+		}
+		sm := t.sent[0]
+		t.sent = t.sent[1:]
+		b.mu.Unlock()
+		sm.result = b.deliver(sm.msg.Hwnd, sm.msg.Message, sm.msg.WParam, sm.msg.LParam)
+		close(sm.done)
+	}
+}
+
+func (b *Backend) SendMessageW(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) (win32.LRESULT, error) {
+	return b.send(hwnd, message, wParam, lParam)
+}
+
+// PostMessageW puts a message into the queue of the goroutine owning hwnd.
+// If hwnd is 0, the message is posted to the calling goroutine.
+func (b *Backend) PostMessageW(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if hwnd == 0 {
+		b.post(b.thread(), 0, message, wParam, lParam)
+		return nil
+	}
+	w := b.windows[hwnd]
+	if w == nil {
+		return fmt.Errorf("%w: HWND %#x", ErrInvalidHandle, hwnd)
+	}
+	b.post(b.threads[w.thread], hwnd, message, wParam, lParam)
+	return nil
+}
+
+// PostThreadMessageW puts a message into the queue of a goroutine.
+// The goroutine must have called any function of the backend to create its queue.
+func (b *Backend) PostThreadMessageW(threadId win32.DWORD, msg win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t := b.threads[threadId]
+	if t == nil {
+		return fmt.Errorf("%w: thread %v", ErrInvalidHandle, threadId)
+	}
+	b.post(t, 0, msg, wParam, lParam)
+	return nil
+}
+
+func (b *Backend) PostQuitMessage(code int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t := b.thread()
+	t.quit, t.quitCode = true, code
+	t.signal()
+}
+
+// match reports whether msg passes the filter of GetMessageW and PeekMessageW.
+func match(msg *win32.MSG, hwnd win32.HWND, min, max win32.UINT) bool {
+	if hwnd == ^win32.HWND(0) {
+		if msg.Hwnd != 0 {
+			return false
+		}
+	} else if hwnd != 0 && msg.Hwnd != hwnd {
+		return false
+	}
+	return min == 0 && max == 0 || msg.Message >= min && msg.Message <= max
+}
+
+// next retrieves the next message of the calling goroutine in the order of
+// sent messages(delivered directly), posted messages, WM_QUIT, WM_PAINT, WM_TIMER and WM_MOUSEHOVER.
+func (b *Backend) next(hwnd win32.HWND, min, max win32.UINT, remove bool) (win32.MSG, bool) {
+	b.mu.Lock()
+	t := b.thread()
+	b.mu.Unlock()
+	b.receiveSent(t)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i := range t.posted {
+		if msg := t.posted[i]; match(&msg, hwnd, min, max) {
+			if remove {
+				t.posted = slices.Delete(t.posted, i, i+1)
+				b.updateKeys(&msg)
+			}
+			return msg, true
+		}
+	}
+	if t.quit {
+		if remove {
+			t.quit = false
+		}
+		return b.newMsg(0, win32.WM_QUIT, win32.WPARAM(t.quitCode), 0), true
+	}
+	for _, h := range b.sortedWindows() {
+		w := b.windows[h]
+		if msg := b.newMsg(h, win32.WM_PAINT, 0, 0); w.thread == t.id && w.invalid && match(&msg, hwnd, min, max) {
+			return msg, true
+		}
+	}
+	for _, tm := range b.timers {
+		if msg := b.newMsg(tm.hwnd, win32.WM_TIMER, win32.WPARAM(tm.id), win32.LPARAM(tm.proc)); tm.thread == t.id && tm.due <= b.now && match(&msg, hwnd, min, max) {
+			if remove {
+				tm.due = b.now + tm.interval
+			}
+			return msg, true
+		}
+	}
+	return b.nextHover(t, hwnd, min, max, remove)
+}
+
+// updateKeys updates the key state with a key message retrieved. b.mu must be held.
+func (b *Backend) updateKeys(msg *win32.MSG) {
+	switch msg.Message {
+	case win32.WM_KEYDOWN, win32.WM_SYSKEYDOWN:
+		b.keys[msg.WParam&0xFF] = true
+	case win32.WM_KEYUP, win32.WM_SYSKEYUP:
+		b.keys[msg.WParam&0xFF] = false
+	}
+}
+
+// wait waits until a message is posted to the calling goroutine, a message
+// is sent to it, or the clock is advanced.
+func (b *Backend) wait() {
+	b.mu.Lock()
+	t := b.thread()
+	b.mu.Unlock()
+	<-t.wake
+}
+
+// callGetMessageHooks calls the WH_GETMESSAGE hooks of the calling goroutine.
+func (b *Backend) callGetMessageHooks(flags win32.PeekMessageFlag, msg *win32.MSG) {
+	b.mu.Lock()
+	id := currentThreadID()
+	i := slices.IndexFunc(b.hooks, func(h *hook) bool { return h.thread == id && h.id == win32.WH_GETMESSAGE })
+	if i == -1 {
+		b.mu.Unlock()
+		return
+	}
+	proc := b.hooks[i].proc
+	b.mu.Unlock()
+	withLParam(msg, func(lParam win32.LPARAM) uintptr {
+		return call(proc, uintptr(win32.HC_ACTION), uintptr(flags), uintptr(lParam))
+	})
+}
+
+// GetMessageW retrieves a message of the calling goroutine, waiting until
+// one is available.
+func (b *Backend) GetMessageW(msg *win32.MSG, hwnd win32.HWND, msgFilterMin win32.UINT, msgFilterMax win32.UINT) win32.BOOL {
+	if hwnd != 0 && hwnd != ^win32.HWND(0) && !b.isWindow(hwnd) {
+		return -1
+	}
+	for {
+		if m, ok := b.next(hwnd, msgFilterMin, msgFilterMax, true); ok {
+			*msg = m
+			b.callGetMessageHooks(win32.PM_REMOVE, msg)
+			if m.Message == win32.WM_QUIT {
+				return 0
+			}
+			return 1
+		}
+		b.wait()
+	}
+}
+
+func (b *Backend) PeekMessageW(msg *win32.MSG, hwnd win32.HWND, msgFilterMin win32.UINT, msgFilterMax win32.UINT, flags win32.PeekMessageFlag) win32.BOOL {
+	m, ok := b.next(hwnd, msgFilterMin, msgFilterMax, flags&win32.PM_REMOVE != 0)
+	if !ok {
+		return 0
+	}
+	*msg = m
+	b.callGetMessageHooks(flags&win32.PM_REMOVE, msg)
+	return 1
+}
+
+// TranslateMessage posts WM_CHAR or WM_SYSCHAR for the WM_KEYDOWN or WM_SYSKEYDOWN
+// of letters, digits, space, enter, backspace, tab and escape.
+// Letters are in lower case unless VK_SHIFT is down, and are control
+// characters if VK_CONTROL is down.
+func (b *Backend) TranslateMessage(msg *win32.MSG) bool {
+	if msg.Message != win32.WM_KEYDOWN && msg.Message != win32.WM_SYSKEYDOWN {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var ch win32.WPARAM
+	switch vk := msg.WParam; {
+	case vk >= 'A' && vk <= 'Z':
+		switch {
+		case b.keys[win32.VK_CONTROL]:
+			ch = vk - 'A' + 1
+		case b.keys[win32.VK_SHIFT]:
+			ch = vk
+		default:
+			ch = vk - 'A' + 'a'
+		}
+	case vk >= '0' && vk <= '9', vk == ' ', vk == '\r', vk == '\b', vk == '\t', vk == 0x1B:
+		ch = vk
+	default:
+		return false
+	}
+	w := b.windows[msg.Hwnd]
+	if w == nil {
+		return false
+	}
+	b.post(b.threads[w.thread], msg.Hwnd, msg.Message+(win32.WM_CHAR-win32.WM_KEYDOWN), ch, msg.LParam)
+	return true
+}
+
+// DispatchMessageW delivers a message to the window procedure, or calls
+// the timer procedure of WM_TIMER if any.
+func (b *Backend) DispatchMessageW(msg *win32.MSG) win32.LRESULT {
+	if msg.Message == win32.WM_TIMER && msg.LParam != 0 {
+		return win32.LRESULT(call(uintptr(msg.LParam), uintptr(msg.Hwnd), uintptr(msg.Message), uintptr(msg.WParam), uintptr(msg.Time)))
+	}
+	if msg.Hwnd == 0 {
+		return 0
+	}
+	b.mu.Lock()
+	w := b.windows[msg.Hwnd]
+	ok := w != nil && w.thread == currentThreadID()
+	b.mu.Unlock()
+	if !ok {
+		return 0
+	}
+	return b.deliver(msg.Hwnd, msg.Message, msg.WParam, msg.LParam)
+}
+
+// SetWindowsHookExW installs a hook procedure of a goroutine.
+// Only WH_GETMESSAGE hooks are called, the hooks of other types are never called.
+func (b *Backend) SetWindowsHookExW(idHook win32.HookID, lpfn uintptr, hMod win32.HINSTANCE, dwThreadId win32.DWORD) (win32.HHOOK, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if dwThreadId == 0 {
+		return 0, fmt.Errorf("%w: global hook", ErrNotSupported)
+	}
+	if b.threads[dwThreadId] == nil {
+		return 0, fmt.Errorf("%w: thread %v", ErrInvalidHandle, dwThreadId)
+	}
+	h := &hook{h: win32.HHOOK(b.newHandle()), id: idHook, proc: lpfn, thread: dwThreadId}
+	b.hooks = slices.Insert(b.hooks, 0, h)
+	return h.h, nil
+}
+
+func (b *Backend) UnhookWindowsHookEx(hhk win32.HHOOK) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	i := slices.IndexFunc(b.hooks, func(h *hook) bool { return h.h == hhk })
+	if i == -1 {
+		return fmt.Errorf("%w: HHOOK %#x", ErrInvalidHandle, hhk)
+	}
+	b.hooks = slices.Delete(b.hooks, i, i+1)
+	return nil
+}
+
+func (b *Backend) CallNextHookEx(hhk win32.HHOOK, nCode win32.HookCode, wParam win32.WPARAM, lParam win32.LPARAM) win32.LRESULT {
+	b.mu.Lock()
+	i := slices.IndexFunc(b.hooks, func(h *hook) bool { return h.h == hhk })
+	if i == -1 {
+		b.mu.Unlock()
+		return 0
+	}
+	cur := b.hooks[i]
+	j := slices.IndexFunc(b.hooks[i+1:], func(h *hook) bool { return h.thread == cur.thread && h.id == cur.id })
+	if j == -1 {
+		b.mu.Unlock()
+		return 0
+	}
+	proc := b.hooks[i+1+j].proc
+	b.mu.Unlock()
+	return win32.LRESULT(call(proc, uintptr(nCode), uintptr(wParam), uintptr(lParam)))
+}
+
+// Pump retrieves and dispatches the messages of the calling goroutine until
+// the queue is empty, and returns the number of messages dispatched.
+// WM_QUIT is left in the queue.
+func (b *Backend) Pump() int {
+	var n int
+	var msg win32.MSG
+	for b.PeekMessageW(&msg, 0, 0, 0, win32.PM_NOREMOVE) != 0 && msg.Message != win32.WM_QUIT {
+		b.PeekMessageW(&msg, 0, 0, 0, win32.PM_REMOVE)
+		b.TranslateMessage(&msg)
+		b.DispatchMessageW(&msg)
+		n++
+	}
+	return n
+}
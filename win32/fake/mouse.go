@@ -0,0 +1,257 @@
+package fake
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mkch/gw/win32"
+)
+
+// HoverTime is the hover time of TrackMouseEvent with HOVER_DEFAULT.
+const HoverTime = 400 * time.Millisecond
+
+// DoubleClickTime is the maximum time between the clicks of a double-click.
+const DoubleClickTime = 500 * time.Millisecond
+
+type mouseTrack struct {
+	flags     win32.TRACKMOUSEEVENT_FLAG
+	hoverTime time.Duration
+	hoverDue  time.Duration
+}
+
+type click struct {
+	hwnd   win32.HWND
+	button win32.WPARAM
+	pt     win32.POINT
+	time   time.Duration
+}
+
+// buttonMessages are the WM_*BUTTONDOWN messages of the MK_* buttons.
+var buttonMessages = map[win32.WPARAM]win32.UINT{
+	win32.MK_LBUTTON:  win32.WM_LBUTTONDOWN,
+	win32.MK_RBUTTON:  win32.WM_RBUTTONDOWN,
+	win32.MK_MBUTTON:  win32.WM_MBUTTONDOWN,
+	win32.MK_XBUTTON1: win32.WM_XBUTTONDOWN,
+	win32.MK_XBUTTON2: win32.WM_XBUTTONDOWN,
+}
+
+// SetCapture captures the mouse to hwnd and returns the window which had
+// captured the mouse, if any.
+func (b *Backend) SetCapture(hwnd win32.HWND) win32.HWND {
+	b.mu.Lock()
+	prev := b.capture
+	b.capture = hwnd
+	b.mu.Unlock()
+	if prev != 0 && prev != hwnd {
+		b.send(prev, win32.WM_CAPTURECHANGED, 0, win32.LPARAM(hwnd))
+	}
+	return prev
+}
+
+// ReleaseCapture releases the mouse capture and sends WM_CAPTURECHANGED
+// to the window which had captured the mouse.
+func (b *Backend) ReleaseCapture() error {
+	b.mu.Lock()
+	prev := b.capture
+	b.capture = 0
+	b.mu.Unlock()
+	if prev != 0 {
+		b.send(prev, win32.WM_CAPTURECHANGED, 0, 0)
+	}
+	return nil
+}
+
+func (b *Backend) GetCapture() win32.HWND {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.capture
+}
+
+// TrackMouseEvent requests WM_MOUSEHOVER and WM_MOUSELEAVE of the client area.
+// The hover time elapses on the virtual clock. TME_NONCLIENT and TME_QUERY are
+// not supported.
+func (b *Backend) TrackMouseEvent(tme *win32.TRACKMOUSEEVENT) error {
+	if tme.Flags&(win32.TME_NONCLIENT|win32.TME_QUERY) != 0 {
+		return fmt.Errorf("%w: TrackMouseEvent flags %#x", ErrNotSupported, tme.Flags)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, err := b.window(tme.HwndTrack); err != nil {
+		return err
+	}
+	flags := tme.Flags &^ win32.TME_CANCEL
+	t := b.tracks[tme.HwndTrack]
+	if tme.Flags&win32.TME_CANCEL != 0 {
+		if t != nil {
+			if t.flags &^= flags; t.flags == 0 {
+				delete(b.tracks, tme.HwndTrack)
+			}
+		}
+		return nil
+	}
+	if tme.HwndTrack != b.mouseWnd {
+		// The mouse is not over the window.
+		if flags&win32.TME_LEAVE != 0 {
+			b.post(b.threads[b.windows[tme.HwndTrack].thread], tme.HwndTrack, win32.WM_MOUSELEAVE, 0, 0)
+		}
+		return nil
+	}
+	if t == nil {
+		t = &mouseTrack{}
+		b.tracks[tme.HwndTrack] = t
+	}
+	t.flags |= flags
+	if flags&win32.TME_HOVER != 0 {
+		t.hoverTime = HoverTime
+		if tme.HoverTime != win32.HOVER_DEFAULT {
+			t.hoverTime = time.Duration(tme.HoverTime) * time.Millisecond
+		}
+		t.hoverDue = b.now + t.hoverTime
+	}
+	return nil
+}
+
+// MouseMove moves the cursor to client coordinates x, y of hwnd and posts
+// WM_MOUSEMOVE to hwnd, or to the window which captured the mouse.
+// WM_MOUSELEAVE is posted to the window the cursor left if requested by TrackMouseEvent.
+func (b *Backend) MouseMove(hwnd win32.HWND, x, y win32.LONG) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	target, lParam, err := b.moveCursor(hwnd, x, y)
+	if err != nil {
+		return err
+	}
+	if b.mouseWnd != hwnd {
+		if t := b.tracks[b.mouseWnd]; t != nil && t.flags&win32.TME_LEAVE != 0 {
+			b.post(b.threads[b.windows[b.mouseWnd].thread], b.mouseWnd, win32.WM_MOUSELEAVE, 0, 0)
+		}
+		delete(b.tracks, b.mouseWnd)
+		b.mouseWnd = hwnd
+	} else if t := b.tracks[hwnd]; t != nil {
+		t.hoverDue = b.now + t.hoverTime
+	}
+	b.post(b.threads[target.thread], target.hwnd, win32.WM_MOUSEMOVE, b.buttons|b.modifierKeys(), lParam)
+	return nil
+}
+
+// MouseDown moves the cursor to client coordinates x, y of hwnd and posts the
+// WM_*BUTTONDOWN message of button, one of MK_LBUTTON, MK_RBUTTON, MK_MBUTTON, MK_XBUTTON1
+// and MK_XBUTTON2. WM_*BUTTONDBLCLK is posted instead if the class of the window has
+// CS_DBLCLKS, and the previous button down is at the same position within DoubleClickTime
+// on the virtual clock.
+func (b *Backend) MouseDown(hwnd win32.HWND, button win32.WPARAM, x, y win32.LONG) error {
+	return b.mouseButton(hwnd, button, x, y, true)
+}
+
+// MouseUp moves the cursor to client coordinates x, y of hwnd and posts the
+// WM_*BUTTONUP message of button. See MouseDown.
+func (b *Backend) MouseUp(hwnd win32.HWND, button win32.WPARAM, x, y win32.LONG) error {
+	return b.mouseButton(hwnd, button, x, y, false)
+}
+
+func (b *Backend) mouseButton(hwnd win32.HWND, button win32.WPARAM, x, y win32.LONG, down bool) error {
+	message, ok := buttonMessages[button]
+	if !ok {
+		return fmt.Errorf("%w: button %#x", ErrNotSupported, button)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	target, lParam, err := b.moveCursor(hwnd, x, y)
+	if err != nil {
+		return err
+	}
+	if down {
+		b.buttons |= button
+		last := b.lastClick
+		b.lastClick = click{target.hwnd, button, b.cursorPos, b.now}
+		if target.class.style&win32.CS_DBLCLKS != 0 && last.hwnd == target.hwnd && last.button == button &&
+			last.pt == b.cursorPos && b.now-last.time <= DoubleClickTime {
+			message += win32.WM_LBUTTONDBLCLK - win32.WM_LBUTTONDOWN
+			b.lastClick = click{}
+		}
+	} else {
+		b.buttons &^= button
+		message += win32.WM_LBUTTONUP - win32.WM_LBUTTONDOWN
+	}
+	wParam := b.buttons | b.modifierKeys()
+	if message >= win32.WM_XBUTTONDOWN && message <= win32.WM_XBUTTONDBLCLK {
+		var x win32.WORD = win32.XBUTTON1
+		if button == win32.MK_XBUTTON2 {
+			x = win32.XBUTTON2
+		}
+		wParam = win32.WPARAM(win32.MAKELONG(win32.WORD(wParam), x))
+	}
+	b.post(b.threads[target.thread], target.hwnd, message, wParam, lParam)
+	return nil
+}
+
+// MouseWheel posts WM_MOUSEWHEEL, or WM_MOUSEHWHEEL if horizontal, of delta
+// to the window under the cursor, see MouseMove.
+func (b *Backend) MouseWheel(delta int, horizontal bool) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	w, err := b.window(b.mouseWnd)
+	if err != nil {
+		return err
+	}
+	message := win32.UINT(win32.WM_MOUSEWHEEL)
+	if horizontal {
+		message = win32.WM_MOUSEHWHEEL
+	}
+	wParam := win32.WPARAM(win32.MAKELONG(win32.WORD(b.buttons|b.modifierKeys()), win32.WORD(int16(delta))))
+	lParam := makeLParam(win32.INT(b.cursorPos.X), win32.INT(b.cursorPos.Y))
+	b.post(b.threads[w.thread], w.hwnd, message, wParam, lParam)
+	return nil
+}
+
+// moveCursor moves the cursor to client coordinates x, y of hwnd, and returns the
+// window receiving the mouse messages and the lParam of client coordinates of it.
+// b.mu must be held.
+func (b *Backend) moveCursor(hwnd win32.HWND, x, y win32.LONG) (target *window, lParam win32.LPARAM, err error) {
+	w, err := b.window(hwnd)
+	if err != nil {
+		return
+	}
+	o := b.origin(w)
+	b.cursorPos = win32.POINT{X: o.X + x, Y: o.Y + y}
+	target = w
+	if c := b.windows[b.capture]; c != nil {
+		target = c
+		co := b.origin(c)
+		x, y = b.cursorPos.X-co.X, b.cursorPos.Y-co.Y
+	}
+	lParam = makeLParam(win32.INT(x), win32.INT(y))
+	return
+}
+
+// modifierKeys returns the MK_SHIFT and MK_CONTROL flags of the async key state.
+// b.mu must be held.
+func (b *Backend) modifierKeys() (mk win32.WPARAM) {
+	if b.asyncKeys[win32.VK_SHIFT] {
+		mk |= win32.MK_SHIFT
+	}
+	if b.asyncKeys[win32.VK_CONTROL] {
+		mk |= win32.MK_CONTROL
+	}
+	return
+}
+
+// nextHover returns WM_MOUSEHOVER of the calling thread due, if any. b.mu must be held.
+func (b *Backend) nextHover(t *thread, hwnd win32.HWND, min, max win32.UINT, remove bool) (win32.MSG, bool) {
+	w := b.windows[b.mouseWnd]
+	tr := b.tracks[b.mouseWnd]
+	if w == nil || tr == nil || w.thread != t.id || tr.flags&win32.TME_HOVER == 0 || tr.hoverDue > b.now {
+		return win32.MSG{}, false
+	}
+	o := b.origin(w)
+	msg := b.newMsg(w.hwnd, win32.WM_MOUSEHOVER, b.buttons|b.modifierKeys(),
+		makeLParam(win32.INT(b.cursorPos.X-o.X), win32.INT(b.cursorPos.Y-o.Y)))
+	if !match(&msg, hwnd, min, max) {
+		return win32.MSG{}, false
+	}
+	if remove {
+		// Hover tracking ends when WM_MOUSEHOVER is generated.
+		tr.flags &^= win32.TME_HOVER
+	}
+	return msg, true
+}
@@ -0,0 +1,164 @@
+package fake
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/mkch/gw/win32"
+)
+
+type pointerInput struct {
+	info  win32.POINTER_INFO
+	pen   *win32.POINTER_PEN_INFO
+	touch *win32.POINTER_TOUCH_INFO
+}
+
+// Pointer posts message, a WM_POINTER* message, of the pointer input to info.HwndTarget.
+// The information of the pointer, returned by GetPointerInfo etc., is the PointerInfo of pen or touch if
+// not nil, or info otherwise. The pointer message is not promoted to mouse messages.
+func (b *Backend) Pointer(message win32.UINT, info *win32.POINTER_INFO, pen *win32.POINTER_PEN_INFO, touch *win32.POINTER_TOUCH_INFO) error {
+	input := &pointerInput{pen: pen, touch: touch}
+	switch {
+	case pen != nil:
+		input.info = pen.PointerInfo
+	case touch != nil:
+		input.info = touch.PointerInfo
+	default:
+		input.info = *info
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	w, err := b.window(input.info.HwndTarget)
+	if err != nil {
+		return err
+	}
+	b.pointers[input.info.PointerId] = input
+	wParam := win32.WPARAM(win32.MAKELONG(win32.WORD(input.info.PointerId), win32.WORD(input.info.PointerFlags)))
+	lParam := makeLParam(win32.INT(input.info.PtPixelLocation.X), win32.INT(input.info.PtPixelLocation.Y))
+	b.post(b.threads[w.thread], w.hwnd, message, wParam, lParam)
+	return nil
+}
+
+// pointer returns the pointer input of id. b.mu must be held.
+func (b *Backend) pointer(id win32.UINT32) (*pointerInput, error) {
+	if p := b.pointers[id]; p != nil {
+		return p, nil
+	}
+	return nil, fmt.Errorf("%w: pointer %v", ErrNotFound, id)
+}
+
+func (b *Backend) GetPointerType(pointerId win32.UINT32) (win32.POINTER_INPUT_TYPE, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p, err := b.pointer(pointerId)
+	if err != nil {
+		return 0, err
+	}
+	return p.info.PointerType, nil
+}
+
+func (b *Backend) GetPointerInfo(pointerId win32.UINT32, info *win32.POINTER_INFO) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p, err := b.pointer(pointerId)
+	if err != nil {
+		return err
+	}
+	*info = p.info
+	return nil
+}
+
+func (b *Backend) GetPointerPenInfo(pointerId win32.UINT32, info *win32.POINTER_PEN_INFO) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p, err := b.pointer(pointerId)
+	if err != nil {
+		return err
+	}
+	if p.pen == nil {
+		return fmt.Errorf("%w: pointer %v is not a pen", ErrNotFound, pointerId)
+	}
+	*info = *p.pen
+	return nil
+}
+
+func (b *Backend) GetPointerTouchInfo(pointerId win32.UINT32, info *win32.POINTER_TOUCH_INFO) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p, err := b.pointer(pointerId)
+	if err != nil {
+		return err
+	}
+	if p.touch == nil {
+		return fmt.Errorf("%w: pointer %v is not a touch", ErrNotFound, pointerId)
+	}
+	*info = *p.touch
+	return nil
+}
+
+// Gesture posts WM_GESTURE of info to hwnd. The gesture information handle is
+// closed by CloseGestureInfoHandle, or by DefWindowProcW. See GestureInfoHandles.
+func (b *Backend) Gesture(hwnd win32.HWND, info *win32.GESTUREINFO) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	w, err := b.window(hwnd)
+	if err != nil {
+		return err
+	}
+	h := win32.HGESTUREINFO(b.newHandle())
+	gi := *info
+	gi.HwndTarget = hwnd
+	b.gestures[h] = gi
+	b.post(b.threads[w.thread], hwnd, win32.WM_GESTURE, win32.WPARAM(gi.ID), win32.LPARAM(h))
+	return nil
+}
+
+// GestureInfoHandles returns the number of the gesture information handles not closed.
+func (b *Backend) GestureInfoHandles() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.gestures)
+}
+
+func (b *Backend) GetGestureInfo(h win32.HGESTUREINFO, info *win32.GESTUREINFO) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	gi, ok := b.gestures[h]
+	if !ok {
+		return fmt.Errorf("%w: HGESTUREINFO %#x", ErrInvalidHandle, h)
+	}
+	*info = gi
+	return nil
+}
+
+func (b *Backend) CloseGestureInfoHandle(h win32.HGESTUREINFO) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.gestures[h]; !ok {
+		return fmt.Errorf("%w: HGESTUREINFO %#x", ErrInvalidHandle, h)
+	}
+	delete(b.gestures, h)
+	return nil
+}
+
+// SetGestureConfig records the configs, see GestureConfig.
+func (b *Backend) SetGestureConfig(hwnd win32.HWND, configs []win32.GESTURECONFIG) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	w, err := b.window(hwnd)
+	if err != nil {
+		return err
+	}
+	w.gestureConfig = slices.Clone(configs)
+	return nil
+}
+
+// GestureConfig returns the configs set by SetGestureConfig.
+func (b *Backend) GestureConfig(hwnd win32.HWND) []win32.GESTURECONFIG {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if w := b.windows[hwnd]; w != nil {
+		return slices.Clone(w.gestureConfig)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package fake
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mkch/gw/win32"
+)
+
+type timer struct {
+	hwnd      win32.HWND
+	id        win32.UINT_PTR
+	proc      uintptr
+	thread    win32.DWORD
+	due       time.Duration
+	interval  time.Duration
+	tolerance win32.ULONG
+}
+
+// Now returns the time of the virtual clock, which starts from 0.
+func (b *Backend) Now() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.now
+}
+
+// Advance advances the virtual clock by d. WM_TIMER of the timers due is retrieved
+// from the message queues afterwards. A timer fires once even if several intervals
+// are elapsed, as Windows does.
+func (b *Backend) Advance(d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.now += d
+	for _, t := range b.threads {
+		t.signal()
+	}
+}
+
+// Timers returns the number of active timers.
+func (b *Backend) Timers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.timers)
+}
+
+// SetTimer creates or replaces a timer. If hwnd is 0, a new timer ID is returned
+// unless idEvent is an existing timer of the calling goroutine.
+func (b *Backend) SetTimer(hwnd win32.HWND, idEvent win32.UINT_PTR, elapse win32.UINT, timerFunc uintptr) (win32.UINT_PTR, error) {
+	return b.SetCoalescableTimer(hwnd, idEvent, elapse, timerFunc, win32.TIMERV_DEFAULT_COALESCING)
+}
+
+// SetCoalescableTimer is SetTimer with a coalescing tolerance, which is recorded
+// but has no effect on the virtual clock. See TimerTolerance.
+func (b *Backend) SetCoalescableTimer(hwnd win32.HWND, idEvent win32.UINT_PTR, elapse win32.UINT, timerFunc uintptr, tolerance win32.ULONG) (win32.UINT_PTR, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	tid := b.thread().id
+	if hwnd != 0 {
+		w, err := b.window(hwnd)
+		if err != nil {
+			return 0, err
+		}
+		if w.thread != tid {
+			return 0, fmt.Errorf("%w: HWND %#x", ErrWrongThread, hwnd)
+		}
+	}
+	interval := time.Duration(min(max(elapse, win32.USER_TIMER_MINIMUM), win32.USER_TIMER_MAXIMUM)) * time.Millisecond
+	for _, tm := range b.timers {
+		if tm.hwnd == hwnd && tm.id == idEvent && tm.thread == tid {
+			tm.proc, tm.due, tm.interval, tm.tolerance = timerFunc, b.now+interval, interval, tolerance
+			return tm.id, nil
+		}
+	}
+	tm := &timer{hwnd: hwnd, id: idEvent, proc: timerFunc, thread: tid, due: b.now + interval, interval: interval, tolerance: tolerance}
+	if hwnd == 0 {
+		b.lastTimer++
+		tm.id = b.lastTimer
+	}
+	b.timers = append(b.timers, tm)
+	if tm.id == 0 {
+		return 1, nil
+	}
+	return tm.id, nil
+}
+
+// KillTimer destroys a timer. The WM_TIMER already retrieved is not affected.
+func (b *Backend) KillTimer(hwnd win32.HWND, idEvent win32.UINT_PTR) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, tm := range b.timers {
+		if tm.hwnd == hwnd && tm.id == idEvent {
+			b.timers = append(b.timers[:i], b.timers[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: timer %v of HWND %#x", ErrNotFound, idEvent, hwnd)
+}
+
+// TimerTolerance returns the coalescing tolerance of a timer.
+func (b *Backend) TimerTolerance(hwnd win32.HWND, idEvent win32.UINT_PTR) (tolerance win32.ULONG, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, tm := range b.timers {
+		if tm.hwnd == hwnd && tm.id == idEvent {
+			return tm.tolerance, true
+		}
+	}
+	return 0, false
+}
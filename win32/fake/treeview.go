@@ -0,0 +1,812 @@
+package fake
+
+import (
+	"fmt"
+	"slices"
+	"unicode/utf16"
+	"unsafe"
+
+	"github.com/mkch/gw/win32"
+)
+
+// treeIndent is the indent of the items of tree views.
+const treeIndent = 19
+
+// treeView is the state of a SysTreeView32 window.
+//
+// The visible items are laid out in rows of defaultItemHeight pixels from the top.
+// An item at level l, 0 for the top-level items, is indented by l*treeIndent pixels,
+// followed by the button treeIndent pixels wide, the state icon 16 pixels wide if
+// the tree view is TVS_CHECKBOXES, the icon 16 pixels wide if the normal image list
+// is set, then the label to the right edge of the window.
+type treeView struct {
+	items       map[win32.HTREEITEM]*treeItem
+	root        treeItem // The parent of the top-level items.
+	selected    *treeItem
+	dropHilite  *treeItem
+	insertMark  *treeItem
+	insertAfter bool
+	imageLists  [3]win32.HIMAGELIST // Indexed by TVSIL_*.
+	editing     *treeItem
+	edit        win32.HWND // The EDIT window of label editing.
+}
+
+type treeItem struct {
+	h                    win32.HTREEITEM
+	parent               *treeItem
+	children             []*treeItem
+	text                 []uint16
+	image, selectedImage win32.INT
+	cChildren            win32.INT
+	state                win32.UINT // TVIS_* except TVIS_SELECTED and TVIS_DROPHILITED.
+	lParam               win32.LPARAM
+}
+
+// treeView returns the tree view state of w. b.mu must be held.
+func (w *window) treeView() *treeView {
+	if w.tv == nil {
+		w.tv = &treeView{items: make(map[win32.HTREEITEM]*treeItem)}
+	}
+	return w.tv
+}
+
+// item returns the item h, or the root for TVI_ROOT and 0.
+func (tv *treeView) item(h win32.HTREEITEM) *treeItem {
+	if h == win32.TVI_ROOT || h == 0 {
+		return &tv.root
+	}
+	return tv.items[h]
+}
+
+// state returns the state of it.
+func (tv *treeView) state(it *treeItem) win32.UINT {
+	state := it.state
+	if tv.selected == it {
+		state |= win32.TVIS_SELECTED
+	}
+	if tv.dropHilite == it {
+		state |= win32.TVIS_DROPHILITED
+	}
+	return state
+}
+
+// tvItem returns the TVITEMW of it in notifications.
+func (tv *treeView) tvItem(it *treeItem) win32.TVITEMW {
+	if it == nil {
+		return win32.TVITEMW{}
+	}
+	return win32.TVITEMW{
+		Mask:      win32.TVIF_HANDLE | win32.TVIF_STATE | win32.TVIF_PARAM,
+		Item:      it.h,
+		State:     tv.state(it),
+		StateMask: 0xFFFF,
+		LParam:    it.lParam,
+	}
+}
+
+// index returns the index of it among its siblings.
+func (it *treeItem) index() int {
+	return slices.Index(it.parent.children, it)
+}
+
+// level returns the level of it, 0 for the top-level items.
+func (it *treeItem) level() (l int) {
+	for p := it.parent; p.parent != nil; p = p.parent {
+		l++
+	}
+	return
+}
+
+// hasButton reports whether it has a button to expand or collapse.
+func (it *treeItem) hasButton() bool {
+	return len(it.children) > 0 || it.cChildren > 0 || it.cChildren == win32.I_CHILDRENCALLBACK
+}
+
+// visible returns the visible items in display order.
+func (tv *treeView) visible() (items []*treeItem) {
+	var walk func(children []*treeItem)
+	walk = func(children []*treeItem) {
+		for _, it := range children {
+			items = append(items, it)
+			if it.state&win32.TVIS_EXPANDED != 0 {
+				walk(it.children)
+			}
+		}
+	}
+	walk(tv.root.children)
+	return
+}
+
+// labelX returns the x coordinate of the label of it in tree view w.
+func (tv *treeView) labelX(w *window, it *treeItem) int {
+	x := (it.level() + 1) * treeIndent
+	if w.style&win32.TVS_CHECKBOXES != 0 {
+		x += 16
+	}
+	if tv.imageLists[win32.TVSIL_NORMAL] != 0 {
+		x += 16
+	}
+	return x
+}
+
+// hitTest returns the item at x, y of the client area of tree view w, and the TVHT_* flags.
+func (tv *treeView) hitTest(w *window, x, y int) (*treeItem, win32.UINT) {
+	if y < 0 {
+		return nil, win32.TVHT_ABOVE
+	}
+	visible := tv.visible()
+	if y/defaultItemHeight >= len(visible) {
+		return nil, win32.TVHT_NOWHERE
+	}
+	if x < 0 {
+		return nil, win32.TVHT_TOLEFT
+	}
+	it := visible[y/defaultItemHeight]
+	left := it.level() * treeIndent
+	switch {
+	case x < left:
+		return it, win32.TVHT_ONITEMINDENT
+	case x < left+treeIndent:
+		return it, gg[win32.UINT](it.hasButton(), win32.TVHT_ONITEMBUTTON, win32.TVHT_ONITEMINDENT)
+	}
+	left += treeIndent
+	if w.style&win32.TVS_CHECKBOXES != 0 {
+		if x < left+16 {
+			return it, win32.TVHT_ONITEMSTATEICON
+		}
+		left += 16
+	}
+	if tv.imageLists[win32.TVSIL_NORMAL] != 0 && x < left+16 {
+		return it, win32.TVHT_ONITEMICON
+	}
+	return it, win32.TVHT_ONITEMLABEL
+}
+
+// remove removes it and its descendants, and returns the items removed.
+func (tv *treeView) remove(it *treeItem) (removed []*treeItem) {
+	it.parent.children = slices.Delete(it.parent.children, it.index(), it.index()+1)
+	var forget func(it *treeItem)
+	forget = func(it *treeItem) {
+		for _, child := range it.children {
+			forget(child)
+		}
+		delete(tv.items, it.h)
+		for _, p := range []**treeItem{&tv.selected, &tv.dropHilite, &tv.insertMark} {
+			if *p == it {
+				*p = nil
+			}
+		}
+		removed = append(removed, it)
+	}
+	forget(it)
+	return
+}
+
+// treeViewProc handles the TVM_* messages of SysTreeView32 windows.
+func (b *Backend) treeViewProc(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) (win32.LRESULT, bool) {
+	switch message {
+	case win32.TVM_DELETEITEM, win32.TVM_EXPAND, win32.TVM_SETITEMW, win32.TVM_SELECTITEM,
+		win32.TVM_ENSUREVISIBLE, win32.TVM_EDITLABELW, win32.TVM_ENDEDITLABELNOW:
+		// Handled without b.mu held, because they send messages.
+		return b.treeViewSend(hwnd, message, wParam, lParam), true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	w := b.windows[hwnd]
+	if w == nil {
+		return 0, false
+	}
+	tv := w.treeView()
+	switch message {
+	case win32.TVM_INSERTITEMW:
+		ins := (*win32.TVINSERTSTRUCTW)(pointer(lParam))
+		parent := tv.item(ins.Parent)
+		if parent == nil {
+			return 0, true
+		}
+		it := &treeItem{h: win32.HTREEITEM(b.newHandle()), parent: parent}
+		if w.style&win32.TVS_CHECKBOXES != 0 {
+			it.state = win32.INDEXTOSTATEIMAGEMASK(1)
+		}
+		setTreeItem(it, &ins.Item.TVITEMW)
+		var i int
+		switch ins.InsertAfter {
+		case win32.TVI_FIRST:
+			i = 0
+		case win32.TVI_LAST, 0:
+			i = len(parent.children)
+		case win32.TVI_SORT:
+			i = len(parent.children)
+			for j, sibling := range parent.children {
+				if slices.Compare(it.text, sibling.text) < 0 {
+					i = j
+					break
+				}
+			}
+		default:
+			after := tv.items[ins.InsertAfter]
+			if after == nil || after.parent != parent {
+				return 0, true
+			}
+			i = after.index() + 1
+		}
+		parent.children = slices.Insert(parent.children, i, it)
+		tv.items[it.h] = it
+		return win32.LRESULT(it.h), true
+	case win32.TVM_GETITEMW:
+		item := (*win32.TVITEMW)(pointer(lParam))
+		it := tv.items[item.Item]
+		if it == nil {
+			return 0, true
+		}
+		if item.Mask&win32.TVIF_TEXT != 0 {
+			copyCString(item.Text, int(item.TextMax), it.text)
+		}
+		if item.Mask&win32.TVIF_IMAGE != 0 {
+			item.Image = it.image
+		}
+		if item.Mask&win32.TVIF_SELECTEDIMAGE != 0 {
+			item.SelectedImage = it.selectedImage
+		}
+		if item.Mask&win32.TVIF_CHILDREN != 0 {
+			item.Children = gg(len(it.children) > 0, 1, it.cChildren)
+		}
+		if item.Mask&win32.TVIF_STATE != 0 {
+			item.State = tv.state(it) & item.StateMask
+		}
+		if item.Mask&win32.TVIF_PARAM != 0 {
+			item.LParam = it.lParam
+		}
+		return 1, true
+	case win32.TVM_GETITEMSTATE:
+		if it := tv.items[win32.HTREEITEM(wParam)]; it != nil {
+			return win32.LRESULT(tv.state(it) & win32.UINT(lParam)), true
+		}
+		return 0, true
+	case win32.TVM_GETCOUNT:
+		return win32.LRESULT(len(tv.items)), true
+	case win32.TVM_GETINDENT:
+		return treeIndent, true
+	case win32.TVM_GETNEXTITEM:
+		var next *treeItem
+		it := tv.items[win32.HTREEITEM(lParam)]
+		visible := tv.visible()
+		switch wParam {
+		case win32.TVGN_ROOT, win32.TVGN_FIRSTVISIBLE:
+			if len(tv.root.children) > 0 {
+				next = tv.root.children[0]
+			}
+		case win32.TVGN_CARET:
+			next = tv.selected
+		case win32.TVGN_DROPHILITE:
+			next = tv.dropHilite
+		case win32.TVGN_LASTVISIBLE:
+			if len(visible) > 0 {
+				next = visible[len(visible)-1]
+			}
+		case win32.TVGN_NEXT, win32.TVGN_PREVIOUS:
+			if it != nil {
+				i := it.index() + gg(wParam == win32.TVGN_NEXT, 1, -1)
+				if i >= 0 && i < len(it.parent.children) {
+					next = it.parent.children[i]
+				}
+			}
+		case win32.TVGN_PARENT:
+			if it != nil && it.parent != &tv.root {
+				next = it.parent
+			}
+		case win32.TVGN_CHILD:
+			if it := tv.item(win32.HTREEITEM(lParam)); it != nil && len(it.children) > 0 {
+				next = it.children[0]
+			}
+		case win32.TVGN_NEXTVISIBLE, win32.TVGN_PREVIOUSVISIBLE:
+			if i := slices.Index(visible, it); it != nil && i >= 0 {
+				i += gg(wParam == win32.TVGN_NEXTVISIBLE, 1, -1)
+				if i >= 0 && i < len(visible) {
+					next = visible[i]
+				}
+			}
+		}
+		if next == nil {
+			return 0, true
+		}
+		return win32.LRESULT(next.h), true
+	case win32.TVM_HITTEST:
+		info := (*win32.TVHITTESTINFO)(pointer(lParam))
+		it, flags := tv.hitTest(w, int(info.Pt.X), int(info.Pt.Y))
+		info.Item, info.Flags = 0, flags
+		if it != nil {
+			info.Item = it.h
+		}
+		return win32.LRESULT(info.Item), true
+	case win32.TVM_GETITEMRECT:
+		rect := (*win32.RECT)(pointer(lParam))
+		it := tv.items[*(*win32.HTREEITEM)(pointer(lParam))]
+		row := slices.Index(tv.visible(), it)
+		if it == nil || row < 0 {
+			return 0, true
+		}
+		*rect = win32.RECT{Top: win32.LONG(row * defaultItemHeight), Right: w.rect.Right - w.rect.Left,
+			Bottom: win32.LONG((row + 1) * defaultItemHeight)}
+		if wParam != 0 {
+			rect.Left = win32.LONG(tv.labelX(w, it))
+			rect.Right = rect.Left + win32.LONG(8*len(it.text))
+		}
+		return 1, true
+	case win32.TVM_SETIMAGELIST:
+		if wParam > win32.TVSIL_STATE {
+			return 0, true
+		}
+		old := tv.imageLists[wParam]
+		tv.imageLists[wParam] = win32.HIMAGELIST(lParam)
+		return win32.LRESULT(old), true
+	case win32.TVM_GETIMAGELIST:
+		if wParam > win32.TVSIL_STATE {
+			return 0, true
+		}
+		return win32.LRESULT(tv.imageLists[wParam]), true
+	case win32.TVM_SETINSERTMARK:
+		tv.insertMark, tv.insertAfter = tv.items[win32.HTREEITEM(lParam)], wParam != 0
+		return 1, true
+	case win32.TVM_GETEDITCONTROL:
+		return win32.LRESULT(tv.edit), true
+	}
+	return 0, false
+}
+
+// setTreeItem sets the fields of it specified by item, except the state.
+func setTreeItem(it *treeItem, item *win32.TVITEMW) {
+	if item.Mask&win32.TVIF_TEXT != 0 {
+		it.text = cString(item.Text)
+	}
+	if item.Mask&win32.TVIF_IMAGE != 0 {
+		it.image = item.Image
+	}
+	if item.Mask&win32.TVIF_SELECTEDIMAGE != 0 {
+		it.selectedImage = item.SelectedImage
+	}
+	if item.Mask&win32.TVIF_CHILDREN != 0 {
+		it.cChildren = item.Children
+	}
+	if item.Mask&win32.TVIF_PARAM != 0 {
+		it.lParam = item.LParam
+	}
+	if item.Mask&win32.TVIF_STATE != 0 {
+		mask := item.StateMask &^ (win32.TVIS_SELECTED | win32.TVIS_DROPHILITED)
+		it.state = it.state&^mask | item.State&mask
+	}
+}
+
+// treeViewSend handles the TVM_* messages of SysTreeView32 window hwnd which send
+// messages. b.mu must not be held.
+func (b *Backend) treeViewSend(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) win32.LRESULT {
+	switch message {
+	case win32.TVM_DELETEITEM:
+		b.mu.Lock()
+		w := b.windows[hwnd]
+		if w == nil {
+			b.mu.Unlock()
+			return 0
+		}
+		tv := w.treeView()
+		it := tv.item(win32.HTREEITEM(lParam))
+		if it == nil {
+			b.mu.Unlock()
+			return 0
+		}
+		// Deleting the selected item selects the next sibling, the previous one or the parent.
+		selected := tv.selected
+		var next *treeItem
+		if it != &tv.root {
+			if i := it.index(); i+1 < len(it.parent.children) {
+				next = it.parent.children[i+1]
+			} else if i > 0 {
+				next = it.parent.children[i-1]
+			} else if it.parent != &tv.root {
+				next = it.parent
+			}
+		}
+		var removed []*treeItem
+		if it == &tv.root {
+			for len(it.children) > 0 {
+				removed = append(removed, tv.remove(it.children[0])...)
+			}
+		} else {
+			removed = tv.remove(it)
+		}
+		var nm *win32.NMTREEVIEWW
+		if selected != nil && tv.selected == nil {
+			tv.selected = next
+			nm = &win32.NMTREEVIEWW{Action: win32.TVC_UNKNOWN, ItemOld: tv.tvItem(selected), ItemNew: tv.tvItem(next)}
+		}
+		b.mu.Unlock()
+		b.treeItemsDeleted(hwnd, removed)
+		if nm != nil {
+			sendNotify(b, hwnd, nm, win32.TVN_SELCHANGEDW)
+		}
+		return 1
+	case win32.TVM_EXPAND:
+		return win32.LRESULT(boolToInt(b.expandTreeItem(hwnd, win32.HTREEITEM(lParam), win32.UINT(wParam), false)))
+	case win32.TVM_SETITEMW:
+		return win32.LRESULT(boolToInt(b.setTreeItem(hwnd, (*win32.TVITEMW)(pointer(lParam)))))
+	case win32.TVM_SELECTITEM:
+		return win32.LRESULT(boolToInt(b.selectTreeItem(hwnd, win32.UINT(wParam), win32.HTREEITEM(lParam), win32.TVC_UNKNOWN)))
+	case win32.TVM_ENSUREVISIBLE:
+		var ancestors []win32.HTREEITEM
+		b.mu.Lock()
+		if w := b.windows[hwnd]; w != nil {
+			if it := w.treeView().items[win32.HTREEITEM(lParam)]; it != nil {
+				for p := it.parent; p.parent != nil; p = p.parent {
+					ancestors = append(ancestors, p.h)
+				}
+			}
+		}
+		b.mu.Unlock()
+		for _, h := range slices.Backward(ancestors) {
+			b.expandTreeItem(hwnd, h, win32.TVE_EXPAND, false)
+		}
+		return 1
+	case win32.TVM_EDITLABELW:
+		return win32.LRESULT(b.editTreeLabel(hwnd, win32.HTREEITEM(lParam)))
+	case win32.TVM_ENDEDITLABELNOW:
+		return win32.LRESULT(boolToInt(b.endTreeLabelEdit(hwnd, wParam != 0)))
+	}
+	return 0
+}
+
+// treeItemsDeleted sends TVN_DELETEITEMW of the items removed from tree view hwnd.
+// b.mu must not be held.
+func (b *Backend) treeItemsDeleted(hwnd win32.HWND, removed []*treeItem) {
+	for _, it := range removed {
+		nm := &win32.NMTREEVIEWW{ItemOld: win32.TVITEMW{Mask: win32.TVIF_HANDLE | win32.TVIF_PARAM, Item: it.h, LParam: it.lParam}}
+		sendNotify(b, hwnd, nm, win32.TVN_DELETEITEMW)
+	}
+}
+
+// expandTreeItem expands, collapses or toggles item h of tree view hwnd as action
+// of TVM_EXPAND. TVN_ITEMEXPANDINGW and TVN_ITEMEXPANDEDW are sent if byUser, or if
+// the item has not been expanded once. The children of an item of I_CHILDRENCALLBACK
+// are counted with TVN_GETDISPINFOW before expanded. b.mu must not be held.
+func (b *Backend) expandTreeItem(hwnd win32.HWND, h win32.HTREEITEM, action win32.UINT, byUser bool) bool {
+	b.mu.Lock()
+	w := b.windows[hwnd]
+	if w == nil || w.treeView().items[h] == nil {
+		b.mu.Unlock()
+		return false
+	}
+	tv := w.treeView()
+	it := tv.items[h]
+	expanded := it.state&win32.TVIS_EXPANDED != 0
+	var expand bool
+	switch action & win32.TVE_TOGGLE {
+	case win32.TVE_EXPAND:
+		expand = true
+	case win32.TVE_TOGGLE:
+		expand = !expanded
+	case 0:
+		b.mu.Unlock()
+		return false
+	}
+	reset := !expand && action&win32.TVE_COLLAPSERESET != 0
+	if expand == expanded && !reset {
+		b.mu.Unlock()
+		return true
+	}
+	if expand && !it.hasButton() {
+		b.mu.Unlock()
+		return false
+	}
+	callback := expand && len(it.children) == 0 && it.cChildren == win32.I_CHILDRENCALLBACK
+	notify := byUser || it.state&win32.TVIS_EXPANDEDONCE == 0
+	item := tv.tvItem(it)
+	b.mu.Unlock()
+
+	if callback {
+		info := &win32.NMTVDISPINFOW{Item: win32.TVITEMW{Mask: win32.TVIF_CHILDREN, Item: h, LParam: item.LParam}}
+		sendNotify(b, hwnd, info, win32.TVN_GETDISPINFOW)
+		if info.Item.Children == 0 {
+			return false
+		}
+	}
+	nm := &win32.NMTREEVIEWW{Action: gg[win32.UINT](expand, win32.TVE_EXPAND, win32.TVE_COLLAPSE), ItemNew: item}
+	if notify && sendNotify(b, hwnd, nm, win32.TVN_ITEMEXPANDINGW) != 0 {
+		return false
+	}
+	b.mu.Lock()
+	if tv.items[h] != it {
+		b.mu.Unlock()
+		return false // Deleted.
+	}
+	var removed []*treeItem
+	if expand {
+		it.state |= win32.TVIS_EXPANDED | win32.TVIS_EXPANDEDONCE
+	} else {
+		it.state &^= win32.TVIS_EXPANDED
+		if reset {
+			it.state &^= win32.TVIS_EXPANDEDONCE
+			for len(it.children) > 0 {
+				removed = append(removed, tv.remove(it.children[0])...)
+			}
+		}
+	}
+	nm.ItemNew = tv.tvItem(it)
+	b.mu.Unlock()
+	b.treeItemsDeleted(hwnd, removed)
+	if notify {
+		sendNotify(b, hwnd, nm, win32.TVN_ITEMEXPANDEDW)
+	}
+	return true
+}
+
+// setTreeItem handles TVM_SETITEMW, and sends TVN_ITEMCHANGEDW if the state changes.
+// b.mu must not be held.
+func (b *Backend) setTreeItem(hwnd win32.HWND, item *win32.TVITEMW) bool {
+	b.mu.Lock()
+	w := b.windows[hwnd]
+	if w == nil || w.treeView().items[item.Item] == nil {
+		b.mu.Unlock()
+		return false
+	}
+	tv := w.treeView()
+	it := tv.items[item.Item]
+	old := tv.state(it)
+	setTreeItem(it, item)
+	nm := &win32.NMTVITEMCHANGE{Changed: win32.TVIF_STATE, Item: it.h, StateNew: tv.state(it), StateOld: old, LParam: it.lParam}
+	b.mu.Unlock()
+	if nm.StateNew != nm.StateOld {
+		sendNotify(b, hwnd, nm, win32.TVN_ITEMCHANGEDW)
+	}
+	return true
+}
+
+// selectTreeItem handles TVM_SELECTITEM. Changing the selection sends TVN_SELCHANGINGW
+// and TVN_SELCHANGEDW of cause, a TVC_* value. b.mu must not be held.
+func (b *Backend) selectTreeItem(hwnd win32.HWND, flag win32.UINT, h win32.HTREEITEM, cause win32.UINT) bool {
+	b.mu.Lock()
+	w := b.windows[hwnd]
+	if w == nil {
+		b.mu.Unlock()
+		return false
+	}
+	tv := w.treeView()
+	it := tv.items[h]
+	if h != 0 && it == nil {
+		b.mu.Unlock()
+		return false
+	}
+	switch flag {
+	case win32.TVGN_DROPHILITE:
+		tv.dropHilite = it
+		b.mu.Unlock()
+		return true
+	case win32.TVGN_FIRSTVISIBLE:
+		b.mu.Unlock()
+		return true
+	case win32.TVGN_CARET:
+	default:
+		b.mu.Unlock()
+		return false
+	}
+	old := tv.selected
+	if old == it {
+		b.mu.Unlock()
+		return true
+	}
+	nm := &win32.NMTREEVIEWW{Action: cause, ItemOld: tv.tvItem(old), ItemNew: tv.tvItem(it)}
+	b.mu.Unlock()
+	if sendNotify(b, hwnd, nm, win32.TVN_SELCHANGINGW) != 0 {
+		return false
+	}
+	b.mu.Lock()
+	if it != nil && tv.items[h] != it {
+		b.mu.Unlock()
+		return false // Deleted.
+	}
+	tv.selected = it
+	nm.ItemOld, nm.ItemNew = tv.tvItem(old), tv.tvItem(it)
+	b.mu.Unlock()
+	sendNotify(b, hwnd, nm, win32.TVN_SELCHANGEDW)
+	return true
+}
+
+// editTreeLabel handles TVM_EDITLABELW. It sends TVN_BEGINLABELEDITW, and returns the
+// EDIT window created to edit the label, or 0 if canceled. b.mu must not be held.
+func (b *Backend) editTreeLabel(hwnd win32.HWND, h win32.HTREEITEM) win32.HWND {
+	b.endTreeLabelEdit(hwnd, true)
+	b.mu.Lock()
+	w := b.windows[hwnd]
+	if w == nil || w.treeView().items[h] == nil {
+		b.mu.Unlock()
+		return 0
+	}
+	tv := w.treeView()
+	it := tv.items[h]
+	text := append(slices.Clone(it.text), 0)
+	info := &win32.NMTVDISPINFOW{Item: tv.tvItem(it)}
+	info.Item.Mask |= win32.TVIF_TEXT
+	info.Item.Text, info.Item.TextMax = (*win32.WCHAR)(unsafe.Pointer(&text[0])), win32.INT(len(text))
+	b.mu.Unlock()
+	if sendNotify(b, hwnd, info, win32.TVN_BEGINLABELEDITW) != 0 {
+		return 0
+	}
+	edit, err := b.createControl("EDIT", hwnd, text)
+	if err != nil {
+		return 0
+	}
+	b.mu.Lock()
+	if tv.items[h] != it {
+		b.mu.Unlock()
+		b.DestroyWindow(edit)
+		return 0
+	}
+	tv.editing, tv.edit = it, edit
+	b.mu.Unlock()
+	return edit
+}
+
+// endTreeLabelEdit handles TVM_ENDEDITLABELNOW. It sends TVN_ENDLABELEDITW with the
+// text of the EDIT window, or without text if canceled, and sets the label if the
+// parent returns TRUE. b.mu must not be held.
+func (b *Backend) endTreeLabelEdit(hwnd win32.HWND, cancel bool) bool {
+	b.mu.Lock()
+	w := b.windows[hwnd]
+	if w == nil || w.treeView().editing == nil {
+		b.mu.Unlock()
+		return false
+	}
+	tv := w.treeView()
+	it, edit := tv.editing, tv.edit
+	tv.editing, tv.edit = nil, 0
+	var text []uint16
+	if e := b.windows[edit]; e != nil {
+		text = append(slices.Clone(e.text), 0)
+	}
+	info := &win32.NMTVDISPINFOW{Item: tv.tvItem(it)}
+	info.Item.Mask |= win32.TVIF_TEXT
+	if !cancel {
+		info.Item.Text, info.Item.TextMax = (*win32.WCHAR)(unsafe.Pointer(&text[0])), win32.INT(len(text))
+	}
+	b.mu.Unlock()
+	if sendNotify(b, hwnd, info, win32.TVN_ENDLABELEDITW) != 0 && !cancel {
+		b.mu.Lock()
+		if tv.items[it.h] == it {
+			it.text = text[:len(text)-1]
+		}
+		b.mu.Unlock()
+	}
+	b.DestroyWindow(edit)
+	return true
+}
+
+// toggleTreeCheckBox toggles the state image of item h of tree view hwnd between
+// unchecked(1) and checked(2), if the tree view is TVS_CHECKBOXES. b.mu must not be held.
+func (b *Backend) toggleTreeCheckBox(hwnd win32.HWND, h win32.HTREEITEM) {
+	b.mu.Lock()
+	w := b.windows[hwnd]
+	if w == nil || w.style&win32.TVS_CHECKBOXES == 0 || w.treeView().items[h] == nil {
+		b.mu.Unlock()
+		return
+	}
+	checked := w.treeView().items[h].state&win32.TVIS_STATEIMAGEMASK == win32.INDEXTOSTATEIMAGEMASK(2)
+	b.mu.Unlock()
+	b.setTreeItem(hwnd, &win32.TVITEMW{
+		Mask:      win32.TVIF_STATE,
+		Item:      h,
+		State:     win32.INDEXTOSTATEIMAGEMASK(gg[win32.UINT](checked, 1, 2)),
+		StateMask: win32.TVIS_STATEIMAGEMASK,
+	})
+}
+
+// TreeViewClick simulates clicking at x, y of the client area of a SysTreeView32 window,
+// or double-clicking if double is true. See treeView for the layout. Clicking the button
+// of an item expands or collapses it, clicking the state icon toggles the checkbox, and
+// clicking elsewhere on an item selects it, then NM_CLICK is sent. Double-clicking an
+// item expands or collapses it as well, and sends NM_DBLCLK.
+func (b *Backend) TreeViewClick(hwnd win32.HWND, x, y int, double bool) error {
+	b.mu.Lock()
+	w, err := b.control(hwnd, "SysTreeView32")
+	if err != nil {
+		b.mu.Unlock()
+		return err
+	}
+	tv := w.treeView()
+	it, flags := tv.hitTest(w, x, y)
+	var h win32.HTREEITEM
+	if it != nil {
+		h = it.h
+	}
+	b.mu.Unlock()
+
+	switch {
+	case flags&win32.TVHT_ONITEMBUTTON != 0:
+		b.expandTreeItem(hwnd, h, win32.TVE_TOGGLE, true)
+	case flags&(win32.TVHT_ONITEMICON|win32.TVHT_ONITEMLABEL) != 0:
+		b.selectTreeItem(hwnd, win32.TVGN_CARET, h, win32.TVC_BYMOUSE)
+	}
+	var nm win32.NMHDR
+	sendNotify(b, hwnd, &nm, win32.NM_CLICK)
+	if flags&win32.TVHT_ONITEMSTATEICON != 0 {
+		b.toggleTreeCheckBox(hwnd, h)
+	}
+	if double {
+		sendNotify(b, hwnd, &nm, win32.NM_DBLCLK)
+		if flags&(win32.TVHT_ONITEMICON|win32.TVHT_ONITEMLABEL) != 0 {
+			b.expandTreeItem(hwnd, h, win32.TVE_TOGGLE, true)
+		}
+	}
+	return nil
+}
+
+// TreeViewKeyDown simulates pressing the key vk in a SysTreeView32 window, which
+// sends TVN_KEYDOWN. VK_SPACE toggles the checkbox of the selected item as well.
+func (b *Backend) TreeViewKeyDown(hwnd win32.HWND, vk win32.WORD) error {
+	b.mu.Lock()
+	w, err := b.control(hwnd, "SysTreeView32")
+	if err != nil {
+		b.mu.Unlock()
+		return err
+	}
+	selected := w.treeView().selected
+	b.mu.Unlock()
+	nm := &win32.NMTVKEYDOWN{VKey: vk}
+	sendNotify(b, hwnd, nm, win32.TVN_KEYDOWN)
+	if vk == win32.VK_SPACE && selected != nil {
+		b.toggleTreeCheckBox(hwnd, selected.h)
+	}
+	return nil
+}
+
+// TreeViewEditLabel simulates the user editing the label of item of a SysTreeView32
+// window of TVS_EDITLABELS: TVM_EDITLABELW is sent, the text is typed into the EDIT
+// window, then TVM_ENDEDITLABELNOW is sent.
+func (b *Backend) TreeViewEditLabel(hwnd win32.HWND, item win32.HTREEITEM, text string) error {
+	b.mu.Lock()
+	w, err := b.control(hwnd, "SysTreeView32")
+	if err == nil && w.style&win32.TVS_EDITLABELS == 0 {
+		err = fmt.Errorf("%w: editing labels of a tree view without TVS_EDITLABELS", ErrNotSupported)
+	}
+	b.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	edit, _ := b.send(hwnd, win32.TVM_EDITLABELW, 0, win32.LPARAM(item))
+	if edit == 0 {
+		return nil // Canceled.
+	}
+	b.mu.Lock()
+	if e := b.windows[win32.HWND(edit)]; e != nil {
+		e.text = utf16.Encode([]rune(text))
+	}
+	b.mu.Unlock()
+	b.send(hwnd, win32.TVM_ENDEDITLABELNOW, 0, 0)
+	return nil
+}
+
+// TreeViewBeginDrag simulates the user starting to drag item of a SysTreeView32
+// window, which sends TVN_BEGINDRAGW. Continue the drag with MouseMove and MouseUp.
+func (b *Backend) TreeViewBeginDrag(hwnd win32.HWND, item win32.HTREEITEM) error {
+	b.mu.Lock()
+	w, err := b.control(hwnd, "SysTreeView32")
+	if err != nil {
+		b.mu.Unlock()
+		return err
+	}
+	if w.style&win32.TVS_DISABLEDRAGDROP != 0 {
+		b.mu.Unlock()
+		return fmt.Errorf("%w: dragging in a tree view of TVS_DISABLEDRAGDROP", ErrNotSupported)
+	}
+	tv := w.treeView()
+	it := tv.items[item]
+	row := slices.Index(tv.visible(), it)
+	if it == nil || row < 0 {
+		b.mu.Unlock()
+		return fmt.Errorf("%w: visible tree view item %#x", ErrNotFound, item)
+	}
+	nm := &win32.NMTREEVIEWW{
+		ItemNew: tv.tvItem(it),
+		PtDrag:  win32.POINT{X: win32.LONG(tv.labelX(w, it)), Y: win32.LONG(row*defaultItemHeight + defaultItemHeight/2)},
+	}
+	b.mu.Unlock()
+	sendNotify(b, hwnd, nm, win32.TVN_BEGINDRAGW)
+	return nil
+}
@@ -0,0 +1,1170 @@
+package fake
+
+import (
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+	"unicode/utf16"
+	"unsafe"
+
+	"github.com/mkch/gw/win32"
+)
+
+type class struct {
+	name       string
+	atom       win32.ATOM
+	proc       uintptr
+	style      win32.CLASS_STYLE
+	instance   win32.HINSTANCE
+	background win32.HBRUSH
+	cursor     win32.HCURSOR
+}
+
+type window struct {
+	hwnd     win32.HWND
+	class    *class
+	proc     uintptr
+	style    win32.WINDOW_STYLE
+	exStyle  win32.WINDOW_EX_STYLE
+	text     []uint16
+	parent   win32.HWND // Parent of a child window, or owner of a top-level window.
+	children []win32.HWND
+	// rect is relative to the client area of the parent for child windows,
+	// or in screen coordinates for top-level windows.
+	rect          win32.RECT
+	restore       win32.RECT // rect before minimized or maximized.
+	visible       bool
+	minimized     bool
+	maximized     bool
+	disabled      bool
+	menu          win32.HMENU
+	id            win32.LONG_PTR
+	userData      win32.LONG_PTR
+	instance      win32.HINSTANCE
+	font          win32.HFONT
+	gestureConfig []win32.GESTURECONFIG
+	lb            *listBox  // LISTBOX and COMBOBOX only.
+	cb            *comboBox // COMBOBOX only.
+	lv            *listView // SysListView32 only.
+	tv            *treeView // SysTreeView32 only.
+	dpi           win32.UINT
+	thread        win32.DWORD
+	// invalid is set by InvalidateRect and reset by BeginPaint.
+	invalid    bool
+	erase      bool
+	updateRect win32.RECT
+	destroying bool
+}
+
+// child reports whether w is a child window.
+func (w *window) child() bool {
+	return w.style&win32.WS_CHILD != 0
+}
+
+// WindowInfo is the state of a window.
+type WindowInfo struct {
+	Class   string
+	Text    string
+	Style   win32.WINDOW_STYLE
+	ExStyle win32.WINDOW_EX_STYLE
+	// Parent is the parent of a child window, or the owner of a top-level window.
+	Parent win32.HWND
+	// Rect is relative to the client area of the parent for child windows,
+	// or in screen coordinates for top-level windows.
+	Rect      win32.RECT
+	Visible   bool
+	Minimized bool
+	Maximized bool
+	Enabled   bool
+	Menu      win32.HMENU
+	ID        win32.LONG_PTR
+	Font      win32.HFONT
+	DPI       win32.UINT
+	Thread    win32.DWORD
+	// Invalid reports whether the window has an update region to be painted.
+	Invalid bool
+}
+
+// Window returns the state of hwnd. Ok is false if hwnd is not a window.
+func (b *Backend) Window(hwnd win32.HWND) (info WindowInfo, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	w := b.windows[hwnd]
+	if w == nil {
+		return
+	}
+	return WindowInfo{
+		Class:     w.class.name,
+		Text:      string(utf16.Decode(w.text)),
+		Style:     w.style,
+		ExStyle:   w.exStyle,
+		Parent:    w.parent,
+		Rect:      w.rect,
+		Visible:   w.visible,
+		Minimized: w.minimized,
+		Maximized: w.maximized,
+		Enabled:   !w.disabled,
+		Menu:      w.menu,
+		ID:        w.id,
+		Font:      w.font,
+		DPI:       w.dpi,
+		Thread:    w.thread,
+		Invalid:   w.invalid,
+	}, true
+}
+
+// Windows returns all the windows in the order of creation.
+func (b *Backend) Windows() []win32.HWND {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.sortedWindows()
+}
+
+// Children returns the child windows of hwnd in the order of creation.
+func (b *Backend) Children(hwnd win32.HWND) []win32.HWND {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if w := b.windows[hwnd]; w != nil {
+		return slices.Clone(w.children)
+	}
+	return nil
+}
+
+// sortedWindows returns all the windows sorted by handle, which is the order of creation.
+// b.mu must be held.
+func (b *Backend) sortedWindows() []win32.HWND {
+	hwnds := make([]win32.HWND, 0, len(b.windows))
+	for h := range b.windows {
+		hwnds = append(hwnds, h)
+	}
+	slices.Sort(hwnds)
+	return hwnds
+}
+
+func (b *Backend) isWindow(hwnd win32.HWND) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.windows[hwnd] != nil
+}
+
+// window returns the window of hwnd. b.mu must be held.
+func (b *Backend) window(hwnd win32.HWND) (*window, error) {
+	if w := b.windows[hwnd]; w != nil {
+		return w, nil
+	}
+	return nil, fmt.Errorf("%w: HWND %#x", ErrInvalidHandle, hwnd)
+}
+
+// className returns the class name of p, which is a C string or an atom.
+func className(p *win32.WCHAR) (name string, atom win32.ATOM) {
+	if uintptr(unsafe.Pointer(p)) < 0x10000 {
+		return "", win32.ATOM(uintptr(unsafe.Pointer(p)))
+	}
+	return goString(p), 0
+}
+
+// findClass finds a window class by name or atom. b.mu must be held.
+func (b *Backend) findClass(p *win32.WCHAR) (*class, error) {
+	name, atom := className(p)
+	if atom == 0 {
+		if cls := b.classes[strings.ToLower(name)]; cls != nil {
+			return cls, nil
+		}
+		return nil, fmt.Errorf("%w: window class %q", ErrNotFound, name)
+	}
+	for _, cls := range b.classes {
+		if cls.atom == atom {
+			return cls, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: window class atom %#x", ErrNotFound, atom)
+}
+
+// systemClasses are the predefined window classes of controls.
+// Their window procedure handles the messages of controlProcs, stores the font
+// of WM_SETFONT and calls DefWindowProcW.
+var systemClasses = []string{"BUTTON", "EDIT", "STATIC", "LISTBOX", "COMBOBOX", "SCROLLBAR",
+	"SysListView32", "SysHeader32", "SysTreeView32", "msctls_progress32", "msctls_trackbar32", "msctls_updown32"}
+
+// controlProcs handle the messages specific to system classes, keyed by the
+// lower case class name. They report whether the message is handled.
+var controlProcs = map[string]func(b *Backend, hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) (win32.LRESULT, bool){
+	"listbox":       (*Backend).listBoxProc,
+	"combobox":      (*Backend).comboBoxProc,
+	"syslistview32": (*Backend).listViewProc,
+	"sysheader32":   (*Backend).headerProc,
+	"systreeview32": (*Backend).treeViewProc,
+}
+
+// createControl creates a visible child window of a system class in parent, with the
+// null terminated text or nil. b.mu must not be held.
+func (b *Backend) createControl(class string, parent win32.HWND, text []uint16) (win32.HWND, error) {
+	name := utf16.Encode([]rune(class + "\x00"))
+	var windowName *win32.WCHAR
+	if len(text) > 0 {
+		windowName = (*win32.WCHAR)(unsafe.Pointer(&text[0]))
+	}
+	return b.CreateWindowExW(0, (*win32.WCHAR)(unsafe.Pointer(&name[0])), windowName, win32.WS_CHILD|win32.WS_VISIBLE,
+		0, 0, 0, 0, parent, 0, 0, 0)
+}
+
+func (b *Backend) registerSystemClasses() {
+	proc := b.NewCallback(func(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) win32.LRESULT {
+		b.mu.Lock()
+		var ctlProc func(b *Backend, hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) (win32.LRESULT, bool)
+		if w := b.windows[hwnd]; w != nil {
+			ctlProc = controlProcs[strings.ToLower(w.class.name)]
+		}
+		b.mu.Unlock()
+		if ctlProc != nil {
+			if r, ok := ctlProc(b, hwnd, message, wParam, lParam); ok {
+				return r
+			}
+		}
+		switch message {
+		case win32.WM_SETFONT:
+			b.mu.Lock()
+			if w := b.windows[hwnd]; w != nil {
+				w.font = win32.HFONT(wParam)
+			}
+			b.mu.Unlock()
+			return 0
+		case win32.WM_GETFONT:
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			if w := b.windows[hwnd]; w != nil {
+				return win32.LRESULT(w.font)
+			}
+			return 0
+		}
+		return b.DefWindowProcW(hwnd, message, wParam, lParam)
+	})
+	for _, name := range systemClasses {
+		b.lastAtom++
+		b.classes[strings.ToLower(name)] = &class{name: name, atom: b.lastAtom, proc: proc}
+	}
+}
+
+func (b *Backend) RegisterClassExW(cls *win32.WNDCLASSEXW) (win32.ATOM, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	name := goString(cls.ClassName)
+	key := strings.ToLower(name)
+	if b.classes[key] != nil {
+		return 0, fmt.Errorf("%w: window class %q", ErrExist, name)
+	}
+	b.lastAtom++
+	b.classes[key] = &class{
+		name:       name,
+		atom:       b.lastAtom,
+		proc:       cls.WndProc,
+		style:      cls.Style,
+		instance:   cls.Instance,
+		background: cls.Background,
+		cursor:     cls.Cursor,
+	}
+	return b.lastAtom, nil
+}
+
+// CreateWindowExW creates a window and sends WM_NCCREATE, WM_CREATE, WM_MOVE and WM_SIZE.
+// A window of WS_VISIBLE is shown with SW_SHOW.
+func (b *Backend) CreateWindowExW(
+	exStyle win32.WINDOW_EX_STYLE,
+	className *win32.WCHAR,
+	windowName *win32.WCHAR,
+	style win32.WINDOW_STYLE,
+	x win32.INT, y win32.INT, width win32.INT, height win32.INT,
+	wndParent win32.HWND,
+	menu win32.HMENU,
+	instance win32.HINSTANCE,
+	param win32.UINT_PTR,
+) (win32.HWND, error) {
+	b.mu.Lock()
+	cls, err := b.findClass(className)
+	if err != nil {
+		b.mu.Unlock()
+		return 0, err
+	}
+	dpi := b.dpi
+	if wndParent != 0 {
+		parent, err := b.window(wndParent)
+		if err != nil {
+			b.mu.Unlock()
+			return 0, err
+		}
+		dpi = parent.dpi
+	} else if style&win32.WS_CHILD != 0 {
+		b.mu.Unlock()
+		return 0, fmt.Errorf("%w: child window without parent", ErrInvalidHandle)
+	}
+	if style&win32.WS_CHILD == 0 {
+		if x == win32.CW_USEDEFAULT {
+			x, y = DefaultX, DefaultY
+		}
+		if width == win32.CW_USEDEFAULT {
+			width, height = DefaultWidth, DefaultHeight
+		}
+	} else {
+		if x == win32.CW_USEDEFAULT {
+			x, y = 0, 0
+		}
+		if width == win32.CW_USEDEFAULT {
+			width, height = 0, 0
+		}
+	}
+	w := &window{
+		hwnd:     win32.HWND(b.newHandle()),
+		class:    cls,
+		proc:     cls.proc,
+		style:    style &^ win32.WS_VISIBLE,
+		exStyle:  exStyle,
+		parent:   wndParent,
+		rect:     win32.RECT{Left: win32.LONG(x), Top: win32.LONG(y), Right: win32.LONG(x + max(width, 0)), Bottom: win32.LONG(y + max(height, 0))},
+		instance: instance,
+		dpi:      dpi,
+		thread:   b.thread().id,
+	}
+	if w.child() {
+		w.id = win32.LONG_PTR(menu)
+		parent := b.windows[wndParent]
+		parent.children = append(parent.children, w.hwnd)
+	} else if menu != 0 {
+		if b.menus[menu] == nil {
+			b.mu.Unlock()
+			return 0, fmt.Errorf("%w: HMENU %#x", ErrInvalidHandle, menu)
+		}
+		w.menu = menu
+	}
+	b.windows[w.hwnd] = w
+	b.mu.Unlock()
+
+	cs := &win32.CREATESTRUCTW{
+		CreateParams: param,
+		Instance:     instance,
+		Menu:         menu,
+		Parent:       wndParent,
+		CY:           height,
+		CX:           width,
+		Y:            y,
+		X:            x,
+		Style:        win32.LONG(style),
+		Name:         windowName,
+		Class:        className,
+		ExStyle:      win32.DWORD(exStyle),
+	}
+	create := func(message win32.UINT) win32.LRESULT {
+		return withLParam(cs, func(lParam win32.LPARAM) win32.LRESULT { return b.deliver(w.hwnd, message, 0, lParam) })
+	}
+	if create(win32.WM_NCCREATE) == 0 {
+		b.DestroyWindow(w.hwnd)
+		return 0, fmt.Errorf("fake: WM_NCCREATE of window class %q failed", cls.name)
+	}
+	if create(win32.WM_CREATE) == -1 {
+		b.DestroyWindow(w.hwnd)
+		return 0, fmt.Errorf("fake: WM_CREATE of window class %q failed", cls.name)
+	}
+	b.notifyPosChanged(w.hwnd, win32.RECT{Left: -1, Top: -1, Right: -1, Bottom: -1}, w.rect, win32.SIZE_RESTORED)
+	if style&win32.WS_VISIBLE != 0 {
+		b.ShowWindow(w.hwnd, win32.SW_SHOW)
+	}
+	return w.hwnd, nil
+}
+
+// DestroyWindow sends WM_DESTROY to the window, destroys its child and owned windows,
+// then sends WM_NCDESTROY. The menu of the window, if any, is destroyed.
+func (b *Backend) DestroyWindow(hwnd win32.HWND) error {
+	b.mu.Lock()
+	w, err := b.window(hwnd)
+	if err == nil && w.thread != currentThreadID() {
+		err = fmt.Errorf("%w: HWND %#x", ErrWrongThread, hwnd)
+	}
+	if err != nil || w.destroying {
+		b.mu.Unlock()
+		return err
+	}
+	w.destroying = true
+	t := b.threads[w.thread]
+	wasActive := t.active == hwnd
+	if wasActive {
+		t.active = 0
+	}
+	b.mu.Unlock()
+
+	if wasActive {
+		b.deliver(hwnd, win32.WM_ACTIVATE, win32.WA_INACTIVE, 0)
+	}
+	b.deliver(hwnd, win32.WM_DESTROY, 0, 0)
+
+	b.mu.Lock()
+	var owned []win32.HWND
+	for _, h := range b.sortedWindows() {
+		if o := b.windows[h]; o.parent == hwnd {
+			owned = append(owned, h)
+		}
+	}
+	b.mu.Unlock()
+	for _, h := range owned {
+		b.DestroyWindow(h)
+	}
+
+	b.deliver(hwnd, win32.WM_NCDESTROY, 0, 0)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if w.child() {
+		if parent := b.windows[w.parent]; parent != nil {
+			parent.children = slices.DeleteFunc(parent.children, func(h win32.HWND) bool { return h == hwnd })
+		}
+	} else if w.menu != 0 {
+		b.destroyMenu(w.menu)
+	}
+	b.timers = slices.DeleteFunc(b.timers, func(tm *timer) bool { return tm.hwnd == hwnd })
+	delete(b.tracks, hwnd)
+	if b.capture == hwnd {
+		b.capture = 0
+	}
+	if b.mouseWnd == hwnd {
+		b.mouseWnd = 0
+	}
+	delete(b.windows, hwnd)
+	return nil
+}
+
+// DefWindowProcW handles WM_NCCREATE, WM_CLOSE, WM_SETTEXT, WM_GETTEXT, WM_GETTEXTLENGTH,
+// WM_PAINT, WM_ERASEBKGND, WM_CONTEXTMENU, Alt+F4 of WM_SYSKEYDOWN, SC_CLOSE of
+// WM_SYSCOMMAND and WM_GESTURE. Other messages are ignored.
+func (b *Backend) DefWindowProcW(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) win32.LRESULT {
+	switch message {
+	case win32.WM_NCCREATE:
+		cs := (*win32.CREATESTRUCTW)(pointer(lParam))
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if w := b.windows[hwnd]; w != nil {
+			w.text = cString(cs.Name)
+		}
+		return 1
+	case win32.WM_CLOSE:
+		b.DestroyWindow(hwnd)
+	case win32.WM_SYSKEYDOWN:
+		if wParam == win32.VK_F4 && lParam&0x20000000 != 0 {
+			b.SendMessageW(hwnd, win32.WM_SYSCOMMAND, win32.SC_CLOSE, 0)
+		}
+	case win32.WM_SYSCOMMAND:
+		if wParam&0xFFF0 == win32.SC_CLOSE {
+			b.SendMessageW(hwnd, win32.WM_CLOSE, 0, 0)
+		}
+	case win32.WM_GESTURE:
+		b.CloseGestureInfoHandle(win32.HGESTUREINFO(lParam))
+	case win32.WM_SETTEXT:
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if w := b.windows[hwnd]; w != nil {
+			w.text = cString((*win32.WCHAR)(pointer(lParam)))
+			return 1
+		}
+	case win32.WM_GETTEXT:
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if w := b.windows[hwnd]; w != nil {
+			return win32.LRESULT(copyCString((*win32.WCHAR)(pointer(lParam)), int(wParam), w.text))
+		}
+	case win32.WM_GETTEXTLENGTH:
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if w := b.windows[hwnd]; w != nil {
+			return win32.LRESULT(len(w.text))
+		}
+	case win32.WM_PAINT:
+		var ps win32.PAINTSTRUCT
+		if _, err := b.BeginPaint(hwnd, &ps); err == nil {
+			b.EndPaint(hwnd, &ps)
+		}
+	case win32.WM_ERASEBKGND:
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if w := b.windows[hwnd]; w != nil && w.class.background != 0 {
+			return 1
+		}
+	case win32.WM_CONTEXTMENU:
+		b.mu.Lock()
+		w := b.windows[hwnd]
+		b.mu.Unlock()
+		if w != nil && w.child() {
+			r, _ := b.send(w.parent, message, wParam, lParam)
+			return r
+		}
+	}
+	return 0
+}
+
+func (b *Backend) CallWindowProcW(proc uintptr, hwnd win32.HWND, msg win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) win32.LRESULT {
+	if proc == 0 {
+		return 0
+	}
+	return win32.LRESULT(call(proc, uintptr(hwnd), uintptr(msg), uintptr(wParam), uintptr(lParam)))
+}
+
+// SetWindowLongPtrW supports GWLP_WNDPROC, GWL_STYLE, GWL_EXSTYLE, GWLP_ID,
+// GWLP_USERDATA, GWLP_HINSTANCE and GWLP_HWNDPARENT.
+func (b *Backend) SetWindowLongPtrW(hwnd win32.HWND, index int, newLong win32.LONG_PTR) (win32.LONG_PTR, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	w, err := b.window(hwnd)
+	if err != nil {
+		return 0, err
+	}
+	var old win32.LONG_PTR
+	switch index {
+	case win32.GWLP_WNDPROC:
+		old, w.proc = win32.LONG_PTR(w.proc), uintptr(newLong)
+	case win32.GWL_STYLE:
+		old, w.style = win32.LONG_PTR(w.style), win32.WINDOW_STYLE(newLong)
+	case win32.GWL_EXSTYLE:
+		old, w.exStyle = win32.LONG_PTR(w.exStyle), win32.WINDOW_EX_STYLE(newLong)
+	case win32.GWLP_ID:
+		old, w.id = w.id, newLong
+	case win32.GWLP_USERDATA:
+		old, w.userData = w.userData, newLong
+	case win32.GWLP_HINSTANCE:
+		old, w.instance = win32.LONG_PTR(w.instance), win32.HINSTANCE(newLong)
+	case win32.GWLP_HWNDPARENT:
+		old, w.parent = win32.LONG_PTR(w.parent), win32.HWND(newLong)
+	default:
+		return 0, fmt.Errorf("%w: window long index %v", ErrNotSupported, index)
+	}
+	return old, nil
+}
+
+// GetWindowLongPtrW supports the indexes supported by SetWindowLongPtrW.
+func (b *Backend) GetWindowLongPtrW(hwnd win32.HWND, index int) (win32.LONG_PTR, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	w, err := b.window(hwnd)
+	if err != nil {
+		return 0, err
+	}
+	switch index {
+	case win32.GWLP_WNDPROC:
+		return win32.LONG_PTR(w.proc), nil
+	case win32.GWL_STYLE:
+		style := w.style
+		if w.visible {
+			style |= win32.WS_VISIBLE
+		}
+		if w.disabled {
+			style |= win32.WS_DISABLED
+		}
+		return win32.LONG_PTR(style), nil
+	case win32.GWL_EXSTYLE:
+		return win32.LONG_PTR(w.exStyle), nil
+	case win32.GWLP_ID:
+		return w.id, nil
+	case win32.GWLP_USERDATA:
+		return w.userData, nil
+	case win32.GWLP_HINSTANCE:
+		return win32.LONG_PTR(w.instance), nil
+	case win32.GWLP_HWNDPARENT:
+		return win32.LONG_PTR(w.parent), nil
+	}
+	return 0, fmt.Errorf("%w: window long index %v", ErrNotSupported, index)
+}
+
+// ShowWindow shows, hides, minimizes, maximizes or restores a window.
+// WM_SHOWWINDOW is sent if the visibility changes, and WM_SIZE is sent if
+// the window is minimized, maximized or restored. A top-level window is
+// activated unless cmdShow is SW_SHOWNA, SW_SHOWNOACTIVATE or SW_SHOWMINNOACTIVE.
+func (b *Backend) ShowWindow(hwnd win32.HWND, cmdShow win32.SHOW_WINDOW_CMD) error {
+	b.mu.Lock()
+	w, err := b.window(hwnd)
+	if err != nil {
+		b.mu.Unlock()
+		return err
+	}
+	wasVisible, old := w.visible, w.rect
+	sizeType := -1
+	w.visible = cmdShow != win32.SW_HIDE
+	switch cmdShow {
+	case win32.SW_SHOWMINIMIZED, win32.SW_MINIMIZE, win32.SW_SHOWMINNOACTIVE, win32.SW_FORCEMINIMIZE:
+		if !w.minimized {
+			if !w.maximized {
+				w.restore = w.rect
+			}
+			w.minimized, w.maximized = true, false
+			sizeType = win32.SIZE_MINIMIZED
+		}
+	case win32.SW_MAXIMIZE:
+		if !w.maximized {
+			if !w.minimized {
+				w.restore = w.rect
+			}
+			w.minimized, w.maximized = false, true
+			w.rect = win32.RECT{Right: win32.LONG(b.metrics[win32.SM_CXSCREEN]), Bottom: win32.LONG(b.metrics[win32.SM_CYSCREEN])}
+			if w.child() {
+				w.rect = b.windows[w.parent].clientRect()
+			}
+			sizeType = win32.SIZE_MAXIMIZED
+		}
+	case win32.SW_SHOWNORMAL, win32.SW_RESTORE, win32.SW_SHOWDEFAULT:
+		if w.minimized || w.maximized {
+			w.minimized, w.maximized = false, false
+			w.rect = w.restore
+			sizeType = win32.SIZE_RESTORED
+		}
+	}
+	activate := !w.child() && w.visible && !w.minimized &&
+		cmdShow != win32.SW_SHOWNA && cmdShow != win32.SW_SHOWNOACTIVATE
+	deactivate := !w.visible && b.threads[w.thread].active == hwnd
+	newRect := w.rect
+	b.mu.Unlock()
+
+	if w.visible != wasVisible {
+		b.deliver(hwnd, win32.WM_SHOWWINDOW, win32.WPARAM(boolToInt(w.visible)), 0)
+	}
+	if sizeType != -1 {
+		b.notifyPosChanged(hwnd, old, newRect, sizeType)
+	}
+	if activate {
+		b.activate(hwnd)
+	} else if deactivate {
+		b.activate(0)
+	}
+	return nil
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// activate makes hwnd the active window of the calling goroutine and sends WM_ACTIVATE.
+func (b *Backend) activate(hwnd win32.HWND) {
+	b.mu.Lock()
+	t := b.thread()
+	prev := t.active
+	t.active = hwnd
+	b.mu.Unlock()
+	if prev == hwnd {
+		return
+	}
+	if prev != 0 {
+		b.deliver(prev, win32.WM_ACTIVATE, win32.WA_INACTIVE, win32.LPARAM(hwnd))
+	}
+	if hwnd != 0 {
+		b.deliver(hwnd, win32.WM_ACTIVATE, win32.WA_ACTIVE, win32.LPARAM(prev))
+	}
+}
+
+// clientRect returns the client rectangle of w.
+func (w *window) clientRect() win32.RECT {
+	return win32.RECT{Right: w.rect.Width(), Bottom: w.rect.Height()}
+}
+
+// notifyPosChanged sends WM_MOVE if the position of the window changed,
+// and WM_SIZE if the size changed.
+func (b *Backend) notifyPosChanged(hwnd win32.HWND, old, new win32.RECT, sizeType int) {
+	if old.Left != new.Left || old.Top != new.Top {
+		b.deliver(hwnd, win32.WM_MOVE, 0, makeLParam(new.Left, new.Top))
+	}
+	if old.Width() != new.Width() || old.Height() != new.Height() || sizeType != win32.SIZE_RESTORED {
+		b.deliver(hwnd, win32.WM_SIZE, win32.WPARAM(sizeType), makeLParam(new.Width(), new.Height()))
+	}
+}
+
+// SetWindowPos moves and resizes a window. The z-order is not simulated.
+func (b *Backend) SetWindowPos(hwnd win32.HWND, hwndInsertAfter win32.HWND, x win32.INT, y win32.INT, cx win32.INT, cy win32.INT, flags win32.UINT) error {
+	b.mu.Lock()
+	w, err := b.window(hwnd)
+	if err != nil {
+		b.mu.Unlock()
+		return err
+	}
+	old := w.rect
+	if flags&win32.SWP_NOMOVE == 0 {
+		w.rect = win32.RECT{Left: win32.LONG(x), Top: win32.LONG(y), Right: win32.LONG(x) + old.Width(), Bottom: win32.LONG(y) + old.Height()}
+	}
+	if flags&win32.SWP_NOSIZE == 0 {
+		w.rect.Right = w.rect.Left + win32.LONG(max(cx, 0))
+		w.rect.Bottom = w.rect.Top + win32.LONG(max(cy, 0))
+	}
+	if w.rect != old {
+		w.minimized, w.maximized = false, false
+	}
+	newRect := w.rect
+	b.mu.Unlock()
+
+	if flags&win32.SWP_SHOWWINDOW != 0 {
+		b.ShowWindow(hwnd, win32.SHOW_WINDOW_CMD(gg(flags&win32.SWP_NOACTIVATE != 0, win32.SW_SHOWNA, win32.SW_SHOW)))
+	} else if flags&win32.SWP_HIDEWINDOW != 0 {
+		b.ShowWindow(hwnd, win32.SW_HIDE)
+	}
+	b.notifyPosChanged(hwnd, old, newRect, win32.SIZE_RESTORED)
+	return nil
+}
+
+// gg returns a if cond is true, otherwise b.
+func gg[T any](cond bool, a, b T) T {
+	if cond {
+		return a
+	}
+	return b
+}
+
+type windowPos struct {
+	hwnd, insertAfter win32.HWND
+	x, y, cx, cy      win32.INT
+	flags             win32.UINT
+}
+
+func (b *Backend) BeginDeferWindowPos(numWindows win32.INT) (win32.HDWP, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	h := win32.HDWP(b.newHandle())
+	b.deferred[h] = make([]windowPos, 0, max(numWindows, 0))
+	return h, nil
+}
+
+func (b *Backend) DeferWindowPos(winPosInfo win32.HDWP, hwnd win32.HWND, hwndInsertAfter win32.HWND, x win32.INT, y win32.INT, cx win32.INT, cy win32.INT, flags win32.UINT) (win32.HDWP, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	list, ok := b.deferred[winPosInfo]
+	if !ok {
+		return 0, fmt.Errorf("%w: HDWP %#x", ErrInvalidHandle, winPosInfo)
+	}
+	if _, err := b.window(hwnd); err != nil {
+		delete(b.deferred, winPosInfo)
+		return 0, err
+	}
+	b.deferred[winPosInfo] = append(list, windowPos{hwnd, hwndInsertAfter, x, y, cx, cy, flags})
+	return winPosInfo, nil
+}
+
+// EndDeferWindowPos calls SetWindowPos for all the deferred windows in order.
+func (b *Backend) EndDeferWindowPos(winPosInfo win32.HDWP) error {
+	b.mu.Lock()
+	list, ok := b.deferred[winPosInfo]
+	delete(b.deferred, winPosInfo)
+	b.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: HDWP %#x", ErrInvalidHandle, winPosInfo)
+	}
+	for _, p := range list {
+		if err := b.SetWindowPos(p.hwnd, p.insertAfter, p.x, p.y, p.cx, p.cy, p.flags); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// origin returns the screen coordinates of the client area of w. b.mu must be held.
+func (b *Backend) origin(w *window) win32.POINT {
+	pt := win32.POINT{X: w.rect.Left, Y: w.rect.Top}
+	if w.child() {
+		if parent := b.windows[w.parent]; parent != nil {
+			o := b.origin(parent)
+			pt.X += o.X
+			pt.Y += o.Y
+		}
+	}
+	return pt
+}
+
+func (b *Backend) GetWindowRect(hwnd win32.HWND, rect *win32.RECT) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	w, err := b.window(hwnd)
+	if err != nil {
+		return err
+	}
+	o := b.origin(w)
+	*rect = win32.RECT{Left: o.X, Top: o.Y, Right: o.X + w.rect.Width(), Bottom: o.Y + w.rect.Height()}
+	return nil
+}
+
+func (b *Backend) GetClientRect(hwnd win32.HWND, rect *win32.RECT) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	w, err := b.window(hwnd)
+	if err != nil {
+		return err
+	}
+	*rect = w.clientRect()
+	return nil
+}
+
+func (b *Backend) ClientToScreen(hwnd win32.HWND, pt *win32.POINT) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	w, err := b.window(hwnd)
+	if err != nil {
+		return err
+	}
+	o := b.origin(w)
+	pt.X += o.X
+	pt.Y += o.Y
+	return nil
+}
+
+func (b *Backend) ScreenToClient(hwnd win32.HWND, pt *win32.POINT) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	w, err := b.window(hwnd)
+	if err != nil {
+		return err
+	}
+	o := b.origin(w)
+	pt.X -= o.X
+	pt.Y -= o.Y
+	return nil
+}
+
+// GetParent returns the parent of a child window, or the owner of a top-level
+// window of WS_POPUP.
+// GetDlgItem returns the child window of hwnd with the ID.
+func (b *Backend) GetDlgItem(hwnd win32.HWND, id win32.INT) (win32.HWND, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	w, err := b.window(hwnd)
+	if err != nil {
+		return 0, err
+	}
+	for _, child := range w.children {
+		if c := b.windows[child]; c != nil && c.id == win32.LONG_PTR(id) {
+			return child, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: control %v of HWND %#x", ErrNotFound, id, hwnd)
+}
+
+func (b *Backend) GetParent(hwnd win32.HWND) (win32.HWND, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	w, err := b.window(hwnd)
+	if err != nil {
+		return 0, err
+	}
+	if w.child() || w.style&win32.WS_POPUP != 0 {
+		return w.parent, nil
+	}
+	return 0, nil
+}
+
+// GetAncestor returns 0 for the parent of top-level windows, there is no desktop window.
+func (b *Backend) GetAncestor(hwnd win32.HWND, flags win32.GET_ANCESTOR_FLAG) (win32.HWND, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	w, err := b.window(hwnd)
+	if err != nil {
+		return 0, err
+	}
+	switch flags {
+	case win32.GA_PARENT:
+		if w.child() {
+			return w.parent, nil
+		}
+		return 0, nil
+	case win32.GA_ROOT:
+		for w.child() {
+			w = b.windows[w.parent]
+		}
+		return w.hwnd, nil
+	case win32.GA_ROOTOWNER:
+		for w.parent != 0 {
+			w = b.windows[w.parent]
+		}
+		return w.hwnd, nil
+	}
+	return 0, fmt.Errorf("%w: GetAncestor flags %v", ErrNotSupported, flags)
+}
+
+// GetActiveWindow returns the active window of the calling goroutine.
+func (b *Backend) GetActiveWindow() win32.HWND {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.thread().active
+}
+
+func (b *Backend) EnableWindow(hwnd win32.HWND, enable bool) bool {
+	b.mu.Lock()
+	w := b.windows[hwnd]
+	if w == nil {
+		b.mu.Unlock()
+		return false
+	}
+	wasDisabled := w.disabled
+	w.disabled = !enable
+	b.mu.Unlock()
+	if wasDisabled == enable {
+		b.deliver(hwnd, win32.WM_ENABLE, win32.WPARAM(boolToInt(enable)), 0)
+	}
+	return wasDisabled
+}
+
+func (b *Backend) IsWindowEnabled(hwnd win32.HWND) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	w := b.windows[hwnd]
+	return w != nil && !w.disabled
+}
+
+// SetWindowTextW sends WM_SETTEXT.
+func (b *Backend) SetWindowTextW(hwnd win32.HWND, str *win32.WCHAR) error {
+	r, err := b.send(hwnd, win32.WM_SETTEXT, 0, addr(unsafe.Pointer(str)))
+	runtime.KeepAlive(str)
+	if err == nil && r == 0 {
+		err = fmt.Errorf("fake: WM_SETTEXT of HWND %#x failed", hwnd)
+	}
+	return err
+}
+
+// GetWindowTextLengthW sends WM_GETTEXTLENGTH.
+func (b *Backend) GetWindowTextLengthW(hwnd win32.HWND) (int, error) {
+	r, err := b.send(hwnd, win32.WM_GETTEXTLENGTH, 0, 0)
+	return int(r), err
+}
+
+// GetWindowTextW sends WM_GETTEXT.
+func (b *Backend) GetWindowTextW(hwnd win32.HWND, buffer *win32.WCHAR, maxCount int) (int, error) {
+	r, err := b.send(hwnd, win32.WM_GETTEXT, win32.WPARAM(maxCount), addr(unsafe.Pointer(buffer)))
+	runtime.KeepAlive(buffer)
+	return int(r), err
+}
+
+// GetClassNameW copies the class name of hwnd, truncated to maxCount-1 characters.
+func (b *Backend) GetClassNameW(hwnd win32.HWND, className *win32.WCHAR, maxCount int) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	w, err := b.window(hwnd)
+	if err != nil {
+		return 0, err
+	}
+	return copyCString(className, maxCount, utf16.Encode([]rune(w.class.name))), nil
+}
+
+// InvalidateRect adds rect(the whole client area if nil) to the update region of hwnd(all windows if 0).
+// WM_PAINT is retrieved from the message queue while the update region is not empty.
+func (b *Backend) InvalidateRect(hwnd win32.HWND, rect *win32.RECT, erase bool) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var ws []*window
+	if hwnd == 0 {
+		for _, w := range b.windows {
+			ws = append(ws, w)
+		}
+	} else {
+		w, err := b.window(hwnd)
+		if err != nil {
+			return err
+		}
+		ws = append(ws, w)
+	}
+	for _, w := range ws {
+		r := w.clientRect()
+		if rect != nil && hwnd != 0 {
+			r = *rect
+		}
+		if w.invalid {
+			r = union(w.updateRect, r)
+		}
+		w.invalid, w.updateRect = true, r
+		w.erase = w.erase || erase
+		b.threads[w.thread].signal()
+	}
+	return nil
+}
+
+func union(a, b win32.RECT) win32.RECT {
+	return win32.RECT{Left: min(a.Left, b.Left), Top: min(a.Top, b.Top), Right: max(a.Right, b.Right), Bottom: max(a.Bottom, b.Bottom)}
+}
+
+// GetDpiForWindow returns the DPI of the window. See SetDPI.
+func (b *Backend) GetDpiForWindow(hwnd win32.HWND) (win32.UINT, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	w, err := b.window(hwnd)
+	if err != nil {
+		return 0, err
+	}
+	return w.dpi, nil
+}
+
+// SetDPI changes the DPI of the top-level window hwnd and its child windows,
+// as if the window was moved to a monitor of the DPI. WM_DPICHANGED is sent to hwnd
+// with the window rect scaled around its top-left corner, and WM_DPICHANGED_AFTERPARENT
+// is sent to the child windows. If hwnd is 0, the DPI of windows created later is changed.
+func (b *Backend) SetDPI(hwnd win32.HWND, dpi win32.UINT) error {
+	b.mu.Lock()
+	if hwnd == 0 {
+		b.dpi = dpi
+		b.mu.Unlock()
+		return nil
+	}
+	w, err := b.window(hwnd)
+	if err != nil {
+		b.mu.Unlock()
+		return err
+	}
+	if w.child() {
+		b.mu.Unlock()
+		return fmt.Errorf("%w: SetDPI of child window", ErrNotSupported)
+	}
+	old := w.dpi
+	var children []win32.HWND
+	var collect func(w *window)
+	collect = func(w *window) {
+		w.dpi = dpi
+		for _, h := range w.children {
+			children = append(children, h)
+			collect(b.windows[h])
+		}
+	}
+	collect(w)
+	suggested := &win32.RECT{
+		Left:   w.rect.Left,
+		Top:    w.rect.Top,
+		Right:  w.rect.Left + win32.LONG(win32.MulDiv(win32.INT(w.rect.Width()), win32.INT(dpi), win32.INT(old))),
+		Bottom: w.rect.Top + win32.LONG(win32.MulDiv(win32.INT(w.rect.Height()), win32.INT(dpi), win32.INT(old))),
+	}
+	b.mu.Unlock()
+
+	withLParam(suggested, func(lParam win32.LPARAM) win32.LRESULT {
+		return b.deliver(hwnd, win32.WM_DPICHANGED, win32.WPARAM(dpi)|win32.WPARAM(dpi)<<16, lParam)
+	})
+	for _, h := range children {
+		b.deliver(h, win32.WM_DPICHANGED_AFTERPARENT, 0, 0)
+	}
+	return nil
+}
+
+// KeyDown posts WM_KEYDOWN of the key vk to hwnd, or WM_SYSKEYDOWN if VK_MENU(Alt)
+// is down. The key state changes when the message is retrieved, see KeyState.
+func (b *Backend) KeyDown(hwnd win32.HWND, vk win32.WORD) error {
+	return b.key(hwnd, vk, true)
+}
+
+// KeyUp posts WM_KEYUP of the key vk to hwnd, or WM_SYSKEYUP if VK_MENU(Alt)
+// is down. The key state changes when the message is retrieved, see KeyState.
+func (b *Backend) KeyUp(hwnd win32.HWND, vk win32.WORD) error {
+	return b.key(hwnd, vk, false)
+}
+
+func (b *Backend) key(hwnd win32.HWND, vk win32.WORD, down bool) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	w, err := b.window(hwnd)
+	if err != nil {
+		return err
+	}
+	var message win32.UINT = win32.WM_KEYUP
+	if down {
+		message = win32.WM_KEYDOWN
+	}
+	if b.asyncKeys[win32.VK_MENU] || vk == win32.VK_MENU && down {
+		message += win32.WM_SYSKEYDOWN - win32.WM_KEYDOWN
+	}
+	// Repeat count 1, with the context code, previous key state and transition state bits.
+	var flags win32.INT
+	if message == win32.WM_SYSKEYDOWN || message == win32.WM_SYSKEYUP {
+		flags |= 0x2000
+	}
+	if !down {
+		flags |= 0xC000
+	} else if b.asyncKeys[vk&0xFF] {
+		flags |= 0x4000 // Auto-repeat.
+	}
+	b.asyncKeys[vk&0xFF] = down
+	b.post(b.threads[w.thread], hwnd, message, win32.WPARAM(vk), makeLParam[win32.INT](1, flags))
+	return nil
+}
+
+// KeyState reports whether the key vk is down as of the last key message retrieved
+// from the message queue, as GetKeyState does. See KeyDown.
+func (b *Backend) KeyState(vk win32.WORD) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.keys[vk&0xFF]
+}
+
+// GetKeyState returns a value with the high-order bit set if the key vk is down,
+// see KeyState. Toggle states are not simulated.
+func (b *Backend) GetKeyState(vk win32.INT) win32.SHORT {
+	if b.KeyState(win32.WORD(vk)) {
+		return -0x8000
+	}
+	return 0
+}
+
+// GetModuleHandleW returns the same fake module handle for all modules.
+func (b *Backend) GetModuleHandleW(moduleName *win32.WCHAR) (win32.HMODULE, error) {
+	return 0x400000, nil
+}
+
+// LoadImageW returns a new handle. No image is loaded.
+func (b *Backend) LoadImageW(instance win32.HINSTANCE, name *win32.WCHAR, imageType win32.UINT, cx win32.INT, cy win32.INT, flag win32.UINT) (win32.HANDLE, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.newHandle(), nil
+}
+
+// LoadImageW_uintptr returns a new handle. No image is loaded.
+func (b *Backend) LoadImageW_uintptr(instance win32.HINSTANCE, name uintptr, imageType win32.UINT, cx win32.INT, cy win32.INT, flag win32.UINT) (win32.HANDLE, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.newHandle(), nil
+}
+
+// GetSystemMetrics returns the value set by SetSystemMetrics, or 0.
+func (b *Backend) GetSystemMetrics(index win32.SystemMetricsIndex) win32.INT {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.metrics[index]
+}
+
+// GetSysColor returns a gray color for all indexes.
+func (b *Backend) GetSysColor(index int) win32.DWORD {
+	return 0xF0F0F0
+}
+
+// GetCursorPos returns the position set by SetCursorPos.
+func (b *Backend) GetCursorPos() (*win32.POINT, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	pt := b.cursorPos
+	return &pt, nil
+}
+
+// GetDialogBaseUnits returns the base units of an 8x16 font.
+func (b *Backend) GetDialogBaseUnits() win32.LONG {
+	return win32.MAKELONG[win32.WORD](8, 16)
+}
+
+// SystemParametersInfoForDpi supports SPI_GETNONCLIENTMETRICS only.
+// All the fonts are 9pt "Segoe UI".
+func (b *Backend) SystemParametersInfoForDpi(action win32.UINT, param win32.UINT, p win32.PVOID, winIni win32.UINT, dpi win32.UINT) error {
+	if action != win32.SPI_GETNONCLIENTMETRICS {
+		return fmt.Errorf("%w: SystemParametersInfoForDpi action %#x", ErrNotSupported, action)
+	}
+	m := (*win32.NONCLIENTMETRICSW)(p)
+	font := win32.LOGFONTW{Height: win32.LONG(-win32.MulDiv(9, win32.INT(dpi), 72)), Weight: 400}
+	copy(font.FaceName[:], utf16ToWCHAR(utf16.Encode([]rune("Segoe UI"))))
+	m.CaptionFont, m.SmCaptionFont, m.MenuFont, m.StatusFont, m.MessageFont = font, font, font, font, font
+	return nil
+}
+
+func utf16ToWCHAR(s []uint16) []win32.WCHAR {
+	r := make([]win32.WCHAR, len(s))
+	for i, c := range s {
+		r[i] = win32.WCHAR(c)
+	}
+	return r
+}
+
+// MessageBoxExW calls Backend.MessageBox.
+func (b *Backend) MessageBoxExW(owner win32.HWND, text *win32.WCHAR, caption *win32.WCHAR, typ win32.MESSAGE_BOX_TYPE, lang win32.WORD) (win32.INT, error) {
+	if b.MessageBox == nil {
+		return win32.IDOK, nil
+	}
+	return b.MessageBox(owner, goString(text), goString(caption), typ), nil
+}
@@ -0,0 +1,261 @@
+package win32
+
+// List view styles.
+const (
+	LVS_ICON            = 0x0000
+	LVS_REPORT          = 0x0001
+	LVS_SMALLICON       = 0x0002
+	LVS_LIST            = 0x0003
+	LVS_TYPEMASK        = 0x0003
+	LVS_SINGLESEL       = 0x0004
+	LVS_SHOWSELALWAYS   = 0x0008
+	LVS_SORTASCENDING   = 0x0010
+	LVS_SORTDESCENDING  = 0x0020
+	LVS_SHAREIMAGELISTS = 0x0040
+	LVS_NOLABELWRAP     = 0x0080
+	LVS_AUTOARRANGE     = 0x0100
+	LVS_EDITLABELS      = 0x0200
+	LVS_OWNERDRAWFIXED  = 0x0400
+	LVS_ALIGNLEFT       = 0x0800
+	LVS_OWNERDATA       = 0x1000
+	LVS_NOSCROLL        = 0x2000
+	LVS_NOCOLUMNHEADER  = 0x4000
+	LVS_NOSORTHEADER    = 0x8000
+)
+
+// List view extended styles.
+const (
+	LVS_EX_GRIDLINES        = 0x00000001
+	LVS_EX_SUBITEMIMAGES    = 0x00000002
+	LVS_EX_CHECKBOXES       = 0x00000004
+	LVS_EX_TRACKSELECT      = 0x00000008
+	LVS_EX_HEADERDRAGDROP   = 0x00000010
+	LVS_EX_FULLROWSELECT    = 0x00000020
+	LVS_EX_ONECLICKACTIVATE = 0x00000040
+	LVS_EX_TWOCLICKACTIVATE = 0x00000080
+	LVS_EX_INFOTIP          = 0x00000400
+	LVS_EX_LABELTIP         = 0x00004000
+	LVS_EX_DOUBLEBUFFER     = 0x00010000
+)
+
+// List view messages.
+const (
+	LVM_FIRST                    = 0x1000
+	LVM_SETIMAGELIST             = LVM_FIRST + 3
+	LVM_GETITEMCOUNT             = LVM_FIRST + 4
+	LVM_DELETEITEM               = LVM_FIRST + 8
+	LVM_DELETEALLITEMS           = LVM_FIRST + 9
+	LVM_GETCALLBACKMASK          = LVM_FIRST + 10
+	LVM_SETCALLBACKMASK          = LVM_FIRST + 11
+	LVM_GETNEXTITEM              = LVM_FIRST + 12
+	LVM_HITTEST                  = LVM_FIRST + 18
+	LVM_ENSUREVISIBLE            = LVM_FIRST + 19
+	LVM_REDRAWITEMS              = LVM_FIRST + 21
+	LVM_DELETECOLUMN             = LVM_FIRST + 28
+	LVM_GETCOLUMNWIDTH           = LVM_FIRST + 29
+	LVM_SETCOLUMNWIDTH           = LVM_FIRST + 30
+	LVM_GETHEADER                = LVM_FIRST + 31
+	LVM_GETTOPINDEX              = LVM_FIRST + 39
+	LVM_GETCOUNTPERPAGE          = LVM_FIRST + 40
+	LVM_SETITEMSTATE             = LVM_FIRST + 43
+	LVM_GETITEMSTATE             = LVM_FIRST + 44
+	LVM_SETITEMCOUNT             = LVM_FIRST + 47
+	LVM_GETSELECTEDCOUNT         = LVM_FIRST + 50
+	LVM_SETEXTENDEDLISTVIEWSTYLE = LVM_FIRST + 54
+	LVM_GETEXTENDEDLISTVIEWSTYLE = LVM_FIRST + 55
+	LVM_SUBITEMHITTEST           = LVM_FIRST + 57
+	LVM_GETITEMW                 = LVM_FIRST + 75
+	LVM_SETITEMW                 = LVM_FIRST + 76
+	LVM_INSERTITEMW              = LVM_FIRST + 77
+	LVM_GETCOLUMNW               = LVM_FIRST + 95
+	LVM_SETCOLUMNW               = LVM_FIRST + 96
+	LVM_INSERTCOLUMNW            = LVM_FIRST + 97
+	LVM_GETITEMTEXTW             = LVM_FIRST + 115
+	LVM_SETITEMTEXTW             = LVM_FIRST + 116
+)
+
+// List view notification codes. The codes are negative numbers in UINT.
+const (
+	LVN_ITEMCHANGING    = 0xFFFFFF9C // LVN_FIRST - 0
+	LVN_ITEMCHANGED     = 0xFFFFFF9B // LVN_FIRST - 1
+	LVN_INSERTITEM      = 0xFFFFFF9A // LVN_FIRST - 2
+	LVN_DELETEITEM      = 0xFFFFFF99 // LVN_FIRST - 3
+	LVN_DELETEALLITEMS  = 0xFFFFFF98 // LVN_FIRST - 4
+	LVN_COLUMNCLICK     = 0xFFFFFF94 // LVN_FIRST - 8
+	LVN_BEGINDRAG       = 0xFFFFFF93 // LVN_FIRST - 9
+	LVN_ODCACHEHINT     = 0xFFFFFF8F // LVN_FIRST - 13
+	LVN_ITEMACTIVATE    = 0xFFFFFF8E // LVN_FIRST - 14
+	LVN_ODSTATECHANGED  = 0xFFFFFF8D // LVN_FIRST - 15
+	LVN_KEYDOWN         = 0xFFFFFF65 // LVN_FIRST - 55
+	LVN_BEGINLABELEDITW = 0xFFFFFF51 // LVN_FIRST - 75
+	LVN_ENDLABELEDITW   = 0xFFFFFF50 // LVN_FIRST - 76
+	LVN_GETDISPINFOW    = 0xFFFFFF4F // LVN_FIRST - 77
+	LVN_SETDISPINFOW    = 0xFFFFFF4E // LVN_FIRST - 78
+	LVN_ODFINDITEMW     = 0xFFFFFF4D // LVN_FIRST - 79
+)
+
+// List view column masks.
+const (
+	LVCF_FMT     = 0x0001
+	LVCF_WIDTH   = 0x0002
+	LVCF_TEXT    = 0x0004
+	LVCF_SUBITEM = 0x0008
+	LVCF_IMAGE   = 0x0010
+	LVCF_ORDER   = 0x0020
+)
+
+// List view column formats.
+const (
+	LVCFMT_LEFT   = 0x0000
+	LVCFMT_RIGHT  = 0x0001
+	LVCFMT_CENTER = 0x0002
+)
+
+// List view item masks.
+const (
+	LVIF_TEXT        = 0x0001
+	LVIF_IMAGE       = 0x0002
+	LVIF_PARAM       = 0x0004
+	LVIF_STATE       = 0x0008
+	LVIF_INDENT      = 0x0010
+	LVIF_COLUMNS     = 0x0200
+	LVIF_NORECOMPUTE = 0x0800
+)
+
+// List view item states.
+const (
+	LVIS_FOCUSED        = 0x0001
+	LVIS_SELECTED       = 0x0002
+	LVIS_CUT            = 0x0004
+	LVIS_DROPHILITED    = 0x0008
+	LVIS_OVERLAYMASK    = 0x0F00
+	LVIS_STATEIMAGEMASK = 0xF000
+)
+
+// Flags of LVM_GETNEXTITEM.
+const (
+	LVNI_ALL      = 0x0000
+	LVNI_FOCUSED  = 0x0001
+	LVNI_SELECTED = 0x0002
+)
+
+// Image lists of LVM_SETIMAGELIST.
+const (
+	LVSIL_NORMAL = 0
+	LVSIL_SMALL  = 1
+	LVSIL_STATE  = 2
+)
+
+// Flags of LVM_SETITEMCOUNT.
+const (
+	LVSICF_NOINVALIDATEALL = 0x00000001
+	LVSICF_NOSCROLL        = 0x00000002
+)
+
+// Flags of LVHITTESTINFO.
+const (
+	LVHT_NOWHERE         = 0x0001
+	LVHT_ONITEMICON      = 0x0002
+	LVHT_ONITEMLABEL     = 0x0004
+	LVHT_ONITEMSTATEICON = 0x0008
+	LVHT_ONITEM          = LVHT_ONITEMICON | LVHT_ONITEMLABEL | LVHT_ONITEMSTATEICON
+	LVHT_ABOVE           = 0x0008
+	LVHT_BELOW           = 0x0010
+	LVHT_TORIGHT         = 0x0020
+	LVHT_TOLEFT          = 0x0040
+)
+
+const (
+	LPSTR_TEXTCALLBACKW = ^uintptr(0) // (LPWSTR)-1
+	I_IMAGECALLBACK     = -1
+	I_IMAGENONE         = -2
+)
+
+type LVCOLUMNW struct {
+	Mask      UINT
+	Fmt       INT
+	Cx        INT
+	Text      *WCHAR
+	TextMax   INT
+	SubItem   INT
+	Image     INT
+	Order     INT
+	CxMin     INT
+	CxDefault INT
+	CxIdeal   INT
+}
+
+type LVITEMW struct {
+	Mask      UINT
+	Item      INT
+	SubItem   INT
+	State     UINT
+	StateMask UINT
+	Text      *WCHAR
+	TextMax   INT
+	Image     INT
+	LParam    LPARAM
+	Indent    INT
+	GroupId   INT
+	Columns   UINT
+	ColumnIDs *UINT
+	ColFmt    *INT
+	Group     INT
+}
+
+type LVHITTESTINFO struct {
+	Pt      POINT
+	Flags   UINT
+	Item    INT
+	SubItem INT
+	Group   INT
+}
+
+type NMLISTVIEW struct {
+	Hdr      NMHDR
+	Item     INT
+	SubItem  INT
+	NewState UINT
+	OldState UINT
+	Changed  UINT
+	Action   POINT
+	LParam   LPARAM
+}
+
+type NMITEMACTIVATE struct {
+	Hdr      NMHDR
+	Item     INT
+	SubItem  INT
+	NewState UINT
+	OldState UINT
+	Changed  UINT
+	Action   POINT
+	LParam   LPARAM
+	KeyFlags UINT
+}
+
+type NMLVDISPINFOW struct {
+	Hdr  NMHDR
+	Item LVITEMW
+}
+
+type NMLVCACHEHINT struct {
+	Hdr  NMHDR
+	From INT
+	To   INT
+}
+
+type NMLVODSTATECHANGE struct {
+	Hdr      NMHDR
+	From     INT
+	To       INT
+	NewState UINT
+	OldState UINT
+}
+
+// NMLVKEYDOWN is packed in C. The flags field after VKey is omitted,
+// because it is not aligned.
+type NMLVKEYDOWN struct {
+	Hdr  NMHDR
+	VKey WORD
+}
@@ -4,6 +4,7 @@ const (
 	WM_NULL                    = 0x0000
 	WM_CREATE                  = 0x0001
 	WM_DESTROY                 = 0x0002
+	WM_MOVE                    = 0x0003
 	WM_SIZE                    = 0x0005
 	WM_ACTIVATE                = 0x0006
 	WM_SETFOCUS                = 0x0007
@@ -16,13 +17,35 @@ const (
 	WM_PAINT                   = 0x000F
 	WM_CLOSE                   = 0x0010
 	WM_QUIT                    = 0x0012
+	WM_ERASEBKGND              = 0x0014
+	WM_SHOWWINDOW              = 0x0018
+	WM_CANCELMODE              = 0x001F
+	WM_SETCURSOR               = 0x0020
+	WM_DRAWITEM                = 0x002B
+	WM_MEASUREITEM             = 0x002C
+	WM_DELETEITEM              = 0x002D
+	WM_NOTIFY                  = 0x004E
 	WM_CONTEXTMENU             = 0x007B
 	WM_STYLECHANGING           = 0x007C
 	WM_STYLECHANGED            = 0x007D
 	WM_DISPLAYCHANGE           = 0x007E
 	WM_GETICON                 = 0x007F
 	WM_SETICON                 = 0x0080
+	WM_KEYDOWN                 = 0x0100
+	WM_KEYUP                   = 0x0101
+	WM_CHAR                    = 0x0102
+	WM_DEADCHAR                = 0x0103
+	WM_SYSKEYDOWN              = 0x0104
+	WM_SYSKEYUP                = 0x0105
+	WM_SYSCHAR                 = 0x0106
+	WM_SYSDEADCHAR             = 0x0107
 	WM_COMMAND                 = 0x0111
+	WM_SYSCOMMAND              = 0x0112
+	WM_TIMER                   = 0x0113
+	WM_HSCROLL                 = 0x0114
+	WM_VSCROLL                 = 0x0115
+	WM_INITMENUPOPUP           = 0x0117
+	WM_NCCREATE                = 0x0081
 	WM_NCDESTROY               = 0x0082
 	WM_MOUSEFIRST              = 0x0200
 	WM_MOUSEMOVE               = 0x0200
@@ -50,6 +73,12 @@ const (
 	WM_DPICHANGED_AFTERPARENT  = 0x02E3
 	WM_GETDPISCALEDSIZE        = 0x02E4
 	WM_SIZING                  = 0x0214
+	WM_CAPTURECHANGED          = 0x0215
+	WM_ENTERSIZEMOVE           = 0x0231
+	WM_EXITSIZEMOVE            = 0x0232
+	WM_MOUSEHOVER              = 0x02A1
+	WM_MOUSELEAVE              = 0x02A3
+	WM_NCMOUSELEAVE            = 0x02A2
 	WM_NCLBUTTONDOWN           = 0x00A1
 	WM_NCHITTEST               = 0x0084
 
@@ -132,3 +161,39 @@ const (
 	MK_XBUTTON1 = 0x0020
 	MK_XBUTTON2 = 0x0040
 )
+
+// HIWORD of wParam of WM_XBUTTONDOWN, WM_XBUTTONUP and WM_XBUTTONDBLCLK.
+const (
+	XBUTTON1 = 0x0001
+	XBUTTON2 = 0x0002
+)
+
+// Distance of one wheel notch of WM_MOUSEWHEEL and WM_MOUSEHWHEEL.
+const WHEEL_DELTA = 120
+
+// wParam of WM_SYSCOMMAND.
+const (
+	SC_SIZE     = 0xF000
+	SC_MOVE     = 0xF010
+	SC_MINIMIZE = 0xF020
+	SC_MAXIMIZE = 0xF030
+	SC_CLOSE    = 0xF060
+	SC_KEYMENU  = 0xF100
+	SC_RESTORE  = 0xF120
+)
+
+// wParam of WM_SIZE.
+const (
+	SIZE_RESTORED  = 0
+	SIZE_MINIMIZED = 1
+	SIZE_MAXIMIZED = 2
+	SIZE_MAXSHOW   = 3
+	SIZE_MAXHIDE   = 4
+)
+
+// wParam of WM_ACTIVATE.
+const (
+	WA_INACTIVE    = 0
+	WA_ACTIVE      = 1
+	WA_CLICKACTIVE = 2
+)
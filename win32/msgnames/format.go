@@ -0,0 +1,399 @@
+package msgnames
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/mkch/gw/win32"
+)
+
+// Handle is a handle or an address parameter, formatted in hexadecimal.
+type Handle uintptr
+
+func (h Handle) String() string {
+	return fmt.Sprintf("%#x", uintptr(h))
+}
+
+// Param is a decoded parameter of a message.
+// Value is an int, a bool, a string, a Handle or a Point.
+type Param struct {
+	Name  string
+	Value any
+}
+
+// Point is a point parameter.
+type Point struct {
+	X, Y int
+}
+
+func (pt Point) String() string {
+	return fmt.Sprintf("(%d,%d)", pt.X, pt.Y)
+}
+
+// Message is a decoded window message.
+type Message struct {
+	Hwnd   win32.HWND
+	ID     win32.UINT
+	Name   string
+	Params []Param
+}
+
+// Param returns the value of the named parameter.
+func (m *Message) Param(name string) (value any, ok bool) {
+	for _, p := range m.Params {
+		if p.Name == name {
+			return p.Value, true
+		}
+	}
+	return nil, false
+}
+
+// String returns m in a form like "WM_SIZE hwnd=0x10 type=SIZE_RESTORED width=640 height=480".
+func (m *Message) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s hwnd=%v", m.Name, Handle(m.Hwnd))
+	for _, p := range m.Params {
+		fmt.Fprintf(&b, " %s=%v", p.Name, p.Value)
+	}
+	return b.String()
+}
+
+// LogValue implements [slog.LogValuer].
+func (m *Message) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("name", m.Name),
+		slog.String("hwnd", Handle(m.Hwnd).String()),
+	}
+	for _, p := range m.Params {
+		if s, ok := p.Value.(fmt.Stringer); ok {
+			attrs = append(attrs, slog.String(p.Name, s.String()))
+		} else {
+			attrs = append(attrs, slog.Any(p.Name, p.Value))
+		}
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// Format decodes msg. The wParam and lParam of the common messages are
+// decoded into named parameters, the raw values are returned for other messages.
+// classOf returns the window class name of a window, it is used to name the
+// notification codes of WM_COMMAND sent by the standard controls.
+// classOf can be nil.
+func Format(msg *win32.MSG, classOf func(hwnd win32.HWND) string) Message {
+	return Message{
+		Hwnd:   msg.Hwnd,
+		ID:     msg.Message,
+		Name:   Name(msg.Message),
+		Params: params(msg.Message, msg.WParam, msg.LParam, classOf),
+	}
+}
+
+func params(message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM, classOf func(hwnd win32.HWND) string) []Param {
+	lo, hi := int(win32.LOWORD(wParam)), int(win32.HIWORD(wParam))
+	switch message {
+	case win32.WM_CREATE, win32.WM_DESTROY, win32.WM_NCDESTROY, win32.WM_CLOSE,
+		win32.WM_PAINT, win32.WM_MOUSELEAVE, win32.WM_NCMOUSELEAVE,
+		win32.WM_CANCELMODE, win32.WM_ENTERSIZEMOVE, win32.WM_EXITSIZEMOVE:
+		return nil
+	case win32.WM_MOVE:
+		return []Param{{"pt", point(lParam)}}
+	case win32.WM_SIZE:
+		return []Param{
+			{"type", lookup(sizeTypes, uint(wParam))},
+			{"width", int(win32.LOWORD(uintptr(lParam)))},
+			{"height", int(win32.HIWORD(uintptr(lParam)))},
+		}
+	case win32.WM_ACTIVATE:
+		return []Param{{"state", lookup(activeStates, uint(lo))}, {"minimized", hi != 0}, {"other", Handle(lParam)}}
+	case win32.WM_SETFOCUS, win32.WM_KILLFOCUS:
+		return []Param{{"other", Handle(wParam)}}
+	case win32.WM_ENABLE:
+		return []Param{{"enabled", wParam != 0}}
+	case win32.WM_SHOWWINDOW:
+		return []Param{{"show", wParam != 0}, {"status", int(lParam)}}
+	case win32.WM_SETFONT:
+		return []Param{{"font", Handle(wParam)}, {"redraw", lParam != 0}}
+	case win32.WM_ERASEBKGND:
+		return []Param{{"hdc", Handle(wParam)}}
+	case win32.WM_KEYDOWN, win32.WM_KEYUP, win32.WM_SYSKEYDOWN, win32.WM_SYSKEYUP:
+		return append([]Param{{"vk", vkName(wParam)}}, keyData(lParam)...)
+	case win32.WM_CHAR, win32.WM_SYSCHAR, win32.WM_DEADCHAR, win32.WM_SYSDEADCHAR:
+		return append([]Param{{"char", fmt.Sprintf("%q", rune(wParam))}}, keyData(lParam)...)
+	case win32.WM_COMMAND:
+		return []Param{{"id", lo}, {"code", commandCode(hi, win32.HWND(lParam), classOf)}, {"ctrl", Handle(lParam)}}
+	case win32.WM_SYSCOMMAND:
+		return []Param{{"cmd", lookup(sysCommands, uint(wParam)&0xFFF0)}, {"pt", point(lParam)}}
+	case win32.WM_TIMER:
+		return []Param{{"id", int(wParam)}, {"proc", Handle(lParam)}}
+	case win32.WM_HSCROLL, win32.WM_VSCROLL:
+		return []Param{{"request", lookup(scrollRequests, uint(lo))}, {"pos", hi}, {"ctrl", Handle(lParam)}}
+	case win32.WM_CTLCOLORMSGBOX, win32.WM_CTLCOLOREDIT, win32.WM_CTLCOLORLISTBOX, win32.WM_CTLCOLORBTN,
+		win32.WM_CTLCOLORDLG, win32.WM_CTLCOLORSCROLLBAR, win32.WM_CTLCOLORSTATIC:
+		return []Param{{"hdc", Handle(wParam)}, {"ctrl", Handle(lParam)}}
+	case win32.WM_MOUSEMOVE,
+		win32.WM_LBUTTONDOWN, win32.WM_LBUTTONUP, win32.WM_LBUTTONDBLCLK,
+		win32.WM_RBUTTONDOWN, win32.WM_RBUTTONUP, win32.WM_RBUTTONDBLCLK,
+		win32.WM_MBUTTONDOWN, win32.WM_MBUTTONUP, win32.WM_MBUTTONDBLCLK,
+		win32.WM_MOUSEHOVER:
+		return []Param{{"keys", flags(mouseKeys, uint(wParam))}, {"pt", point(lParam)}}
+	case win32.WM_XBUTTONDOWN, win32.WM_XBUTTONUP, win32.WM_XBUTTONDBLCLK:
+		return []Param{{"keys", flags(mouseKeys, uint(lo))}, {"button", hi}, {"pt", point(lParam)}}
+	case win32.WM_MOUSEWHEEL, win32.WM_MOUSEHWHEEL:
+		return []Param{{"keys", flags(mouseKeys, uint(lo))}, {"delta", int(int16(hi))}, {"pt", point(lParam)}}
+	case win32.WM_NCHITTEST:
+		return []Param{{"pt", point(lParam)}}
+	case win32.WM_CONTEXTMENU:
+		return []Param{{"wnd", Handle(wParam)}, {"pt", point(lParam)}}
+	case win32.WM_SETCURSOR:
+		return []Param{
+			{"wnd", Handle(wParam)},
+			{"hittest", int(int16(win32.LOWORD(uintptr(lParam))))},
+			{"mouse", Name(win32.UINT(win32.HIWORD(uintptr(lParam))))},
+		}
+	case win32.WM_DPICHANGED:
+		return []Param{{"xdpi", lo}, {"ydpi", hi}, {"rect", Handle(lParam)}}
+	case win32.WM_QUIT:
+		return []Param{{"code", int(wParam)}}
+	}
+	return []Param{{"wParam", Handle(wParam)}, {"lParam", Handle(lParam)}}
+}
+
+func point(lParam win32.LPARAM) Point {
+	return Point{win32.GET_X_LPARAM(lParam), win32.GET_Y_LPARAM(lParam)}
+}
+
+// keyData decodes the lParam of keystroke messages.
+func keyData(lParam win32.LPARAM) []Param {
+	l := uint32(lParam)
+	return []Param{
+		{"repeat", int(l & 0xFFFF)},
+		{"scan", int(l >> 16 & 0xFF)},
+		{"extended", l&(1<<24) != 0},
+		{"alt", l&(1<<29) != 0},
+		{"wasDown", l&(1<<30) != 0},
+		{"up", l&(1<<31) != 0},
+	}
+}
+
+func vkName(vk win32.WPARAM) string {
+	if vk >= '0' && vk <= '9' || vk >= 'A' && vk <= 'Z' {
+		return string(rune(vk))
+	}
+	if vk >= 0x70 && vk <= 0x87 {
+		return fmt.Sprintf("VK_F%d", vk-0x70+1)
+	}
+	if vk >= 0x60 && vk <= 0x69 {
+		return fmt.Sprintf("VK_NUMPAD%d", vk-0x60)
+	}
+	return lookup(vkNames, uint(vk))
+}
+
+func commandCode(code int, ctrl win32.HWND, classOf func(hwnd win32.HWND) string) string {
+	if ctrl == 0 {
+		switch code {
+		case 0:
+			return "menu"
+		case 1:
+			return "accelerator"
+		}
+	} else if classOf != nil {
+		if codes := notifyCodes[strings.ToLower(classOf(ctrl))]; codes != nil {
+			return lookup(codes, uint(code))
+		}
+	}
+	return fmt.Sprintf("%#x", code)
+}
+
+// lookup returns the name of v in names, or v in hexadecimal if not found.
+func lookup(names map[uint]string, v uint) string {
+	if name, ok := names[v]; ok {
+		return name
+	}
+	return fmt.Sprintf("%#x", v)
+}
+
+type flagName struct {
+	flag uint
+	name string
+}
+
+// flags returns the names of the bits set in v, separated by "|".
+func flags(names []flagName, v uint) string {
+	var s []string
+	for _, f := range names {
+		if v&f.flag != 0 {
+			s = append(s, f.name)
+			v &^= f.flag
+		}
+	}
+	if v != 0 {
+		s = append(s, fmt.Sprintf("%#x", v))
+	}
+	if len(s) == 0 {
+		return "0"
+	}
+	return strings.Join(s, "|")
+}
+
+var sizeTypes = map[uint]string{
+	win32.SIZE_RESTORED:  "SIZE_RESTORED",
+	win32.SIZE_MINIMIZED: "SIZE_MINIMIZED",
+	win32.SIZE_MAXIMIZED: "SIZE_MAXIMIZED",
+	win32.SIZE_MAXSHOW:   "SIZE_MAXSHOW",
+	win32.SIZE_MAXHIDE:   "SIZE_MAXHIDE",
+}
+
+var activeStates = map[uint]string{
+	win32.WA_INACTIVE:    "WA_INACTIVE",
+	win32.WA_ACTIVE:      "WA_ACTIVE",
+	win32.WA_CLICKACTIVE: "WA_CLICKACTIVE",
+}
+
+var mouseKeys = []flagName{
+	{win32.MK_LBUTTON, "MK_LBUTTON"},
+	{win32.MK_RBUTTON, "MK_RBUTTON"},
+	{win32.MK_SHIFT, "MK_SHIFT"},
+	{win32.MK_CONTROL, "MK_CONTROL"},
+	{win32.MK_MBUTTON, "MK_MBUTTON"},
+	{win32.MK_XBUTTON1, "MK_XBUTTON1"},
+	{win32.MK_XBUTTON2, "MK_XBUTTON2"},
+}
+
+var sysCommands = map[uint]string{
+	0xF000: "SC_SIZE",
+	0xF010: "SC_MOVE",
+	0xF020: "SC_MINIMIZE",
+	0xF030: "SC_MAXIMIZE",
+	0xF040: "SC_NEXTWINDOW",
+	0xF050: "SC_PREVWINDOW",
+	0xF060: "SC_CLOSE",
+	0xF070: "SC_VSCROLL",
+	0xF080: "SC_HSCROLL",
+	0xF090: "SC_MOUSEMENU",
+	0xF100: "SC_KEYMENU",
+	0xF120: "SC_RESTORE",
+	0xF130: "SC_TASKLIST",
+	0xF140: "SC_SCREENSAVE",
+	0xF150: "SC_HOTKEY",
+	0xF160: "SC_DEFAULT",
+	0xF170: "SC_MONITORPOWER",
+	0xF180: "SC_CONTEXTHELP",
+}
+
+var scrollRequests = map[uint]string{
+	0: "SB_LINEUP",
+	1: "SB_LINEDOWN",
+	2: "SB_PAGEUP",
+	3: "SB_PAGEDOWN",
+	4: "SB_THUMBPOSITION",
+	5: "SB_THUMBTRACK",
+	6: "SB_TOP",
+	7: "SB_BOTTOM",
+	8: "SB_ENDSCROLL",
+}
+
+var vkNames = map[uint]string{
+	0x01: "VK_LBUTTON",
+	0x02: "VK_RBUTTON",
+	0x03: "VK_CANCEL",
+	0x04: "VK_MBUTTON",
+	0x05: "VK_XBUTTON1",
+	0x06: "VK_XBUTTON2",
+	0x08: "VK_BACK",
+	0x09: "VK_TAB",
+	0x0C: "VK_CLEAR",
+	0x0D: "VK_RETURN",
+	0x10: "VK_SHIFT",
+	0x11: "VK_CONTROL",
+	0x12: "VK_MENU",
+	0x13: "VK_PAUSE",
+	0x14: "VK_CAPITAL",
+	0x1B: "VK_ESCAPE",
+	0x20: "VK_SPACE",
+	0x21: "VK_PRIOR",
+	0x22: "VK_NEXT",
+	0x23: "VK_END",
+	0x24: "VK_HOME",
+	0x25: "VK_LEFT",
+	0x26: "VK_UP",
+	0x27: "VK_RIGHT",
+	0x28: "VK_DOWN",
+	0x2C: "VK_SNAPSHOT",
+	0x2D: "VK_INSERT",
+	0x2E: "VK_DELETE",
+	0x2F: "VK_HELP",
+	0x5B: "VK_LWIN",
+	0x5C: "VK_RWIN",
+	0x5D: "VK_APPS",
+	0x6A: "VK_MULTIPLY",
+	0x6B: "VK_ADD",
+	0x6C: "VK_SEPARATOR",
+	0x6D: "VK_SUBTRACT",
+	0x6E: "VK_DECIMAL",
+	0x6F: "VK_DIVIDE",
+	0x90: "VK_NUMLOCK",
+	0x91: "VK_SCROLL",
+	0xA0: "VK_LSHIFT",
+	0xA1: "VK_RSHIFT",
+	0xA2: "VK_LCONTROL",
+	0xA3: "VK_RCONTROL",
+	0xA4: "VK_LMENU",
+	0xA5: "VK_RMENU",
+}
+
+// notifyCodes are the WM_COMMAND notification codes of the standard
+// controls, keyed by lower case class name.
+var notifyCodes = map[string]map[uint]string{
+	"button": {
+		0: "BN_CLICKED",
+		1: "BN_PAINT",
+		2: "BN_HILITE",
+		3: "BN_UNHILITE",
+		4: "BN_DISABLE",
+		5: "BN_DOUBLECLICKED",
+		6: "BN_SETFOCUS",
+		7: "BN_KILLFOCUS",
+	},
+	"edit": {
+		0x0100: "EN_SETFOCUS",
+		0x0200: "EN_KILLFOCUS",
+		0x0300: "EN_CHANGE",
+		0x0400: "EN_UPDATE",
+		0x0500: "EN_ERRSPACE",
+		0x0501: "EN_MAXTEXT",
+		0x0601: "EN_HSCROLL",
+		0x0602: "EN_VSCROLL",
+		0x0700: "EN_ALIGN_LTR_EC",
+		0x0701: "EN_ALIGN_RTL_EC",
+		0x0800: "EN_BEFORE_PASTE",
+		0x0801: "EN_AFTER_PASTE",
+	},
+	"listbox": {
+		0xFFFE: "LBN_ERRSPACE",
+		1:      "LBN_SELCHANGE",
+		2:      "LBN_DBLCLK",
+		3:      "LBN_SELCANCEL",
+		4:      "LBN_SETFOCUS",
+		5:      "LBN_KILLFOCUS",
+	},
+	"combobox": {
+		0xFFFF: "CBN_ERRSPACE",
+		1:      "CBN_SELCHANGE",
+		2:      "CBN_DBLCLK",
+		3:      "CBN_SETFOCUS",
+		4:      "CBN_KILLFOCUS",
+		5:      "CBN_EDITCHANGE",
+		6:      "CBN_EDITUPDATE",
+		7:      "CBN_DROPDOWN",
+		8:      "CBN_CLOSEUP",
+		9:      "CBN_SELENDOK",
+		10:     "CBN_SELENDCANCEL",
+	},
+	"static": {
+		0: "STN_CLICKED",
+		1: "STN_DBLCLK",
+		2: "STN_ENABLE",
+		3: "STN_DISABLE",
+	},
+}
@@ -0,0 +1,68 @@
+package msgnames_test
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/mkch/gw/win32"
+	"github.com/mkch/gw/win32/msgnames"
+)
+
+func TestName(t *testing.T) {
+	for _, c := range []struct {
+		message win32.UINT
+		name    string
+	}{
+		{win32.WM_SIZE, "WM_SIZE"},
+		{win32.WM_COMMAND, "WM_COMMAND"},
+		{win32.WM_USER, "WM_USER+0"},
+		{win32.WM_USER + 5, "WM_USER+5"},
+		{win32.WM_APP + 1, "WM_APP+1"},
+		{0xC123, "0xc123"},
+	} {
+		if name := msgnames.Name(c.message); name != c.name {
+			t.Errorf("Name(%#x) = %q, want %q", c.message, name, c.name)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	classes := map[win32.HWND]string{2: "Button", 3: "EDIT"}
+	classOf := func(hwnd win32.HWND) string { return classes[hwnd] }
+	for _, c := range []struct {
+		msg  win32.MSG
+		want string
+	}{
+		{win32.MSG{Hwnd: 1, Message: win32.WM_SIZE, WParam: win32.SIZE_MAXIMIZED, LParam: win32.LPARAM(win32.MAKELONG[win32.WORD](640, 480))},
+			"WM_SIZE hwnd=0x1 type=SIZE_MAXIMIZED width=640 height=480"},
+		{win32.MSG{Hwnd: 1, Message: win32.WM_LBUTTONDOWN, WParam: win32.MK_LBUTTON | win32.MK_SHIFT, LParam: win32.LPARAM(win32.MAKELONG[win32.WORD](0xFFFF, 2))},
+			"WM_LBUTTONDOWN hwnd=0x1 keys=MK_LBUTTON|MK_SHIFT pt=(-1,2)"},
+		// Repeat 1, scan code 0x1C, extended, previous key state down.
+		{win32.MSG{Hwnd: 1, Message: win32.WM_KEYDOWN, WParam: 0x0D, LParam: 0x411C0001},
+			"WM_KEYDOWN hwnd=0x1 vk=VK_RETURN repeat=1 scan=28 extended=true alt=false wasDown=true up=false"},
+		{win32.MSG{Hwnd: 1, Message: win32.WM_COMMAND, WParam: win32.WPARAM(win32.MAKELONG[win32.WORD](100, win32.BN_CLICKED)), LParam: 2},
+			"WM_COMMAND hwnd=0x1 id=100 code=BN_CLICKED ctrl=0x2"},
+		{win32.MSG{Hwnd: 1, Message: win32.WM_COMMAND, WParam: win32.WPARAM(win32.MAKELONG[win32.WORD](100, 0x300)), LParam: 3},
+			"WM_COMMAND hwnd=0x1 id=100 code=EN_CHANGE ctrl=0x3"},
+		{win32.MSG{Hwnd: 1, Message: win32.WM_COMMAND, WParam: win32.WPARAM(win32.MAKELONG[win32.WORD](100, 1))},
+			"WM_COMMAND hwnd=0x1 id=100 code=accelerator ctrl=0x0"},
+		{win32.MSG{Hwnd: 1, Message: win32.WM_USER, WParam: 1, LParam: -1},
+			"WM_USER+0 hwnd=0x1 wParam=0x1 lParam=" + msgnames.Handle(^uintptr(0)).String()},
+	} {
+		m := msgnames.Format(&c.msg, classOf)
+		if s := m.String(); s != c.want {
+			t.Errorf("got  %v\nwant %v", s, c.want)
+		}
+	}
+}
+
+func TestLogValue(t *testing.T) {
+	m := msgnames.Format(&win32.MSG{Hwnd: 1, Message: win32.WM_MOVE, LParam: win32.LPARAM(win32.MAKELONG[win32.WORD](3, 4))}, nil)
+	if pt, _ := m.Param("pt"); pt != (msgnames.Point{X: 3, Y: 4}) {
+		t.Fatal(pt)
+	}
+	want := slog.GroupValue(slog.String("name", "WM_MOVE"), slog.String("hwnd", "0x1"), slog.String("pt", "(3,4)"))
+	if v := m.LogValue(); !v.Equal(want) {
+		t.Fatal(v)
+	}
+}
@@ -0,0 +1,75 @@
+package win32
+
+// NMHDR is the header of the WM_NOTIFY notifications.
+// The notification structures of controls begin with NMHDR.
+type NMHDR struct {
+	HwndFrom HWND
+	IdFrom   UINT_PTR
+	Code     UINT
+}
+
+// Common notification codes. The codes are negative numbers in UINT.
+const (
+	NM_OUTOFMEMORY = 0xFFFFFFFF // NM_FIRST - 1
+	NM_CLICK       = 0xFFFFFFFE // NM_FIRST - 2
+	NM_DBLCLK      = 0xFFFFFFFD // NM_FIRST - 3
+	NM_RETURN      = 0xFFFFFFFC // NM_FIRST - 4
+	NM_RCLICK      = 0xFFFFFFFB // NM_FIRST - 5
+	NM_RDBLCLK     = 0xFFFFFFFA // NM_FIRST - 6
+	NM_SETFOCUS    = 0xFFFFFFF9 // NM_FIRST - 7
+	NM_KILLFOCUS   = 0xFFFFFFF8 // NM_FIRST - 8
+	NM_CUSTOMDRAW  = 0xFFFFFFF4 // NM_FIRST - 12
+	NM_HOVER       = 0xFFFFFFF3 // NM_FIRST - 13
+)
+
+// Owner-draw control types.
+const (
+	ODT_MENU     = 1
+	ODT_LISTBOX  = 2
+	ODT_COMBOBOX = 3
+	ODT_BUTTON   = 4
+	ODT_STATIC   = 5
+)
+
+// Owner-draw actions.
+const (
+	ODA_DRAWENTIRE = 0x0001
+	ODA_SELECT     = 0x0002
+	ODA_FOCUS      = 0x0004
+)
+
+// Owner-draw states.
+const (
+	ODS_SELECTED     = 0x0001
+	ODS_GRAYED       = 0x0002
+	ODS_DISABLED     = 0x0004
+	ODS_CHECKED      = 0x0008
+	ODS_FOCUS        = 0x0010
+	ODS_DEFAULT      = 0x0020
+	ODS_COMBOBOXEDIT = 0x1000
+	ODS_HOTLIGHT     = 0x0040
+	ODS_INACTIVE     = 0x0080
+	ODS_NOACCEL      = 0x0100
+	ODS_NOFOCUSRECT  = 0x0200
+)
+
+type DRAWITEMSTRUCT struct {
+	CtlType    UINT
+	CtlID      UINT
+	ItemID     UINT
+	ItemAction UINT
+	ItemState  UINT
+	HwndItem   HWND
+	HDC        HDC
+	RcItem     RECT
+	ItemData   ULONG_PTR
+}
+
+type MEASUREITEMSTRUCT struct {
+	CtlType    UINT
+	CtlID      UINT
+	ItemID     UINT
+	ItemWidth  UINT
+	ItemHeight UINT
+	ItemData   ULONG_PTR
+}
@@ -0,0 +1,169 @@
+package win32
+
+// Pointer input messages.
+const (
+	WM_POINTERUPDATE         = 0x0245
+	WM_POINTERDOWN           = 0x0246
+	WM_POINTERUP             = 0x0247
+	WM_POINTERENTER          = 0x0249
+	WM_POINTERLEAVE          = 0x024A
+	WM_POINTERCAPTURECHANGED = 0x024C
+	WM_POINTERWHEEL          = 0x024E
+	WM_POINTERHWHEEL         = 0x024F
+	WM_GESTURE               = 0x0119
+	WM_GESTURENOTIFY         = 0x011A
+)
+
+type POINTER_INPUT_TYPE DWORD
+
+const (
+	PT_POINTER  POINTER_INPUT_TYPE = 1
+	PT_TOUCH    POINTER_INPUT_TYPE = 2
+	PT_PEN      POINTER_INPUT_TYPE = 3
+	PT_MOUSE    POINTER_INPUT_TYPE = 4
+	PT_TOUCHPAD POINTER_INPUT_TYPE = 5
+)
+
+type POINTER_FLAGS UINT32
+
+const (
+	POINTER_FLAG_NONE           POINTER_FLAGS = 0x00000000
+	POINTER_FLAG_NEW            POINTER_FLAGS = 0x00000001
+	POINTER_FLAG_INRANGE        POINTER_FLAGS = 0x00000002
+	POINTER_FLAG_INCONTACT      POINTER_FLAGS = 0x00000004
+	POINTER_FLAG_FIRSTBUTTON    POINTER_FLAGS = 0x00000010
+	POINTER_FLAG_SECONDBUTTON   POINTER_FLAGS = 0x00000020
+	POINTER_FLAG_THIRDBUTTON    POINTER_FLAGS = 0x00000040
+	POINTER_FLAG_FOURTHBUTTON   POINTER_FLAGS = 0x00000080
+	POINTER_FLAG_FIFTHBUTTON    POINTER_FLAGS = 0x00000100
+	POINTER_FLAG_PRIMARY        POINTER_FLAGS = 0x00002000
+	POINTER_FLAG_CONFIDENCE     POINTER_FLAGS = 0x00004000
+	POINTER_FLAG_CANCELED       POINTER_FLAGS = 0x00008000
+	POINTER_FLAG_DOWN           POINTER_FLAGS = 0x00010000
+	POINTER_FLAG_UPDATE         POINTER_FLAGS = 0x00020000
+	POINTER_FLAG_UP             POINTER_FLAGS = 0x00040000
+	POINTER_FLAG_WHEEL          POINTER_FLAGS = 0x00080000
+	POINTER_FLAG_HWHEEL         POINTER_FLAGS = 0x00100000
+	POINTER_FLAG_CAPTURECHANGED POINTER_FLAGS = 0x00200000
+	POINTER_FLAG_HASTRANSFORM   POINTER_FLAGS = 0x00400000
+)
+
+// GET_POINTERID_WPARAM returns the pointer ID of the wParam of pointer messages.
+func GET_POINTERID_WPARAM(wParam WPARAM) UINT32 {
+	return UINT32(LOWORD(wParam))
+}
+
+type POINTER_INFO struct {
+	PointerType           POINTER_INPUT_TYPE
+	PointerId             UINT32
+	FrameId               UINT32
+	PointerFlags          POINTER_FLAGS
+	SourceDevice          HANDLE
+	HwndTarget            HWND
+	PtPixelLocation       POINT
+	PtHimetricLocation    POINT
+	PtPixelLocationRaw    POINT
+	PtHimetricLocationRaw POINT
+	Time                  DWORD
+	HistoryCount          UINT32
+	InputData             INT32
+	KeyStates             DWORD
+	PerformanceCount      UINT64
+	ButtonChangeType      INT32
+	_                     UINT32 // Trailing padding of 386, where UINT64 is 4-byte aligned in Go.
+}
+
+type PEN_FLAGS UINT32
+
+const (
+	PEN_FLAG_NONE     PEN_FLAGS = 0x00000000
+	PEN_FLAG_BARREL   PEN_FLAGS = 0x00000001
+	PEN_FLAG_INVERTED PEN_FLAGS = 0x00000002
+	PEN_FLAG_ERASER   PEN_FLAGS = 0x00000004
+)
+
+type PEN_MASK UINT32
+
+const (
+	PEN_MASK_NONE     PEN_MASK = 0x00000000
+	PEN_MASK_PRESSURE PEN_MASK = 0x00000001
+	PEN_MASK_ROTATION PEN_MASK = 0x00000002
+	PEN_MASK_TILT_X   PEN_MASK = 0x00000004
+	PEN_MASK_TILT_Y   PEN_MASK = 0x00000008
+)
+
+type POINTER_PEN_INFO struct {
+	PointerInfo POINTER_INFO
+	PenFlags    PEN_FLAGS
+	PenMask     PEN_MASK
+	Pressure    UINT32 // 0 to 1024.
+	Rotation    UINT32 // 0 to 359 degrees.
+	TiltX       INT32  // -90 to +90 degrees.
+	TiltY       INT32  // -90 to +90 degrees.
+}
+
+type TOUCH_MASK UINT32
+
+const (
+	TOUCH_MASK_NONE        TOUCH_MASK = 0x00000000
+	TOUCH_MASK_CONTACTAREA TOUCH_MASK = 0x00000001
+	TOUCH_MASK_ORIENTATION TOUCH_MASK = 0x00000002
+	TOUCH_MASK_PRESSURE    TOUCH_MASK = 0x00000004
+)
+
+type POINTER_TOUCH_INFO struct {
+	PointerInfo  POINTER_INFO
+	TouchFlags   UINT32
+	TouchMask    TOUCH_MASK
+	RcContact    RECT
+	RcContactRaw RECT
+	Orientation  UINT32 // 0 to 359 degrees.
+	Pressure     UINT32 // 0 to 1024.
+}
+
+type HGESTUREINFO HANDLE
+
+// Gesture IDs.
+const (
+	GID_BEGIN        = 1
+	GID_END          = 2
+	GID_ZOOM         = 3
+	GID_PAN          = 4
+	GID_ROTATE       = 5
+	GID_TWOFINGERTAP = 6
+	GID_PRESSANDTAP  = 7
+)
+
+// Gesture flags.
+const (
+	GF_BEGIN   = 0x00000001
+	GF_INERTIA = 0x00000002
+	GF_END     = 0x00000004
+)
+
+// GC_ALLGESTURES of GESTURECONFIG.Want enables all gestures of GESTURECONFIG.ID 0.
+const GC_ALLGESTURES = 0x00000001
+
+type POINTS struct {
+	X, Y SHORT
+}
+
+type GESTUREINFO struct {
+	Size       UINT
+	Flags      DWORD
+	ID         DWORD
+	HwndTarget HWND
+	Location   POINTS
+	InstanceID DWORD
+	SequenceID DWORD
+	_          UINT32 // Padding for the alignment of Arguments, which is 4 in Go on 386.
+	Arguments  UINT64
+	ExtraArgs  UINT
+	_          UINT32 // Trailing padding.
+}
+
+type GESTURECONFIG struct {
+	ID    DWORD
+	Want  DWORD
+	Block DWORD
+}
@@ -0,0 +1,222 @@
+package win32
+
+type HTREEITEM HANDLE
+
+// Special HTREEITEM values of TVINSERTSTRUCTW.
+const (
+	TVI_ROOT  HTREEITEM = ^HTREEITEM(0xFFFF) // -0x10000
+	TVI_FIRST HTREEITEM = ^HTREEITEM(0xFFFE) // -0x0FFFF
+	TVI_LAST  HTREEITEM = ^HTREEITEM(0xFFFD) // -0x0FFFE
+	TVI_SORT  HTREEITEM = ^HTREEITEM(0xFFFC) // -0x0FFFD
+)
+
+// Tree view styles.
+const (
+	TVS_HASBUTTONS      = 0x0001
+	TVS_HASLINES        = 0x0002
+	TVS_LINESATROOT     = 0x0004
+	TVS_EDITLABELS      = 0x0008
+	TVS_DISABLEDRAGDROP = 0x0010
+	TVS_SHOWSELALWAYS   = 0x0020
+	TVS_RTLREADING      = 0x0040
+	TVS_NOTOOLTIPS      = 0x0080
+	TVS_CHECKBOXES      = 0x0100
+	TVS_TRACKSELECT     = 0x0200
+	TVS_SINGLEEXPAND    = 0x0400
+	TVS_INFOTIP         = 0x0800
+	TVS_FULLROWSELECT   = 0x1000
+	TVS_NOSCROLL        = 0x2000
+	TVS_NONEVENHEIGHT   = 0x4000
+	TVS_NOHSCROLL       = 0x8000
+)
+
+// Tree view messages.
+const (
+	TVM_FIRST           = 0x1100
+	TVM_DELETEITEM      = TVM_FIRST + 1
+	TVM_EXPAND          = TVM_FIRST + 2
+	TVM_GETITEMRECT     = TVM_FIRST + 4
+	TVM_GETCOUNT        = TVM_FIRST + 5
+	TVM_GETINDENT       = TVM_FIRST + 6
+	TVM_SETINDENT       = TVM_FIRST + 7
+	TVM_GETIMAGELIST    = TVM_FIRST + 8
+	TVM_SETIMAGELIST    = TVM_FIRST + 9
+	TVM_GETNEXTITEM     = TVM_FIRST + 10
+	TVM_SELECTITEM      = TVM_FIRST + 11
+	TVM_GETEDITCONTROL  = TVM_FIRST + 15
+	TVM_HITTEST         = TVM_FIRST + 17
+	TVM_SORTCHILDREN    = TVM_FIRST + 19
+	TVM_ENSUREVISIBLE   = TVM_FIRST + 20
+	TVM_ENDEDITLABELNOW = TVM_FIRST + 22
+	TVM_SETINSERTMARK   = TVM_FIRST + 26
+	TVM_GETITEMSTATE    = TVM_FIRST + 39
+	TVM_INSERTITEMW     = TVM_FIRST + 50
+	TVM_GETITEMW        = TVM_FIRST + 62
+	TVM_SETITEMW        = TVM_FIRST + 63
+	TVM_EDITLABELW      = TVM_FIRST + 65
+)
+
+// Tree view notification codes. The codes are negative numbers in UINT.
+const (
+	TVN_KEYDOWN         = 0xFFFFFE64 // TVN_FIRST - 12
+	TVN_ITEMCHANGINGW   = 0xFFFFFE5F // TVN_FIRST - 17
+	TVN_ITEMCHANGEDW    = 0xFFFFFE5D // TVN_FIRST - 19
+	TVN_SELCHANGINGW    = 0xFFFFFE3E // TVN_FIRST - 50
+	TVN_SELCHANGEDW     = 0xFFFFFE3D // TVN_FIRST - 51
+	TVN_GETDISPINFOW    = 0xFFFFFE3C // TVN_FIRST - 52
+	TVN_SETDISPINFOW    = 0xFFFFFE3B // TVN_FIRST - 53
+	TVN_ITEMEXPANDINGW  = 0xFFFFFE3A // TVN_FIRST - 54
+	TVN_ITEMEXPANDEDW   = 0xFFFFFE39 // TVN_FIRST - 55
+	TVN_BEGINDRAGW      = 0xFFFFFE38 // TVN_FIRST - 56
+	TVN_BEGINRDRAGW     = 0xFFFFFE37 // TVN_FIRST - 57
+	TVN_DELETEITEMW     = 0xFFFFFE36 // TVN_FIRST - 58
+	TVN_BEGINLABELEDITW = 0xFFFFFE35 // TVN_FIRST - 59
+	TVN_ENDLABELEDITW   = 0xFFFFFE34 // TVN_FIRST - 60
+)
+
+// Tree view item masks.
+const (
+	TVIF_TEXT          = 0x0001
+	TVIF_IMAGE         = 0x0002
+	TVIF_PARAM         = 0x0004
+	TVIF_STATE         = 0x0008
+	TVIF_HANDLE        = 0x0010
+	TVIF_SELECTEDIMAGE = 0x0020
+	TVIF_CHILDREN      = 0x0040
+	TVIF_INTEGRAL      = 0x0080
+)
+
+// Tree view item states.
+const (
+	TVIS_SELECTED       = 0x0002
+	TVIS_CUT            = 0x0004
+	TVIS_DROPHILITED    = 0x0008
+	TVIS_BOLD           = 0x0010
+	TVIS_EXPANDED       = 0x0020
+	TVIS_EXPANDEDONCE   = 0x0040
+	TVIS_EXPANDPARTIAL  = 0x0080
+	TVIS_OVERLAYMASK    = 0x0F00
+	TVIS_STATEIMAGEMASK = 0xF000
+)
+
+// Values of the Children field of TVITEMW.
+const (
+	I_CHILDRENCALLBACK = -1
+	I_CHILDRENAUTO     = -2
+)
+
+// Actions of TVM_EXPAND.
+const (
+	TVE_COLLAPSE      = 0x0001
+	TVE_EXPAND        = 0x0002
+	TVE_TOGGLE        = 0x0003
+	TVE_EXPANDPARTIAL = 0x4000
+	TVE_COLLAPSERESET = 0x8000
+)
+
+// Flags of TVM_GETNEXTITEM and TVM_SELECTITEM.
+const (
+	TVGN_ROOT            = 0x0000
+	TVGN_NEXT            = 0x0001
+	TVGN_PREVIOUS        = 0x0002
+	TVGN_PARENT          = 0x0003
+	TVGN_CHILD           = 0x0004
+	TVGN_FIRSTVISIBLE    = 0x0005
+	TVGN_NEXTVISIBLE     = 0x0006
+	TVGN_PREVIOUSVISIBLE = 0x0007
+	TVGN_DROPHILITE      = 0x0008
+	TVGN_CARET           = 0x0009
+	TVGN_LASTVISIBLE     = 0x000A
+)
+
+// Image lists of TVM_SETIMAGELIST.
+const (
+	TVSIL_NORMAL = 0
+	TVSIL_STATE  = 2
+)
+
+// Causes of selection changes in NMTREEVIEWW.
+const (
+	TVC_UNKNOWN    = 0x0000
+	TVC_BYMOUSE    = 0x0001
+	TVC_BYKEYBOARD = 0x0002
+)
+
+// Flags of TVHITTESTINFO.
+const (
+	TVHT_NOWHERE         = 0x0001
+	TVHT_ONITEMICON      = 0x0002
+	TVHT_ONITEMLABEL     = 0x0004
+	TVHT_ONITEMINDENT    = 0x0008
+	TVHT_ONITEMBUTTON    = 0x0010
+	TVHT_ONITEMRIGHT     = 0x0020
+	TVHT_ONITEMSTATEICON = 0x0040
+	TVHT_ONITEM          = TVHT_ONITEMICON | TVHT_ONITEMLABEL | TVHT_ONITEMSTATEICON
+	TVHT_ABOVE           = 0x0100
+	TVHT_BELOW           = 0x0200
+	TVHT_TORIGHT         = 0x0400
+	TVHT_TOLEFT          = 0x0800
+)
+
+type TVITEMW struct {
+	Mask          UINT
+	Item          HTREEITEM
+	State         UINT
+	StateMask     UINT
+	Text          *WCHAR
+	TextMax       INT
+	Image         INT
+	SelectedImage INT
+	Children      INT
+	LParam        LPARAM
+}
+
+type TVITEMEXW struct {
+	TVITEMW
+	Integral      INT
+	StateEx       UINT
+	Hwnd          HWND
+	ExpandedImage INT
+	Reserved      INT
+}
+
+type TVINSERTSTRUCTW struct {
+	Parent      HTREEITEM
+	InsertAfter HTREEITEM
+	Item        TVITEMEXW // Union of TVITEMEXW and TVITEMW in C.
+}
+
+type TVHITTESTINFO struct {
+	Pt    POINT
+	Flags UINT
+	Item  HTREEITEM
+}
+
+type NMTREEVIEWW struct {
+	Hdr     NMHDR
+	Action  UINT
+	ItemOld TVITEMW
+	ItemNew TVITEMW
+	PtDrag  POINT
+}
+
+type NMTVDISPINFOW struct {
+	Hdr  NMHDR
+	Item TVITEMW
+}
+
+type NMTVITEMCHANGE struct {
+	Hdr      NMHDR
+	Changed  UINT
+	Item     HTREEITEM
+	StateNew UINT
+	StateOld UINT
+	LParam   LPARAM
+}
+
+// NMTVKEYDOWN is packed in C. The flags field after VKey is omitted,
+// because it is not aligned.
+type NMTVKEYDOWN struct {
+	Hdr  NMHDR
+	VKey WORD
+}
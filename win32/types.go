@@ -36,6 +36,9 @@ type UINT uint32
 type ULONG uint32     // An unsigned LONG.
 type ULONGLONG uint64 // A 64-bit unsigned integer.
 type USHORT uint16
+type INT32 int32   // A 32-bit signed integer.
+type UINT32 uint32 // An unsigned INT32.
+type UINT64 uint64 // An unsigned INT64.
 type HRESULT LONG
 
 type PVOID unsafe.Pointer
@@ -1,3 +1,5 @@
+//go:build windows
+
 package win32
 
 // This file is a go implementation of Windows Version Helper APIs versionhelpers.h
@@ -1,3 +1,5 @@
+//go:build windows
+
 package win32_test
 
 import (
@@ -0,0 +1,7 @@
+package win32
+
+// SetWindowLongPtrW is not available on 386.
+var lzGetWindowLongPtrW = lzUser32.NewProc("GetWindowLongW")
+
+// SetWindowLongPtrW is not available on 386.
+var lzSetWindowLongPtrW = lzUser32.NewProc("SetWindowLongW")
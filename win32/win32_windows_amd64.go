@@ -0,0 +1,4 @@
+package win32
+
+var lzGetWindowLongPtrW = lzUser32.NewProc("GetWindowLongPtrW")
+var lzSetWindowLongPtrW = lzUser32.NewProc("SetWindowLongPtrW")
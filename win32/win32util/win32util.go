@@ -5,7 +5,6 @@ import (
 	"unsafe"
 
 	"github.com/mkch/gw/win32"
-	"golang.org/x/sys/windows"
 )
 
 func CString(str string, p *[]win32.WCHAR) {
@@ -63,7 +62,7 @@ func RegisterClass(cls *WndClass) (win32.ATOM, error) {
 	var wndClass = win32.WNDCLASSEXW{
 		Size:       win32.UINT(unsafe.Sizeof(win32.WNDCLASSEXW{})),
 		ClassName:  &classNameBuf[0],
-		WndProc:    windows.NewCallback(cls.WndProc),
+		WndProc:    win32.NewCallback(cls.WndProc),
 		Style:      cls.Style,
 		ClsExtra:   cls.ClsExtra,
 		WndExtra:   cls.WndExtra,
@@ -129,6 +128,17 @@ func GetWindowText(hwnd win32.HWND) (string, error) {
 	return GoString(&buf[0], n+1), nil
 }
 
+// GetClassName returns the class name of hwnd.
+func GetClassName(hwnd win32.HWND) (string, error) {
+	// The maximum length of a class name is 256.
+	var buf [257]win32.WCHAR
+	n, err := win32.GetClassNameW(hwnd, &buf[0], len(buf))
+	if err != nil {
+		return "", err
+	}
+	return GoString(&buf[0], n+1), nil
+}
+
 func SetWindowText(hwnd win32.HWND, str string) error {
 	var buf []win32.WCHAR
 	CString(str, &buf)
@@ -0,0 +1,118 @@
+package window
+
+import (
+	"github.com/mkch/gw/internal/uithread"
+	"github.com/mkch/gw/win32"
+)
+
+// ModifierKeys is the state of the modifier keys.
+type ModifierKeys uint8
+
+const (
+	ModShift ModifierKeys = 1 << iota
+	ModControl
+	ModAlt
+	ModWin
+)
+
+func (m ModifierKeys) Shift() bool {
+	return m&ModShift != 0
+}
+
+func (m ModifierKeys) Control() bool {
+	return m&ModControl != 0
+}
+
+func (m ModifierKeys) Alt() bool {
+	return m&ModAlt != 0
+}
+
+func (m ModifierKeys) Win() bool {
+	return m&ModWin != 0
+}
+
+// keyDown reports whether the virtual key vk is down.
+func keyDown(vk win32.INT) bool {
+	return win32.GetKeyState(vk) < 0
+}
+
+// CurrentModifierKeys returns the state of the modifier keys as of the
+// message being processed.
+func CurrentModifierKeys() (m ModifierKeys) {
+	uithread.Check()
+	if keyDown(win32.VK_SHIFT) {
+		m |= ModShift
+	}
+	if keyDown(win32.VK_CONTROL) {
+		m |= ModControl
+	}
+	if keyDown(win32.VK_MENU) {
+		m |= ModAlt
+	}
+	if keyDown(win32.VK_LWIN) || keyDown(win32.VK_RWIN) {
+		m |= ModWin
+	}
+	return
+}
+
+// KeyEvent is a keyboard event.
+type KeyEvent struct {
+	Message   win32.UINT // WM_KEYDOWN, WM_CHAR, WM_SYSKEYDOWN etc.
+	VKey      win32.WORD // Virtual-key code of keystroke messages.
+	Char      rune       // Character code of character messages, a UTF-16 code unit.
+	ScanCode  win32.BYTE
+	Repeat    int  // Repeat count.
+	Extended  bool // Extended key, such as the right-hand ALT and CTRL keys.
+	AltDown   bool // The context code, ALT is down when the key is pressed.
+	WasDown   bool // The key is down before the message is sent.
+	Sys       bool // WM_SYSKEYDOWN, WM_SYSKEYUP, WM_SYSCHAR or WM_SYSDEADCHAR.
+	Modifiers ModifierKeys
+	// Handled, if set by the event handler, suppresses the default processing.
+	Handled bool
+}
+
+func newKeyEvent(message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) *KeyEvent {
+	l := uint32(lParam)
+	e := &KeyEvent{
+		Message:   message,
+		ScanCode:  win32.BYTE(l >> 16),
+		Repeat:    int(l & 0xFFFF),
+		Extended:  l&(1<<24) != 0,
+		AltDown:   l&(1<<29) != 0,
+		WasDown:   l&(1<<30) != 0,
+		Modifiers: CurrentModifierKeys(),
+	}
+	switch message {
+	case win32.WM_KEYDOWN, win32.WM_KEYUP:
+		e.VKey = win32.WORD(wParam)
+	case win32.WM_SYSKEYDOWN, win32.WM_SYSKEYUP:
+		e.VKey, e.Sys = win32.WORD(wParam), true
+	case win32.WM_CHAR, win32.WM_DEADCHAR:
+		e.Char = rune(wParam)
+	case win32.WM_SYSCHAR, win32.WM_SYSDEADCHAR:
+		e.Char, e.Sys = rune(wParam), true
+	}
+	return e
+}
+
+// handleKey calls the keyboard event handler of message, if any.
+// It reports whether the event is handled.
+func (w *WindowBase) handleKey(message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) bool {
+	var handler func(e *KeyEvent)
+	switch message {
+	case win32.WM_KEYDOWN, win32.WM_SYSKEYDOWN:
+		handler = w.OnKeyDown
+	case win32.WM_KEYUP, win32.WM_SYSKEYUP:
+		handler = w.OnKeyUp
+	case win32.WM_CHAR, win32.WM_SYSCHAR:
+		handler = w.OnChar
+	case win32.WM_DEADCHAR, win32.WM_SYSDEADCHAR:
+		handler = w.OnDeadChar
+	}
+	if handler == nil {
+		return false
+	}
+	e := newKeyEvent(message, wParam, lParam)
+	handler(e)
+	return e.Handled
+}
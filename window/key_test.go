@@ -0,0 +1,69 @@
+package window_test
+
+import (
+	"testing"
+
+	"github.com/mkch/gw/metrics"
+	"github.com/mkch/gw/win32"
+	"github.com/mkch/gw/window"
+)
+
+func TestKeyEvent(t *testing.T) {
+	w := newWindow(t, &window.Spec{Width: metrics.Px(100), Height: metrics.Px(100)})
+	var downs []window.KeyEvent
+	var chars []rune
+	w.OnKeyDown = func(e *window.KeyEvent) {
+		downs = append(downs, *e)
+		// Suppress Alt+F4.
+		e.Handled = e.VKey == win32.VK_F4
+	}
+	w.OnChar = func(e *window.KeyEvent) {
+		chars = append(chars, e.Char)
+	}
+
+	backend.KeyDown(w.HWND(), win32.VK_SHIFT)
+	backend.KeyDown(w.HWND(), 'A')
+	backend.KeyDown(w.HWND(), 'A') // Auto-repeat.
+	backend.KeyUp(w.HWND(), 'A')
+	backend.KeyUp(w.HWND(), win32.VK_SHIFT)
+	pump()
+	if len(downs) != 3 {
+		t.Fatal(downs)
+	}
+	if e := downs[1]; e.VKey != 'A' || e.WasDown || e.Sys || e.Repeat != 1 || !e.Modifiers.Shift() || e.Modifiers.Control() {
+		t.Fatalf("%+v", e)
+	}
+	if e := downs[2]; !e.WasDown {
+		t.Fatalf("%+v", e)
+	}
+	if string(chars) != "AA" {
+		t.Fatal(string(chars))
+	}
+
+	downs = nil
+	backend.KeyDown(w.HWND(), win32.VK_MENU)
+	backend.KeyDown(w.HWND(), win32.VK_F4)
+	backend.KeyUp(w.HWND(), win32.VK_F4)
+	backend.KeyUp(w.HWND(), win32.VK_MENU)
+	pump()
+	if e := downs[1]; e.VKey != win32.VK_F4 || !e.Sys || !e.AltDown || !e.Modifiers.Alt() {
+		t.Fatalf("%+v", e)
+	}
+	if _, ok := backend.Window(w.HWND()); !ok {
+		t.Fatal("closed by handled Alt+F4")
+	}
+
+	// Not handled.
+	w.OnKeyDown = nil
+	backend.KeyDown(w.HWND(), win32.VK_MENU)
+	backend.KeyDown(w.HWND(), win32.VK_F4)
+	backend.KeyUp(w.HWND(), win32.VK_F4)
+	backend.KeyUp(w.HWND(), win32.VK_MENU)
+	pump()
+	if _, ok := backend.Window(w.HWND()); ok {
+		t.Fatal("not closed by Alt+F4")
+	}
+	if backend.KeyState(win32.VK_MENU) {
+		t.Fatal("Alt is down")
+	}
+}
@@ -0,0 +1,108 @@
+package window
+
+import (
+	"github.com/mkch/gw/internal/uithread"
+	"github.com/mkch/gw/layout"
+	"github.com/mkch/gw/metrics"
+	"github.com/mkch/gw/win32"
+)
+
+// layoutElement is the layout.Element of a window.
+type layoutElement struct {
+	w    *WindowBase
+	size layout.Size // Preferred size in dpi.
+	dpi  win32.UINT
+}
+
+func (e *layoutElement) Measure(dpi win32.UINT) layout.Size {
+	return layout.Size{
+		Width:  metrics.DPIConv(e.size.Width, e.dpi, dpi),
+		Height: metrics.DPIConv(e.size.Height, e.dpi, dpi),
+	}
+}
+
+// Arrange moves the window in the MoveBatch of the parent window if it is relaying out,
+// or immediately otherwise.
+func (e *layoutElement) Arrange(bounds layout.Rect, dpi win32.UINT) {
+	if hParent, _ := win32.GetParent(e.w.hwnd); hParent != 0 {
+		if parent := LookupWindowBase(hParent); parent != nil && parent.layoutBatch != nil {
+			parent.layoutBatch.Move(e.w.hwnd, bounds.X, bounds.Y, bounds.Width, bounds.Height)
+			return
+		}
+	}
+	win32.SetWindowPos(e.w.hwnd, 0, bounds.X, bounds.Y, bounds.Width, bounds.Height, win32.SWP_NOZORDER|win32.SWP_NOACTIVATE)
+}
+
+// LayoutElement returns the layout.Element of w, to be placed by the layout of its parent.
+// The preferred size of the element is the size of w when this method is called,
+// scaled to the DPI of the layout.
+func (w *WindowBase) LayoutElement() (layout.Element, error) {
+	uithread.CheckWindow(w.hwnd)
+	rect, err := w.GetWindowRect()
+	if err != nil {
+		return nil, err
+	}
+	dpi, err := w.DPI()
+	if err != nil {
+		return nil, err
+	}
+	return &layoutElement{w: w, size: layout.Size{Width: win32.INT(rect.Width()), Height: win32.INT(rect.Height())}, dpi: dpi}, nil
+}
+
+// Layout returns the layout set by SetLayout, or nil if none.
+func (w *WindowBase) Layout() layout.Element {
+	uithread.CheckWindow(w.hwnd)
+	return w.layoutRoot
+}
+
+// SetLayout sets the layout of the child windows of w, which arranges them in the
+// client area of w now and whenever w is resized or its DPI changes.
+// A nil l removes the layout.
+func (w *WindowBase) SetLayout(l layout.Element) error {
+	uithread.CheckWindow(w.hwnd)
+	w.layoutRoot = l
+	if l == nil {
+		for _, key := range w.layoutListeners {
+			key.Remove()
+		}
+		w.layoutListeners = nil
+		return nil
+	}
+	if w.layoutListeners == nil {
+		relayout := func(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) {
+			if message == win32.WM_SIZE && wParam == win32.SIZE_MINIMIZED {
+				return
+			}
+			w.Relayout()
+		}
+		// A top level window is resized on WM_DPICHANGED, which sends WM_SIZE.
+		w.layoutListeners = []MsgListenerKey{
+			w.AddMsgListener(win32.WM_SIZE, relayout),
+			w.AddMsgListener(win32.WM_DPICHANGED_AFTERPARENT, relayout),
+		}
+	}
+	return w.Relayout()
+}
+
+// Relayout arranges the child windows of w by its layout again.
+// The child windows are moved at once in a MoveBatch.
+// Call it after the layout is modified.
+func (w *WindowBase) Relayout() error {
+	uithread.CheckWindow(w.hwnd)
+	if w.layoutRoot == nil {
+		return nil
+	}
+	rect, err := w.GetClientRect()
+	if err != nil {
+		return err
+	}
+	dpi, err := w.DPI()
+	if err != nil {
+		return err
+	}
+	batch := w.BeginMove()
+	w.layoutBatch = batch
+	defer func() { w.layoutBatch = nil }()
+	w.layoutRoot.Arrange(layout.Rect{Width: win32.INT(rect.Width()), Height: win32.INT(rect.Height())}, dpi)
+	return batch.Commit()
+}
@@ -0,0 +1,197 @@
+package window
+
+import (
+	"unsafe"
+
+	"github.com/mkch/gw/internal/uithread"
+	"github.com/mkch/gw/metrics"
+	"github.com/mkch/gw/win32"
+)
+
+// MouseButton is a mouse button.
+type MouseButton uint8
+
+const (
+	MouseNone MouseButton = iota
+	MouseLeft
+	MouseRight
+	MouseMiddle
+	MouseX1
+	MouseX2
+)
+
+// MouseEvent is a mouse event.
+type MouseEvent struct {
+	Message win32.UINT    // WM_MOUSEMOVE, WM_LBUTTONDOWN, WM_MOUSEWHEEL etc.
+	Opt     MouseClickOpt // The mouse buttons and modifier keys down.
+	Button  MouseButton   // The button of button messages.
+	// Client coordinates in PX and DIP.
+	X, Y       int
+	DipX, DipY int
+	// Distance of wheel rotation in multiples or divisions of WHEEL_DELTA.
+	// Positive values are forward(away from the user) for WM_MOUSEWHEEL and
+	// right for WM_MOUSEHWHEEL.
+	WheelDelta int
+	// Handled, if set by the event handler, suppresses the default processing.
+	Handled bool
+}
+
+// DragHandler handles the mouse events of a drag started by WindowBase.BeginDrag.
+type DragHandler struct {
+	// OnMove is called when the mouse moves during the drag.
+	OnMove func(e *MouseEvent)
+	// OnEnd is called when the drag ends. If canceled, e is nil.
+	OnEnd func(e *MouseEvent, canceled bool)
+}
+
+type drag struct {
+	DragHandler
+	button MouseButton
+}
+
+func isMouseMessage(message win32.UINT) bool {
+	return message >= win32.WM_MOUSEFIRST && message <= win32.WM_MOUSELAST ||
+		message == win32.WM_MOUSEHOVER || message == win32.WM_MOUSELEAVE || message == win32.WM_CAPTURECHANGED
+}
+
+// mouseButton returns the button of a button message.
+func mouseButton(message win32.UINT, wParam win32.WPARAM) MouseButton {
+	switch message {
+	case win32.WM_LBUTTONDOWN, win32.WM_LBUTTONUP, win32.WM_LBUTTONDBLCLK:
+		return MouseLeft
+	case win32.WM_RBUTTONDOWN, win32.WM_RBUTTONUP, win32.WM_RBUTTONDBLCLK:
+		return MouseRight
+	case win32.WM_MBUTTONDOWN, win32.WM_MBUTTONUP, win32.WM_MBUTTONDBLCLK:
+		return MouseMiddle
+	case win32.WM_XBUTTONDOWN, win32.WM_XBUTTONUP, win32.WM_XBUTTONDBLCLK:
+		if win32.HIWORD(wParam) == win32.XBUTTON1 {
+			return MouseX1
+		}
+		return MouseX2
+	}
+	return MouseNone
+}
+
+func (w *WindowBase) newMouseEvent(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) *MouseEvent {
+	e := &MouseEvent{
+		Message: message,
+		Opt:     MouseClickOpt(win32.LOWORD(wParam)),
+		Button:  mouseButton(message, wParam),
+		X:       win32.GET_X_LPARAM(lParam),
+		Y:       win32.GET_Y_LPARAM(lParam),
+	}
+	if message == win32.WM_MOUSEWHEEL || message == win32.WM_MOUSEHWHEEL {
+		e.WheelDelta = int(int16(win32.HIWORD(wParam)))
+		// Screen coordinates.
+		pt := win32.POINT{X: win32.LONG(e.X), Y: win32.LONG(e.Y)}
+		win32.ScreenToClient(hwnd, &pt)
+		e.X, e.Y = int(pt.X), int(pt.Y)
+	}
+	e.DipX, e.DipY = e.X, e.Y
+	if dpi, err := win32.GetDpiForWindow(hwnd); err == nil {
+		e.DipX = int(metrics.DPIConv(win32.INT(e.X), dpi, win32.USER_DEFAULT_SCREEN_DPI))
+		e.DipY = int(metrics.DPIConv(win32.INT(e.Y), dpi, win32.USER_DEFAULT_SCREEN_DPI))
+	}
+	return e
+}
+
+// trackMouse requests WM_MOUSEHOVER and WM_MOUSELEAVE if needed.
+func (w *WindowBase) trackMouse(hwnd win32.HWND) {
+	var flags win32.TRACKMOUSEEVENT_FLAG
+	if w.OnMouseHover != nil {
+		flags |= win32.TME_HOVER
+	}
+	if w.OnMouseLeave != nil {
+		flags |= win32.TME_LEAVE
+	}
+	if flags &^= w.mouseTracking; flags == 0 {
+		return
+	}
+	tme := win32.TRACKMOUSEEVENT{
+		Size:      win32.DWORD(unsafe.Sizeof(win32.TRACKMOUSEEVENT{})),
+		Flags:     flags,
+		HwndTrack: hwnd,
+		HoverTime: win32.HOVER_DEFAULT,
+	}
+	if win32.TrackMouseEvent(&tme) == nil {
+		w.mouseTracking |= flags
+	}
+}
+
+// handleMouse calls the mouse event handler of message, if any.
+// It reports whether the event is handled.
+func (w *WindowBase) handleMouse(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) bool {
+	var handler func(e *MouseEvent)
+	switch message {
+	case win32.WM_MOUSEMOVE:
+		w.trackMouse(hwnd)
+		if w.drag != nil {
+			handler = w.drag.OnMove
+		} else {
+			handler = w.OnMouseMove
+		}
+	case win32.WM_LBUTTONDOWN, win32.WM_RBUTTONDOWN, win32.WM_MBUTTONDOWN, win32.WM_XBUTTONDOWN:
+		handler = w.OnMouseDown
+	case win32.WM_LBUTTONUP, win32.WM_RBUTTONUP, win32.WM_MBUTTONUP, win32.WM_XBUTTONUP:
+		if d := w.drag; d != nil && d.button == mouseButton(message, wParam) {
+			w.drag = nil
+			win32.ReleaseCapture()
+			if d.OnEnd != nil {
+				handler = func(e *MouseEvent) { d.OnEnd(e, false) }
+			}
+		} else {
+			handler = w.OnMouseUp
+		}
+	case win32.WM_LBUTTONDBLCLK, win32.WM_RBUTTONDBLCLK, win32.WM_MBUTTONDBLCLK, win32.WM_XBUTTONDBLCLK:
+		// The second press of a double-click is a press too.
+		if down, dblclk := w.OnMouseDown, w.OnMouseDoubleClick; down != nil || dblclk != nil {
+			handler = func(e *MouseEvent) {
+				if down != nil {
+					down(e)
+				}
+				if dblclk != nil {
+					dblclk(e)
+				}
+			}
+		}
+	case win32.WM_MOUSEWHEEL, win32.WM_MOUSEHWHEEL:
+		handler = w.OnMouseWheel
+	case win32.WM_MOUSEHOVER:
+		w.mouseTracking &^= win32.TME_HOVER
+		handler = w.OnMouseHover
+	case win32.WM_MOUSELEAVE:
+		w.mouseTracking = 0
+		if w.OnMouseLeave != nil {
+			w.OnMouseLeave()
+		}
+	case win32.WM_CAPTURECHANGED:
+		// Capture is taken by another window.
+		if d := w.drag; d != nil && win32.HWND(lParam) != hwnd {
+			w.drag = nil
+			if d.OnEnd != nil {
+				d.OnEnd(nil, true)
+			}
+		}
+	}
+	if handler == nil {
+		return false
+	}
+	e := w.newMouseEvent(hwnd, message, wParam, lParam)
+	handler(e)
+	return e.Handled
+}
+
+// BeginDrag captures the mouse and starts a drag of the button of the mouse down event e.
+// The mouse movements are reported to h.OnMove instead of OnMouseMove until the button is released
+// or the capture is lost, and h.OnEnd is called then.
+func (w *WindowBase) BeginDrag(e *MouseEvent, h DragHandler) {
+	uithread.CheckWindow(w.hwnd)
+	w.drag = &drag{DragHandler: h, button: e.Button}
+	win32.SetCapture(w.hwnd)
+}
+
+// Dragging reports whether a drag started by BeginDrag is in progress.
+func (w *WindowBase) Dragging() bool {
+	uithread.CheckWindow(w.hwnd)
+	return w.drag != nil
+}
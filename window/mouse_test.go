@@ -0,0 +1,120 @@
+package window_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mkch/gw/metrics"
+	"github.com/mkch/gw/win32"
+	"github.com/mkch/gw/win32/fake"
+	"github.com/mkch/gw/window"
+)
+
+func TestMouseEvent(t *testing.T) {
+	w := newWindow(t, &window.Spec{X: metrics.Px(0), Y: metrics.Px(0), Width: metrics.Px(300), Height: metrics.Px(300)})
+	backend.SetDPI(w.HWND(), 192)
+	var events []string
+	var last window.MouseEvent
+	record := func(name string) func(e *window.MouseEvent) {
+		return func(e *window.MouseEvent) {
+			events = append(events, name)
+			last = *e
+		}
+	}
+	w.OnMouseMove = record("move")
+	w.OnMouseDown = record("down")
+	w.OnMouseUp = record("up")
+	w.OnMouseDoubleClick = record("dblclk")
+	w.OnMouseWheel = record("wheel")
+	w.OnMouseHover = record("hover")
+	w.OnMouseLeave = func() { events = append(events, "leave") }
+
+	backend.MouseMove(w.HWND(), 100, 50)
+	pump()
+	if last.X != 100 || last.Y != 50 || last.DipX != 50 || last.DipY != 25 {
+		t.Fatalf("%+v", last)
+	}
+	backend.Advance(fake.HoverTime)
+	pump()
+	backend.MouseDown(w.HWND(), win32.MK_XBUTTON2, 100, 50)
+	backend.MouseUp(w.HWND(), win32.MK_XBUTTON2, 100, 50)
+	backend.MouseDown(w.HWND(), win32.MK_XBUTTON2, 100, 50)
+	pump()
+	if last.Button != window.MouseX2 || !last.Opt.XButton2() {
+		t.Fatalf("%+v", last)
+	}
+	backend.MouseUp(w.HWND(), win32.MK_XBUTTON2, 100, 50)
+	backend.MouseWheel(-win32.WHEEL_DELTA, false)
+	pump()
+	if last.WheelDelta != -win32.WHEEL_DELTA || last.X != 100 || last.Y != 50 {
+		t.Fatalf("%+v", last)
+	}
+
+	other := newWindow(t, &window.Spec{Width: metrics.Px(100), Height: metrics.Px(100)})
+	backend.MouseMove(other.HWND(), 1, 1)
+	pump()
+	if want := []string{"move", "hover", "down", "up", "down", "dblclk", "up", "wheel", "leave"}; !slices.Equal(events, want) {
+		t.Fatal(events)
+	}
+}
+
+func TestDoubleClickDown(t *testing.T) {
+	w := newWindow(t, &window.Spec{X: metrics.Px(0), Y: metrics.Px(0), Width: metrics.Px(100), Height: metrics.Px(100)})
+	var downs, legacyDowns, dblclks int
+	w.OnMouseDown = func(e *window.MouseEvent) { downs++ }
+	w.OnMouseDoubleClick = func(e *window.MouseEvent) { dblclks++ }
+	w.OnLButtonDown = func(opt window.MouseClickOpt, x, y int) { legacyDowns++ }
+	backend.Advance(time.Second) // Not a double-click with the previous tests.
+	backend.MouseDown(w.HWND(), win32.MK_LBUTTON, 10, 10)
+	backend.MouseUp(w.HWND(), win32.MK_LBUTTON, 10, 10)
+	backend.MouseDown(w.HWND(), win32.MK_LBUTTON, 10, 10)
+	backend.MouseUp(w.HWND(), win32.MK_LBUTTON, 10, 10)
+	pump()
+	// OnLButtonDown is not called for WM_LBUTTONDBLCLK.
+	if downs != 2 || dblclks != 1 || legacyDowns != 1 {
+		t.Fatal(downs, dblclks, legacyDowns)
+	}
+}
+
+func TestDrag(t *testing.T) {
+	w := newWindow(t, &window.Spec{X: metrics.Px(0), Y: metrics.Px(0), Width: metrics.Px(100), Height: metrics.Px(100)})
+	var moves []int
+	var ended, canceled bool
+	w.OnMouseDown = func(e *window.MouseEvent) {
+		w.BeginDrag(e, window.DragHandler{
+			OnMove: func(e *window.MouseEvent) { moves = append(moves, e.X) },
+			OnEnd:  func(e *window.MouseEvent, c bool) { ended, canceled = true, c },
+		})
+	}
+	moved := false
+	w.OnMouseMove = func(e *window.MouseEvent) { moved = true }
+
+	other := newWindow(t, &window.Spec{X: metrics.Px(200), Y: metrics.Px(0), Width: metrics.Px(100), Height: metrics.Px(100)})
+	backend.MouseDown(w.HWND(), win32.MK_LBUTTON, 10, 10)
+	pump()
+	if !w.Dragging() || win32.GetCapture() != w.HWND() {
+		t.Fatal("not dragging")
+	}
+	// Moves outside are reported to the window capturing the mouse.
+	backend.MouseMove(other.HWND(), 10, 10)
+	backend.MouseUp(other.HWND(), win32.MK_LBUTTON, 10, 10)
+	pump()
+	if !slices.Equal(moves, []int{210}) || moved {
+		t.Fatal(moves, moved)
+	}
+	if !ended || canceled || w.Dragging() || win32.GetCapture() != 0 {
+		t.Fatal(ended, canceled)
+	}
+
+	// Capture lost.
+	ended = false
+	backend.Advance(time.Second) // Not a double-click.
+	backend.MouseDown(w.HWND(), win32.MK_LBUTTON, 10, 10)
+	pump()
+	win32.SetCapture(other.HWND())
+	if !ended || !canceled || w.Dragging() {
+		t.Fatal(ended, canceled)
+	}
+	win32.ReleaseCapture()
+}
@@ -0,0 +1,68 @@
+package window
+
+import (
+	"errors"
+
+	"github.com/mkch/gw/internal/uithread"
+	"github.com/mkch/gw/win32"
+)
+
+// MoveBatch is a batch of position changes of child windows applied at once by Commit.
+// See WindowBase.BeginMove.
+type MoveBatch struct {
+	hdwp      win32.HDWP
+	err       error
+	committed bool
+	thread    win32.DWORD // The UI thread calling BeginMove.
+}
+
+// defaultMoveBatchSize is the initial number of windows of a MoveBatch,
+// which grows as needed.
+const defaultMoveBatchSize = 8
+
+// ErrMoveBatchCommitted is returned if a committed MoveBatch is used.
+var ErrMoveBatchCommitted = errors.New("move batch already committed")
+
+// BeginMove begins a batch to move and resize the child windows of w.
+// The changes are applied at once when Commit is called, which avoids the flicker
+// and redundant WM_SIZE cascades of moving the windows one by one.
+// Any error is reported by Commit.
+func (w *WindowBase) BeginMove() *MoveBatch {
+	uithread.CheckWindow(w.hwnd)
+	hdwp, err := win32.BeginDeferWindowPos(defaultMoveBatchSize)
+	return &MoveBatch{hdwp: hdwp, err: err, thread: win32.GetCurrentThreadId()}
+}
+
+// SetWindowPos adds a change of the position, size and z-order of child to the batch.
+// The parameters are the same as win32.SetWindowPos.
+func (b *MoveBatch) SetWindowPos(child win32.HWND, insertAfter win32.HWND, x, y, cx, cy win32.INT, flags win32.UINT) {
+	uithread.CheckThread(b.thread)
+	if b.committed {
+		b.err = ErrMoveBatchCommitted
+	}
+	if b.err != nil {
+		return
+	}
+	// The handle is freed by the system if DeferWindowPos fails.
+	b.hdwp, b.err = win32.DeferWindowPos(b.hdwp, child, insertAfter, x, y, cx, cy, flags)
+}
+
+// Move adds a change of the position and size of child to the batch, in pixels.
+func (b *MoveBatch) Move(child win32.HWND, x, y, width, height win32.INT) {
+	uithread.CheckThread(b.thread)
+	b.SetWindowPos(child, 0, x, y, width, height, win32.SWP_NOZORDER|win32.SWP_NOACTIVATE)
+}
+
+// Commit applies all the changes of the batch.
+// It returns the first error occurred since BeginMove, if any.
+func (b *MoveBatch) Commit() error {
+	uithread.CheckThread(b.thread)
+	if b.committed {
+		return ErrMoveBatchCommitted
+	}
+	b.committed = true
+	if b.err != nil {
+		return b.err
+	}
+	return win32.EndDeferWindowPos(b.hdwp)
+}
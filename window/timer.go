@@ -0,0 +1,71 @@
+package window
+
+import (
+	"time"
+
+	"github.com/mkch/gg"
+	"github.com/mkch/gw/internal/timer"
+	"github.com/mkch/gw/internal/uithread"
+	"github.com/mkch/gw/win32"
+)
+
+// NoCoalescing as TimerSpec.Tolerance disables timer coalescing.
+const NoCoalescing = timer.NoCoalescing
+
+// TimerSpec is the specification of a timer.
+type TimerSpec struct {
+	Interval time.Duration
+	// OneShot timers run once, and repeating timers run every Interval until killed.
+	OneShot bool
+	// Tolerance is the delay the system is allowed to add to coalesce the timer with
+	// other timers to save power. 0 means the system default. See NoCoalescing.
+	Tolerance time.Duration
+	Func      func()
+}
+
+// TimerKey identifies a timer.
+type TimerKey struct {
+	m  *timer.Map
+	id win32.UINT_PTR
+}
+
+// Kill destroys the timer. Killing a killed timer, or a fired one-shot timer,
+// does nothing.
+func (k TimerKey) Kill() error {
+	if k.m == nil {
+		return nil
+	}
+	uithread.CheckThread(k.m.Thread())
+	return k.m.Kill(k.id)
+}
+
+// StartTimer starts a timer of spec, which runs in the message loop with WM_TIMER of w.
+// The timers are killed when the window is destroyed.
+func (w *WindowBase) StartTimer(spec *TimerSpec) (TimerKey, error) {
+	uithread.CheckWindow(w.hwnd)
+	if spec.Func == nil {
+		panic("nil timer func")
+	}
+	if w.timers == nil {
+		w.timers = timer.New(w.hwnd)
+	}
+	id, err := w.timers.Set(spec.Interval, spec.OneShot, spec.Tolerance, spec.Func)
+	if err != nil {
+		return TimerKey{}, err
+	}
+	return TimerKey{w.timers, id}, nil
+}
+
+// SetTimer starts a timer that calls f every interval. See StartTimer.
+// It panics if the timer can't be created.
+func (w *WindowBase) SetTimer(interval time.Duration, f func()) TimerKey {
+	uithread.CheckWindow(w.hwnd)
+	return gg.Must(w.StartTimer(&TimerSpec{Interval: interval, Func: f}))
+}
+
+// AfterFunc starts a one-shot timer that calls f after d. See StartTimer.
+// It panics if the timer can't be created.
+func (w *WindowBase) AfterFunc(d time.Duration, f func()) TimerKey {
+	uithread.CheckWindow(w.hwnd)
+	return gg.Must(w.StartTimer(&TimerSpec{Interval: d, OneShot: true, Func: f}))
+}
@@ -0,0 +1,70 @@
+package window_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mkch/gw/win32"
+	"github.com/mkch/gw/window"
+)
+
+func TestTimer(t *testing.T) {
+	w := newWindow(t, &window.Spec{})
+	timers := backend.Timers()
+	var ticks, once int
+	repeat := w.SetTimer(100*time.Millisecond, func() { ticks++ })
+	w.AfterFunc(150*time.Millisecond, func() { once++ })
+	backend.Advance(100 * time.Millisecond)
+	pump()
+	backend.Advance(100 * time.Millisecond)
+	pump()
+	backend.Advance(100 * time.Millisecond)
+	pump()
+	if ticks != 3 || once != 1 {
+		t.Fatal(ticks, once)
+	}
+	if n := backend.Timers(); n != timers+1 {
+		t.Fatal(n)
+	}
+	if err := repeat.Kill(); err != nil {
+		t.Fatal(err)
+	}
+	if err := repeat.Kill(); err != nil {
+		t.Fatal(err)
+	}
+	backend.Advance(time.Second)
+	pump()
+	if ticks != 3 || backend.Timers() != timers {
+		t.Fatal(ticks, backend.Timers())
+	}
+
+	// Coalescing tolerance.
+	if _, err := w.StartTimer(&window.TimerSpec{Interval: time.Second, Tolerance: 50 * time.Millisecond, Func: func() {}}); err != nil {
+		t.Fatal(err)
+	}
+	noCoalescing, err := w.StartTimer(&window.TimerSpec{Interval: time.Second, Tolerance: window.NoCoalescing, Func: func() {}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backend.Timers() != timers+2 {
+		t.Fatal(backend.Timers())
+	}
+	var tolerances []win32.ULONG
+	for id := win32.UINT_PTR(0x6777_0000); id < 0x6777_0010; id++ {
+		if tolerance, ok := backend.TimerTolerance(w.HWND(), id); ok {
+			tolerances = append(tolerances, tolerance)
+		}
+	}
+	if len(tolerances) != 2 || tolerances[0] != 50 || tolerances[1] != win32.TIMERV_NO_COALESCING {
+		t.Fatal(tolerances)
+	}
+
+	// Timers are killed with the window.
+	w.Destroy()
+	if n := backend.Timers(); n != timers {
+		t.Fatal(n)
+	}
+	if err := noCoalescing.Kill(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,119 @@
+package window
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/mkch/gw/internal/uithread"
+	"github.com/mkch/gw/win32"
+	"github.com/mkch/gw/win32/msgnames"
+	"github.com/mkch/gw/win32/win32util"
+)
+
+// MsgTracer logs the messages that match its filters.
+// The zero value logs all messages to slog.Default() at LevelInfo.
+type MsgTracer struct {
+	Logger *slog.Logger // nil means slog.Default().
+	Level  slog.Level
+	// HWND, if not 0, limits tracing to the messages of this window.
+	HWND win32.HWND
+	// MinMsg and MaxMsg, if not both 0, limit tracing to the messages in [MinMsg, MaxMsg].
+	MinMsg, MaxMsg win32.UINT
+	// Class, if not empty, limits tracing to the windows of this class. Case insensitive.
+	Class string
+}
+
+// Match reports whether the message of hwnd passes the filters of t.
+func (t *MsgTracer) Match(hwnd win32.HWND, message win32.UINT) bool {
+	if t.HWND != 0 && hwnd != t.HWND {
+		return false
+	}
+	if (t.MinMsg != 0 || t.MaxMsg != 0) && (message < t.MinMsg || message > t.MaxMsg) {
+		return false
+	}
+	if t.Class != "" {
+		if class, err := win32util.GetClassName(hwnd); err != nil || !strings.EqualFold(class, t.Class) {
+			return false
+		}
+	}
+	return true
+}
+
+// Log logs msg with the decoded m followed by attrs.
+func (t *MsgTracer) Log(msg string, m *win32.MSG, attrs ...slog.Attr) {
+	logger := t.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	ctx := context.Background()
+	if !logger.Enabled(ctx, t.Level) {
+		return
+	}
+	decoded := msgnames.Format(m, className)
+	all := []slog.Attr{slog.Any("message", &decoded)}
+	if m.Hwnd != 0 {
+		if class, err := win32util.GetClassName(m.Hwnd); err == nil {
+			all = append(all, slog.String("class", class))
+		}
+	}
+	logger.LogAttrs(ctx, t.Level, msg, append(all, attrs...)...)
+}
+
+// className returns the class name of hwnd, or "" if failed.
+func className(hwnd win32.HWND) string {
+	class, _ := win32util.GetClassName(hwnd)
+	return class
+}
+
+// Link numbers reported by the tracer of WindowBase.
+const (
+	LinkNative  = -1 // The native window procedure.
+	LinkDefault = 0  // The default handling of WindowBase. Link n > 0 is the WndProc set by the nth SetWndProc.
+)
+
+// msgTrace records a message being processed by realWndProc.
+type msgTrace struct {
+	link int // The deepest link of the WndProc chain reached.
+}
+
+// traceDepths is the number of traced messages being processed in each thread.
+var traceDepths = uithread.NewLocal(func() *int { return new(int) }, nil)
+
+// SetMsgTracer sets the tracer which logs the messages processed by the
+// window procedure of w, along with the result, the processing time and
+// the link of the WndProc chain where the processing stops, that is
+// the last link which did not call its prevWndProc.
+// A nil t stops tracing.
+func (w *WindowBase) SetMsgTracer(t *MsgTracer) {
+	uithread.CheckWindow(w.hwnd)
+	w.tracer = t
+}
+
+// traceLink records that link of the WndProc chain is reached.
+func (w *WindowBase) traceLink(link int) {
+	if n := len(w.traces); n > 0 {
+		w.traces[n-1].link = link
+	}
+}
+
+// tracedWndProc calls wndProc and logs the message with w.tracer.
+func (w *WindowBase) tracedWndProc(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) win32.LRESULT {
+	tracer := w.tracer
+	w.traces = append(w.traces, &msgTrace{link: w.links})
+	depth := traceDepths.Get()
+	*depth++
+	start := time.Now()
+	ret := w.wndProc(hwnd, message, wParam, lParam, w.prevWndProc)
+	elapsed := time.Since(start)
+	*depth--
+	trace := w.traces[len(w.traces)-1]
+	w.traces = w.traces[:len(w.traces)-1]
+	tracer.Log("wndproc", &win32.MSG{Hwnd: hwnd, Message: message, WParam: wParam, LParam: lParam},
+		slog.Int64("result", int64(ret)),
+		slog.Int("link", trace.link),
+		slog.Duration("elapsed", elapsed),
+		slog.Int("depth", *depth))
+	return ret
+}
@@ -0,0 +1,62 @@
+package window_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/mkch/gw/metrics"
+	"github.com/mkch/gw/win32"
+	"github.com/mkch/gw/window"
+)
+
+func TestMsgTracer(t *testing.T) {
+	const (
+		WM_HANDLED = win32.WM_USER + 100
+		WM_PASSED  = win32.WM_USER + 101
+	)
+	w := newWindow(t, &window.Spec{Width: metrics.Px(100), Height: metrics.Px(100)})
+	w.SetWndProc(func(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM, prevWndProc win32.WndProc) win32.LRESULT {
+		if message == WM_HANDLED {
+			return 42
+		}
+		return prevWndProc(hwnd, message, wParam, lParam)
+	})
+	var buf bytes.Buffer
+	w.SetMsgTracer(&window.MsgTracer{
+		Logger: slog.New(slog.NewJSONHandler(&buf, nil)),
+		MinMsg: WM_HANDLED,
+		MaxMsg: WM_PASSED,
+		Class:  "GITHUB.COM/MKCH/GW/WND_CLASS",
+	})
+	win32.SendMessageW(w.HWND(), win32.WM_NULL, 0, 0) // Filtered.
+	win32.SendMessageW(w.HWND(), WM_HANDLED, 1, 2)
+	win32.SendMessageW(w.HWND(), WM_PASSED, 0, 0)
+
+	type record struct {
+		Msg     string
+		Message map[string]string
+		Link    int
+		Result  int
+	}
+	var records []record
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var r record
+		if err := dec.Decode(&r); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, r)
+	}
+	if len(records) != 2 {
+		t.Fatal(records)
+	}
+	if r := records[0]; r.Msg != "wndproc" || r.Message["name"] != "WM_USER+100" || r.Link != 2 || r.Result != 42 || r.Message["wParam"] != "0x1" {
+		t.Fatal(r)
+	}
+	// The Window handles nothing, the native window procedure is reached.
+	if r := records[1]; r.Message["name"] != "WM_USER+101" || r.Link != window.LinkNative {
+		t.Fatal(r)
+	}
+}
@@ -2,32 +2,64 @@ package window
 
 import (
 	"errors"
+	"sync"
 	"unsafe"
 
 	"github.com/mkch/gg"
 	"github.com/mkch/gw/internal/appmsg"
+	"github.com/mkch/gw/internal/timer"
+	"github.com/mkch/gw/internal/uithread"
+	"github.com/mkch/gw/layout"
 	"github.com/mkch/gw/menu"
 	"github.com/mkch/gw/metrics"
 	"github.com/mkch/gw/paint"
 	"github.com/mkch/gw/util/callback"
 	"github.com/mkch/gw/win32"
 	"github.com/mkch/gw/win32/win32util"
-	"golang.org/x/sys/windows"
 )
 
 var CW_USEDEFAULT = metrics.Px(win32.CW_USEDEFAULT)
 
 type msgProc func(msg *win32.MSG) bool
 
-var msgPreTranslatorMap = make(map[win32.HWND]msgProc)
+// threadWindows is the attached windows of a UI thread.
+type threadWindows struct {
+	windows        map[win32.HWND]*WindowBase
+	preTranslators map[win32.HWND]msgProc
+	wndProc        *wndProcSlot // Created by the first Attach.
+	exited         bool         // The message loop of the thread has exited.
+}
+
+var threadWindowsLocal = uithread.NewLocal(func() *threadWindows {
+	return &threadWindows{windows: make(map[win32.HWND]*WindowBase), preTranslators: make(map[win32.HWND]msgProc)}
+}, func(t *threadWindows) {
+	t.exited = true
+	t.releaseWndProc()
+})
+
+// releaseWndProc releases the window procedure of t, after the thread has exited
+// and the last window is destroyed. The windows left are still dispatched by it.
+func (t *threadWindows) releaseWndProc() {
+	if t.exited && len(t.windows) == 0 && t.wndProc != nil {
+		t.wndProc.release()
+		t.wndProc = nil
+	}
+}
+
+// currentThreadWindows returns the windows of the calling thread.
+func currentThreadWindows() *threadWindows {
+	return threadWindowsLocal.Get()
+}
 
 func PreTranslateMessage(msg *win32.MSG) bool {
-	if p := msgPreTranslatorMap[msg.Hwnd]; p != nil {
+	uithread.Check()
+	preTranslators := currentThreadWindows().preTranslators
+	if p := preTranslators[msg.Hwnd]; p != nil {
 		if translated := p(msg); translated {
 			return true
 		}
 	}
-	if p := msgPreTranslatorMap[win32.GetActiveWindow()]; p != nil {
+	if p := preTranslators[win32.GetActiveWindow()]; p != nil {
 		return p(msg)
 	}
 	return false
@@ -36,14 +68,47 @@ func PreTranslateMessage(msg *win32.MSG) bool {
 // LookupWindowBase looks up the WindowBase associated with hwnd.
 // It returns nil if not found.
 func LookupWindowBase(hwnd win32.HWND) *WindowBase {
-	return windowBaseMap[hwnd]
+	uithread.Check()
+	return currentThreadWindows().windows[hwnd]
 }
 
-var windowBaseMap = make(map[win32.HWND]*WindowBase)
+// wndProcSlot is the window procedure of the windows attached in a UI thread,
+// which finds the WindowBase without looking up the thread on every message.
+// Callbacks can't be released, so the slots of the exited threads are reused.
+type wndProcSlot struct {
+	proc    uintptr
+	windows map[win32.HWND]*WindowBase
+}
 
-var wndProc = windows.NewCallback(func(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) win32.LRESULT {
-	return windowBaseMap[hwnd].realWndProc(hwnd, message, wParam, lParam)
-})
+var freeWndProcSlots struct {
+	sync.Mutex
+	slots []*wndProcSlot
+}
+
+// newWndProcSlot returns a slot dispatching the messages to windows.
+// The callback is created after the win32 backend is set.
+func newWndProcSlot(windows map[win32.HWND]*WindowBase) *wndProcSlot {
+	freeWndProcSlots.Lock()
+	defer freeWndProcSlots.Unlock()
+	if n := len(freeWndProcSlots.slots); n > 0 {
+		s := freeWndProcSlots.slots[n-1]
+		freeWndProcSlots.slots = freeWndProcSlots.slots[:n-1]
+		s.windows = windows
+		return s
+	}
+	s := &wndProcSlot{windows: windows}
+	s.proc = win32.NewCallback(func(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) win32.LRESULT {
+		return s.windows[hwnd].realWndProc(hwnd, message, wParam, lParam)
+	})
+	return s
+}
+
+// release puts s back for reuse, when the windows of the thread using s are gone.
+func (s *wndProcSlot) release() {
+	freeWndProcSlots.Lock()
+	defer freeWndProcSlots.Unlock()
+	freeWndProcSlots.slots = append(freeWndProcSlots.slots, s)
+}
 
 type WndProc func(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM, prevWndProc win32.WndProc) win32.LRESULT
 
@@ -57,28 +122,64 @@ type MsgListenerKey struct {
 
 // Remove removes the listener.
 func (k MsgListenerKey) Remove() {
+	uithread.Check()
 	delete(*k.m, k)
 }
 
 type WindowBase struct {
-	OnLButtonUp    func(opt MouseClickOpt, x int, y int)
-	OnLButtonDown  func(opt MouseClickOpt, x int, y int)
-	OnRButtonUp    func(opt MouseClickOpt, x int, y int)
-	OnRButtonDown  func(opt MouseClickOpt, x int, y int)
-	paintCb        *callback.Callback[*paint.PaintData, struct{}]
-	msgListeners   map[win32.UINT]msgListenerMap
-	values         map[any]any
-	hwnd           win32.HWND
-	wndProc        WndProc
-	prevWndProc    win32.WndProc
-	nativeWndProc  uintptr
-	menu           *menu.Menu
-	menuAccel      []win32.ACCEL // Accelerator table of the window menu.
-	popupMenuAccel []win32.ACCEL // Accelerator table of the popup menu(context menu).
-	accelKeyTable  win32.HACCEL
+	// OnLButtonDown and OnRButtonDown are called for WM_LBUTTONDOWN and
+	// WM_RBUTTONDOWN only, not for the second press of a double-click, see OnMouseDown.
+	OnLButtonUp   func(opt MouseClickOpt, x int, y int)
+	OnLButtonDown func(opt MouseClickOpt, x int, y int)
+	OnRButtonUp   func(opt MouseClickOpt, x int, y int)
+	OnRButtonDown func(opt MouseClickOpt, x int, y int)
+	// Keyboard event handlers. The WM_SYS* messages are handled
+	// by the same handlers with KeyEvent.Sys set.
+	OnKeyDown  func(e *KeyEvent)
+	OnKeyUp    func(e *KeyEvent)
+	OnChar     func(e *KeyEvent)
+	OnDeadChar func(e *KeyEvent)
+	// Mouse event handlers. OnMouseDown, OnMouseUp and OnMouseDoubleClick
+	// handle all the buttons, see MouseEvent.Button.
+	// The default window class has CS_DBLCLKS, so the second press of a double-click
+	// arrives as WM_*BUTTONDBLCLK instead of WM_*BUTTONDOWN. It is reported to
+	// OnMouseDown, with MouseEvent.Message of WM_*BUTTONDBLCLK, before OnMouseDoubleClick.
+	OnMouseMove        func(e *MouseEvent)
+	OnMouseDown        func(e *MouseEvent)
+	OnMouseUp          func(e *MouseEvent)
+	OnMouseDoubleClick func(e *MouseEvent)
+	OnMouseWheel       func(e *MouseEvent) // WM_MOUSEWHEEL and WM_MOUSEHWHEEL.
+	OnMouseHover       func(e *MouseEvent)
+	OnMouseLeave       func()
+	paintCb            *callback.Callback[*paint.PaintData, struct{}]
+	msgListeners       map[win32.UINT]msgListenerMap
+	values             map[any]any
+	hwnd               win32.HWND
+	wndProc            WndProc
+	prevWndProc        win32.WndProc
+	nativeWndProc      uintptr
+	thread             *threadWindows // The windows of the thread owning w, see Attach.
+	reflectDefaulted   bool           // A reflected message reached the native window procedure, see reflect.
+	menu               *menu.Menu
+	menuAccel          []win32.ACCEL // Accelerator table of the window menu.
+	popupMenuAccel     []win32.ACCEL // Accelerator table of the popup menu(context menu).
+	accelKeyTable      win32.HACCEL
+	// Layout of the child windows, see SetLayout.
+	layoutRoot      layout.Element
+	layoutListeners []MsgListenerKey
+	layoutBatch     *MoveBatch // The MoveBatch of Relayout in progress.
+	// Message tracing, see SetMsgTracer.
+	tracer *MsgTracer
+	links  int         // Number of the WndProcs set by SetWndProc.
+	traces []*msgTrace // Traced messages being processed.
+	// Mouse state.
+	mouseTracking win32.TRACKMOUSEEVENT_FLAG // Events requested by TrackMouseEvent.
+	drag          *drag                      // See BeginDrag.
+	timers        *timer.Map                 // See StartTimer.
 }
 
 func (w *WindowBase) Destroy() error {
+	uithread.CheckWindow(w.hwnd)
 	return win32.DestroyWindow(w.hwnd)
 }
 
@@ -88,19 +189,24 @@ func (w *WindowBase) Destroy() error {
 // A nil p removes the pre-translator.
 func (w *WindowBase) setMsgPreTranslator(p msgProc) {
 	if p == nil {
-		delete(msgPreTranslatorMap, w.hwnd)
+		delete(w.thread.preTranslators, w.hwnd)
+		return
 	}
-	msgPreTranslatorMap[w.hwnd] = p
+	w.thread.preTranslators[w.hwnd] = p
 }
 
 // SetWndProc sets the window procedure of w.
 // It panics if wndProc is nil.
 func (w *WindowBase) SetWndProc(wndProc WndProc) {
+	uithread.CheckWindow(w.hwnd)
 	if wndProc == nil {
 		panic(errors.New("nil WndProc"))
 	}
 	oldProc, oldPrevProc := w.wndProc, w.prevWndProc
+	w.links++
+	oldLink := w.links - 1
 	w.prevWndProc = func(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) win32.LRESULT {
+		w.traceLink(oldLink)
 		return oldProc(hwnd, message, wParam, lParam, oldPrevProc)
 	}
 	w.wndProc = wndProc
@@ -159,8 +265,15 @@ func (w *WindowBase) realWndProc(hwnd win32.HWND, message win32.UINT, wParam win
 			win32.DestroyAcceleratorTable(w.accelKeyTable)
 			w.accelKeyTable = 0
 		}
-		delete(windowBaseMap, hwnd)
-		delete(msgPreTranslatorMap, hwnd)
+		if w.timers != nil {
+			w.timers.KillAll()
+		}
+		delete(w.thread.windows, hwnd)
+		delete(w.thread.preTranslators, hwnd)
+		w.thread.releaseWndProc()
+	}
+	if w.tracer != nil && w.tracer.Match(hwnd, message) {
+		return w.tracedWndProc(hwnd, message, wParam, lParam)
 	}
 	return w.wndProc(hwnd, message, wParam, lParam, w.prevWndProc)
 }
@@ -209,6 +322,7 @@ type PopupMenuSpec struct {
 // TrackPopupMenu tracks a popup menu.
 // If spec is nil, default flag, empty exclude RECT and GetCursorPos() are used.
 func (w *WindowBase) TrackPopupMenu(menu *menu.Menu, spec *PopupMenuSpec) error {
+	uithread.CheckWindow(w.hwnd)
 	var flags win32.TRACK_POPUP_MENU_FLAG
 	var pt = &win32.POINT{}
 	var params *win32.TPMPARAMS
@@ -280,22 +394,27 @@ func (w *WindowBase) rebuildAccelTable() error {
 }
 
 func (w *WindowBase) Show(cmd win32.SHOW_WINDOW_CMD) {
+	uithread.CheckWindow(w.hwnd)
 	win32.ShowWindow(w.hwnd, cmd)
 }
 
 func (w *WindowBase) SetText(text string) error {
+	uithread.CheckWindow(w.hwnd)
 	return win32util.SetWindowText(w.hwnd, text)
 }
 
 func (w *WindowBase) Text() (string, error) {
+	uithread.CheckWindow(w.hwnd)
 	return win32util.GetWindowText(w.hwnd)
 }
 
 func (w *WindowBase) DPI() (win32.UINT, error) {
+	uithread.CheckWindow(w.hwnd)
 	return win32.GetDpiForWindow(w.hwnd)
 }
 
 func (w *WindowBase) GetClientRect() (*win32.RECT, error) {
+	uithread.CheckWindow(w.hwnd)
 	var rect win32.RECT
 	if err := win32.GetClientRect(w.hwnd, &rect); err != nil {
 		return nil, err
@@ -304,6 +423,7 @@ func (w *WindowBase) GetClientRect() (*win32.RECT, error) {
 }
 
 func (w *WindowBase) GetWindowRect() (*win32.RECT, error) {
+	uithread.CheckWindow(w.hwnd)
 	var rect win32.RECT
 	if err := win32.GetWindowRect(w.hwnd, &rect); err != nil {
 		return nil, err
@@ -312,31 +432,55 @@ func (w *WindowBase) GetWindowRect() (*win32.RECT, error) {
 }
 
 func (w *WindowBase) InvalidateRect(rect *win32.RECT, eraseBk bool) error {
+	uithread.CheckWindow(w.hwnd)
 	return win32.InvalidateRect(w.hwnd, rect, eraseBk)
 }
 
+// reflect sends message reflected from the parent to the attached window hwnd, and
+// reports whether it is handled before reaching the native window procedure of hwnd.
+// handled is false if hwnd is not attached.
+func reflect(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) (ret win32.LRESULT, handled bool) {
+	w := Query(hwnd)
+	if w == nil {
+		return 0, false
+	}
+	defaulted := w.reflectDefaulted // Messages may be reflected recursively.
+	w.reflectDefaulted = false
+	ret, _ = win32.SendMessageW(hwnd, message, wParam, lParam)
+	handled = !w.reflectDefaulted
+	w.reflectDefaulted = defaulted
+	return
+}
+
 // wiErrAlreadyAttached is returned by Attach if the HWND or *WindowBase
 // is already attached.
 var ErrAlreadyAttached = errors.New("already attached")
 
 func Query(hwnd win32.HWND) *WindowBase {
-	return windowBaseMap[hwnd]
+	uithread.Check()
+	return currentThreadWindows().windows[hwnd]
 }
 
 func Attach(hwnd win32.HWND, window *WindowBase) error {
-	if Query(hwnd) != nil {
+	uithread.CheckWindow(hwnd)
+	thread := currentThreadWindows()
+	if thread.windows[hwnd] != nil {
 		return ErrAlreadyAttached
 	}
+	if thread.wndProc == nil {
+		thread.wndProc = newWndProcSlot(thread.windows)
+	}
 	if proc, err := win32.GetWindowLongPtrW(hwnd, win32.GWLP_WNDPROC); err != nil {
 		return err
-	} else if proc == win32.LONG_PTR(wndProc) {
+	} else if proc == win32.LONG_PTR(thread.wndProc.proc) {
 		return ErrAlreadyAttached
 	}
 
-	if oldProc, err := win32.SetWindowLongPtrW(hwnd, win32.GWLP_WNDPROC, win32.LONG_PTR(wndProc)); err != nil {
+	if oldProc, err := win32.SetWindowLongPtrW(hwnd, win32.GWLP_WNDPROC, win32.LONG_PTR(thread.wndProc.proc)); err != nil {
 		return err
 	} else {
-		windowBaseMap[hwnd] = window
+		thread.windows[hwnd] = window
+		window.thread = thread
 		window.nativeWndProc = uintptr(oldProc)
 		window.prevWndProc = func(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) win32.LRESULT {
 			if window.msgListeners != nil {
@@ -347,10 +491,34 @@ func Attach(hwnd win32.HWND, window *WindowBase) error {
 				}
 			}
 			switch message {
-			case win32.WM_CTLCOLORSTATIC:
-				if ret, err := win32.SendMessageW(win32.HWND(lParam), appmsg.REFLECT_CTLCOLORSTATIC, wParam, lParam); err == nil && ret != 0 {
+			// The messages of controls are reflected to the attached controls only, because
+			// the reflected messages may conflict with other messages of native windows.
+			// The messages not handled by the controls are processed as usual.
+			case win32.WM_CTLCOLORSTATIC, win32.WM_CTLCOLOREDIT, win32.WM_CTLCOLORBTN, win32.WM_CTLCOLORLISTBOX:
+				if ret, handled := reflect(win32.HWND(lParam), appmsg.ReflectCtlColor[message], wParam, lParam); handled && ret != 0 {
 					return ret
 				}
+			case win32.WM_NOTIFY:
+				hdr := win32.LParamPointer[win32.NMHDR](lParam)
+				if ret, handled := reflect(hdr.HwndFrom, appmsg.REFLECT_NOTIFY, wParam, lParam); handled {
+					return ret
+				}
+			case win32.WM_DRAWITEM:
+				if wParam != 0 { // Not menu.
+					item := win32.LParamPointer[win32.DRAWITEMSTRUCT](lParam)
+					if ret, handled := reflect(item.HwndItem, appmsg.REFLECT_DRAWITEM, wParam, lParam); handled {
+						return ret
+					}
+				}
+			case win32.WM_MEASUREITEM:
+				// Controls are found by ID, which must be unique among the siblings.
+				if wParam != 0 { // Not menu.
+					if ctrl, err := win32.GetDlgItem(hwnd, win32.INT(wParam)); err == nil {
+						if ret, handled := reflect(ctrl, appmsg.REFLECT_MEASUREITEM, wParam, lParam); handled {
+							return ret
+						}
+					}
+				}
 			case win32.WM_COMMAND:
 				if lParam != 0 {
 					win32.SendMessageW(win32.HWND(lParam), appmsg.REFLECT_COMMAND, wParam, lParam)
@@ -373,6 +541,15 @@ func Attach(hwnd win32.HWND, window *WindowBase) error {
 				if window.OnRButtonDown != nil {
 					window.OnRButtonDown(MouseClickOpt(wParam), int(win32.GET_X_LPARAM(lParam)), int(win32.GET_Y_LPARAM(lParam)))
 				}
+			case win32.WM_KEYDOWN, win32.WM_KEYUP, win32.WM_CHAR, win32.WM_DEADCHAR,
+				win32.WM_SYSKEYDOWN, win32.WM_SYSKEYUP, win32.WM_SYSCHAR, win32.WM_SYSDEADCHAR:
+				if window.handleKey(message, wParam, lParam) {
+					return 0 // Not calling default.
+				}
+			case win32.WM_TIMER:
+				if window.timers != nil && window.timers.Fire(win32.UINT_PTR(wParam)) {
+					return 0 // Not calling default.
+				}
 			case win32.WM_PAINT:
 				if window.paintCb == nil {
 					break
@@ -391,14 +568,20 @@ func Attach(hwnd win32.HWND, window *WindowBase) error {
 				return 0 // Not calling default.
 			case win32.WM_DPICHANGED:
 				// For top level windows.
-
-				// To defeat go vet (possible misuse of unsafe.Pointer).
-				// Should be suggested := (*win32.RECT)(unsafe.Pointer(uintptr(lParam)))
-				suggested := (*win32.RECT)(unsafe.Pointer(uintptr(unsafe.Pointer(nil)) + uintptr(lParam)))
+				suggested := win32.LParamPointer[win32.RECT](lParam)
 				win32.SetWindowPos(hwnd, 0,
 					win32.INT(suggested.Left), win32.INT(suggested.Top), win32.INT(suggested.Width()), win32.INT(suggested.Height()),
 					win32.SWP_NOZORDER|win32.SWP_NOACTIVATE)
 			}
+			if isMouseMessage(message) && window.handleMouse(hwnd, message, wParam, lParam) {
+				return 0 // Not calling default.
+			}
+			switch message {
+			case appmsg.REFLECT_NOTIFY, appmsg.REFLECT_DRAWITEM, appmsg.REFLECT_MEASUREITEM,
+				appmsg.REFLECT_CTLCOLORSTATIC, appmsg.REFLECT_CTLCOLOREDIT, appmsg.REFLECT_CTLCOLORBTN, appmsg.REFLECT_CTLCOLORLISTBOX:
+				window.reflectDefaulted = true // Not handled.
+			}
+			window.traceLink(LinkNative)
 			return win32.CallWindowProcW(window.nativeWndProc, hwnd, message, wParam, lParam)
 		}
 		window.wndProc = func(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM, prevWndProc win32.WndProc) win32.LRESULT {
@@ -410,6 +593,7 @@ func Attach(hwnd win32.HWND, window *WindowBase) error {
 }
 
 func (w *WindowBase) AddPaintCallback(f func(data *paint.PaintData, prev func(*paint.PaintData))) {
+	uithread.CheckWindow(w.hwnd)
 	if w.paintCb == nil {
 		w.paintCb = callback.New(func(data *paint.PaintData, prev func(*paint.PaintData) (struct{}, error)) (_ struct{}, _ error) {
 			return
@@ -422,6 +606,7 @@ func (w *WindowBase) AddPaintCallback(f func(data *paint.PaintData, prev func(*p
 }
 
 func (w *WindowBase) AddDoubleBufferingPaintCallback() (err error) {
+	uithread.CheckWindow(w.hwnd)
 	rect, err := w.GetClientRect()
 	if err != nil {
 		return
@@ -495,6 +680,7 @@ func (w *WindowBase) AddDoubleBufferingPaintCallback() (err error) {
 // To remove the listener, call Remove() of the returned MsgListenerKey.
 func (w *WindowBase) AddMsgListener(message win32.UINT,
 	listener func(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM)) MsgListenerKey {
+	uithread.CheckWindow(w.hwnd)
 	if listener == nil {
 		panic("nil listener")
 	}
@@ -516,6 +702,7 @@ func (w *WindowBase) AddMsgListener(message win32.UINT,
 // if no value is associated with key.
 // See Context.Value() in context package for the concept and usage of associated value.
 func (w *WindowBase) Value(key any) any {
+	uithread.CheckWindow(w.hwnd)
 	if w.values == nil {
 		return nil
 	}
@@ -526,6 +713,7 @@ func (w *WindowBase) Value(key any) any {
 // Setting A nil value deletes the value associated with key if any.
 // See Context.Value() in context package for the concept and usage of associated value.
 func (w *WindowBase) SetValue(key, value any) {
+	uithread.CheckWindow(w.hwnd)
 	if value == nil {
 		delete(w.values, key)
 		return
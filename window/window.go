@@ -1,7 +1,10 @@
 package window
 
 import (
+	"sync"
+
 	"github.com/mkch/gg"
+	"github.com/mkch/gw/internal/uithread"
 	"github.com/mkch/gw/menu"
 	"github.com/mkch/gw/metrics"
 	"github.com/mkch/gw/win32"
@@ -10,7 +13,17 @@ import (
 
 const defClassName = "github.com/mkch/gw/wnd_class"
 
-var defClassRegistered = false
+var registerDefClass = sync.OnceFunc(func() {
+	gg.Must(win32util.RegisterClass(&win32util.WndClass{
+		ClassName: defClassName,
+		WndProc: func(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) win32.LRESULT {
+			return win32.DefWindowProcW(hwnd, message, wParam, lParam)
+		},
+		Style:      win32.CS_DBLCLKS,
+		Background: win32.HBRUSH(win32.COLOR_WINDOW + 1),
+		Cursor:     gg.Must(win32.LoadImageW_uintptr[win32.HCURSOR](0, uintptr(win32.OCR_NORMAL), win32.IMAGE_CURSOR, 0, 0, win32.LR_DEFAULTSIZE|win32.LR_SHARED)),
+	}))
+})
 
 type Spec struct {
 	ClassName string
@@ -37,17 +50,8 @@ type Window struct {
 }
 
 func New(spec *Spec) (*Window, error) {
-	if !defClassRegistered {
-		gg.Must(win32util.RegisterClass(&win32util.WndClass{
-			ClassName: defClassName,
-			WndProc: func(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM) win32.LRESULT {
-				return win32.DefWindowProcW(hwnd, message, wParam, lParam)
-			},
-			Background: win32.HBRUSH(win32.COLOR_WINDOW + 1),
-			Cursor:     gg.Must(win32.LoadImageW_uintptr[win32.HCURSOR](0, uintptr(win32.OCR_NORMAL), win32.IMAGE_CURSOR, 0, 0, win32.LR_DEFAULTSIZE|win32.LR_SHARED)),
-		}))
-		defClassRegistered = true
-	}
+	uithread.Check()
+	registerDefClass()
 	if spec.ClassName == "" {
 		copy := *spec
 		copy.ClassName = defClassName
@@ -151,6 +155,7 @@ func New(spec *Spec) (*Window, error) {
 }
 
 func (w *Window) SetMenu(menu *menu.Menu) error {
+	uithread.CheckWindow(w.hwnd)
 	return w.setMenu(menu)
 }
 
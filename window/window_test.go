@@ -0,0 +1,347 @@
+package window_test
+
+import (
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/mkch/gw/internal/uithread"
+	"github.com/mkch/gw/menu"
+	"github.com/mkch/gw/metrics"
+	"github.com/mkch/gw/paint"
+	"github.com/mkch/gw/win32"
+	"github.com/mkch/gw/win32/fake"
+	"github.com/mkch/gw/window"
+)
+
+var backend = fake.New()
+
+func TestMain(m *testing.M) {
+	win32.SetBackend(backend)
+	os.Exit(m.Run())
+}
+
+// pump processes the messages in the queue as the message loop of gwapp does.
+func pump() {
+	var msg win32.MSG
+	for win32.PeekMessageW(&msg, 0, 0, 0, win32.PM_REMOVE) != 0 {
+		if !window.PreTranslateMessage(&msg) {
+			win32.TranslateMessage(&msg)
+			win32.DispatchMessageW(&msg)
+		}
+	}
+}
+
+// messages returns the message IDs delivered to hwnd.
+func messages(hwnd win32.HWND) []win32.UINT {
+	var ids []win32.UINT
+	for _, msg := range backend.Delivered(hwnd) {
+		ids = append(ids, msg.Message)
+	}
+	return ids
+}
+
+func newWindow(t *testing.T, spec *window.Spec) *window.Window {
+	t.Helper()
+	w, err := window.New(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if window.LookupWindowBase(w.HWND()) != nil {
+			w.Destroy()
+		}
+	})
+	return w
+}
+
+func TestNew(t *testing.T) {
+	created := false
+	w := newWindow(t, &window.Spec{
+		Text:     "Hello",
+		Style:    win32.WS_OVERLAPPEDWINDOW,
+		X:        metrics.Px(10),
+		Y:        metrics.Px(20),
+		Width:    metrics.Px(300),
+		Height:   metrics.Px(200),
+		OnCreate: func() { created = true },
+	})
+	if !created {
+		t.Fatal("OnCreate not called")
+	}
+	info, ok := backend.Window(w.HWND())
+	if !ok {
+		t.Fatal("no window")
+	}
+	if info.Class != "github.com/mkch/gw/wnd_class" || info.Text != "Hello" {
+		t.Fatal(info.Class, info.Text)
+	}
+	if want := (win32.RECT{Left: 10, Top: 20, Right: 310, Bottom: 220}); info.Rect != want {
+		t.Fatal(info.Rect)
+	}
+	if ids := messages(w.HWND()); !slices.Contains(ids, win32.WM_CREATE) || !slices.Contains(ids, win32.WM_SIZE) {
+		t.Fatal(ids)
+	}
+
+	if err := w.SetText("World"); err != nil {
+		t.Fatal(err)
+	}
+	if text, err := w.Text(); err != nil {
+		t.Fatal(err)
+	} else if text != "World" {
+		t.Fatal(text)
+	}
+	if rect, err := w.GetClientRect(); err != nil {
+		t.Fatal(err)
+	} else if rect.Width() != 300 || rect.Height() != 200 {
+		t.Fatal(rect)
+	}
+}
+
+func TestShowDefault(t *testing.T) {
+	w := newWindow(t, &window.Spec{
+		Style:  win32.WS_OVERLAPPEDWINDOW | win32.WS_VISIBLE,
+		X:      window.CW_USEDEFAULT,
+		Y:      window.CW_USEDEFAULT,
+		Width:  window.CW_USEDEFAULT,
+		Height: window.CW_USEDEFAULT,
+	})
+	info, _ := backend.Window(w.HWND())
+	if !info.Visible {
+		t.Fatal("not visible")
+	}
+	if want := (win32.RECT{Left: fake.DefaultX, Top: fake.DefaultY, Right: fake.DefaultX + fake.DefaultWidth, Bottom: fake.DefaultY + fake.DefaultHeight}); info.Rect != want {
+		t.Fatal(info.Rect)
+	}
+	if active := win32.GetActiveWindow(); active != w.HWND() {
+		t.Fatal(active)
+	}
+
+	w.Show(win32.SW_MAXIMIZE)
+	if info, _ := backend.Window(w.HWND()); !info.Maximized {
+		t.Fatal("not maximized")
+	}
+	w.Show(win32.SW_RESTORE)
+	if info, _ := backend.Window(w.HWND()); info.Maximized || info.Rect.Width() != fake.DefaultWidth {
+		t.Fatal(info)
+	}
+}
+
+func TestClose(t *testing.T) {
+	allowClose, destroyed := false, false
+	w := newWindow(t, &window.Spec{
+		Width:     metrics.Px(100),
+		Height:    metrics.Px(100),
+		OnClose:   func() bool { return allowClose },
+		OnDestroy: func() { destroyed = true },
+	})
+	hwnd := w.HWND()
+
+	win32.PostMessageW(hwnd, win32.WM_CLOSE, 0, 0)
+	pump()
+	if _, ok := backend.Window(hwnd); !ok || destroyed {
+		t.Fatal("closed")
+	}
+
+	allowClose = true
+	win32.PostMessageW(hwnd, win32.WM_CLOSE, 0, 0)
+	pump()
+	if _, ok := backend.Window(hwnd); ok {
+		t.Fatal("not closed")
+	}
+	if !destroyed {
+		t.Fatal("OnDestroy not called")
+	}
+	if window.LookupWindowBase(hwnd) != nil {
+		t.Fatal("not detached")
+	}
+	ids := messages(hwnd)
+	if i, j := slices.Index(ids, win32.WM_DESTROY), slices.Index(ids, win32.WM_NCDESTROY); i == -1 || j < i {
+		t.Fatal(ids)
+	}
+}
+
+func TestMenu(t *testing.T) {
+	m := menu.New(false)
+	file, _ := m.InsertItem(-1, &menu.ItemSpec{Title: "File"})
+	fileMenu := menu.New(true)
+	file.SetSubmenu(fileMenu)
+	var clicked []string
+	open, err := fileMenu.InsertItem(-1, &menu.ItemSpec{
+		Title:    "Open",
+		AccelKey: menu.AccelKey{Mod: menu.ModCtrl, VKeyCode: 'O'},
+		OnClick:  func() { clicked = append(clicked, "open") },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := newWindow(t, &window.Spec{Width: metrics.Px(100), Height: metrics.Px(100), Menu: m})
+	if info, _ := backend.Window(w.HWND()); info.Menu != m.HMENU() {
+		t.Fatal(info.Menu)
+	}
+
+	// Accelerator key Ctrl+O.
+	backend.KeyDown(w.HWND(), win32.VK_CONTROL)
+	backend.KeyDown(w.HWND(), 'O')
+	backend.KeyUp(w.HWND(), 'O')
+	backend.KeyUp(w.HWND(), win32.VK_CONTROL)
+	pump()
+	if !slices.Equal(clicked, []string{"open"}) {
+		t.Fatal(clicked)
+	}
+
+	// Disabled items are not activated by accelerator keys.
+	open.SetDisabled(true)
+	backend.KeyDown(w.HWND(), win32.VK_CONTROL)
+	backend.KeyDown(w.HWND(), 'O')
+	backend.KeyUp(w.HWND(), 'O')
+	backend.KeyUp(w.HWND(), win32.VK_CONTROL)
+	pump()
+	if len(clicked) != 1 {
+		t.Fatal(clicked)
+	}
+
+	hMenu, hFileMenu := m.HMENU(), fileMenu.HMENU()
+	w.Destroy()
+	if _, err := backend.MenuItems(hMenu); err == nil {
+		t.Fatal("menu not destroyed")
+	}
+	if _, err := backend.MenuItems(hFileMenu); err == nil {
+		t.Fatal("submenu not destroyed")
+	}
+}
+
+func TestTrackPopupMenu(t *testing.T) {
+	popup := menu.New(true)
+	defer popup.Destroy()
+	clicked := false
+	item, _ := popup.InsertItem(-1, &menu.ItemSpec{Title: "Copy", OnClick: func() { clicked = true }})
+
+	w := newWindow(t, &window.Spec{Width: metrics.Px(100), Height: metrics.Px(100)})
+	var x, y win32.INT
+	backend.TrackPopupMenu = func(m win32.HMENU, px, py win32.INT) win32.UINT {
+		x, y = px, py
+		return win32.UINT(item.ID())
+	}
+	defer func() { backend.TrackPopupMenu = nil }()
+
+	if err := w.TrackPopupMenu(popup, &window.PopupMenuSpec{X: 30, Y: 40}); err != nil {
+		t.Fatal(err)
+	}
+	if x != 30 || y != 40 {
+		t.Fatal(x, y)
+	}
+	if !slices.Contains(messages(w.HWND()), win32.WM_INITMENUPOPUP) {
+		t.Fatal("no WM_INITMENUPOPUP")
+	}
+	if clicked {
+		t.Fatal("WM_COMMAND is not posted")
+	}
+	pump()
+	if !clicked {
+		t.Fatal("not clicked")
+	}
+}
+
+func TestPaint(t *testing.T) {
+	objects := backend.GDIObjects()
+	w := newWindow(t, &window.Spec{Width: metrics.Px(100), Height: metrics.Px(50)})
+	pump()
+
+	var painted []paint.PaintData
+	w.AddPaintCallback(func(data *paint.PaintData, prev func(*paint.PaintData)) {
+		painted = append(painted, *data)
+		prev(data)
+	})
+	w.InvalidateRect(&win32.RECT{Left: 1, Top: 2, Right: 3, Bottom: 4}, false)
+	w.InvalidateRect(&win32.RECT{Left: 5, Top: 6, Right: 7, Bottom: 8}, false)
+	if info, _ := backend.Window(w.HWND()); !info.Invalid {
+		t.Fatal("not invalid")
+	}
+	pump()
+	if len(painted) != 1 {
+		t.Fatal(painted)
+	}
+	if want := (win32.RECT{Left: 1, Top: 2, Right: 7, Bottom: 8}); painted[0].Rect != want {
+		t.Fatal(painted[0].Rect)
+	}
+	if info, _ := backend.Window(w.HWND()); info.Invalid {
+		t.Fatal("not validated")
+	}
+	if n := backend.GDIObjects(); n != objects {
+		t.Fatal("GDI objects leaked", n-objects)
+	}
+}
+
+func TestDPIChanged(t *testing.T) {
+	w := newWindow(t, &window.Spec{
+		X:      metrics.Px(10),
+		Y:      metrics.Px(10),
+		Width:  metrics.Dip(100),
+		Height: metrics.Dip(50),
+	})
+	if err := backend.SetDPI(w.HWND(), 192); err != nil {
+		t.Fatal(err)
+	}
+	if dpi, _ := w.DPI(); dpi != 192 {
+		t.Fatal(dpi)
+	}
+	if rect, _ := w.GetWindowRect(); *rect != (win32.RECT{Left: 10, Top: 10, Right: 210, Bottom: 110}) {
+		t.Fatal(rect)
+	}
+}
+
+// inThread runs f in a new UI thread, whose message loop exits when f returns.
+func inThread(f func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		id := win32.GetCurrentThreadId()
+		uithread.Register(id)
+		defer uithread.Unregister(id)
+		f()
+	}()
+	<-done
+}
+
+// wndProcOf returns the window procedure of a new window created in a new UI thread.
+func wndProcOf() (proc win32.LONG_PTR) {
+	inThread(func() {
+		w, err := window.New(&window.Spec{})
+		if err != nil {
+			panic(err)
+		}
+		defer w.Destroy()
+		proc, _ = win32.GetWindowLongPtrW(w.HWND(), win32.GWLP_WNDPROC)
+	})
+	return
+}
+
+func TestThreadExit(t *testing.T) {
+	id := win32.GetCurrentThreadId()
+	uithread.Register(id)
+	w := newWindow(t, &window.Spec{})
+	msgs := 0
+	w.SetWndProc(func(hwnd win32.HWND, message win32.UINT, wParam win32.WPARAM, lParam win32.LPARAM, prev win32.WndProc) win32.LRESULT {
+		if message == win32.WM_USER {
+			msgs++
+		}
+		return prev(hwnd, message, wParam, lParam)
+	})
+	proc, _ := win32.GetWindowLongPtrW(w.HWND(), win32.GWLP_WNDPROC)
+	uithread.Unregister(id) // The message loop exits with w left.
+
+	// The window procedure of w is not reused by another thread until w is destroyed.
+	if p := wndProcOf(); p == proc {
+		t.Fatal("reused")
+	}
+	win32.SendMessageW(w.HWND(), win32.WM_USER, 0, 0)
+	if msgs != 1 {
+		t.Fatal(msgs)
+	}
+	w.Destroy()
+	if p := wndProcOf(); p != proc {
+		t.Fatal("not reused")
+	}
+}